@@ -0,0 +1,87 @@
+// cmd/fakebank/main.go
+
+// Fake sandbox banks for local development and e2e tests: every bank listens on its own port
+// (base port + index), so it can be set as api_base_url of the bank.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"multibank/backend/internal/fakebank"
+	"multibank/backend/internal/shutdown"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// go run ./cmd/fakebank --banks=abank,sbank,vbank --port=9001 --client-id=team014 --client-secret=secret
+
+func main() {
+	var (
+		banks        = flag.String("banks", "abank,sbank,vbank", "comma-separated bank codes")
+		port         = flag.Int("port", 9001, "port of the first bank, the next banks get the next ports")
+		clientID     = flag.String("client-id", "team014", "team login for /auth/bank-token")
+		clientSecret = flag.String("client-secret", "secret", "team secret for /auth/bank-token")
+		autoApprove  = flag.Bool("auto-approve", true, "authorize consents right away")
+		latency      = flag.Duration("latency", 0, "latency added to every response")
+		jitter       = flag.Duration("jitter", 0, "random extra latency up to the value")
+		errorRate    = flag.Float64("error-rate", 0, "share of requests answered with 503 (0..1)")
+		oauth2       = flag.String("oauth2", "", "comma-separated bank codes issuing tokens by /oauth2/token (adapter 'oauth2')")
+	)
+	flag.Parse()
+
+	log := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	oauth2Banks := map[string]bool{}
+	for _, code := range strings.Split(*oauth2, ",") {
+		oauth2Banks[strings.TrimSpace(code)] = true
+	}
+
+	var srvs servers
+	for i, code := range strings.Split(*banks, ",") {
+		code = strings.TrimSpace(code)
+		if code == "" {
+			continue
+		}
+		bank := fakebank.New(log, fakebank.Options{
+			Code:         code,
+			ClientID:     *clientID,
+			ClientSecret: *clientSecret,
+			OAuth2:       oauth2Banks[code],
+			AutoApprove:  *autoApprove,
+			Latency:      *latency,
+			Jitter:       *jitter,
+			ErrorRate:    *errorRate,
+		})
+		srv := &http.Server{
+			Addr:              ":" + strconv.Itoa(*port+i),
+			Handler:           bank,
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+		srvs = append(srvs, srv)
+
+		go func() {
+			log.Info("fake bank started", slog.String("bank", code), slog.String("url", "http://localhost"+srv.Addr))
+			if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Error("fake bank stopped with error", slog.String("bank", code), slog.Any("err", err))
+				os.Exit(1)
+			}
+		}()
+	}
+
+	shutdown.Wait(srvs, log)
+}
+
+type servers []*http.Server
+
+func (s servers) Stop(ctx context.Context) error {
+	var errs []error
+	for _, srv := range s {
+		errs = append(errs, srv.Shutdown(ctx))
+	}
+	return errors.Join(errs...)
+}
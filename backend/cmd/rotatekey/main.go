@@ -0,0 +1,75 @@
+// cmd/rotatekey/main.go
+
+// Re-encrypts bank secrets in the DB (banks.password, bank_tokens.access_token) by the current master key.
+// To rotate the key: put the new key into secrets.master_key (MB_MASTER_KEY), move the old one into
+// secrets.old_master_keys (MB_OLD_MASTER_KEYS), run the command, then remove the old key from the config.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"multibank/backend/internal/config"
+	"multibank/backend/internal/logger"
+	"multibank/backend/internal/secret"
+	"multibank/backend/internal/storage/sqlite"
+	"os"
+	"time"
+)
+
+// go run ./cmd/rotatekey --generate
+// MB_MASTER_KEY=<new> MB_OLD_MASTER_KEYS=<old> go run ./cmd/rotatekey --config=./config/local.yaml
+
+func main() {
+	var (
+		configPath = flag.String("config", os.Getenv("CONFIG_PATH"), "config file path")
+		generate   = flag.Bool("generate", false, "print a new random master key and exit")
+	)
+	flag.Parse()
+
+	if *generate {
+		fmt.Println(secret.GenerateKey())
+		return
+	}
+	if *configPath == "" {
+		fmt.Fprintln(os.Stderr, "config file path is empty")
+		os.Exit(2)
+	}
+
+	cfg := config.MustLoadByPath(*configPath)
+	log := logger.Setup(cfg.Logger.Level)
+
+	if err := run(log, cfg); err != nil {
+		log.Error("key rotation failed", logger.Err(err))
+		os.Exit(1)
+	}
+}
+
+func run(log *slog.Logger, cfg *config.Config) error {
+	box, err := secret.NewBox(cfg.Secrets.MasterKey, cfg.Secrets.OldMasterKeys...)
+	if err != nil {
+		return fmt.Errorf("master key: %w", err)
+	}
+
+	st, err := sqlite.New(cfg.StoragePath)
+	if err != nil {
+		return fmt.Errorf("sqlite init: %w", err)
+	}
+	defer st.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	// the schema may be older than the binary
+	if err := st.Migrate(ctx); err != nil {
+		return fmt.Errorf("sqlite migrate: %w", err)
+	}
+
+	n, err := sqlite.NewBankRepo(st.DB(), box).EncryptSecrets(ctx)
+	if err != nil {
+		return err
+	}
+	log.Info("bank secrets re-encrypted", slog.Int("values", n), slog.String("key_id", box.KeyID()))
+	return nil
+}
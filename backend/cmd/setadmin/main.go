@@ -0,0 +1,65 @@
+// cmd/setadmin/main.go
+
+// Grants admin rights (access to /admin/*) to a registered user, or revokes them with --revoke.
+// Admins cannot be created through the API, so the first one is set by this command.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"multibank/backend/internal/config"
+	"multibank/backend/internal/logger"
+	usersvc "multibank/backend/internal/service/user"
+	"multibank/backend/internal/storage/sqlite"
+	"os"
+	"time"
+)
+
+// go run ./cmd/setadmin --config=./config/local.yaml --email=admin@example.com
+// go run ./cmd/setadmin --config=./config/local.yaml --email=admin@example.com --revoke
+
+func main() {
+	var (
+		configPath = flag.String("config", os.Getenv("CONFIG_PATH"), "config file path")
+		email      = flag.String("email", "", "email of the registered user")
+		revoke     = flag.Bool("revoke", false, "revoke admin rights instead of granting them")
+	)
+	flag.Parse()
+
+	if *configPath == "" {
+		fmt.Fprintln(os.Stderr, "config file path is empty")
+		os.Exit(2)
+	}
+	if *email == "" {
+		fmt.Fprintln(os.Stderr, "email is empty")
+		os.Exit(2)
+	}
+
+	cfg := config.MustLoadByPath(*configPath)
+	log := logger.Setup(cfg.Logger.Level)
+
+	if err := run(log, cfg, *email, !*revoke); err != nil {
+		log.Error("failed to set admin rights", logger.Err(err))
+		os.Exit(1)
+	}
+}
+
+func run(log *slog.Logger, cfg *config.Config, email string, admin bool) error {
+	st, err := sqlite.New(cfg.StoragePath)
+	if err != nil {
+		return fmt.Errorf("sqlite init: %w", err)
+	}
+	defer st.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	// the schema may be older than the binary
+	if err := st.Migrate(ctx); err != nil {
+		return fmt.Errorf("sqlite migrate: %w", err)
+	}
+
+	return usersvc.New(log, sqlite.NewUserRepo(st.DB())).SetAdmin(ctx, email, admin)
+}
@@ -22,7 +22,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns the list of accounts available for the authorized user, aggregated across all connected banks.\nEach account includes nickname, status, subtype, opening date, and current balance (InterimAvailable).",
+                "description": "Returns the list of accounts available for the authorized user, aggregated across all connected banks.\nEach account includes nickname, status, subtype, opening date, all balances reported by the bank\n(signed, negative for Debit) and the primary balance (InterimAvailable if present) in amount/currency.\nData is served from the local store refreshed in background: last_synced_at shows the refresh time,\nstale=true means the bank has not been reachable for a while.",
                 "produces": [
                     "application/json"
                 ],
@@ -63,62 +63,66 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/recommended-products": {
+        "/accounts/{accountId}/transactions": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "description": "Returns transaction history of the account from the local store (synced from the bank in background).\nDates accept YYYY-MM-DD or RFC3339. Newest transactions first.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "admin/products"
-                ],
-                "summary": "List recommended product rules",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dto.RecommendedRule"
-                            }
-                        }
-                    }
-                }
-            },
-            "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "admin/products"
+                    "accounts"
                 ],
-                "summary": "Upsert recommended rule",
+                "summary": "List account transactions",
                 "parameters": [
                     {
-                        "description": "Rule triplet",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.RecommendedUpsertRequest"
-                        }
+                        "type": "string",
+                        "description": "Account ID (from /accounts)",
+                        "name": "accountId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Bank ID of the account (optional)",
+                        "name": "bank_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Booking date from (YYYY-MM-DD or RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Booking date to (YYYY-MM-DD or RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (from 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 500)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TransactionsPageResponse"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -126,44 +130,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
-                    }
-                }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "consumes": [
-                    "application/json"
-                ],
-                "tags": [
-                    "admin/products"
-                ],
-                "summary": "Delete recommended rule",
-                "parameters": [
-                    {
-                        "description": "Rule triplet to delete",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.RecommendedUpsertRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "No Content"
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -177,55 +151,58 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/login": {
-            "post": {
-                "description": "Returns access_token using e-mail and password.\n\n**Request example**\n` + "`" + `` + "`" + `` + "`" + `json\n{\n\"email\": \"user@example.com\",\n\"password\": \"P@ssw0rd123\"\n}\n` + "`" + `` + "`" + `` + "`" + `\n\n**Response example**\n` + "`" + `` + "`" + `` + "`" + `json\n{\n\"access_token\": \"eyJhbGciOi...\",\n\"expires_in\": 3600\n}\n` + "`" + `` + "`" + `` + "`" + `",
-                "consumes": [
-                    "application/json"
+        "/admin/banks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Returns all banks, disabled ones too, with their settings and token status. Credentials are never returned.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
-                ],
-                "summary": "Login",
-                "parameters": [
-                    {
-                        "description": "Login payload",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.LoginRequest"
-                        }
-                    }
+                    "admin/banks"
                 ],
+                "summary": "List all banks",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.TokenResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.BankAdminResponse"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/auth/register": {
+            },
             "post": {
-                "description": "User registration. Returns access_token (usable immediately).\n\n**Request example**\n` + "`" + `` + "`" + `` + "`" + `json\n{\n\"email\": \"user@example.com\",\n\"first_name\": \"Ivan\",\n\"last_name\": \"Petrov\",\n\"patronymic\": \"Ivanovich\",\n\"birthdate\": \"1990-01-15\",\n\"password\": \"P@ssw0rd123\"\n}\n` + "`" + `` + "`" + `` + "`" + `\n\n**Response example**\n` + "`" + `` + "`" + `` + "`" + `json\n{\n\"access_token\": \"eyJhbGciOi...\",\n\"expires_in\": 3600\n}\n` + "`" + `` + "`" + `` + "`" + `",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a bank with the team credentials. Adapter selects the API dialect of the bank (sandbox by default).",
                 "consumes": [
                     "application/json"
                 ],
@@ -233,17 +210,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin/banks"
                 ],
-                "summary": "Register user",
+                "summary": "Create bank",
                 "parameters": [
                     {
-                        "description": "Register payload",
-                        "name": "request",
+                        "description": "Bank",
+                        "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.RegisterRequest"
+                            "$ref": "#/definitions/dto.BankCreateRequest"
                         }
                     }
                 ],
@@ -251,7 +228,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/dto.TokenResponse"
+                            "$ref": "#/definitions/dto.BankAdminResponse"
                         }
                     },
                     "400": {
@@ -260,8 +237,20 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
                     "409": {
-                        "description": "email already used",
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -269,42 +258,39 @@ const docTemplate = `{
                 }
             }
         },
-        "/banks": {
+        "/admin/banks/breakers": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves all enabled banks and shows whether backend is authorized for each (token cached) and token expiry.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Returns the circuit breaker state of banks whose calls have failed since start. While the circuit is open,\ncalls to the bank fail fast until retry_at, then a single probe call decides whether to close it. Admins only.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "banks"
+                    "admin/banks"
                 ],
-                "summary": "Get a list of available banks",
+                "summary": "Circuit breakers of bank APIs",
                 "responses": {
                     "200": {
-                        "description": "List of banks",
+                        "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/dto.BankResponse"
+                                "$ref": "#/definitions/dto.BankBreakerResponse"
                             }
                         }
                     },
-                    "403": {
-                        "description": "Access denied",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -312,14 +298,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/banks/{id}/authorize": {
-            "post": {
+        "/admin/banks/{id}": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Requests or refreshes access token for the bank and stores it in cache/DB.",
+                "description": "Replaces name, api_base_url, adapter and rate limit of the bank. The cached bank token is dropped.",
                 "consumes": [
                     "application/json"
                 ],
@@ -327,9 +313,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "banks"
+                    "admin/banks"
                 ],
-                "summary": "Authorize backend in a specific bank",
+                "summary": "Update bank",
                 "parameters": [
                     {
                         "type": "integer",
@@ -337,29 +323,44 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Bank settings",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.BankUpdateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.BankAuthorizeResponse"
+                            "$ref": "#/definitions/dto.BankAdminResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid id",
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "Access denied",
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "502": {
-                        "description": "Bank auth failed",
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -367,98 +368,115 @@ const docTemplate = `{
                 }
             }
         },
-        "/consents": {
-            "get": {
+        "/admin/banks/{id}/credentials": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns the user's consents. Can be filtered by bank.",
+                "description": "Replaces the team login and secret in the bank API and drops the cached bank token.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Consents"
+                    "admin/banks"
                 ],
-                "summary": "List my consents",
+                "summary": "Rotate bank credentials",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Filter by bank id",
-                        "name": "bank_id",
-                        "in": "query"
+                        "description": "Bank ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Credentials",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.BankCredentialsRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dto.ConsentResponse"
-                            }
+                            "$ref": "#/definitions/dto.BankAdminResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/consents/request": {
+        "/admin/banks/{id}/disable": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Creates a request for consent (account-consent) in the bank and saves the draft with us. If the bank auto—confirms, the consent_id will be returned immediately, otherwise the status will be Awaiting Authorization.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "A disabled bank is hidden from users and skipped by background jobs; its data is kept.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Consents"
+                    "admin/banks"
                 ],
-                "summary": "Request account consent",
+                "summary": "Disable bank",
                 "parameters": [
                     {
-                        "description": "Create consent payload",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.ConsentCreateRequest"
-                        }
+                        "type": "integer",
+                        "description": "Bank ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.ConsentResponse"
+                            "$ref": "#/definitions/dto.BankAdminResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -472,8 +490,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/consents/{id}": {
-            "get": {
+        "/admin/banks/{id}/enable": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -483,13 +501,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Consents"
+                    "admin/banks"
                 ],
-                "summary": "Get consent by id",
+                "summary": "Enable bank",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Consent ID (internal)",
+                        "description": "Bank ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -499,11 +517,17 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.ConsentResponse"
+                            "$ref": "#/definitions/dto.BankAdminResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -515,38 +539,53 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/admin/banks/{id}/token": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "description": "Requests a new bank token even if the cached one is still valid.",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Consents"
+                    "admin/banks"
                 ],
-                "summary": "Delete consent",
+                "summary": "Refresh bank token",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Consent ID (internal)",
+                        "description": "Bank ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BankAuthorizeResponse"
+                        }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -554,39 +593,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/consents/{id}/refresh": {
-            "post": {
+        "/admin/recommendation-rules": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Asks the bank by request_id/consent_id and updates our status (and consent_id, if available).",
+                "description": "Targeting rules of recommended products, the highest priority first.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Consents"
-                ],
-                "summary": "Refresh consent status",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Consent ID (internal)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "admin/products"
                 ],
+                "summary": "List recommendation rules",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.ConsentResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.RecommendationRuleResponse"
+                            }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -598,48 +631,50 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/me": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Возвращает профиль текущего аутентифицированного пользователя",
+                "description": "A product is recommended to the user if it is a target of an active rule and the user meets\nall conditions of the rule (age, total balance in RUB, connection of the product bank).",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "me"
+                    "admin/products"
                 ],
-                "summary": "Get current user",
+                "summary": "Create recommendation rule",
                 "parameters": [
                     {
-                        "type": "string",
-                        "default": "Bearer eyJhbGciOi...",
-                        "description": "Bearer {token}",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
+                        "description": "Rule",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RecommendationRuleRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/dto.UserResponse"
+                            "$ref": "#/definitions/dto.RecommendationRuleResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -653,14 +688,13 @@ const docTemplate = `{
                 }
             }
         },
-        "/products": {
-            "get": {
+        "/admin/recommendation-rules/{id}": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns a merged list of products from all enabled banks with optional filters.",
                 "consumes": [
                     "application/json"
                 ],
@@ -668,35 +702,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "admin/products"
                 ],
-                "summary": "Get aggregated products",
+                "summary": "Update recommendation rule",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Type: deposit|loan|card|account",
-                        "name": "product_type",
-                        "in": "query"
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "array",
-                        "items": {
-                            "type": "integer"
-                        },
-                        "collectionFormat": "csv",
-                        "description": "Repeatable bank id filter",
-                        "name": "bank_id",
-                        "in": "query"
+                        "description": "Rule",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RecommendationRuleRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dto.ProductResponse"
-                            }
+                            "$ref": "#/definitions/dto.RecommendationRuleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "403": {
@@ -705,6 +742,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -712,46 +755,29 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/users/{id}": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Доступ ограничён владельцем токена (запрещён доступ к чужим профилям).",
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "users"
+                    "admin/products"
                 ],
-                "summary": "Get user by ID",
+                "summary": "Delete recommendation rule",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "default": "Bearer eyJhbGciOi...",
-                        "description": "Bearer {token}",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
-                    },
                     {
                         "type": "integer",
-                        "description": "User ID",
+                        "description": "Rule ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/dto.UserResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -759,12 +785,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
-                        }
-                    },
                     "403": {
                         "description": "Forbidden",
                         "schema": {
@@ -785,29 +805,2454 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "domain.ConsentStatus": {
-            "type": "string",
-            "enum": [
-                "AwaitingAuthorization",
-                "Rejected",
-                "Authorized",
-                "Revoked"
-            ],
-            "x-enum-varnames": [
-                "AwaitingAuthorisation",
-                "Rejected",
-                "Authorised",
-                "Revoked"
-            ]
         },
-        "domain.Permission": {
-            "type": "string",
-            "enum": [
-                "ReadAccountsDetail",
-                "ReadBalances",
+        "/admin/recommended-products": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin/products"
+                ],
+                "summary": "List recommended product rules",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.RecommendedRule"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin/products"
+                ],
+                "summary": "Upsert recommended rule",
+                "parameters": [
+                    {
+                        "description": "Rule triplet",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RecommendedUpsertRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin/products"
+                ],
+                "summary": "Delete recommended rule",
+                "parameters": [
+                    {
+                        "description": "Rule triplet to delete",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RecommendedUpsertRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/recommended-products/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Impressions, clicks and CTR of recommended products per day (UTC), rule and bank.\nrule_id is empty for products of the static recommended list.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin/products"
+                ],
+                "summary": "Recommended products report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Date from (YYYY-MM-DD or RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date to (YYYY-MM-DD or RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.RecommendationStatResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Returns access_token using e-mail and password.\n\n**Request example**\n` + "`" + `` + "`" + `` + "`" + `json\n{\n\"email\": \"user@example.com\",\n\"password\": \"P@ssw0rd123\"\n}\n` + "`" + `` + "`" + `` + "`" + `\n\n**Response example**\n` + "`" + `` + "`" + `` + "`" + `json\n{\n\"access_token\": \"eyJhbGciOi...\",\n\"expires_in\": 3600\n}\n` + "`" + `` + "`" + `` + "`" + `",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Login",
+                "parameters": [
+                    {
+                        "description": "Login payload",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "User registration. Returns access_token (usable immediately).\n\n**Request example**\n` + "`" + `` + "`" + `` + "`" + `json\n{\n\"email\": \"user@example.com\",\n\"first_name\": \"Ivan\",\n\"last_name\": \"Petrov\",\n\"patronymic\": \"Ivanovich\",\n\"birthdate\": \"1990-01-15\",\n\"password\": \"P@ssw0rd123\"\n}\n` + "`" + `` + "`" + `` + "`" + `\n\n**Response example**\n` + "`" + `` + "`" + `` + "`" + `json\n{\n\"access_token\": \"eyJhbGciOi...\",\n\"expires_in\": 3600\n}\n` + "`" + `` + "`" + `` + "`" + `",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Register user",
+                "parameters": [
+                    {
+                        "description": "Register payload",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "email already used",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/banks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves all enabled banks and shows whether backend is authorized for each (token cached) and token expiry.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "banks"
+                ],
+                "summary": "Get a list of available banks",
+                "responses": {
+                    "200": {
+                        "description": "List of banks",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.BankResponse"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/banks/{id}/authorize": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requests or refreshes access token for the bank and stores it in cache/DB.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "banks"
+                ],
+                "summary": "Authorize backend in a specific bank",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bank ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BankAuthorizeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid id",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bank auth failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/consents": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the user's consents. Can be filtered by bank.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Consents"
+                ],
+                "summary": "List my consents",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Filter by bank id",
+                        "name": "bank_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ConsentResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/consents/request": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a request for consent (account-consent) in the bank and saves the draft with us. If the bank auto—confirms, the consent_id will be returned immediately, otherwise the status will be Awaiting Authorization.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Consents"
+                ],
+                "summary": "Request account consent",
+                "parameters": [
+                    {
+                        "description": "Create consent payload",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ConsentCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ConsentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/consents/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Consents"
+                ],
+                "summary": "Get consent by id",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Consent ID (internal)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ConsentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revokes the consent at the bank (DELETE /account-consents/{id}), then keeps it with status Revoked\nand revoked_at as a proof that access was withdrawn. If the bank call fails the consent is not changed.",
+                "tags": [
+                    "Consents"
+                ],
+                "summary": "Revoke consent",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Consent ID (internal)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/consents/{id}/refresh": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Asks the bank by request_id/consent_id and updates our status (and consent_id, if available).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Consents"
+                ],
+                "summary": "Refresh consent status",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Consent ID (internal)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ConsentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает профиль текущего аутентифицированного пользователя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "me"
+                ],
+                "summary": "Get current user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer eyJhbGciOi...",
+                        "description": "Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/agreements": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns products opened by the current user; agreements are refreshed from connected banks first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "me"
+                ],
+                "summary": "My product agreements",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Filter by bank id",
+                        "name": "bank_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.AgreementResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/agreements/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Closes the agreement in the bank; it is kept in the list with status closed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "me"
+                ],
+                "summary": "Close product agreement",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Agreement ID (internal)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AgreementResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/balances/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns balance series built from daily snapshots taken by the background account sync:\nper account (account currency), per bank and total (converted to base with the rate of each point).\nEvery point is the balance at the end of the period; the period starts at point date.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "me"
+                ],
+                "summary": "Balances over time",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "From date (YYYY-MM-DD), default to-29 days",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To date (YYYY-MM-DD), default today",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "day | week | month (default day)",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of bank and total series, default RUB",
+                        "name": "base",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BalanceHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/net-worth": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Converts the primary balance of every user's account into the base currency\nusing the Bank of Russia rates (latest rate on or before today).\nrates lists the rate and its date used for each currency, missing — currencies without a rate\n(such accounts are returned with included=false and are not counted in total).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "me"
+                ],
+                "summary": "Net worth in one currency",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "USD",
+                        "description": "Base currency (ISO code), default RUB",
+                        "name": "base",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.NetWorthResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/notifications": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the latest 100 notifications of the current user (e.g. rate changes of subscribed products).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "me"
+                ],
+                "summary": "My notifications",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only unread notifications",
+                        "name": "unread",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.NotificationResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/notifications/{id}/read": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "me"
+                ],
+                "summary": "Mark notification as read",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Notification ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/recommendations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Products matched by recommendation rules for the current user (by priority of the rule, with the reason),\nfollowed by products of the static recommended list.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "me"
+                ],
+                "summary": "Products recommended to me",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.RecommendationResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/subscriptions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "me"
+                ],
+                "summary": "My product subscriptions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ProductSubscriptionResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payments"
+                ],
+                "summary": "List my payments",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.PaymentResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Makes the payment described by the authorized payment consent (each consent can be used once).\nPoll GET /payments/{id} to follow the status. If the bank did not answer, the payment is sent again\nin the background with the same idempotency key. 503 means the bank was not called (it is failing\nand temporarily cut off): the consent stays free and the request can be repeated later.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payments"
+                ],
+                "summary": "Initiate payment",
+                "parameters": [
+                    {
+                        "description": "Payment payload",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PaymentCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PaymentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/consents": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payments"
+                ],
+                "summary": "List my payment consents",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.PaymentConsentResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requests a single-use payment consent in the bank of the user's account (account_id from /accounts).\nIf the bank auto-approves it, status is Authorized and the payment can be initiated right away,\notherwise poll GET /payments/consents/{id} until it is Authorized.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payments"
+                ],
+                "summary": "Create payment consent",
+                "parameters": [
+                    {
+                        "description": "Payment consent payload",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PaymentConsentCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PaymentConsentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/consents/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payments"
+                ],
+                "summary": "Get payment consent",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Payment consent ID (internal)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PaymentConsentResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payments"
+                ],
+                "summary": "Get payment status",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Payment ID (internal)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PaymentResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/products": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns a merged list of products of all enabled banks from the local catalogue (refreshed in background).\nfetched_at is the time of the last successful fetch from the bank, bank_health is \"down\" if the bank\nAPI failed on the last refresh (its products are then served from the previous fetch).\nWithout limit all matching products are returned. With limit the list is paginated by cursor:\nif there are more products, the X-Next-Cursor header and Link (rel=\"next\") point to the next page.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Get aggregated products",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Type: deposit|loan|card|account",
+                        "name": "product_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Repeatable bank id filter",
+                        "name": "bank_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum interest rate, % per year",
+                        "name": "min_rate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum term, months",
+                        "name": "term_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum term, months",
+                        "name": "term_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Sum that must fit into min/max amount of the product",
+                        "name": "amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Text search in name and description",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort: bank (default) | rate | term",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc | desc (default desc for rate, asc otherwise)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from X-Next-Cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ProductResponse"
+                            }
+                        },
+                        "headers": {
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor of the next page (absent on the last page)"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/compare": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns deposits of all enabled banks that accept the amount for the term (deposits without a fixed term\nor with exactly this term) with projected payouts: simple interest paid at the end of the term and\nmonthly capitalization. Offers are ranked by the capitalized payout, rank 1 is the best one.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Compare deposits",
+                "parameters": [
+                    {
+                        "description": "Amount, term and currency",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.DepositCompareRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.DepositCompareResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/compare-loans": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns loans and credit cards of all enabled banks that accept the amount for the term with the total\ncost for annuity and differentiated payments side by side. Ranked by the annuity overpayment, rank 1 is the cheapest.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Compare loans",
+                "parameters": [
+                    {
+                        "description": "Amount, term and currency",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.LoanCompareRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LoanCompareResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/sources": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the state of the product catalogue fetch for every enabled bank.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Product sources health",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ProductSourceResponse"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{bank}/{productId}/apply": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Opens the product (deposit, card, loan) for the current user through the product-agreements flow.\nThe bank must be connected (account consent with client_id). A product agreement consent is requested\non the first call; if the bank does not approve it automatically, 409 is returned until it is approved.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Open a bank product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bank code",
+                        "name": "bank",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Product ID in the bank",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Amount, term and source account",
+                        "name": "input",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AgreementApplyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AgreementResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{bank}/{productId}/click": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Records a click of the current user on the product for the recommended products report.\nClicks on products that are not recommended to the user are accepted but not recorded.",
+                "tags": [
+                    "products"
+                ],
+                "summary": "Track click on a recommended product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bank code",
+                        "name": "bank",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Product ID in the bank",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{bank}/{productId}/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns changes of interest rate, min/max amount and term of the product found between catalogue refreshes.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Product parameters history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bank code",
+                        "name": "bank",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Product ID in the bank",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProductHistoryResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{bank}/{productId}/schedule": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Calculates the monthly repayment schedule of a loan or a credit card for the amount and term:\npayment, interest and principal parts of every month and the total overpayment.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Loan repayment schedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bank code",
+                        "name": "bank",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Product ID in the bank",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Loan amount",
+                        "name": "amount",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Term in months (the product term by default)",
+                        "name": "term",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "annuity (default) | differentiated",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PaymentScheduleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{bank}/{productId}/subscription": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The user gets a notification (GET /me/notifications) when the interest rate of the product changes.",
+                "tags": [
+                    "products"
+                ],
+                "summary": "Subscribe to product rate changes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bank code",
+                        "name": "bank",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Product ID in the bank",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Unsubscribe from product rate changes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bank code",
+                        "name": "bank",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Product ID in the bank",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transfers": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transfers"
+                ],
+                "summary": "List my transfers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.TransferResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Moves money between two accounts of the user (from /accounts), also in different banks.\nThe source account must have the same currency as the target and enough balance.\nA payment consent and a payment are made at the source bank; follow the status with GET /transfers/{id}:\nAwaitingAuthorization -\u003e Initiating -\u003e Processing -\u003e Completed | Failed. Completed (completed_at) is reported\nby the source bank: the payment is settled there, the target bank may show the money a bit later.\nA transfer stays Initiating while the source bank does not answer the payment, it is retried in the background.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transfers"
+                ],
+                "summary": "Transfer between own accounts",
+                "parameters": [
+                    {
+                        "description": "Transfer payload",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TransferCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TransferResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transfers/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transfers"
+                ],
+                "summary": "Get transfer status",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transfer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TransferResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Доступ ограничён владельцем токена (запрещён доступ к чужим профилям).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer eyJhbGciOi...",
+                        "description": "Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "domain.AgreementStatus": {
+            "type": "string",
+            "enum": [
+                "active",
+                "closed"
+            ],
+            "x-enum-varnames": [
+                "AgreementActive",
+                "AgreementClosed"
+            ]
+        },
+        "domain.BankConnection": {
+            "type": "string",
+            "enum": [
+                "",
+                "connected",
+                "not_connected"
+            ],
+            "x-enum-comments": {
+                "BankConnectionConnected": "the user already has an authorized consent in the bank",
+                "BankConnectionNotConnected": "cross-sell to users of other banks"
+            },
+            "x-enum-varnames": [
+                "BankConnectionAny",
+                "BankConnectionConnected",
+                "BankConnectionNotConnected"
+            ]
+        },
+        "domain.BankHealth": {
+            "type": "string",
+            "enum": [
+                "ok",
+                "down",
+                "unknown"
+            ],
+            "x-enum-comments": {
+                "BankHealthDown": "the last fetch failed",
+                "BankHealthOK": "the last fetch succeeded",
+                "BankHealthUnknown": "never fetched"
+            },
+            "x-enum-varnames": [
+                "BankHealthOK",
+                "BankHealthDown",
+                "BankHealthUnknown"
+            ]
+        },
+        "domain.BreakerState": {
+            "type": "string",
+            "enum": [
+                "closed",
+                "open",
+                "half_open"
+            ],
+            "x-enum-comments": {
+                "BreakerClosed": "requests go to the bank",
+                "BreakerHalfOpen": "one probe request decides whether to close the circuit",
+                "BreakerOpen": "requests fail fast until RetryAt"
+            },
+            "x-enum-varnames": [
+                "BreakerClosed",
+                "BreakerOpen",
+                "BreakerHalfOpen"
+            ]
+        },
+        "domain.ConsentStatus": {
+            "type": "string",
+            "enum": [
+                "AwaitingAuthorization",
+                "Rejected",
+                "Authorized",
+                "Revoked"
+            ],
+            "x-enum-varnames": [
+                "AwaitingAuthorisation",
+                "Rejected",
+                "Authorised",
+                "Revoked"
+            ]
+        },
+        "domain.NotificationKind": {
+            "type": "string",
+            "enum": [
+                "product_rate_changed"
+            ],
+            "x-enum-varnames": [
+                "NotificationProductRateChanged"
+            ]
+        },
+        "domain.PaymentStatus": {
+            "type": "string",
+            "enum": [
+                "Initiating",
+                "Pending",
+                "AcceptedSettlementInProcess",
+                "AcceptedSettlementCompleted",
+                "AcceptedCreditSettlementCompleted",
+                "Rejected"
+            ],
+            "x-enum-varnames": [
+                "PaymentInitiating",
+                "PaymentPending",
+                "PaymentAcceptedSettlementProcess",
+                "PaymentAcceptedSettlementComplete",
+                "PaymentAcceptedCreditComplete",
+                "PaymentRejected"
+            ]
+        },
+        "domain.Permission": {
+            "type": "string",
+            "enum": [
+                "ReadAccountsDetail",
+                "ReadBalances",
                 "ReadTransactionsDetail"
             ],
             "x-enum-varnames": [
@@ -816,210 +3261,1414 @@ const docTemplate = `{
                 "ReadTransactionsDetail"
             ]
         },
-        "dto.AccountResponse": {
+        "domain.ProductField": {
+            "type": "string",
+            "enum": [
+                "interest_rate",
+                "min_amount",
+                "max_amount",
+                "term_months"
+            ],
+            "x-enum-varnames": [
+                "FieldInterestRate",
+                "FieldMinAmount",
+                "FieldMaxAmount",
+                "FieldTermMonths"
+            ]
+        },
+        "domain.ScheduleType": {
+            "type": "string",
+            "enum": [
+                "annuity",
+                "differentiated"
+            ],
+            "x-enum-comments": {
+                "ScheduleAnnuity": "equal monthly payments",
+                "ScheduleDifferentiated": "equal principal parts, interest on the remaining debt"
+            },
+            "x-enum-varnames": [
+                "ScheduleAnnuity",
+                "ScheduleDifferentiated"
+            ]
+        },
+        "domain.TransferStatus": {
+            "type": "string",
+            "enum": [
+                "AwaitingAuthorization",
+                "Initiating",
+                "Processing",
+                "Completed",
+                "Failed"
+            ],
+            "x-enum-comments": {
+                "TransferAwaitingAuthorization": "payment consent is not approved yet",
+                "TransferCompleted": "the source bank settled the payment (the target account is not checked)",
+                "TransferInitiating": "payment is being initiated (retried until the bank answers)",
+                "TransferProcessing": "payment is initiated, money is on the way"
+            },
+            "x-enum-varnames": [
+                "TransferAwaitingAuthorization",
+                "TransferInitiating",
+                "TransferProcessing",
+                "TransferCompleted",
+                "TransferFailed"
+            ]
+        },
+        "dto.AccountResponse": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "account_sub_type": {
+                    "type": "string"
+                },
+                "amount": {
+                    "description": "primary balance (signed)",
+                    "type": "string"
+                },
+                "balance_type": {
+                    "description": "primary balance type, e.g. InterimAvailable",
+                    "type": "string"
+                },
+                "balances": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.BalanceResponse"
+                    }
+                },
+                "bank_code": {
+                    "type": "string"
+                },
+                "bank_id": {
+                    "type": "integer"
+                },
+                "client_id": {
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "primary balance currency",
+                    "type": "string"
+                },
+                "identification": {
+                    "description": "account number",
+                    "type": "string"
+                },
+                "last_synced_at": {
+                    "type": "string"
+                },
+                "nickname": {
+                    "type": "string"
+                },
+                "opening_date": {
+                    "type": "string"
+                },
+                "stale": {
+                    "type": "boolean"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AgreementApplyRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "deposits and loans",
+                    "type": "string",
+                    "example": "50000.00"
+                },
+                "source_account_id": {
+                    "description": "account to fund the deposit from",
+                    "type": "string",
+                    "example": "acc-1010"
+                },
+                "term_months": {
+                    "description": "deposits and loans",
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "dto.AgreementResponse": {
+            "type": "object",
+            "properties": {
+                "account_number": {
+                    "type": "string"
+                },
+                "agreement_id": {
+                    "type": "string"
+                },
+                "amount": {
+                    "type": "string"
+                },
+                "bank_code": {
+                    "type": "string"
+                },
+                "closed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "product_type": {
+                    "type": "string"
+                },
+                "source_account_id": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.AgreementStatus"
+                        }
+                    ],
+                    "example": "active"
+                },
+                "term_months": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.BalanceHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "accounts": {
+                    "description": "in the account currency",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.BalanceSeriesResponse"
+                    }
+                },
+                "banks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.BalanceSeriesResponse"
+                    }
+                },
+                "base_currency": {
+                    "description": "currency of banks and total",
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2025-09-18"
+                },
+                "interval": {
+                    "type": "string",
+                    "example": "day"
+                },
+                "missing": {
+                    "description": "currencies without a rate (not in banks and total)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-10-17"
+                },
+                "total": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.BalancePointResponse"
+                    }
+                }
+            }
+        },
+        "dto.BalancePointResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "balance at the end of the period",
+                    "type": "string",
+                    "example": "15000.00"
+                },
+                "date": {
+                    "description": "start of the period",
+                    "type": "string",
+                    "example": "2025-10-13"
+                }
+            }
+        },
+        "dto.BalanceResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "negative for Debit",
+                    "type": "string",
+                    "example": "-1500.00"
+                },
+                "credit_debit_indicator": {
+                    "type": "string",
+                    "example": "Debit"
+                },
+                "credit_lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CreditLineResponse"
+                    }
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "date_time": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "InterimAvailable"
+                }
+            }
+        },
+        "dto.BalanceSeriesResponse": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "bank_code": {
+                    "type": "string"
+                },
+                "bank_id": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.BalancePointResponse"
+                    }
+                }
+            }
+        },
+        "dto.BankAdminResponse": {
+            "type": "object",
+            "properties": {
+                "adapter": {
+                    "type": "string",
+                    "example": "sandbox"
+                },
+                "api_base_url": {
+                    "type": "string"
+                },
+                "authorized": {
+                    "type": "boolean"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_enabled": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rate_burst": {
+                    "type": "integer"
+                },
+                "rate_limit": {
+                    "type": "number"
+                },
+                "token_expires": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.BankAuthorizeResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "authorized"
+                },
+                "token_expires": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.BankBreakerResponse": {
+            "type": "object",
+            "properties": {
+                "bank_code": {
+                    "type": "string"
+                },
+                "failures": {
+                    "description": "consecutive failed calls",
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "opened_at": {
+                    "type": "string"
+                },
+                "retry_at": {
+                    "description": "next probe call (open state)",
+                    "type": "string"
+                },
+                "state": {
+                    "description": "closed | open | half_open",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.BreakerState"
+                        }
+                    ],
+                    "example": "closed"
+                }
+            }
+        },
+        "dto.BankCreateRequest": {
+            "type": "object",
+            "properties": {
+                "adapter": {
+                    "description": "sandbox if empty",
+                    "type": "string",
+                    "example": "sandbox"
+                },
+                "api_base_url": {
+                    "type": "string",
+                    "example": "https://vbank.open.bankingapi.ru"
+                },
+                "code": {
+                    "type": "string",
+                    "example": "vbank"
+                },
+                "is_enabled": {
+                    "description": "true if null",
+                    "type": "boolean"
+                },
+                "login": {
+                    "type": "string",
+                    "example": "team014"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Velocity Bank"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "rate_burst": {
+                    "description": "10 if null",
+                    "type": "integer",
+                    "example": 10
+                },
+                "rate_limit": {
+                    "description": "requests per second, 0 = unlimited, 5 if null",
+                    "type": "number",
+                    "example": 5
+                }
+            }
+        },
+        "dto.BankCredentialsRequest": {
+            "type": "object",
+            "properties": {
+                "login": {
+                    "type": "string",
+                    "example": "team014"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.BankResponse": {
+            "type": "object",
+            "properties": {
+                "api_base_url": {
+                    "type": "string"
+                },
+                "authorized": {
+                    "description": "from domain.BankToken",
+                    "type": "boolean"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_enabled": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "token_expires": {
+                    "description": "from domain.BankToken",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.BankUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "adapter": {
+                    "description": "sandbox if empty",
+                    "type": "string",
+                    "example": "sandbox"
+                },
+                "api_base_url": {
+                    "type": "string",
+                    "example": "https://vbank.open.bankingapi.ru"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Velocity Bank"
+                },
+                "rate_burst": {
+                    "description": "10 if null",
+                    "type": "integer",
+                    "example": 10
+                },
+                "rate_limit": {
+                    "description": "requests per second, 0 = unlimited, 5 if null",
+                    "type": "number",
+                    "example": 5
+                }
+            }
+        },
+        "dto.ConsentCreateRequest": {
+            "type": "object",
+            "properties": {
+                "bank_code": {
+                    "description": "instead of bank_id",
+                    "type": "string"
+                },
+                "client_id": {
+                    "description": "e.g. team014-1",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ConsentResponse": {
+            "type": "object",
+            "properties": {
+                "auto_approved": {
+                    "type": "boolean"
+                },
+                "bank_code": {
+                    "type": "string"
+                },
+                "client_id": {
+                    "type": "string"
+                },
+                "consent_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "creation_datetime": {
+                    "type": "string"
+                },
+                "expiration_datetime": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Permission"
+                    }
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "requesting_bank": {
+                    "type": "string"
+                },
+                "requesting_bank_name": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/domain.ConsentStatus"
+                },
+                "status_update_datetime": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreditLineResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "included": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "Credit"
+                }
+            }
+        },
+        "dto.DepositCompareRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 100000
+                },
+                "currency": {
+                    "description": "RUB if empty",
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "term_months": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "dto.DepositCompareResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "offers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.DepositOfferResponse"
+                    }
+                },
+                "term_months": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.DepositOfferResponse": {
+            "type": "object",
+            "properties": {
+                "capitalized_interest": {
+                    "description": "interest capitalized monthly",
+                    "type": "number",
+                    "example": 9380.69
+                },
+                "capitalized_payout": {
+                    "type": "number",
+                    "example": 109380.69
+                },
+                "product": {
+                    "$ref": "#/definitions/dto.ProductResponse"
+                },
+                "rank": {
+                    "description": "1 = the best payout",
+                    "type": "integer",
+                    "example": 1
+                },
+                "simple_interest": {
+                    "description": "interest paid at the end of the term",
+                    "type": "number",
+                    "example": 9000
+                },
+                "simple_payout": {
+                    "type": "number",
+                    "example": 109000
+                }
+            }
+        },
+        "dto.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "sth went wrong"
+                }
+            }
+        },
+        "dto.LoanCompareRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 300000
+                },
+                "currency": {
+                    "description": "RUB if empty",
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "term_months": {
+                    "type": "integer",
+                    "example": 24
+                }
+            }
+        },
+        "dto.LoanCompareResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "offers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.LoanOfferResponse"
+                    }
+                },
+                "term_months": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.LoanOfferResponse": {
+            "type": "object",
+            "properties": {
+                "annuity": {
+                    "$ref": "#/definitions/dto.ScheduleSummaryResponse"
+                },
+                "differentiated": {
+                    "$ref": "#/definitions/dto.ScheduleSummaryResponse"
+                },
+                "product": {
+                    "$ref": "#/definitions/dto.ProductResponse"
+                },
+                "rank": {
+                    "description": "1 = the cheapest",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "dto.LoginRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "P@ssw0rd123"
+                }
+            }
+        },
+        "dto.NetWorthAccountResponse": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "amount": {
+                    "description": "in the account currency",
+                    "type": "string",
+                    "example": "1000.00"
+                },
+                "balance_type": {
+                    "type": "string",
+                    "example": "InterimAvailable"
+                },
+                "bank_code": {
+                    "type": "string"
+                },
+                "converted": {
+                    "description": "in base_currency",
+                    "type": "string",
+                    "example": "81234.50"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "included": {
+                    "type": "boolean"
+                },
+                "nickname": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.NetWorthRateResponse": {
             "type": "object",
             "properties": {
-                "account_id": {
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2025-10-17"
+                },
+                "rate": {
+                    "description": "base_currency for 1 unit of currency",
+                    "type": "number",
+                    "example": 81.2345
+                }
+            }
+        },
+        "dto.NetWorthResponse": {
+            "type": "object",
+            "properties": {
+                "accounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.NetWorthAccountResponse"
+                    }
+                },
+                "base_currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "missing": {
+                    "description": "currencies without a known rate (not in total)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "rates": {
+                    "description": "rate and date used for each currency",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.NetWorthRateResponse"
+                    }
+                },
+                "total": {
+                    "description": "sum of included accounts in base_currency",
+                    "type": "string",
+                    "example": "152300.50"
+                }
+            }
+        },
+        "dto.NotificationResponse": {
+            "type": "object",
+            "properties": {
+                "bank_code": {
                     "type": "string"
                 },
-                "account_sub_type": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.NotificationKind"
+                        }
+                    ],
+                    "example": "product_rate_changed"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "product_id": {
                     "type": "string"
                 },
+                "read_at": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PaymentConsentCreateRequest": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "description": "debtor account of the user",
+                    "type": "string",
+                    "example": "acc-1010"
+                },
+                "amount": {
+                    "description": "\u003e 0",
+                    "type": "string",
+                    "example": "1500.00"
+                },
+                "bank_id": {
+                    "description": "optional",
+                    "type": "integer"
+                },
+                "creditor_account": {
+                    "description": "account number of the recipient",
+                    "type": "string",
+                    "example": "40817810"
+                },
+                "creditor_bank_code": {
+                    "description": "empty = the same bank",
+                    "type": "string",
+                    "example": "sbank"
+                },
+                "creditor_name": {
+                    "type": "string",
+                    "example": "Ivan Petrov"
+                },
+                "currency": {
+                    "description": "default: account currency",
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "reference": {
+                    "type": "string",
+                    "example": "Rent for October"
+                }
+            }
+        },
+        "dto.PaymentConsentResponse": {
+            "type": "object",
+            "properties": {
                 "amount": {
                     "type": "string"
                 },
+                "auto_approved": {
+                    "type": "boolean"
+                },
                 "bank_code": {
                     "type": "string"
                 },
                 "client_id": {
                     "type": "string"
                 },
+                "consent_id": {
+                    "type": "string"
+                },
+                "consent_type": {
+                    "type": "string",
+                    "example": "single_use"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "creation_datetime": {
+                    "type": "string"
+                },
+                "creditor_account": {
+                    "type": "string"
+                },
+                "creditor_bank_code": {
+                    "type": "string"
+                },
+                "creditor_name": {
+                    "type": "string"
+                },
                 "currency": {
                     "type": "string"
                 },
-                "nickname": {
+                "debtor_account": {
                     "type": "string"
                 },
-                "opening_date": {
+                "expiration_datetime": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "request_id": {
                     "type": "string"
                 },
                 "status": {
+                    "$ref": "#/definitions/domain.ConsentStatus"
+                },
+                "status_update_datetime": {
+                    "type": "string"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "dto.BankAuthorizeResponse": {
+        "dto.PaymentCreateRequest": {
             "type": "object",
             "properties": {
-                "status": {
-                    "type": "string",
-                    "example": "authorized"
-                },
-                "token_expires": {
+                "comment": {
                     "type": "string"
+                },
+                "payment_consent_id": {
+                    "description": "id from POST /payments/consents",
+                    "type": "integer",
+                    "example": 1
                 }
             }
         },
-        "dto.BankResponse": {
+        "dto.PaymentResponse": {
             "type": "object",
             "properties": {
-                "api_base_url": {
+                "amount": {
                     "type": "string"
                 },
-                "authorized": {
-                    "description": "from domain.BankToken",
-                    "type": "boolean"
+                "bank_code": {
+                    "type": "string"
                 },
-                "code": {
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "creation_datetime": {
+                    "type": "string"
+                },
+                "creditor_account": {
+                    "type": "string"
+                },
+                "creditor_bank_code": {
                     "type": "string"
                 },
+                "creditor_name": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "debtor_account": {
+                    "type": "string"
+                },
+                "final": {
+                    "description": "the status will not change anymore",
+                    "type": "boolean"
+                },
                 "id": {
                     "type": "integer"
                 },
-                "is_enabled": {
-                    "type": "boolean"
+                "payment_consent_id": {
+                    "type": "integer"
                 },
-                "name": {
+                "payment_id": {
                     "type": "string"
                 },
-                "token_expires": {
-                    "description": "from domain.BankToken",
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.PaymentStatus"
+                        }
+                    ],
+                    "example": "AcceptedSettlementInProcess"
+                },
+                "status_update_datetime": {
+                    "type": "string"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "dto.ConsentCreateRequest": {
+        "dto.PaymentScheduleResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "overpayment": {
+                    "description": "total interest",
+                    "type": "number"
+                },
+                "payments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.SchedulePaymentResponse"
+                    }
+                },
+                "product": {
+                    "$ref": "#/definitions/dto.ProductResponse"
+                },
+                "term_months": {
+                    "type": "integer"
+                },
+                "total_payment": {
+                    "type": "number"
+                },
+                "type": {
+                    "description": "annuity | differentiated",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.ScheduleType"
+                        }
+                    ],
+                    "example": "annuity"
+                }
+            }
+        },
+        "dto.ProductChangeResponse": {
+            "type": "object",
+            "properties": {
+                "changed_at": {
+                    "type": "string"
+                },
+                "field": {
+                    "description": "interest_rate | min_amount | max_amount | term_months",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.ProductField"
+                        }
+                    ],
+                    "example": "interest_rate"
+                },
+                "new_value": {
+                    "type": "number",
+                    "example": 9
+                },
+                "old_value": {
+                    "type": "number",
+                    "example": 8.5
+                }
+            }
+        },
+        "dto.ProductHistoryResponse": {
             "type": "object",
             "properties": {
                 "bank_code": {
-                    "description": "instead of bank_id",
                     "type": "string"
                 },
-                "client_id": {
-                    "description": "e.g. team014-1",
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ProductChangeResponse"
+                    }
+                },
+                "product_id": {
                     "type": "string"
                 }
             }
         },
-        "dto.ConsentResponse": {
+        "dto.ProductResponse": {
             "type": "object",
             "properties": {
-                "auto_approved": {
+                "bank_code": {
+                    "type": "string"
+                },
+                "bank_health": {
+                    "description": "ok | down | unknown",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.BankHealth"
+                        }
+                    ],
+                    "example": "ok"
+                },
+                "bank_id": {
+                    "type": "integer"
+                },
+                "bank_name": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "fetched_at": {
+                    "type": "string"
+                },
+                "interestRate": {
+                    "type": "number"
+                },
+                "is_recommended": {
                     "type": "boolean"
                 },
-                "bank_code": {
+                "maxAmount": {
+                    "type": "number"
+                },
+                "minAmount": {
+                    "type": "number"
+                },
+                "productId": {
                     "type": "string"
                 },
-                "client_id": {
+                "productName": {
                     "type": "string"
                 },
-                "consent_id": {
+                "productType": {
                     "type": "string"
                 },
-                "created_at": {
+                "recommendation_reason": {
+                    "description": "why the product is recommended to the user",
                     "type": "string"
                 },
-                "creation_datetime": {
+                "termMonths": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.ProductSourceResponse": {
+            "type": "object",
+            "properties": {
+                "bank_code": {
                     "type": "string"
                 },
-                "expiration_datetime": {
+                "bank_id": {
+                    "type": "integer"
+                },
+                "bank_name": {
                     "type": "string"
                 },
-                "id": {
+                "failures": {
+                    "description": "consecutive failed fetches",
                     "type": "integer"
                 },
-                "permissions": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/domain.Permission"
-                    }
+                "health": {
+                    "description": "ok | down | unknown",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.BankHealth"
+                        }
+                    ],
+                    "example": "ok"
                 },
-                "reason": {
+                "last_attempt_at": {
                     "type": "string"
                 },
-                "request_id": {
+                "last_error": {
                     "type": "string"
                 },
-                "requesting_bank": {
+                "last_success_at": {
                     "type": "string"
                 },
-                "requesting_bank_name": {
+                "products_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.ProductSubscriptionResponse": {
+            "type": "object",
+            "properties": {
+                "bank_code": {
                     "type": "string"
                 },
-                "status": {
-                    "$ref": "#/definitions/domain.ConsentStatus"
+                "created_at": {
+                    "type": "string"
                 },
-                "status_update_datetime": {
+                "product_id": {
                     "type": "string"
                 },
-                "updated_at": {
+                "product_name": {
+                    "description": "empty if the bank does not offer the product anymore",
                     "type": "string"
                 }
             }
         },
-        "dto.ErrorResponse": {
+        "dto.RecommendationResponse": {
             "type": "object",
             "properties": {
-                "error": {
-                    "type": "string",
-                    "example": "sth went wrong"
+                "product": {
+                    "$ref": "#/definitions/dto.ProductResponse"
+                },
+                "rank": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "reason": {
+                    "description": "empty for products of the static recommended list",
+                    "type": "string"
                 }
             }
         },
-        "dto.LoginRequest": {
+        "dto.RecommendationRuleRequest": {
             "type": "object",
             "properties": {
-                "email": {
+                "active_from": {
+                    "type": "string"
+                },
+                "active_to": {
+                    "type": "string"
+                },
+                "bank_code": {
+                    "description": "target, empty = any",
                     "type": "string",
-                    "example": "user@example.com"
+                    "example": "abank"
                 },
-                "password": {
+                "bank_connection": {
+                    "description": "connected | not_connected",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.BankConnection"
+                        }
+                    ],
+                    "example": "not_connected"
+                },
+                "max_age": {
+                    "type": "integer"
+                },
+                "max_balance": {
+                    "type": "number"
+                },
+                "min_age": {
+                    "description": "conditions, null = not checked",
+                    "type": "integer",
+                    "example": 60
+                },
+                "min_balance": {
+                    "description": "total balance in RUB",
+                    "type": "number",
+                    "example": 100000
+                },
+                "name": {
                     "type": "string",
-                    "example": "P@ssw0rd123"
+                    "example": "Deposits for pensioners"
+                },
+                "priority": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "product_type": {
+                    "type": "string",
+                    "example": "deposit"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Higher rate for clients over 60"
                 }
             }
         },
-        "dto.ProductResponse": {
+        "dto.RecommendationRuleResponse": {
             "type": "object",
             "properties": {
-                "bank_code": {
+                "active_from": {
                     "type": "string"
                 },
-                "bank_id": {
-                    "type": "integer"
-                },
-                "bank_name": {
+                "active_to": {
                     "type": "string"
                 },
-                "description": {
-                    "type": "string"
+                "bank_code": {
+                    "description": "target, empty = any",
+                    "type": "string",
+                    "example": "abank"
                 },
-                "fetched_at": {
+                "bank_connection": {
+                    "description": "connected | not_connected",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.BankConnection"
+                        }
+                    ],
+                    "example": "not_connected"
+                },
+                "created_at": {
                     "type": "string"
                 },
-                "interestRate": {
-                    "type": "number"
+                "id": {
+                    "type": "integer"
                 },
-                "is_recommended": {
-                    "type": "boolean"
+                "max_age": {
+                    "type": "integer"
                 },
-                "maxAmount": {
+                "max_balance": {
                     "type": "number"
                 },
-                "minAmount": {
-                    "type": "number"
+                "min_age": {
+                    "description": "conditions, null = not checked",
+                    "type": "integer",
+                    "example": 60
                 },
-                "productId": {
-                    "type": "string"
+                "min_balance": {
+                    "description": "total balance in RUB",
+                    "type": "number",
+                    "example": 100000
                 },
-                "productName": {
+                "name": {
+                    "type": "string",
+                    "example": "Deposits for pensioners"
+                },
+                "priority": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "product_id": {
                     "type": "string"
                 },
-                "productType": {
+                "product_type": {
+                    "type": "string",
+                    "example": "deposit"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Higher rate for clients over 60"
+                },
+                "updated_at": {
                     "type": "string"
+                }
+            }
+        },
+        "dto.RecommendationStatResponse": {
+            "type": "object",
+            "properties": {
+                "bank_code": {
+                    "type": "string",
+                    "example": "abank"
                 },
-                "termMonths": {
+                "clicks": {
+                    "type": "integer"
+                },
+                "ctr": {
+                    "description": "clicks / impressions",
+                    "type": "number",
+                    "example": 0.042
+                },
+                "day": {
+                    "type": "string",
+                    "example": "2025-11-01"
+                },
+                "impressions": {
+                    "type": "integer"
+                },
+                "rule_id": {
+                    "description": "empty for the static recommended list",
                     "type": "integer"
+                },
+                "rule_name": {
+                    "type": "string"
                 }
             }
         },
@@ -1087,6 +4736,49 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.SchedulePaymentResponse": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "description": "debt left after the payment",
+                    "type": "number",
+                    "example": 92224.17
+                },
+                "interest": {
+                    "type": "number",
+                    "example": 1250
+                },
+                "month": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "payment": {
+                    "type": "number",
+                    "example": 9025.83
+                },
+                "principal": {
+                    "type": "number",
+                    "example": 7775.83
+                }
+            }
+        },
+        "dto.ScheduleSummaryResponse": {
+            "type": "object",
+            "properties": {
+                "first_payment": {
+                    "type": "number"
+                },
+                "last_payment": {
+                    "type": "number"
+                },
+                "overpayment": {
+                    "type": "number"
+                },
+                "total_payment": {
+                    "type": "number"
+                }
+            }
+        },
         "dto.TokenResponse": {
             "type": "object",
             "properties": {
@@ -1100,6 +4792,153 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.TransactionResponse": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "amount": {
+                    "type": "string"
+                },
+                "bank_code": {
+                    "type": "string"
+                },
+                "bank_transaction_code": {
+                    "type": "string",
+                    "example": "ReceivedCreditTransfer"
+                },
+                "booking_date_time": {
+                    "type": "string"
+                },
+                "credit_debit_indicator": {
+                    "type": "string",
+                    "example": "Credit"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "info": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "Booked"
+                },
+                "transaction_id": {
+                    "type": "string"
+                },
+                "value_date_time": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.TransactionsPageResponse": {
+            "type": "object",
+            "properties": {
+                "last_synced_at": {
+                    "type": "string"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                },
+                "transactions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TransactionResponse"
+                    }
+                }
+            }
+        },
+        "dto.TransferAccountRef": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string",
+                    "example": "acc-1010"
+                },
+                "bank_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.TransferCreateRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "1500.00"
+                },
+                "source": {
+                    "$ref": "#/definitions/dto.TransferAccountRef"
+                },
+                "target": {
+                    "$ref": "#/definitions/dto.TransferAccountRef"
+                }
+            }
+        },
+        "dto.TransferResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string"
+                },
+                "completed_at": {
+                    "description": "when the source bank settled the payment",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "payment_consent_id": {
+                    "type": "integer"
+                },
+                "payment_id": {
+                    "type": "integer"
+                },
+                "source_account_id": {
+                    "type": "string"
+                },
+                "source_bank_code": {
+                    "type": "string"
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.TransferStatus"
+                        }
+                    ],
+                    "example": "Processing"
+                },
+                "target_account_id": {
+                    "type": "string"
+                },
+                "target_bank_code": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
         "dto.UserResponse": {
             "type": "object",
             "properties": {
@@ -9,10 +9,18 @@ import (
 	"log/slog"
 	"multibank/backend/internal/domain"
 	"multibank/backend/internal/logger"
+	"multibank/backend/internal/secret"
 	"multibank/backend/internal/service/account"
+	"multibank/backend/internal/service/agreement"
+	"multibank/backend/internal/service/balance"
 	"multibank/backend/internal/service/bank"
 	"multibank/backend/internal/service/consent"
+	"multibank/backend/internal/service/fx"
+	"multibank/backend/internal/service/notification"
+	"multibank/backend/internal/service/payment"
 	"multibank/backend/internal/service/product"
+	"multibank/backend/internal/service/recommendation"
+	"multibank/backend/internal/service/transfer"
 	"net/http"
 	"strconv"
 	"time"
@@ -32,6 +40,7 @@ type App struct {
 	log     *slog.Logger
 	cfg     *config.Config
 	httpSrv *http.Server
+	server  *httpserver.Server
 	storage *sqlite.Storage
 }
 
@@ -48,27 +57,58 @@ func New(log *slog.Logger, cfg *config.Config) (*App, error) {
 		return nil, fmt.Errorf("sqlite migrate: %w", err)
 	}
 
+	// bank secrets are encrypted at rest, plaintext values of older versions are encrypted on start
+	box, err := secret.NewBox(cfg.Secrets.MasterKey, cfg.Secrets.OldMasterKeys...)
+	if err != nil {
+		_ = st.Close()
+		return nil, fmt.Errorf("master key: %w", err)
+	}
+	bankRepo := sqlite.NewBankRepo(st.DB(), box)
+	if n, err := bankRepo.EncryptSecrets(context.Background()); err != nil {
+		_ = st.Close()
+		return nil, fmt.Errorf("encrypt bank secrets: %w", err)
+	} else if n > 0 {
+		log.Info("bank secrets encrypted by the current master key", slog.Int("values", n), slog.String("key_id", box.KeyID()))
+	}
+
 	// --- repo + services ---
 	userRepo := sqlite.NewUserRepo(st.DB())
 	userSvc := user.New(log, userRepo)
 
-	bankRepo := sqlite.NewBankRepo(st.DB())
-	bankSvc := bank.New(log, bankRepo)
+	// shared transport of bank API calls: retries with backoff and a circuit breaker per bank
+	obTransport := openbanking.NewTransport(log, http.DefaultTransport, openbanking.DefaultTransportOptions())
+	obHTTP := &http.Client{Timeout: 10 * time.Second, Transport: obTransport}
+
+	// bank API dialects by banks.adapter, the sandbox one by default
+	obAdapters := openbanking.NewAdapters(openbanking.NewSandboxAdapter(
+		log,
+		obHTTP,
+		cfg.Requester.Code, // X-Requesting-Bank
+		cfg.Requester.Name, // RequestingBankName
+		cfg.Requester.Reason,
+	))
+
+	bankSvc := bank.New(log, bankRepo, obAdapters)
+
+	// banks declared in the config (secrets from env or files) are created or updated in the DB
+	bankDecls, err := cfg.BankDeclarations()
+	if err != nil {
+		_ = st.Close()
+		return nil, fmt.Errorf("config banks: %w", err)
+	}
+	if err := bankSvc.Reconcile(context.Background(), bankDecls); err != nil {
+		_ = st.Close()
+		return nil, fmt.Errorf("reconcile banks: %w", err)
+	}
 
 	productRecRepo := sqlite.NewRecommendedProductsRepo(st.DB())
-	productClient := openbanking.NewProductClient(log, &http.Client{Timeout: 10 * time.Second})
-	prodSvc := product.New(log, bankRepo, bankSvc, productRecRepo, productClient)
+	notificationRepo := sqlite.NewNotificationRepo(st.DB())
+	notificationSvc := notification.New(log, notificationRepo)
 
-	recommendedSvc := product.NewRecommendedService(productRecRepo)
+	recommendationEventRepo := sqlite.NewRecommendationEventRepo(st.DB())
+	recommendedSvc := product.NewRecommendedService(productRecRepo, recommendationEventRepo)
 
 	consentRepo := sqlite.NewConsentRepo(st.DB())
-	consentClient := openbanking.NewConsentClient(
-		log,
-		&http.Client{Timeout: 10 * time.Second},
-		"team014",           // X-Requesting-Bank (можно захардкодить)
-		"Team 14 Multibank", // RequestingBankName
-		"Агрегация счетов для HackAPI", // Reason
-	)
 
 	// using default permissions (all)
 	defaultPerms := []domain.Permission{
@@ -81,15 +121,49 @@ func New(log *slog.Logger, cfg *config.Config) (*App, error) {
 		log,
 		consentRepo,
 		bankSvc, // для получения bank и access_token
-		consentClient,
+		obAdapters,
 		defaultPerms,
-		"team014",
-		"Team 14 Multibank",
-		"Агрегация счетов для HackAPI",
+		cfg.Requester.Code,
+		cfg.Requester.Name,
+		cfg.Requester.Reason,
 	)
 
-	accountClient := openbanking.NewAccountClient(log, &http.Client{Timeout: 10 * time.Second})
-	accountSvc := account.New(log, consentRepo, bankSvc, accountClient)
+	transactionRepo := sqlite.NewTransactionRepo(st.DB())
+	accountRepo := sqlite.NewAccountRepo(st.DB())
+	snapshotRepo := sqlite.NewBalanceSnapshotRepo(st.DB())
+	accountSvc := account.New(
+		log,
+		consentRepo,
+		bankSvc,
+		obAdapters,
+		transactionRepo,
+		accountRepo,
+		snapshotRepo,
+		15*time.Minute, // accounts not refreshed for 15 min are stale (3 sync intervals)
+	)
+
+	fxRepo := sqlite.NewFXRepo(st.DB())
+	fxSvc := fx.New(log, fxRepo, accountSvc, &http.Client{Timeout: 20 * time.Second}, cfg.FX.Source)
+
+	balanceSvc := balance.New(log, snapshotRepo, fxSvc)
+
+	ruleRepo := sqlite.NewRecommendationRuleRepo(st.DB())
+	recommendationSvc := recommendation.New(log, ruleRepo, userRepo, consentRepo, fxSvc)
+
+	productRepo := sqlite.NewProductRepo(st.DB())
+	prodSvc := product.New(log, bankRepo, bankSvc, productRecRepo, productRepo, notificationSvc, recommendationSvc,
+		recommendationEventRepo, obAdapters)
+
+	paymentRepo := sqlite.NewPaymentRepo(st.DB())
+	paymentClient := openbanking.NewPaymentClient(log, obHTTP, cfg.Requester.Code)
+	paymentSvc := payment.New(log, paymentRepo, bankSvc, accountSvc, paymentClient, cfg.Requester.Code)
+
+	transferRepo := sqlite.NewTransferRepo(st.DB())
+	transferSvc := transfer.New(log, transferRepo, accountSvc, paymentSvc)
+
+	agreementRepo := sqlite.NewAgreementRepo(st.DB())
+	agreementClient := openbanking.NewProductAgreementClient(log, obHTTP, cfg.Requester.Code)
+	agreementSvc := agreement.New(log, agreementRepo, consentRepo, bankSvc, agreementClient, cfg.Requester.Code)
 
 	jwtMgr := jwt.New(cfg.HTTPServer.JWTSecret, cfg.HTTPServer.TokenTTL)
 	authSvc := auth.New(log, userSvc, jwtMgr)
@@ -97,15 +171,24 @@ func New(log *slog.Logger, cfg *config.Config) (*App, error) {
 	// --- chi mux via httpserver.New ---
 	srv := httpserver.New(
 		httpserver.Deps{
-			Logger:             log,
-			UserService:        userSvc, // implements handlers.User
-			AuthService:        authSvc, // implements handlers.Auth
-			BankService:        bankSvc, // implements handlers.Bank
-			ProductService:     prodSvc, // implements handlers.Product
-			RecommendedService: recommendedSvc,
-			ConsentService:     consentSvc, // implements handlers.Consent
-			AccountService:     accountSvc, // implements handlers.Account
-			JWT:                jwtMgr,
+			Logger:              log,
+			UserService:         userSvc,     // implements handlers.User
+			AuthService:         authSvc,     // implements handlers.Auth
+			BankService:         bankSvc,     // implements handlers.Bank
+			BankAdmin:           bankSvc,     // implements handlers.BankAdmin
+			BankBreakers:        obTransport, // implements handlers.BankBreakers
+			ProductService:      prodSvc,     // implements handlers.Product
+			RecommendedService:  recommendedSvc,
+			ConsentService:      consentSvc,        // implements handlers.Consent
+			AccountService:      accountSvc,        // implements handlers.Account
+			FXService:           fxSvc,             // implements handlers.FX
+			BalanceService:      balanceSvc,        // implements handlers.BalanceHistory
+			PaymentService:      paymentSvc,        // implements handlers.Payment
+			TransferService:     transferSvc,       // implements handlers.Transfer
+			AgreementService:    agreementSvc,      // implements handlers.Agreement
+			NotificationService: notificationSvc,   // implements handlers.Notification
+			RecommendationRules: recommendationSvc, // implements handlers.RecommendationRules
+			JWT:                 jwtMgr,
 		},
 		httpserver.Options{
 			RequestTimeout: cfg.HTTPServer.Timeout,
@@ -117,6 +200,25 @@ func New(log *slog.Logger, cfg *config.Config) (*App, error) {
 			ConsentEnsureOnStart:  true,
 			ConsentEnsureInterval: 5 * time.Minute,
 			ConsentEnsureWorkers:  4,
+
+			AccountSyncOnStart:  true,
+			AccountSyncInterval: 5 * time.Minute,
+			AccountSyncWorkers:  4,
+
+			TransactionSyncOnStart:  true,
+			TransactionSyncInterval: 15 * time.Minute,
+			TransactionSyncWorkers:  2,
+
+			FXImportOnStart:  true,
+			FXImportInterval: cfg.FX.ImportInterval,
+
+			TransferPollInterval: 30 * time.Second,
+
+			PaymentResumeInterval: time.Minute,
+
+			ProductRefreshOnStart:  true,
+			ProductRefreshInterval: 30 * time.Minute,
+			ProductRefreshWorkers:  4,
 		},
 	)
 
@@ -132,6 +234,7 @@ func New(log *slog.Logger, cfg *config.Config) (*App, error) {
 		log:     log,
 		cfg:     cfg,
 		httpSrv: httpSrv,
+		server:  srv,
 		storage: st,
 	}, nil
 }
@@ -160,6 +263,9 @@ func (a *App) MustRun() {
 
 // Stop — graceful shutdown + resources closing
 func (a *App) Stop(ctx context.Context) error {
+	a.log.Info("stopping background jobs...")
+	a.server.Stop() // cancels bank calls of background jobs
+
 	a.log.Info("shutting down http server...")
 	if err := a.httpSrv.Shutdown(ctx); err != nil {
 		a.log.Error("http shutdown error", logger.Err(err))
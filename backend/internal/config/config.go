@@ -4,8 +4,11 @@ package config
 
 import (
 	"flag"
+	"fmt"
 	"log/slog"
+	"multibank/backend/internal/domain"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
@@ -16,6 +19,33 @@ type Config struct {
 	StoragePath string `yaml:"storage_path" env:"MB_STORAGE_PATH" env-required:"true"`
 	Logger      `yaml:"logger"`
 	HTTPServer  `yaml:"http_server"`
+	FX          `yaml:"fx"`
+	Secrets     `yaml:"secrets"`
+	Requester   Requester `yaml:"requester"`
+	Banks       []Bank    `yaml:"banks"` // reconciled into the banks table on start
+}
+
+// Requester — the team as the requesting bank of consents, payments and product agreements
+type Requester struct {
+	Code   string `yaml:"code" env:"MB_REQUESTER_CODE" env-default:"team014"`                          // X-Requesting-Bank
+	Name   string `yaml:"name" env:"MB_REQUESTER_NAME" env-default:"Team 14 Multibank"`                // requesting_bank_name
+	Reason string `yaml:"reason" env:"MB_REQUESTER_REASON" env-default:"Агрегация счетов для HackAPI"` // reason of account consents
+}
+
+// Bank — bank API the team is registered in. The secret is taken from the first source set:
+// client_secret_file, client_secret_env, client_secret.
+type Bank struct {
+	Code             string   `yaml:"code"`
+	Name             string   `yaml:"name"`
+	APIBaseURL       string   `yaml:"api_base_url"`
+	ClientID         string   `yaml:"client_id"`
+	ClientSecret     string   `yaml:"client_secret"`      // inline, for development only
+	ClientSecretEnv  string   `yaml:"client_secret_env"`  // name of the env var with the secret
+	ClientSecretFile string   `yaml:"client_secret_file"` // file with the secret (Docker/Kubernetes secrets)
+	Adapter          string   `yaml:"adapter"`            // sandbox if empty
+	RateLimit        *float64 `yaml:"rate_limit"`
+	RateBurst        *int     `yaml:"rate_burst"`
+	Enabled          *bool    `yaml:"enabled"`
 }
 
 type HTTPServer struct {
@@ -25,6 +55,62 @@ type HTTPServer struct {
 	JWTSecret string        `yaml:"jwt_secret" env:"MB_AUTH_SECRET" env-required:"true"`
 }
 
+type FX struct {
+	Source         string        `yaml:"source" env:"MB_FX_SOURCE" env-default:"https://www.cbr.ru/scripts/XML_daily.asp"` // URL or local file
+	ImportInterval time.Duration `yaml:"import_interval" env:"MB_FX_IMPORT_INTERVAL" env-default:"6h"`                     // 0 = import only on start
+}
+
+// Secrets — master keys of the envelope encryption of bank secrets in the DB (base64 of 32 bytes)
+type Secrets struct {
+	MasterKey     string   `yaml:"master_key" env:"MB_MASTER_KEY" env-required:"true"`
+	OldMasterKeys []string `yaml:"old_master_keys" env:"MB_OLD_MASTER_KEYS" env-separator:","` // readable until rotation is done
+}
+
+// BankDeclarations returns the banks of the config with resolved secrets
+func (c *Config) BankDeclarations() ([]domain.BankDeclaration, error) {
+	out := make([]domain.BankDeclaration, 0, len(c.Banks))
+	for _, b := range c.Banks {
+		secret, err := b.secret()
+		if err != nil {
+			return nil, fmt.Errorf("bank %s: %w", b.Code, err)
+		}
+		out = append(out, domain.BankDeclaration{
+			Code:       b.Code,
+			Name:       b.Name,
+			APIBaseURL: b.APIBaseURL,
+			Login:      b.ClientID,
+			Password:   secret,
+			Adapter:    b.Adapter,
+			RateLimit:  b.RateLimit,
+			RateBurst:  b.RateBurst,
+			Enabled:    b.Enabled,
+		})
+	}
+	return out, nil
+}
+
+// secret returns nil if no source is set or the env var is empty
+func (b Bank) secret() (*string, error) {
+	switch {
+	case b.ClientSecretFile != "":
+		data, err := os.ReadFile(b.ClientSecretFile)
+		if err != nil {
+			return nil, fmt.Errorf("read client_secret_file: %w", err)
+		}
+		s := strings.TrimRight(string(data), "\r\n")
+		return &s, nil
+	case b.ClientSecretEnv != "":
+		if s := os.Getenv(b.ClientSecretEnv); s != "" {
+			return &s, nil
+		}
+		return nil, nil
+	case b.ClientSecret != "":
+		s := b.ClientSecret
+		return &s, nil
+	}
+	return nil, nil
+}
+
 type Logger struct {
 	LevelString string     `yaml:"level" env:"MB_LOG_LEVEL" env-default:"info"`
 	Level       slog.Level `yaml:"-"` // will be loaded later
@@ -1,15 +1,120 @@
 package domain
 
-// AccountShort — то, что нам нужно для UI (без хранения в БД)
+import "time"
+
+// AccountShort — то, что нам нужно для UI (собирается из bank_accounts + balances)
 type AccountShort struct {
 	AccountID      string // /accounts.data.account[*].accountId
 	Nickname       string // /accounts ... nickname
 	Status         string // Enabled/Disabled (как в API)
 	AccountSubType string // /accounts ... accountSubType
 	OpeningDate    string // YYYY-MM-DD (как в API)
-	Amount         string // из /accounts/{id}/balances -> InterimAvailable.amount.amount
-	Currency       string // из /accounts/{id}/balances -> InterimAvailable.amount.currency
+	Identification string // account number (/accounts ... account[0].identification)
+	Amount         string // primary balance amount (signed), see PrimaryBalance
+	Currency       string // primary balance currency
+	BalanceType    string // type of the primary balance, e.g. InterimAvailable
+	Balances       []Balance
 
+	BankID   int64
 	BankCode string
 	ClientID string
+
+	LastSyncedAt *time.Time // when the account was refreshed from the bank last time
+	Stale        bool       // LastSyncedAt is too old (bank is down or slow)
+}
+
+// Number is the account identification used in payments (account id if the bank did not report it)
+func (a AccountShort) Number() string {
+	if a.Identification != "" {
+		return a.Identification
+	}
+	return a.AccountID
+}
+
+// BankAccount — счёт пользователя, сохранённый локально (таблица bank_accounts)
+type BankAccount struct {
+	ID             int64
+	UserID         int64
+	BankID         int64
+	ConsentID      int64 // account_consents.id (internal)
+	AccountID      string
+	ClientID       string
+	Status         string
+	Currency       string
+	AccountType    string
+	AccountSubType string
+	Nickname       string
+	OpeningDate    string
+	Identification string // account number
+	LastSyncedAt   *time.Time
+}
+
+// Balance — баланс счёта из /accounts/{id}/balances (таблица balances)
+type Balance struct {
+	Type                 string // InterimAvailable/InterimBooked/ClosingAvailable/...
+	Amount               string // signed: "-" for Debit (overdraft)
+	Currency             string
+	CreditDebitIndicator string // Credit/Debit (как в API)
+	DateTime             *time.Time
+	CreditLines          []CreditLine
+}
+
+// CreditLine — кредитный лимит, включённый (или нет) в баланс
+type CreditLine struct {
+	Included bool   `json:"included"`
+	Type     string `json:"type"` // Available/Credit/Emergency/Pre-Agreed/Temporary
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// primaryBalanceOrder — which balance is shown as "the" balance of the account
+var primaryBalanceOrder = []string{
+	"InterimAvailable",
+	"InterimBooked",
+	"ClosingAvailable",
+	"ClosingBooked",
+	"Expected",
+	"OpeningAvailable",
+	"OpeningBooked",
+}
+
+// PrimaryBalance chooses the balance to show by default (see primaryBalanceOrder).
+// Returns false if there are no balances.
+func PrimaryBalance(balances []Balance) (Balance, bool) {
+	if len(balances) == 0 {
+		return Balance{}, false
+	}
+	for _, t := range primaryBalanceOrder {
+		for _, b := range balances {
+			if b.Type == t {
+				return b, true
+			}
+		}
+	}
+	return balances[0], true
+}
+
+// Transaction — операция по счёту из /accounts/{id}/transactions (хранится в таблице transactions)
+type Transaction struct {
+	TransactionID        string     // /transactions.data.transaction[*].transactionId
+	AccountID            string     // /transactions ... accountId
+	Amount               string     // amount.amount (как в API)
+	Currency             string     // amount.currency
+	CreditDebitIndicator string     // Credit/Debit
+	Status               string     // Booked/Pending
+	BookingDateTime      *time.Time // bookingDateTime
+	ValueDateTime        *time.Time // valueDateTime
+	Info                 string     // transactionInformation
+	BankTransactionCode  string     // bankTransactionCode.code, e.g. ReceivedCreditTransfer
+
+	BankID   int64
+	BankCode string
+}
+
+// TransactionFilter — date range and pagination for transaction history
+type TransactionFilter struct {
+	From  *time.Time
+	To    *time.Time
+	Page  int // starts from 1
+	Limit int
 }
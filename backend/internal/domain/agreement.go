@@ -0,0 +1,56 @@
+// internal/domain/agreement.go
+package domain
+
+import "time"
+
+// ProductAgreementConsent — consent of the user to read, open and close product agreements
+// in one bank (table product_agreement_consents). One authorized consent is reused for all agreements.
+// Statuses are the same as for account consents (see ConsentStatus).
+type ProductAgreementConsent struct {
+	ID       int64
+	UserID   int64
+	BankID   int64
+	BankCode string
+
+	ConsentState
+
+	ClientID       string // e.g. team014-1
+	RequestingBank string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type AgreementStatus string
+
+const (
+	AgreementActive AgreementStatus = "active"
+	AgreementClosed AgreementStatus = "closed"
+)
+
+// ProductAgreement — bank product (deposit, card, loan) opened by the user (table product_agreements)
+type ProductAgreement struct {
+	ID        int64
+	UserID    int64
+	BankID    int64
+	BankCode  string
+	ConsentID int64 // product_agreement_consents.id (internal)
+
+	AgreementID string // id in the bank
+	ProductID   string
+	ProductType string
+	ProductName string
+
+	Amount          string // e.g. "50000.00", empty for products without amount (cards)
+	TermMonths      int
+	SourceAccountID string // account the deposit was funded from
+	AccountNumber   string // account opened by the bank for the agreement, if reported
+
+	Status    AgreementStatus
+	StartDate *time.Time
+	EndDate   *time.Time
+	ClosedAt  *time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
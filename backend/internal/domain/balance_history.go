@@ -0,0 +1,54 @@
+// internal/domain/balance_history.go
+
+package domain
+
+import "time"
+
+// BalanceSnapshot — primary balance of an account on a date (table balance_snapshots, one row per day)
+type BalanceSnapshot struct {
+	UserID      int64
+	BankID      int64
+	BankCode    string
+	AccountID   string
+	Date        time.Time // day of the snapshot (UTC), derived from TakenAt on write
+	Amount      int64     // kopecks (minor units), signed
+	Currency    string
+	BalanceType string
+	TakenAt     time.Time // when the balance was received from the bank
+
+	Active bool // on read: the account is still synced (stored under an authorized consent)
+}
+
+type HistoryInterval string
+
+const (
+	IntervalDay   HistoryInterval = "day"
+	IntervalWeek  HistoryInterval = "week"
+	IntervalMonth HistoryInterval = "month"
+)
+
+// BalanceHistory — balances over time: per account (account currency), per bank and total (BaseCurrency)
+type BalanceHistory struct {
+	From         time.Time
+	To           time.Time
+	Interval     HistoryInterval
+	BaseCurrency string
+	Accounts     []BalanceSeries
+	Banks        []BalanceSeries
+	Total        []BalancePoint
+	Missing      []string // currencies without a rate (not counted in bank and total series)
+}
+
+type BalanceSeries struct {
+	AccountID string // empty for bank series
+	BankID    int64
+	BankCode  string
+	Currency  string
+	Points    []BalancePoint
+}
+
+// BalancePoint — balance at the end of the period that starts at Date
+type BalancePoint struct {
+	Date   time.Time
+	Amount int64 // kopecks (minor units); converted sums are rounded to whole kopecks
+}
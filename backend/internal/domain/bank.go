@@ -4,6 +4,18 @@ package domain
 
 import "time"
 
+// bank adapters (banks.adapter)
+const (
+	BankAdapterSandbox = "sandbox" // hackathon sandbox API, default
+	BankAdapterOAuth2  = "oauth2"  // sandbox layout, OAuth2 client_credentials token
+)
+
+// outgoing rate limit of a new bank
+const (
+	DefaultBankRateLimit = 5
+	DefaultBankRateBurst = 10
+)
+
 type Bank struct {
 	ID         int64     `json:"id"`
 	Name       string    `json:"name"`
@@ -12,10 +24,26 @@ type Bank struct {
 	Login      string    `json:"-"` // don't give it out
 	Password   string    `json:"-"` // don't give it out
 	IsEnabled  bool      `json:"is_enabled"`
+	RateLimit  float64   `json:"rate_limit"` // outgoing requests per second, 0 = unlimited
+	RateBurst  int       `json:"rate_burst"` // requests allowed at once above the rate
+	Adapter    string    `json:"adapter"`    // API dialect of the bank, BankAdapterSandbox if empty
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
 }
 
+// BankDeclaration — bank declared in the config. Nil and empty optional fields are not managed by the config.
+type BankDeclaration struct {
+	Code       string
+	Name       string
+	APIBaseURL string
+	Login      string  // client_id
+	Password   *string // client_secret, nil if the config has no secret for the bank
+	Adapter    string
+	RateLimit  *float64
+	RateBurst  *int
+	Enabled    *bool
+}
+
 type BankToken struct {
 	BankID      int64     `json:"bank_id"`
 	AccessToken string    `json:"access_token"`
@@ -0,0 +1,23 @@
+// internal/domain/breaker.go
+
+package domain
+
+import "time"
+
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"    // requests go to the bank
+	BreakerOpen     BreakerState = "open"      // requests fail fast until RetryAt
+	BreakerHalfOpen BreakerState = "half_open" // one probe request decides whether to close the circuit
+)
+
+// BankBreaker — state of the circuit breaker of the bank API in the openbanking transport
+type BankBreaker struct {
+	BankCode  string
+	State     BreakerState
+	Failures  int // consecutive failed calls
+	OpenedAt  *time.Time
+	RetryAt   *time.Time // when the next probe is allowed (open state)
+	LastError string
+}
@@ -1,7 +1,10 @@
 // internal/domain/consent.go
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type ConsentStatus string
 
@@ -12,6 +15,68 @@ const (
 	Revoked               ConsentStatus = "Revoked"
 )
 
+// ParseConsentStatus maps consent statuses of bank answers ("approved", "pending", "Authorised" ...)
+// to ConsentStatus. An auto-approved consent is Authorized whatever the status says.
+func ParseConsentStatus(raw string, autoApproved *bool) ConsentStatus {
+	if autoApproved != nil && *autoApproved {
+		return Authorised
+	}
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "approved", "authorised", "authorized":
+		return Authorised
+	case "pending", "awaitingauthorization", "awaitingauthorisation":
+		return AwaitingAuthorisation
+	case "rejected":
+		return Rejected
+	case "revoked":
+		return Revoked
+	default:
+		return ConsentStatus(raw)
+	}
+}
+
+// ConsentState — consent as the bank reports it, shared by account, payment and product agreement consents
+type ConsentState struct {
+	// from POST-response:
+	RequestID    string
+	ConsentID    *string
+	Status       ConsentStatus
+	AutoApproved *bool
+
+	// dates for consent status
+	CreationDateTime     *time.Time
+	StatusUpdateDateTime *time.Time
+	ExpirationDateTime   *time.Time
+}
+
+// Key is the id to ask the bank about the consent: consent id once it is issued, request id before
+func (s ConsentState) Key() string {
+	if s.ConsentID != nil && *s.ConsentID != "" {
+		return *s.ConsentID
+	}
+	return s.RequestID
+}
+
+// Apply takes the state from a later answer of the bank, values the bank did not send are kept
+func (s *ConsentState) Apply(v ConsentState) {
+	if v.Status != "" {
+		s.Status = v.Status
+	}
+	if v.ConsentID != nil && *v.ConsentID != "" {
+		cid := *v.ConsentID
+		s.ConsentID = &cid
+	}
+	if v.CreationDateTime != nil {
+		s.CreationDateTime = v.CreationDateTime
+	}
+	if v.StatusUpdateDateTime != nil {
+		s.StatusUpdateDateTime = v.StatusUpdateDateTime
+	}
+	if v.ExpirationDateTime != nil {
+		s.ExpirationDateTime = v.ExpirationDateTime
+	}
+}
+
 type Permission string
 
 const (
@@ -26,11 +91,7 @@ type AccountConsent struct {
 	BankID   int64
 	BankCode string
 
-	// from POST-response:
-	RequestID    string
-	ConsentID    *string
-	Status       ConsentStatus
-	AutoApproved *bool
+	ConsentState
 
 	// client_id
 	ClientID string
@@ -41,10 +102,7 @@ type AccountConsent struct {
 	RequestingBank     string
 	RequestingBankName string
 
-	// dates for consent status
-	CreationDateTime     *time.Time
-	StatusUpdateDateTime *time.Time
-	ExpirationDateTime   *time.Time
+	RevokedAt *time.Time // when the consent was revoked at the bank by the user
 
 	// internal timestamps
 	CreatedAt time.Time
@@ -0,0 +1,16 @@
+// internal/domain/deposit.go
+package domain
+
+// DepositOffer — projected payout of a deposit for the amount and term of the comparison
+type DepositOffer struct {
+	Product Product
+
+	Rank       int // 1 = the best payout
+	Amount     float64
+	TermMonths int
+
+	SimpleInterest      float64 // interest paid at the end of the term
+	SimplePayout        float64
+	CapitalizedInterest float64 // interest added to the deposit monthly
+	CapitalizedPayout   float64
+}
@@ -0,0 +1,45 @@
+// internal/domain/fx.go
+
+package domain
+
+import "time"
+
+// BaseCurrency — currency of the Bank of Russia rates (all rates are RUB per unit)
+const BaseCurrency = "RUB"
+
+// FXRate — official rate of a currency to RUB on a date (table fx_rates)
+type FXRate struct {
+	Date     time.Time // date the rate is set for (YYYY-MM-DD)
+	Currency string    // ISO code, e.g. USD
+	Nominal  int       // units of the currency the value is given for (e.g. 10 for CNY)
+	Value    float64   // RUB for Nominal units
+	Rate     float64   // RUB for 1 unit (Value / Nominal)
+	Source   string    // where the rate was imported from
+}
+
+// NetWorth — all user's balances converted to one currency
+type NetWorth struct {
+	BaseCurrency string
+	Total        float64
+	Accounts     []NetWorthAccount
+	Rates        []NetWorthRate // rates used for conversion (one per currency)
+	Missing      []string       // currencies without a known rate (not included in Total)
+}
+
+type NetWorthAccount struct {
+	AccountID   string
+	BankCode    string
+	Nickname    string
+	BalanceType string
+	Amount      float64 // in the account currency
+	Currency    string
+	Converted   float64 // in BaseCurrency
+	Included    bool    // false if there is no rate for the currency
+}
+
+// NetWorthRate — how Currency was converted to BaseCurrency
+type NetWorthRate struct {
+	Currency string
+	Rate     float64   // BaseCurrency for 1 unit of Currency
+	Date     time.Time // date of the rate (the older of the two rates for cross rates)
+}
@@ -0,0 +1,50 @@
+// internal/domain/loan.go
+package domain
+
+type ScheduleType string
+
+const (
+	ScheduleAnnuity        ScheduleType = "annuity"        // equal monthly payments
+	ScheduleDifferentiated ScheduleType = "differentiated" // equal principal parts, interest on the remaining debt
+)
+
+// SchedulePayment — one monthly payment of the repayment schedule
+type SchedulePayment struct {
+	Month     int
+	Payment   float64
+	Interest  float64
+	Principal float64
+	Balance   float64 // debt left after the payment
+}
+
+// PaymentSchedule — repayment schedule of a credit product for the amount and term
+type PaymentSchedule struct {
+	Product    Product
+	Type       ScheduleType
+	Amount     float64
+	TermMonths int
+
+	Payments     []SchedulePayment
+	TotalPayment float64
+	Overpayment  float64 // total interest
+}
+
+// ScheduleSummary — totals of a schedule for side by side comparison
+type ScheduleSummary struct {
+	FirstPayment float64
+	LastPayment  float64
+	TotalPayment float64
+	Overpayment  float64
+}
+
+// LoanOffer — cost of a credit product for the amount and term of the comparison
+type LoanOffer struct {
+	Product Product
+
+	Rank       int // 1 = the cheapest
+	Amount     float64
+	TermMonths int
+
+	Annuity        ScheduleSummary
+	Differentiated ScheduleSummary
+}
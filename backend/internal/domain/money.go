@@ -0,0 +1,67 @@
+// internal/domain/money.go
+
+package domain
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidMoney — the amount is not a decimal number with at most two fraction digits
+var ErrInvalidMoney = errors.New("invalid money amount")
+
+// maxMoneyDigits keeps kopecks of the whole part within int64
+const maxMoneyDigits = 15
+
+// ParseKopecks parses a decimal amount ("1500", "1500.5", "-20.00") into kopecks without rounding.
+// More than two fraction digits, exponents and thousand separators are rejected.
+func ParseKopecks(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	neg := strings.HasPrefix(s, "-")
+	if neg {
+		s = s[1:]
+	}
+
+	whole, frac, dot := strings.Cut(s, ".")
+	if whole == "" || len(whole) > maxMoneyDigits || !isDigits(whole) ||
+		(dot && frac == "") || len(frac) > 2 || !isDigits(frac) {
+		return 0, ErrInvalidMoney
+	}
+
+	k, _ := strconv.ParseInt(whole, 10, 64)
+	k *= 100
+	if frac != "" {
+		f, _ := strconv.ParseInt(frac, 10, 64)
+		if len(frac) == 1 {
+			f *= 10
+		}
+		k += f
+	}
+	if neg {
+		k = -k
+	}
+	return k, nil
+}
+
+// FormatKopecks formats kopecks as a decimal amount with two fraction digits ("1500.50")
+func FormatKopecks(k int64) string {
+	sign := ""
+	if k < 0 {
+		sign, k = "-", -k
+	}
+	frac := strconv.FormatInt(k%100, 10)
+	if len(frac) == 1 {
+		frac = "0" + frac
+	}
+	return sign + strconv.FormatInt(k/100, 10) + "." + frac
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
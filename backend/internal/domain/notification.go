@@ -0,0 +1,26 @@
+// internal/domain/notification.go
+package domain
+
+import "time"
+
+type NotificationKind string
+
+const (
+	NotificationProductRateChanged NotificationKind = "product_rate_changed"
+)
+
+// Notification — message for the user shown in the app (table notifications)
+type Notification struct {
+	ID      int64
+	UserID  int64
+	Kind    NotificationKind
+	Title   string
+	Message string
+
+	// what the notification is about (empty if not related to a product)
+	BankCode  string
+	ProductID string
+
+	CreatedAt time.Time
+	ReadAt    *time.Time
+}
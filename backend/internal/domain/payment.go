@@ -0,0 +1,80 @@
+// internal/domain/payment.go
+package domain
+
+import "time"
+
+// PaymentConsent — consent of the user to one payment from their account (table payment_consents).
+// Statuses are the same as for account consents (see ConsentStatus).
+type PaymentConsent struct {
+	ID       int64
+	UserID   int64
+	BankID   int64
+	BankCode string
+
+	ConsentState
+
+	ClientID       string // e.g. team014-1
+	RequestingBank string
+	ConsentType    string // single_use
+
+	// payment details the consent is given for
+	Amount           string // e.g. "1500.00"
+	Currency         string
+	DebtorAccount    string // account number of the user in the bank of the consent
+	CreditorAccount  string // account number of the recipient
+	CreditorName     string
+	CreditorBankCode string // empty = the same bank
+	Reference        string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type PaymentStatus string
+
+const (
+	// PaymentInitiating — local status of a reserved payment: the bank is being called or its answer
+	// was lost, the payment id is not known yet
+	PaymentInitiating PaymentStatus = "Initiating"
+
+	PaymentPending                    PaymentStatus = "Pending"
+	PaymentAcceptedSettlementProcess  PaymentStatus = "AcceptedSettlementInProcess"
+	PaymentAcceptedSettlementComplete PaymentStatus = "AcceptedSettlementCompleted"
+	PaymentAcceptedCreditComplete     PaymentStatus = "AcceptedCreditSettlementCompleted"
+	PaymentRejected                   PaymentStatus = "Rejected"
+)
+
+// IsFinal reports whether the bank will not change the status anymore
+func (s PaymentStatus) IsFinal() bool {
+	switch s {
+	case PaymentAcceptedSettlementComplete, PaymentAcceptedCreditComplete, PaymentRejected:
+		return true
+	}
+	return false
+}
+
+// Payment — domestic payment initiated by a payment consent (table payments)
+type Payment struct {
+	ID               int64
+	UserID           int64
+	BankID           int64
+	BankCode         string
+	PaymentConsentID int64 // payment_consents.id (internal)
+
+	PaymentID string // id in the bank
+	Status    PaymentStatus
+
+	Amount           string
+	Currency         string
+	DebtorAccount    string
+	CreditorAccount  string
+	CreditorName     string
+	CreditorBankCode string
+	Comment          string
+
+	CreationDateTime     *time.Time
+	StatusUpdateDateTime *time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
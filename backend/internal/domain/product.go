@@ -19,10 +19,111 @@ type Product struct {
 	BankName  string    `json:"bank_name"`
 	FetchedAt time.Time `json:"fetched_at"`
 
+	// health of the source bank: products of a failing bank are served from the last successful fetch
+	BankHealth BankHealth `json:"bank_health"`
+
 	IsRecommended bool `json:"is_recommended"`
+
+	// set by the recommendation rule matched for the current user
+	RecommendationReason   string `json:"recommendation_reason,omitempty"`
+	RecommendationPriority int    `json:"-"`
+	RecommendationRuleID   *int64 `json:"-"`
 }
 
 type ProductFilter struct {
-	ProductType string
-	BankIDs     []int64
+	ProductType  string
+	ProductTypes []string // any of the types, compared case-insensitively (lower-case values)
+	BankIDs      []int64
+
+	MinRate *float64 // % per year
+	MinTerm *int     // months
+	MaxTerm *int     // months
+	Amount  *float64 // the sum must fit into [min_amount, max_amount] of the product
+	Query   string   // text search in name and description (case-insensitive)
+
+	Sort   ProductSort
+	Desc   bool
+	Limit  int    // 0 = no pagination
+	Cursor string // NextCursor of the previous page
+
+	UserID int64 // recommendation rules are evaluated for the user (0 = the static list only)
+}
+
+type ProductSort string
+
+const (
+	ProductSortBank ProductSort = "bank" // bank name, product type, product name (default)
+	ProductSortRate ProductSort = "rate"
+	ProductSortTerm ProductSort = "term"
+)
+
+// ProductPage — products of the catalogue page; NextCursor is empty on the last page
+type ProductPage struct {
+	Items      []Product
+	NextCursor string
+}
+
+type BankHealth string
+
+const (
+	BankHealthOK      BankHealth = "ok"      // the last fetch succeeded
+	BankHealthDown    BankHealth = "down"    // the last fetch failed
+	BankHealthUnknown BankHealth = "unknown" // never fetched
+)
+
+// ProductSource — state of the product catalogue fetch from one bank (table product_sources)
+type ProductSource struct {
+	BankID   int64
+	BankCode string
+	BankName string
+
+	LastAttemptAt *time.Time
+	LastSuccessAt *time.Time
+	LastError     string
+	Failures      int // consecutive failed fetches
+	ProductsCount int // products stored after the last successful fetch
+}
+
+// Health of the bank as a product source
+func (s ProductSource) Health() BankHealth {
+	switch {
+	case s.LastAttemptAt == nil:
+		return BankHealthUnknown
+	case s.Failures > 0:
+		return BankHealthDown
+	default:
+		return BankHealthOK
+	}
+}
+
+type ProductField string
+
+const (
+	FieldInterestRate ProductField = "interest_rate"
+	FieldMinAmount    ProductField = "min_amount"
+	FieldMaxAmount    ProductField = "max_amount"
+	FieldTermMonths   ProductField = "term_months"
+)
+
+// ProductChange — change of one product parameter between two fetches (table product_history)
+type ProductChange struct {
+	ID          int64
+	BankID      int64
+	BankCode    string
+	ProductID   string
+	ProductName string
+	Field       ProductField
+	OldValue    float64
+	NewValue    float64
+	ChangedAt   time.Time
+}
+
+// ProductSubscription — the user wants to be notified when the product rate changes (table product_subscriptions)
+type ProductSubscription struct {
+	UserID      int64
+	BankID      int64
+	BankCode    string
+	ProductID   string
+	ProductName string
+	CreatedAt   time.Time
 }
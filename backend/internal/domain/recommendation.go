@@ -0,0 +1,153 @@
+// internal/domain/recommendation.go
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// BankConnection — condition on the bank of the product among banks connected by the user
+type BankConnection string
+
+const (
+	BankConnectionAny          BankConnection = ""
+	BankConnectionConnected    BankConnection = "connected"     // the user already has an authorized consent in the bank
+	BankConnectionNotConnected BankConnection = "not_connected" // cross-sell to users of other banks
+)
+
+// RecommendationRule — targeting rule of recommended products (table recommendation_rules).
+// Empty target fields match any product, nil conditions are not checked.
+type RecommendationRule struct {
+	ID   int64
+	Name string
+
+	// target
+	BankCode    string
+	ProductID   string
+	ProductType string
+
+	// conditions
+	MinAge         *int
+	MaxAge         *int
+	MinBalance     *float64 // total balance of the user in RUB
+	MaxBalance     *float64
+	BankConnection BankConnection
+
+	Priority   int // the higher the earlier the product is recommended
+	ActiveFrom *time.Time
+	ActiveTo   *time.Time
+	Reason     string // shown to the user as recommendation_reason
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// UserProfile — facts about the user checked by recommendation rules
+type UserProfile struct {
+	UserID         int64
+	Age            *int     // nil if the birth date is unknown
+	TotalBalance   *float64 // RUB, nil if unknown
+	ConnectedBanks map[string]struct{}
+}
+
+// Active reports whether the rule is active at the moment
+func (r RecommendationRule) Active(at time.Time) bool {
+	if r.ActiveFrom != nil && at.Before(*r.ActiveFrom) {
+		return false
+	}
+	if r.ActiveTo != nil && !at.Before(*r.ActiveTo) {
+		return false
+	}
+	return true
+}
+
+// Targets reports whether the product is a target of the rule
+func (r RecommendationRule) Targets(p Product) bool {
+	if r.BankCode != "" && !strings.EqualFold(r.BankCode, p.BankCode) {
+		return false
+	}
+	if r.ProductID != "" && r.ProductID != p.ProductID {
+		return false
+	}
+	if r.ProductType != "" && !strings.EqualFold(r.ProductType, p.ProductType) {
+		return false
+	}
+	return true
+}
+
+// Matches reports whether the rule recommends the product to the user.
+// Conditions on unknown facts (no birth date, no balances) do not match.
+func (r RecommendationRule) Matches(u UserProfile, p Product) bool {
+	if !r.Targets(p) {
+		return false
+	}
+	if r.MinAge != nil || r.MaxAge != nil {
+		if u.Age == nil || (r.MinAge != nil && *u.Age < *r.MinAge) || (r.MaxAge != nil && *u.Age > *r.MaxAge) {
+			return false
+		}
+	}
+	if r.MinBalance != nil || r.MaxBalance != nil {
+		if u.TotalBalance == nil ||
+			(r.MinBalance != nil && *u.TotalBalance < *r.MinBalance) ||
+			(r.MaxBalance != nil && *u.TotalBalance > *r.MaxBalance) {
+			return false
+		}
+	}
+	_, connected := u.ConnectedBanks[strings.ToLower(p.BankCode)]
+	switch r.BankConnection {
+	case BankConnectionConnected:
+		return connected
+	case BankConnectionNotConnected:
+		return !connected
+	}
+	return true
+}
+
+type RecommendationEventKind string
+
+const (
+	RecommendationImpression RecommendationEventKind = "impression" // the recommended product was shown
+	RecommendationClick      RecommendationEventKind = "click"
+)
+
+// RecommendationEvent — impression or click of a recommended product (table recommendation_events)
+type RecommendationEvent struct {
+	Kind        RecommendationEventKind
+	UserID      int64
+	BankCode    string
+	ProductID   string
+	ProductType string
+	RuleID      *int64 // nil for products of the static recommended list
+	CreatedAt   time.Time
+}
+
+// RecommendationStat — impressions and clicks per rule, bank and day
+type RecommendationStat struct {
+	Day         string // YYYY-MM-DD (UTC)
+	RuleID      *int64 // nil = the static recommended list
+	RuleName    string
+	BankCode    string
+	Impressions int
+	Clicks      int
+}
+
+// CTR — clicks per impression
+func (s RecommendationStat) CTR() float64 {
+	if s.Impressions == 0 {
+		return 0
+	}
+	return float64(s.Clicks) / float64(s.Impressions)
+}
+
+// AgeAt returns full years from the birth date (YYYY-MM-DD) to the date
+func AgeAt(birthDate string, at time.Time) (int, bool) {
+	b, err := time.Parse("2006-01-02", strings.TrimSpace(birthDate))
+	if err != nil {
+		return 0, false
+	}
+	age := at.Year() - b.Year()
+	if at.Month() < b.Month() || (at.Month() == b.Month() && at.Day() < b.Day()) {
+		age--
+	}
+	return age, true
+}
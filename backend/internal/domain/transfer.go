@@ -0,0 +1,45 @@
+// internal/domain/transfer.go
+package domain
+
+import "time"
+
+type TransferStatus string
+
+const (
+	TransferAwaitingAuthorization TransferStatus = "AwaitingAuthorization" // payment consent is not approved yet
+	TransferInitiating            TransferStatus = "Initiating"            // payment is being initiated (retried until the bank answers)
+	TransferProcessing            TransferStatus = "Processing"            // payment is initiated, money is on the way
+	TransferCompleted             TransferStatus = "Completed"             // the source bank settled the payment (the target account is not checked)
+	TransferFailed                TransferStatus = "Failed"
+)
+
+// IsFinal reports whether the transfer will not change anymore
+func (s TransferStatus) IsFinal() bool {
+	return s == TransferCompleted || s == TransferFailed
+}
+
+// Transfer — move of money between two accounts of the same user (table transfers).
+// Runs as a payment consent + payment at the source bank.
+type Transfer struct {
+	ID     int64
+	UserID int64
+
+	SourceBankID    int64
+	SourceBankCode  string
+	SourceAccountID string
+	TargetBankID    int64
+	TargetBankCode  string
+	TargetAccountID string
+
+	Amount   string
+	Currency string
+
+	Status           TransferStatus
+	PaymentConsentID *int64 // payment_consents.id
+	PaymentID        *int64 // payments.id
+	Error            string // why the transfer failed
+
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	CompletedAt *time.Time // when the source bank settled the payment
+}
@@ -0,0 +1,316 @@
+// internal/fakebank/accounts.go
+
+package fakebank
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	permAccounts     = "ReadAccountsDetail"
+	permBalances     = "ReadBalances"
+	permTransactions = "ReadTransactionsDetail"
+)
+
+type amount struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+type accountView struct {
+	AccountID      string                  `json:"accountId"`
+	Status         string                  `json:"status"`
+	Currency       string                  `json:"currency"`
+	AccountType    string                  `json:"accountType"`
+	AccountSubType string                  `json:"accountSubType"`
+	Nickname       string                  `json:"nickname"`
+	OpeningDate    string                  `json:"openingDate"`
+	Account        []accountIdentification `json:"account"`
+}
+
+type accountIdentification struct {
+	SchemeName     string `json:"schemeName"` // RU.CBR.PAN
+	Identification string `json:"identification"`
+	Name           string `json:"name"`
+}
+
+type balanceView struct {
+	AccountID            string `json:"accountId"`
+	Type                 string `json:"type"`
+	DateTime             string `json:"dateTime"`
+	Amount               amount `json:"amount"`
+	CreditDebitIndicator string `json:"creditDebitIndicator"`
+}
+
+type transactionView struct {
+	AccountID              string    `json:"accountId"`
+	TransactionID          string    `json:"transactionId"`
+	Amount                 amount    `json:"amount"`
+	CreditDebitIndicator   string    `json:"creditDebitIndicator"`
+	Status                 string    `json:"status"`
+	BookingDateTime        time.Time `json:"bookingDateTime"`
+	ValueDateTime          time.Time `json:"valueDateTime"`
+	TransactionInformation string    `json:"transactionInformation"`
+	BankTransactionCode    struct {
+		Code string `json:"code"`
+	} `json:"bankTransactionCode"`
+}
+
+type productView struct {
+	ProductID    string  `json:"productId"`
+	ProductType  string  `json:"productType"`
+	ProductName  string  `json:"productName"`
+	Description  *string `json:"description"`
+	InterestRate *string `json:"interestRate"`
+	MinAmount    *string `json:"minAmount"`
+	MaxAmount    *string `json:"maxAmount"`
+	TermMonths   *int    `json:"termMonths"`
+}
+
+// accounts — GET /accounts?client_id=...
+func (s *Server) accounts(w http.ResponseWriter, r *http.Request) {
+	clientID, ok, reason := s.allowed(r, permAccounts)
+	if !ok {
+		writeError(w, http.StatusForbidden, reason)
+		return
+	}
+	if q := r.URL.Query().Get("client_id"); q != "" && q != clientID {
+		writeError(w, http.StatusForbidden, "consent belongs to another client")
+		return
+	}
+
+	s.mu.Lock()
+	items := s.accountsOf(clientID)
+	out := make([]accountView, 0, len(items))
+	for _, a := range items {
+		out = append(out, accountView{
+			AccountID:      a.ID,
+			Status:         a.Status,
+			Currency:       a.Currency,
+			AccountType:    a.AccountType,
+			AccountSubType: a.AccountSubType,
+			Nickname:       a.Nickname,
+			OpeningDate:    a.OpeningDate,
+			Account: []accountIdentification{
+				{SchemeName: "RU.CBR.PAN", Identification: a.Number, Name: a.Nickname},
+			},
+		})
+	}
+	s.mu.Unlock()
+
+	var resp struct {
+		Data struct {
+			Account []accountView `json:"account"`
+		} `json:"data"`
+	}
+	resp.Data.Account = out
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// balances — GET /accounts/{accountId}/balances
+func (s *Server) balances(w http.ResponseWriter, r *http.Request) {
+	clientID, ok, reason := s.allowed(r, permBalances)
+	if !ok {
+		writeError(w, http.StatusForbidden, reason)
+		return
+	}
+
+	s.mu.Lock()
+	a, ok := s.findAccount(clientID, chi.URLParam(r, "accountId"))
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "account not found")
+		return
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	value, indicator := money(a.Balance)
+	var resp struct {
+		Data struct {
+			Balance []balanceView `json:"balance"`
+		} `json:"data"`
+	}
+	for _, typ := range []string{"InterimAvailable", "InterimBooked"} {
+		resp.Data.Balance = append(resp.Data.Balance, balanceView{
+			AccountID:            a.ID,
+			Type:                 typ,
+			DateTime:             now,
+			Amount:               amount{Amount: value, Currency: a.Currency},
+			CreditDebitIndicator: indicator,
+		})
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// transactions — GET /accounts/{accountId}/transactions?from_booking_date_time&to_booking_date_time&page&limit
+func (s *Server) transactions(w http.ResponseWriter, r *http.Request) {
+	clientID, ok, reason := s.allowed(r, permTransactions)
+	if !ok {
+		writeError(w, http.StatusForbidden, reason)
+		return
+	}
+
+	s.mu.Lock()
+	a, ok := s.findAccount(clientID, chi.URLParam(r, "accountId"))
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "account not found")
+		return
+	}
+
+	q := r.URL.Query()
+	from, _ := time.Parse(time.RFC3339, q.Get("from_booking_date_time"))
+	to, _ := time.Parse(time.RFC3339, q.Get("to_booking_date_time"))
+	page, _ := strconv.Atoi(q.Get("page"))
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 50
+	}
+
+	items := make([]transactionView, 0, len(a.Transactions))
+	for _, t := range a.Transactions {
+		if (!from.IsZero() && t.BookingTime.Before(from)) || (!to.IsZero() && t.BookingTime.After(to)) {
+			continue
+		}
+		v := transactionView{
+			AccountID:              a.ID,
+			TransactionID:          t.ID,
+			Status:                 "Booked",
+			BookingDateTime:        t.BookingTime,
+			ValueDateTime:          t.BookingTime,
+			TransactionInformation: t.Info,
+		}
+		v.Amount.Amount, v.CreditDebitIndicator = money(t.Amount)
+		v.Amount.Currency = a.Currency
+		v.BankTransactionCode.Code = t.Code
+		items = append(items, v)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].BookingDateTime.After(items[j].BookingDateTime) })
+
+	var resp struct {
+		Data struct {
+			Transaction []transactionView `json:"transaction"`
+		} `json:"data"`
+		Meta struct {
+			TotalPages int `json:"totalPages"`
+		} `json:"meta"`
+	}
+	resp.Meta.TotalPages = (len(items) + limit - 1) / limit
+	start := min((page-1)*limit, len(items))
+	resp.Data.Transaction = items[start:min(start+limit, len(items))]
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// products — GET /products?product_type=..., public like in the sandbox
+func (s *Server) products(w http.ResponseWriter, r *http.Request) {
+	typ := r.URL.Query().Get("product_type")
+
+	var resp struct {
+		Data struct {
+			Product []productView `json:"product"`
+		} `json:"data"`
+	}
+	resp.Data.Product = []productView{}
+
+	s.mu.Lock()
+	for _, p := range s.data.Products {
+		if typ != "" && p.Type != typ {
+			continue
+		}
+		resp.Data.Product = append(resp.Data.Product, productView{
+			ProductID:    p.ID,
+			ProductType:  p.Type,
+			ProductName:  p.Name,
+			Description:  optional(p.Description),
+			InterestRate: optional(p.InterestRate),
+			MinAmount:    optional(p.MinAmount),
+			MaxAmount:    optional(p.MaxAmount),
+			TermMonths:   p.TermMonths,
+		})
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// AddTransaction books the transaction on the account of the client, as an operation in the bank app does
+func (s *Server) AddTransaction(clientID, accountID string, t Transaction) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := s.accountsOf(clientID)
+	for i := range items {
+		if items[i].ID == accountID {
+			items[i].Transactions = append(items[i].Transactions, t)
+			return true
+		}
+	}
+	return false
+}
+
+// SetBalance changes the balance of the account of the client
+func (s *Server) SetBalance(clientID, accountID string, balance float64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := s.accountsOf(clientID)
+	for i := range items {
+		if items[i].ID == accountID {
+			items[i].Balance = balance
+			return true
+		}
+	}
+	return false
+}
+
+// SetProductRate changes the interest rate of the product, the next /products returns the new rate
+func (s *Server) SetProductRate(productID, rate string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.data.Products {
+		if s.data.Products[i].ID == productID {
+			s.data.Products[i].InterestRate = rate
+			return true
+		}
+	}
+	return false
+}
+
+// CloseAccount removes the account of the client, the bank does not return it anymore
+func (s *Server) CloseAccount(clientID, accountID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := s.accountsOf(clientID)
+	for i := range items {
+		if items[i].ID == accountID {
+			s.data.Clients[clientID] = append(items[:i:i], items[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// money formats a signed amount as the bank does: positive amount + Credit/Debit
+func money(v float64) (string, string) {
+	if v < 0 {
+		return strconv.FormatFloat(-v, 'f', 2, 64), "Debit"
+	}
+	return strconv.FormatFloat(v, 'f', 2, 64), "Credit"
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
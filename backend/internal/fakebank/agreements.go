@@ -0,0 +1,283 @@
+// internal/fakebank/agreements.go
+
+package fakebank
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	agreementActive = "active"
+	agreementClosed = "closed"
+)
+
+type agreement struct {
+	ID            string
+	ClientID      string
+	Product       Product
+	Amount        float64
+	TermMonths    *int
+	Status        string
+	StartDate     time.Time
+	AccountNumber string
+}
+
+type agreementConsentRequest struct {
+	ClientID               string `json:"client_id"`
+	ReadProductAgreements  bool   `json:"read_product_agreements"`
+	OpenProductAgreements  bool   `json:"open_product_agreements"`
+	CloseProductAgreements bool   `json:"close_product_agreements"`
+}
+
+type agreementRequest struct {
+	ProductID  string   `json:"product_id"`
+	Amount     *float64 `json:"amount"`
+	TermMonths *int     `json:"term_months"`
+}
+
+type agreementView struct {
+	AgreementID   string  `json:"agreement_id"`
+	ProductID     string  `json:"product_id"`
+	ProductName   string  `json:"product_name"`
+	ProductType   string  `json:"product_type"`
+	Amount        float64 `json:"amount"`
+	TermMonths    *int    `json:"term_months"`
+	Status        string  `json:"status"`
+	StartDate     string  `json:"start_date"`
+	EndDate       string  `json:"end_date,omitempty"`
+	AccountNumber string  `json:"account_number"`
+}
+
+// requestAgreementConsent — POST /product-agreement-consents/request?client_id=...
+func (s *Server) requestAgreementConsent(w http.ResponseWriter, r *http.Request) {
+	var in agreementConsentRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeError(w, http.StatusBadRequest, "bad json: "+err.Error())
+		return
+	}
+	if in.ClientID == "" {
+		in.ClientID = r.URL.Query().Get("client_id")
+	}
+	if in.ClientID == "" {
+		writeError(w, http.StatusBadRequest, "client_id is required")
+		return
+	}
+
+	var perms []string
+	if in.ReadProductAgreements {
+		perms = append(perms, "ReadProductAgreements")
+	}
+	if in.OpenProductAgreements {
+		perms = append(perms, "OpenProductAgreements")
+	}
+	if in.CloseProductAgreements {
+		perms = append(perms, "CloseProductAgreements")
+	}
+
+	now := time.Now().UTC()
+	c := &consent{
+		RequestID:   randomID("areq"),
+		ClientID:    in.ClientID,
+		Status:      statusAwaiting,
+		Permissions: perms,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		ExpiresAt:   now.Add(consentTTL),
+	}
+
+	s.mu.Lock()
+	auto := s.opts.AutoApprove
+	s.agrConsents[c.RequestID] = c
+	if auto {
+		c.ConsentID = randomID("aconsent")
+		c.Status = statusAuthorized
+		s.agrConsents[c.ConsentID] = c
+	}
+	resp := consentRequestResponse{
+		RequestID:    c.RequestID,
+		Status:       "pending",
+		Message:      "product agreement consent is waiting for the client approval",
+		CreatedAt:    now.Format(time.RFC3339),
+		AutoApproved: auto,
+	}
+	if auto {
+		id := c.ConsentID
+		resp.ConsentID = &id
+		resp.Status = "approved"
+		resp.Message = "product agreement consent approved automatically"
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// getAgreementConsent — GET /product-agreement-consents/{id}
+func (s *Server) getAgreementConsent(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	c, ok := s.agrConsents[chi.URLParam(r, "id")]
+	var v consentView
+	if ok {
+		v = c.view()
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "product agreement consent not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, v)
+}
+
+// openAgreement — POST /product-agreements?client_id=...; the amount must be within the product limits
+func (s *Server) openAgreement(w http.ResponseWriter, r *http.Request) {
+	clientID, ok, reason := s.agreementAllowed(r, "OpenProductAgreements")
+	if !ok {
+		writeError(w, http.StatusForbidden, reason)
+		return
+	}
+	var in agreementRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeError(w, http.StatusBadRequest, "bad json: "+err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var product *Product
+	for i := range s.data.Products {
+		if s.data.Products[i].ID == in.ProductID {
+			product = &s.data.Products[i]
+		}
+	}
+	if product == nil {
+		writeError(w, http.StatusNotFound, "product not found")
+		return
+	}
+
+	a := &agreement{
+		ID:            randomID("agr"),
+		ClientID:      clientID,
+		Product:       *product,
+		TermMonths:    product.TermMonths,
+		Status:        agreementActive,
+		StartDate:     time.Now().UTC(),
+		AccountNumber: fmt.Sprintf("42305810%012d", len(s.agreements)+1),
+	}
+	if in.TermMonths != nil {
+		a.TermMonths = in.TermMonths
+	}
+	if in.Amount != nil {
+		a.Amount = *in.Amount
+		if !within(a.Amount, product.MinAmount, product.MaxAmount) {
+			writeError(w, http.StatusUnprocessableEntity, "amount is out of the product limits")
+			return
+		}
+	}
+	s.agreements = append(s.agreements, a)
+	writeJSON(w, http.StatusOK, map[string]agreementView{"data": a.view()})
+}
+
+// listAgreements — GET /product-agreements?client_id=...
+func (s *Server) listAgreements(w http.ResponseWriter, r *http.Request) {
+	clientID, ok, reason := s.agreementAllowed(r, "ReadProductAgreements")
+	if !ok {
+		writeError(w, http.StatusForbidden, reason)
+		return
+	}
+
+	s.mu.Lock()
+	out := []agreementView{}
+	for _, a := range s.agreements {
+		if a.ClientID == clientID {
+			out = append(out, a.view())
+		}
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string][]agreementView{"data": out})
+}
+
+// closeAgreement — DELETE /product-agreements/{id}?client_id=...
+func (s *Server) closeAgreement(w http.ResponseWriter, r *http.Request) {
+	clientID, ok, reason := s.agreementAllowed(r, "CloseProductAgreements")
+	if !ok {
+		writeError(w, http.StatusForbidden, reason)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, a := range s.agreements {
+		if a.ID == chi.URLParam(r, "id") && a.ClientID == clientID {
+			a.Status = agreementClosed
+			writeJSON(w, http.StatusOK, map[string]agreementView{"data": a.view()})
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "product agreement not found")
+}
+
+// AgreementCount returns the number of product agreements opened in the bank (closed ones included)
+func (s *Server) AgreementCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.agreements)
+}
+
+// agreementAllowed checks the X-Product-Agreement-Consent-Id of the request against the client_id
+func (s *Server) agreementAllowed(r *http.Request, perm string) (string, bool, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clientID := r.URL.Query().Get("client_id")
+	c, ok := s.agrConsents[r.Header.Get("X-Product-Agreement-Consent-Id")]
+	switch {
+	case !ok:
+		return "", false, "product agreement consent not found"
+	case c.Status != statusAuthorized:
+		return "", false, "product agreement consent is " + c.Status
+	case c.ClientID != clientID:
+		return "", false, "product agreement consent of another client"
+	case !slices.Contains(c.Permissions, perm):
+		return "", false, "product agreement consent has no permission " + perm
+	}
+	return clientID, true, ""
+}
+
+func (a *agreement) view() agreementView {
+	v := agreementView{
+		AgreementID:   a.ID,
+		ProductID:     a.Product.ID,
+		ProductName:   a.Product.Name,
+		ProductType:   a.Product.Type,
+		Amount:        a.Amount,
+		TermMonths:    a.TermMonths,
+		Status:        a.Status,
+		StartDate:     a.StartDate.Format("2006-01-02"),
+		AccountNumber: a.AccountNumber,
+	}
+	if a.TermMonths != nil {
+		v.EndDate = a.StartDate.AddDate(0, *a.TermMonths, 0).Format("2006-01-02")
+	}
+	return v
+}
+
+// within checks the amount against the product limits ("" = no limit)
+func within(v float64, minAmount, maxAmount string) bool {
+	if lo, err := strconv.ParseFloat(minAmount, 64); err == nil && v < lo {
+		return false
+	}
+	if hi, err := strconv.ParseFloat(maxAmount, 64); err == nil && v > hi {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,227 @@
+// internal/fakebank/consent.go
+
+package fakebank
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	statusAwaiting   = "AwaitingAuthorization"
+	statusAuthorized = "Authorized"
+	statusRejected   = "Rejected"
+	statusRevoked    = "Revoked"
+
+	consentTTL = 365 * 24 * time.Hour
+)
+
+type consent struct {
+	RequestID      string
+	ConsentID      string // issued on approval
+	ClientID       string
+	Status         string
+	Permissions    []string
+	RequestingBank string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	ExpiresAt      time.Time
+}
+
+type consentRequest struct {
+	ClientID           string   `json:"client_id"`
+	Permissions        []string `json:"permissions"`
+	Reason             string   `json:"reason"`
+	RequestingBank     string   `json:"requesting_bank"`
+	RequestingBankName string   `json:"requesting_bank_name"`
+}
+
+type consentRequestResponse struct {
+	RequestID    string  `json:"request_id"`
+	ConsentID    *string `json:"consent_id"`
+	Status       string  `json:"status"` // approved | pending
+	Message      string  `json:"message"`
+	CreatedAt    string  `json:"created_at"`
+	AutoApproved bool    `json:"auto_approved"`
+}
+
+type consentView struct {
+	Data struct {
+		ConsentID            string    `json:"consentId"`
+		Status               string    `json:"status"`
+		CreationDateTime     time.Time `json:"creationDateTime"`
+		StatusUpdateDateTime time.Time `json:"statusUpdateDateTime"`
+		Permissions          []string  `json:"permissions"`
+		ExpirationDateTime   time.Time `json:"expirationDateTime"`
+	} `json:"data"`
+}
+
+func (s *Server) requestConsent(w http.ResponseWriter, r *http.Request) {
+	var in consentRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeError(w, http.StatusBadRequest, "bad json: "+err.Error())
+		return
+	}
+	if in.ClientID == "" || len(in.Permissions) == 0 {
+		writeError(w, http.StatusBadRequest, "client_id and permissions are required")
+		return
+	}
+
+	now := time.Now().UTC()
+	c := &consent{
+		RequestID:      randomID("req"),
+		ClientID:       in.ClientID,
+		Status:         statusAwaiting,
+		Permissions:    in.Permissions,
+		RequestingBank: in.RequestingBank,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		ExpiresAt:      now.Add(consentTTL),
+	}
+
+	s.mu.Lock()
+	auto := s.opts.AutoApprove
+	s.consents[c.RequestID] = c
+	if auto {
+		s.authorize(c, now)
+	}
+	resp := consentRequestResponse{
+		RequestID:    c.RequestID,
+		Status:       "pending",
+		Message:      "consent is waiting for the client approval",
+		CreatedAt:    now.Format(time.RFC3339),
+		AutoApproved: auto,
+	}
+	if auto {
+		id := c.ConsentID
+		resp.ConsentID = &id
+		resp.Status = "approved"
+		resp.Message = "consent approved automatically"
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) getConsent(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	c, ok := s.consents[chi.URLParam(r, "id")]
+	var v consentView
+	if ok {
+		v = c.view()
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "consent not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, v)
+}
+
+func (s *Server) deleteConsent(w http.ResponseWriter, r *http.Request) {
+	if !s.setStatus(chi.URLParam(r, "id"), statusRevoked) {
+		writeError(w, http.StatusNotFound, "consent not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) approveConsent(w http.ResponseWriter, r *http.Request) {
+	if !s.Approve(chi.URLParam(r, "id")) {
+		writeError(w, http.StatusNotFound, "consent not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) rejectConsent(w http.ResponseWriter, r *http.Request) {
+	if !s.setStatus(chi.URLParam(r, "id"), statusRejected) {
+		writeError(w, http.StatusNotFound, "consent not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Approve authorizes the consent by request or consent id, as the client does in the bank app
+func (s *Server) Approve(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.consents[id]
+	if !ok {
+		return false
+	}
+	if c.Status == statusAwaiting {
+		s.authorize(c, time.Now().UTC())
+	}
+	return true
+}
+
+// ConsentStatus returns the status of the consent by request or consent id
+func (s *Server) ConsentStatus(id string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.consents[id]
+	if !ok {
+		return "", false
+	}
+	return c.Status, true
+}
+
+// authorize issues the consent id, s.mu must be held
+func (s *Server) authorize(c *consent, now time.Time) {
+	c.ConsentID = randomID("consent")
+	c.Status = statusAuthorized
+	c.UpdatedAt = now
+	s.consents[c.ConsentID] = c
+}
+
+func (s *Server) setStatus(id, status string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.consents[id]
+	if !ok {
+		return false
+	}
+	c.Status = status
+	c.UpdatedAt = time.Now().UTC()
+	return true
+}
+
+// allowed checks the x-consent-id of a data request (authorized, not expired, with the permission)
+// and returns the client of the consent
+func (s *Server) allowed(r *http.Request, perm string) (string, bool, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.consents[r.Header.Get("x-consent-id")]
+	switch {
+	case !ok:
+		return "", false, "consent not found"
+	case c.Status != statusAuthorized:
+		return "", false, "consent is " + c.Status
+	case time.Now().After(c.ExpiresAt):
+		return "", false, "consent expired"
+	case !slices.Contains(c.Permissions, perm):
+		return "", false, "consent has no permission " + perm
+	}
+	return c.ClientID, true, ""
+}
+
+func (c *consent) view() consentView {
+	var v consentView
+	v.Data.ConsentID = c.ConsentID
+	v.Data.Status = c.Status
+	v.Data.CreationDateTime = c.CreatedAt
+	v.Data.StatusUpdateDateTime = c.UpdatedAt
+	v.Data.Permissions = c.Permissions
+	v.Data.ExpirationDateTime = c.ExpiresAt
+	return v
+}
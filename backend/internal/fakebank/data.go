@@ -0,0 +1,144 @@
+// internal/fakebank/data.go
+
+package fakebank
+
+import (
+	"fmt"
+	"hash/fnv"
+	mathrand "math/rand/v2"
+	"time"
+)
+
+// Data — seeded state of the fake bank
+type Data struct {
+	Clients  map[string][]Account // accounts by client_id; clients not listed get generated accounts
+	Products []Product
+}
+
+type Account struct {
+	ID             string
+	Nickname       string
+	Status         string // Enabled | Disabled
+	Currency       string
+	AccountType    string // Personal | Business
+	AccountSubType string // Checking | Savings | CreditCard
+	OpeningDate    string // 2006-01-02
+	Number         string // 20 digits, used in payments
+	Balance        float64
+	Transactions   []Transaction
+}
+
+type Transaction struct {
+	ID          string
+	Amount      float64 // signed: negative = Debit
+	Info        string
+	Code        string
+	BookingTime time.Time
+}
+
+type Product struct {
+	ID           string
+	Type         string // deposit | loan | credit_card | card | account
+	Name         string
+	Description  string
+	InterestRate string // percent per year, "" = not set
+	MinAmount    string
+	MaxAmount    string
+	TermMonths   *int
+}
+
+// DefaultData returns the product line shared by the sandbox banks, with rates that differ per bank
+func DefaultData(code string) *Data {
+	r := seeded(code)
+	rate := func(base float64) string { return fmt.Sprintf("%.2f", base+float64(r.IntN(150))/100) }
+	term := func(m int) *int { return &m }
+
+	return &Data{
+		Clients: map[string][]Account{},
+		Products: []Product{
+			{ID: "prod-" + code + "-deposit-001", Type: "deposit", Name: "Накопительный вклад",
+				Description: "Вклад с ежемесячной выплатой процентов", InterestRate: rate(15),
+				MinAmount: "10000", MaxAmount: "5000000", TermMonths: term(12)},
+			{ID: "prod-" + code + "-deposit-002", Type: "deposit", Name: "Вклад «Короткий»",
+				Description: "Вклад на полгода", InterestRate: rate(13.5),
+				MinAmount: "50000", MaxAmount: "3000000", TermMonths: term(6)},
+			{ID: "prod-" + code + "-loan-001", Type: "loan", Name: "Потребительский кредит",
+				Description: "Кредит наличными без залога", InterestRate: rate(19),
+				MinAmount: "50000", MaxAmount: "3000000", TermMonths: term(36)},
+			{ID: "prod-" + code + "-card-001", Type: "credit_card", Name: "Кредитная карта",
+				Description: "Льготный период 100 дней", InterestRate: rate(29),
+				MinAmount: "0", MaxAmount: "500000"},
+			{ID: "prod-" + code + "-card-002", Type: "card", Name: "Дебетовая карта",
+				Description: "Кэшбэк до 5% в выбранных категориях"},
+		},
+	}
+}
+
+// accountsOf returns accounts of the client, generating them on the first request, s.mu must be held
+func (s *Server) accountsOf(clientID string) []Account {
+	if items, ok := s.data.Clients[clientID]; ok {
+		return items
+	}
+	items := generateAccounts(s.opts.Code, clientID)
+	s.data.Clients[clientID] = items
+	return items
+}
+
+// findAccount looks for the account of the client, s.mu must be held
+func (s *Server) findAccount(clientID, accountID string) (Account, bool) {
+	for _, a := range s.accountsOf(clientID) {
+		if a.ID == accountID {
+			return a, true
+		}
+	}
+	return Account{}, false
+}
+
+// generateAccounts makes a checking and a savings account; the same client always gets the same data
+func generateAccounts(code, clientID string) []Account {
+	r := seeded(code + "/" + clientID)
+	now := time.Now().UTC().Truncate(time.Hour)
+
+	mk := func(n int, subType, nickname string, balance float64) Account {
+		id := fmt.Sprintf("acc-%s-%s-%d", code, clientID, n)
+		a := Account{
+			ID:             id,
+			Nickname:       nickname,
+			Status:         "Enabled",
+			Currency:       "RUB",
+			AccountType:    "Personal",
+			AccountSubType: subType,
+			OpeningDate:    now.AddDate(-1-r.IntN(3), -r.IntN(12), 0).Format("2006-01-02"),
+			Number:         fmt.Sprintf("40817810%012d", r.Int64N(1e12)),
+			Balance:        balance,
+		}
+		for i := range 5 {
+			amount := float64(100+r.IntN(20000)) + float64(r.IntN(100))/100
+			info, txCode := "Оплата покупки", "PMNT"
+			if i%3 == 0 {
+				info, txCode = "Пополнение", "RCDT"
+			} else {
+				amount = -amount
+			}
+			a.Transactions = append(a.Transactions, Transaction{
+				ID:          fmt.Sprintf("tx-%s-%d", id, i+1),
+				Amount:      amount,
+				Info:        info,
+				Code:        txCode,
+				BookingTime: now.Add(-time.Duration(i*(24+r.IntN(72))) * time.Hour),
+			})
+		}
+		return a
+	}
+
+	return []Account{
+		mk(1, "Checking", "Текущий счёт", float64(10000+r.IntN(200000))+float64(r.IntN(100))/100),
+		mk(2, "Savings", "Сберегательный счёт", float64(50000+r.IntN(500000))),
+	}
+}
+
+func seeded(key string) *mathrand.Rand {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	return mathrand.New(mathrand.NewPCG(h.Sum64(), 0))
+}
@@ -0,0 +1,284 @@
+// internal/fakebank/fakebank.go
+
+// Package fakebank implements the part of the sandbox Open Banking API used by the backend
+// (bank token, account consents, accounts, balances, transactions, products, payments, product agreements)
+// with seeded data, so the whole consent -> accounts -> products -> payments flow works offline.
+package fakebank
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	mathrand "math/rand/v2"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Options struct {
+	Code         string // bank code (abank, sbank, vbank ...)
+	Name         string
+	ClientID     string // team login for /auth/bank-token
+	ClientSecret string
+	TokenTTL     time.Duration // lifetime of bank tokens, 24h if 0
+	OAuth2       bool          // tokens by POST /oauth2/token (JSON client_credentials) instead of /auth/bank-token
+
+	AutoApprove bool // consents are authorized right away, otherwise they wait for Approve
+
+	Latency   time.Duration // added to every response
+	Jitter    time.Duration // random extra latency up to Jitter
+	ErrorRate float64       // share of requests answered with 503 (0..1)
+
+	Data *Data // seeded clients and products, DefaultData(Code) if nil
+}
+
+// Server — fake bank, an http.Handler
+type Server struct {
+	log  *slog.Logger
+	mux  *chi.Mux
+	opts Options
+
+	mu       sync.Mutex
+	data     *Data
+	tokens   map[string]time.Time // access token -> expires at
+	consents map[string]*consent  // by request id and by consent id
+
+	payConsents map[string]*paymentConsent // by request id and by consent id
+	payments    map[string]*payment        // by payment id
+	idempotency map[string]string          // X-Idempotency-Key -> payment id
+
+	agrConsents map[string]*consent // product agreement consents by request id and by consent id
+	agreements  []*agreement
+}
+
+func New(log *slog.Logger, opts Options) *Server {
+	if opts.TokenTTL <= 0 {
+		opts.TokenTTL = 24 * time.Hour
+	}
+	if opts.Name == "" {
+		opts.Name = opts.Code
+	}
+	data := opts.Data
+	if data == nil {
+		data = DefaultData(opts.Code)
+	}
+
+	s := &Server{
+		log:      log.With(slog.String("fakebank", opts.Code)),
+		opts:     opts,
+		data:     data,
+		tokens:   make(map[string]time.Time),
+		consents: make(map[string]*consent),
+
+		payConsents: make(map[string]*paymentConsent),
+		payments:    make(map[string]*payment),
+		idempotency: make(map[string]string),
+		agrConsents: make(map[string]*consent),
+	}
+
+	r := chi.NewRouter()
+	r.Use(s.inject)
+
+	r.Post("/auth/bank-token", s.bankToken)
+	r.Post("/oauth2/token", s.oauth2Token)
+	r.Get("/products", s.products)
+
+	r.Group(func(r chi.Router) {
+		r.Use(s.auth)
+
+		r.Post("/account-consents/request", s.requestConsent)
+		r.Get("/account-consents/{id}", s.getConsent)
+		r.Delete("/account-consents/{id}", s.deleteConsent)
+
+		r.Get("/accounts", s.accounts)
+		r.Get("/accounts/{accountId}/balances", s.balances)
+		r.Get("/accounts/{accountId}/transactions", s.transactions)
+
+		r.Post("/payment-consents/request", s.requestPaymentConsent)
+		r.Get("/payment-consents/{id}", s.getPaymentConsent)
+		r.Post("/payments", s.createPayment)
+		r.Get("/payments/{id}", s.getPayment)
+
+		r.Post("/product-agreement-consents/request", s.requestAgreementConsent)
+		r.Get("/product-agreement-consents/{id}", s.getAgreementConsent)
+		r.Post("/product-agreements", s.openAgreement)
+		r.Get("/product-agreements", s.listAgreements)
+		r.Delete("/product-agreements/{id}", s.closeAgreement)
+	})
+
+	// manual approval of consents (AutoApprove = false)
+	r.Post("/fake/account-consents/{id}/approve", s.approveConsent)
+	r.Post("/fake/account-consents/{id}/reject", s.rejectConsent)
+	r.Post("/fake/payment-consents/{id}/approve", s.approvePaymentConsent)
+	r.Post("/fake/payment-consents/{id}/reject", s.rejectPaymentConsent)
+
+	s.mux = r
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) { s.mux.ServeHTTP(w, r) }
+
+func (s *Server) Code() string { return s.opts.Code }
+
+// SetAutoApprove switches auto-approval of new consents
+func (s *Server) SetAutoApprove(v bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.opts.AutoApprove = v
+}
+
+// SetOAuth2 switches the token endpoint between /auth/bank-token and /oauth2/token
+func (s *Server) SetOAuth2(v bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.opts.OAuth2 = v
+}
+
+// SetFaults changes injected latency and error rate
+func (s *Server) SetFaults(latency, jitter time.Duration, errorRate float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.opts.Latency, s.opts.Jitter, s.opts.ErrorRate = latency, jitter, errorRate
+}
+
+// inject adds configured latency and answers a share of requests with 503
+func (s *Server) inject(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		delay, jitter, rate := s.opts.Latency, s.opts.Jitter, s.opts.ErrorRate
+		s.mu.Unlock()
+
+		if jitter > 0 {
+			delay += mathrand.N(jitter)
+		}
+		if delay > 0 && !sleep(r.Context(), delay) {
+			return
+		}
+		if rate > 0 && mathrand.Float64() < rate {
+			s.log.Debug("injected failure", slog.String("method", r.Method), slog.String("path", r.URL.Path))
+			writeError(w, http.StatusServiceUnavailable, "injected failure")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ClientID    string `json:"client_id"`
+	Algorithm   string `json:"algorithm"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+type oauth2TokenRequest struct {
+	GrantType    string `json:"grant_type"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+}
+
+// bankToken — sandbox flow, credentials in the query
+func (s *Server) bankToken(w http.ResponseWriter, r *http.Request) {
+	if s.oauth2() {
+		writeError(w, http.StatusNotFound, "use /oauth2/token")
+		return
+	}
+	q := r.URL.Query()
+	s.issueToken(w, q.Get("client_id"), q.Get("client_secret"))
+}
+
+// oauth2Token — client_credentials grant with a JSON body
+func (s *Server) oauth2Token(w http.ResponseWriter, r *http.Request) {
+	if !s.oauth2() {
+		writeError(w, http.StatusNotFound, "use /auth/bank-token")
+		return
+	}
+	var in oauth2TokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeError(w, http.StatusBadRequest, "bad json: "+err.Error())
+		return
+	}
+	if in.GrantType != "client_credentials" {
+		writeError(w, http.StatusBadRequest, "unsupported grant_type")
+		return
+	}
+	s.issueToken(w, in.ClientID, in.ClientSecret)
+}
+
+func (s *Server) oauth2() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.opts.OAuth2
+}
+
+func (s *Server) issueToken(w http.ResponseWriter, clientID, clientSecret string) {
+	if clientID != s.opts.ClientID || clientSecret != s.opts.ClientSecret {
+		writeError(w, http.StatusUnauthorized, "invalid client credentials")
+		return
+	}
+
+	token := randomID("tok")
+	s.mu.Lock()
+	s.tokens[token] = time.Now().Add(s.opts.TokenTTL)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, tokenResponse{
+		AccessToken: token,
+		TokenType:   "bearer",
+		ClientID:    s.opts.ClientID,
+		Algorithm:   "HS256",
+		ExpiresIn:   int64(s.opts.TokenTTL / time.Second),
+	})
+}
+
+// auth checks the bank token issued by /auth/bank-token or /oauth2/token
+func (s *Server) auth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		const prefix = "Bearer "
+		h := r.Header.Get("Authorization")
+		if len(h) <= len(prefix) || h[:len(prefix)] != prefix {
+			writeError(w, http.StatusUnauthorized, "missing bearer token")
+			return
+		}
+		s.mu.Lock()
+		exp, ok := s.tokens[h[len(prefix):]]
+		s.mu.Unlock()
+		if !ok || time.Now().After(exp) {
+			writeError(w, http.StatusUnauthorized, "invalid or expired token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError answers like the sandbox does: {"detail": "..."}
+func writeError(w http.ResponseWriter, code int, detail string) {
+	writeJSON(w, code, map[string]string{"detail": detail})
+}
+
+func randomID(prefix string) string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return prefix + "-" + hex.EncodeToString(b)
+}
+
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,349 @@
+// internal/fakebank/payments.go
+
+package fakebank
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	paymentInProcess = "AcceptedSettlementInProcess"
+	paymentCompleted = "AcceptedSettlementCompleted"
+
+	paymentConsentTTL = 24 * time.Hour
+)
+
+// paymentConsent — single-use consent for one payment
+type paymentConsent struct {
+	RequestID       string
+	ConsentID       string // issued on approval
+	ClientID        string
+	Status          string
+	Amount          string
+	Currency        string
+	DebtorAccount   string // account number
+	CreditorAccount string
+	PaymentID       string // set when the consent is used
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	ExpiresAt       time.Time
+}
+
+type payment struct {
+	ID              string
+	ClientID        string
+	Status          string
+	Amount          float64
+	DebtorAccount   string
+	CreditorAccount string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+type paymentConsentRequest struct {
+	ClientID        string      `json:"client_id"`
+	ConsentType     string      `json:"consent_type"`
+	Amount          json.Number `json:"amount"`
+	Currency        string      `json:"currency"`
+	DebtorAccount   string      `json:"debtor_account"`
+	CreditorAccount string      `json:"creditor_account"`
+}
+
+type paymentConsentView struct {
+	Data struct {
+		ConsentID            string    `json:"consentId"`
+		Status               string    `json:"status"`
+		CreationDateTime     time.Time `json:"creationDateTime"`
+		StatusUpdateDateTime time.Time `json:"statusUpdateDateTime"`
+		ExpirationDateTime   time.Time `json:"expirationDateTime"`
+	} `json:"data"`
+}
+
+type paymentRequest struct {
+	Data struct {
+		Initiation struct {
+			InstructedAmount amount `json:"instructedAmount"`
+			DebtorAccount    struct {
+				Identification string `json:"identification"`
+			} `json:"debtorAccount"`
+			CreditorAccount struct {
+				Identification string `json:"identification"`
+			} `json:"creditorAccount"`
+		} `json:"initiation"`
+	} `json:"data"`
+}
+
+type paymentView struct {
+	Data struct {
+		PaymentID            string    `json:"paymentId"`
+		Status               string    `json:"status"`
+		CreationDateTime     time.Time `json:"creationDateTime"`
+		StatusUpdateDateTime time.Time `json:"statusUpdateDateTime"`
+	} `json:"data"`
+}
+
+// requestPaymentConsent — POST /payment-consents/request
+func (s *Server) requestPaymentConsent(w http.ResponseWriter, r *http.Request) {
+	var in paymentConsentRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeError(w, http.StatusBadRequest, "bad json: "+err.Error())
+		return
+	}
+	if in.ClientID == "" || in.DebtorAccount == "" || in.CreditorAccount == "" {
+		writeError(w, http.StatusBadRequest, "client_id, debtor_account and creditor_account are required")
+		return
+	}
+	if v, err := in.Amount.Float64(); err != nil || v <= 0 {
+		writeError(w, http.StatusBadRequest, "amount must be positive")
+		return
+	}
+
+	now := time.Now().UTC()
+	c := &paymentConsent{
+		RequestID:       randomID("preq"),
+		ClientID:        in.ClientID,
+		Status:          statusAwaiting,
+		Amount:          in.Amount.String(),
+		Currency:        in.Currency,
+		DebtorAccount:   in.DebtorAccount,
+		CreditorAccount: in.CreditorAccount,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+		ExpiresAt:       now.Add(paymentConsentTTL),
+	}
+
+	s.mu.Lock()
+	if _, ok := s.accountByNumber(in.ClientID, in.DebtorAccount); !ok {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, "debtor account not found")
+		return
+	}
+	auto := s.opts.AutoApprove
+	s.payConsents[c.RequestID] = c
+	if auto {
+		s.authorizePayment(c, now)
+	}
+	resp := consentRequestResponse{
+		RequestID:    c.RequestID,
+		Status:       "pending",
+		Message:      "payment consent is waiting for the client approval",
+		CreatedAt:    now.Format(time.RFC3339),
+		AutoApproved: auto,
+	}
+	if auto {
+		id := c.ConsentID
+		resp.ConsentID = &id
+		resp.Status = "approved"
+		resp.Message = "payment consent approved automatically"
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// getPaymentConsent — GET /payment-consents/{id}
+func (s *Server) getPaymentConsent(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	c, ok := s.payConsents[chi.URLParam(r, "id")]
+	var v paymentConsentView
+	if ok {
+		v.Data.ConsentID = c.ConsentID
+		v.Data.Status = c.Status
+		v.Data.CreationDateTime = c.CreatedAt
+		v.Data.StatusUpdateDateTime = c.UpdatedAt
+		v.Data.ExpirationDateTime = c.ExpiresAt
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "payment consent not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, v)
+}
+
+// createPayment — POST /payments?client_id=... with X-Payment-Consent-Id. A repeated X-Idempotency-Key
+// returns the payment made by the first call, a used consent cannot pay again.
+func (s *Server) createPayment(w http.ResponseWriter, r *http.Request) {
+	var in paymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeError(w, http.StatusBadRequest, "bad json: "+err.Error())
+		return
+	}
+	init := in.Data.Initiation
+	value, err := strconv.ParseFloat(init.InstructedAmount.Amount, 64)
+	if err != nil || value <= 0 {
+		writeError(w, http.StatusBadRequest, "amount must be positive")
+		return
+	}
+	clientID := r.URL.Query().Get("client_id")
+	key := r.Header.Get("X-Idempotency-Key")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if id, ok := s.idempotency[key]; ok && key != "" {
+		writeJSON(w, http.StatusCreated, s.payments[id].view())
+		return
+	}
+
+	c, ok := s.payConsents[r.Header.Get("X-Payment-Consent-Id")]
+	switch {
+	case !ok:
+		writeError(w, http.StatusForbidden, "payment consent not found")
+		return
+	case c.Status != statusAuthorized:
+		writeError(w, http.StatusForbidden, "payment consent is "+c.Status)
+		return
+	case c.PaymentID != "":
+		writeError(w, http.StatusConflict, "payment consent is already used")
+		return
+	case c.ClientID != clientID || c.Amount != init.InstructedAmount.Amount ||
+		c.DebtorAccount != init.DebtorAccount.Identification || c.CreditorAccount != init.CreditorAccount.Identification:
+		writeError(w, http.StatusUnprocessableEntity, "payment does not match the consent")
+		return
+	}
+
+	i, _ := s.accountByNumber(clientID, c.DebtorAccount)
+	debtor := &s.data.Clients[clientID][i]
+	if debtor.Balance < value {
+		writeError(w, http.StatusUnprocessableEntity, "insufficient funds")
+		return
+	}
+
+	now := time.Now().UTC()
+	p := &payment{
+		ID:              randomID("pay"),
+		ClientID:        clientID,
+		Status:          paymentInProcess,
+		Amount:          value,
+		DebtorAccount:   c.DebtorAccount,
+		CreditorAccount: c.CreditorAccount,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	debtor.Balance -= value
+	debtor.Transactions = append(debtor.Transactions, Transaction{
+		ID: "tx-" + p.ID, Amount: -value, Info: "Перевод", Code: "PMNT", BookingTime: now,
+	})
+
+	c.PaymentID = p.ID
+	s.payments[p.ID] = p
+	if key != "" {
+		s.idempotency[key] = p.ID
+	}
+	writeJSON(w, http.StatusCreated, p.view())
+}
+
+// getPayment — GET /payments/{id}; a payment in process settles on the first look,
+// the creditor account is credited if it is in this bank
+func (s *Server) getPayment(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.payments[chi.URLParam(r, "id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "payment not found")
+		return
+	}
+	if p.Status == paymentInProcess {
+		now := time.Now().UTC()
+		p.Status = paymentCompleted
+		p.UpdatedAt = now
+		for clientID := range s.data.Clients {
+			if i, ok := s.accountByNumber(clientID, p.CreditorAccount); ok {
+				creditor := &s.data.Clients[clientID][i]
+				creditor.Balance += p.Amount
+				creditor.Transactions = append(creditor.Transactions, Transaction{
+					ID: "tx-" + p.ID + "-in", Amount: p.Amount, Info: "Перевод", Code: "RCDT", BookingTime: now,
+				})
+				break
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, p.view())
+}
+
+func (s *Server) approvePaymentConsent(w http.ResponseWriter, r *http.Request) {
+	if !s.ApprovePayment(chi.URLParam(r, "id")) {
+		writeError(w, http.StatusNotFound, "payment consent not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) rejectPaymentConsent(w http.ResponseWriter, r *http.Request) {
+	if !s.RejectPayment(chi.URLParam(r, "id")) {
+		writeError(w, http.StatusNotFound, "payment consent not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ApprovePayment authorizes the payment consent by request or consent id
+func (s *Server) ApprovePayment(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.payConsents[id]
+	if !ok {
+		return false
+	}
+	if c.Status == statusAwaiting {
+		s.authorizePayment(c, time.Now().UTC())
+	}
+	return true
+}
+
+// RejectPayment rejects the payment consent by request or consent id
+func (s *Server) RejectPayment(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.payConsents[id]
+	if !ok {
+		return false
+	}
+	c.Status = statusRejected
+	c.UpdatedAt = time.Now().UTC()
+	return true
+}
+
+// PaymentCount returns the number of payments made by the bank
+func (s *Server) PaymentCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.payments)
+}
+
+// authorizePayment issues the payment consent id, s.mu must be held
+func (s *Server) authorizePayment(c *paymentConsent, now time.Time) {
+	c.ConsentID = randomID("pconsent")
+	c.Status = statusAuthorized
+	c.UpdatedAt = now
+	s.payConsents[c.ConsentID] = c
+}
+
+// accountByNumber returns the index of the client's account with the number (or id), s.mu must be held
+func (s *Server) accountByNumber(clientID, number string) (int, bool) {
+	for i, a := range s.accountsOf(clientID) {
+		if a.Number == number || a.ID == number {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func (p *payment) view() paymentView {
+	var v paymentView
+	v.Data.PaymentID = p.ID
+	v.Data.Status = p.Status
+	v.Data.CreationDateTime = p.CreatedAt
+	v.Data.StatusUpdateDateTime = p.UpdatedAt
+	return v
+}
@@ -1,13 +1,60 @@
 package dto
 
+import "time"
+
 type AccountResponse struct {
-	AccountID      string `json:"account_id"`
-	Nickname       string `json:"nickname"`
-	Status         string `json:"status"`
-	AccountSubType string `json:"account_sub_type"`
-	OpeningDate    string `json:"opening_date"`
-	Amount         string `json:"amount"`
-	Currency       string `json:"currency"`
-	BankCode       string `json:"bank_code"`
-	ClientID       string `json:"client_id"`
+	AccountID      string            `json:"account_id"`
+	Nickname       string            `json:"nickname"`
+	Status         string            `json:"status"`
+	AccountSubType string            `json:"account_sub_type"`
+	OpeningDate    string            `json:"opening_date"`
+	Identification string            `json:"identification,omitempty"` // account number
+	Amount         string            `json:"amount"`                   // primary balance (signed)
+	Currency       string            `json:"currency"`                 // primary balance currency
+	BalanceType    string            `json:"balance_type"`             // primary balance type, e.g. InterimAvailable
+	Balances       []BalanceResponse `json:"balances"`
+	BankID         int64             `json:"bank_id"`
+	BankCode       string            `json:"bank_code"`
+	ClientID       string            `json:"client_id"`
+	LastSyncedAt   *time.Time        `json:"last_synced_at"`
+	Stale          bool              `json:"stale"`
+}
+
+type BalanceResponse struct {
+	Type                 string               `json:"type" example:"InterimAvailable"`
+	Amount               string               `json:"amount" example:"-1500.00"` // negative for Debit
+	Currency             string               `json:"currency" example:"RUB"`
+	CreditDebitIndicator string               `json:"credit_debit_indicator" example:"Debit"`
+	DateTime             *time.Time           `json:"date_time,omitempty"`
+	CreditLines          []CreditLineResponse `json:"credit_lines,omitempty"`
+}
+
+type CreditLineResponse struct {
+	Included bool   `json:"included"`
+	Type     string `json:"type" example:"Credit"`
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+type TransactionResponse struct {
+	TransactionID        string     `json:"transaction_id"`
+	AccountID            string     `json:"account_id"`
+	Amount               string     `json:"amount"`
+	Currency             string     `json:"currency"`
+	CreditDebitIndicator string     `json:"credit_debit_indicator" example:"Credit"`
+	Status               string     `json:"status" example:"Booked"`
+	BookingDateTime      *time.Time `json:"booking_date_time,omitempty"`
+	ValueDateTime        *time.Time `json:"value_date_time,omitempty"`
+	Info                 string     `json:"info,omitempty"`
+	BankTransactionCode  string     `json:"bank_transaction_code,omitempty" example:"ReceivedCreditTransfer"`
+	BankCode             string     `json:"bank_code"`
+}
+
+type TransactionsPageResponse struct {
+	Transactions []TransactionResponse `json:"transactions"`
+	Page         int                   `json:"page"`
+	Limit        int                   `json:"limit"`
+	Total        int                   `json:"total"`
+	TotalPages   int                   `json:"total_pages"`
+	LastSyncedAt *time.Time            `json:"last_synced_at,omitempty"`
 }
@@ -0,0 +1,56 @@
+// internal/http-server/dto/agreement.go
+package dto
+
+import (
+	"multibank/backend/internal/domain"
+	"time"
+)
+
+type AgreementApplyRequest struct {
+	Amount          string `json:"amount,omitempty" example:"50000.00"`            // deposits and loans
+	TermMonths      int    `json:"term_months,omitempty" example:"12"`             // deposits and loans
+	SourceAccountID string `json:"source_account_id,omitempty" example:"acc-1010"` // account to fund the deposit from
+}
+
+type AgreementResponse struct {
+	ID          int64                  `json:"id"`
+	BankCode    string                 `json:"bank_code"`
+	AgreementID string                 `json:"agreement_id"`
+	ProductID   string                 `json:"product_id"`
+	ProductType string                 `json:"product_type,omitempty"`
+	ProductName string                 `json:"product_name,omitempty"`
+	Status      domain.AgreementStatus `json:"status" example:"active"`
+
+	Amount          string `json:"amount,omitempty"`
+	TermMonths      int    `json:"term_months,omitempty"`
+	SourceAccountID string `json:"source_account_id,omitempty"`
+	AccountNumber   string `json:"account_number,omitempty"`
+
+	StartDate *time.Time `json:"start_date,omitempty"`
+	EndDate   *time.Time `json:"end_date,omitempty"`
+	ClosedAt  *time.Time `json:"closed_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func AgreementResponseFromDomain(a domain.ProductAgreement) AgreementResponse {
+	return AgreementResponse{
+		ID:              a.ID,
+		BankCode:        a.BankCode,
+		AgreementID:     a.AgreementID,
+		ProductID:       a.ProductID,
+		ProductType:     a.ProductType,
+		ProductName:     a.ProductName,
+		Status:          a.Status,
+		Amount:          a.Amount,
+		TermMonths:      a.TermMonths,
+		SourceAccountID: a.SourceAccountID,
+		AccountNumber:   a.AccountNumber,
+		StartDate:       a.StartDate,
+		EndDate:         a.EndDate,
+		ClosedAt:        a.ClosedAt,
+		CreatedAt:       a.CreatedAt,
+		UpdatedAt:       a.UpdatedAt,
+	}
+}
@@ -0,0 +1,70 @@
+// internal/http-server/dto/balance_history.go
+
+package dto
+
+import "multibank/backend/internal/domain"
+
+type BalanceHistoryResponse struct {
+	From         string                  `json:"from" example:"2025-09-18"`
+	To           string                  `json:"to" example:"2025-10-17"`
+	Interval     string                  `json:"interval" example:"day"`
+	BaseCurrency string                  `json:"base_currency" example:"RUB"` // currency of banks and total
+	Accounts     []BalanceSeriesResponse `json:"accounts"`                    // in the account currency
+	Banks        []BalanceSeriesResponse `json:"banks"`
+	Total        []BalancePointResponse  `json:"total"`
+	Missing      []string                `json:"missing"` // currencies without a rate (not in banks and total)
+}
+
+type BalanceSeriesResponse struct {
+	AccountID string                 `json:"account_id,omitempty"`
+	BankID    int64                  `json:"bank_id"`
+	BankCode  string                 `json:"bank_code"`
+	Currency  string                 `json:"currency"`
+	Points    []BalancePointResponse `json:"points"`
+}
+
+type BalancePointResponse struct {
+	Date   string `json:"date" example:"2025-10-13"` // start of the period
+	Amount string `json:"amount" example:"15000.00"` // balance at the end of the period
+}
+
+func BalanceHistoryResponseFromDomain(d domain.BalanceHistory) BalanceHistoryResponse {
+	out := BalanceHistoryResponse{
+		From:         d.From.Format("2006-01-02"),
+		To:           d.To.Format("2006-01-02"),
+		Interval:     string(d.Interval),
+		BaseCurrency: d.BaseCurrency,
+		Accounts:     make([]BalanceSeriesResponse, 0, len(d.Accounts)),
+		Banks:        make([]BalanceSeriesResponse, 0, len(d.Banks)),
+		Total:        balancePoints(d.Total),
+		Missing:      d.Missing,
+	}
+	if out.Missing == nil {
+		out.Missing = []string{}
+	}
+	for _, s := range d.Accounts {
+		out.Accounts = append(out.Accounts, balanceSeries(s))
+	}
+	for _, s := range d.Banks {
+		out.Banks = append(out.Banks, balanceSeries(s))
+	}
+	return out
+}
+
+func balanceSeries(s domain.BalanceSeries) BalanceSeriesResponse {
+	return BalanceSeriesResponse{
+		AccountID: s.AccountID,
+		BankID:    s.BankID,
+		BankCode:  s.BankCode,
+		Currency:  s.Currency,
+		Points:    balancePoints(s.Points),
+	}
+}
+
+func balancePoints(items []domain.BalancePoint) []BalancePointResponse {
+	out := make([]BalancePointResponse, 0, len(items))
+	for _, p := range items {
+		out = append(out, BalancePointResponse{Date: p.Date.Format("2006-01-02"), Amount: domain.FormatKopecks(p.Amount)})
+	}
+	return out
+}
@@ -2,7 +2,10 @@
 
 package dto
 
-import "time"
+import (
+	"multibank/backend/internal/domain"
+	"time"
+)
 
 type BankResponse struct {
 	ID           int64     `json:"id"`
@@ -18,3 +21,91 @@ type BankAuthorizeResponse struct {
 	Status       string    `json:"status" example:"authorized"`
 	TokenExpires time.Time `json:"token_expires"`
 }
+
+type BankBreakerResponse struct {
+	BankCode  string              `json:"bank_code"`
+	State     domain.BreakerState `json:"state" example:"closed"` // closed | open | half_open
+	Failures  int                 `json:"failures"`               // consecutive failed calls
+	OpenedAt  *time.Time          `json:"opened_at,omitempty"`
+	RetryAt   *time.Time          `json:"retry_at,omitempty"` // next probe call (open state)
+	LastError string              `json:"last_error,omitempty"`
+}
+
+// BankAdminResponse — bank settings for the admin, credentials are never given out
+type BankAdminResponse struct {
+	ID           int64     `json:"id"`
+	Name         string    `json:"name"`
+	Code         string    `json:"code"`
+	APIBaseURL   string    `json:"api_base_url"`
+	Adapter      string    `json:"adapter" example:"sandbox"`
+	IsEnabled    bool      `json:"is_enabled"`
+	RateLimit    float64   `json:"rate_limit"`
+	RateBurst    int       `json:"rate_burst"`
+	Authorized   bool      `json:"authorized"`
+	TokenExpires time.Time `json:"token_expires,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func BankAdminResponseFromDomain(b domain.Bank) BankAdminResponse {
+	return BankAdminResponse{
+		ID:         b.ID,
+		Name:       b.Name,
+		Code:       b.Code,
+		APIBaseURL: b.APIBaseURL,
+		Adapter:    b.Adapter,
+		IsEnabled:  b.IsEnabled,
+		RateLimit:  b.RateLimit,
+		RateBurst:  b.RateBurst,
+		CreatedAt:  b.CreatedAt,
+		UpdatedAt:  b.UpdatedAt,
+	}
+}
+
+// BankUpdateRequest — settings of the bank, the code and credentials are changed separately
+type BankUpdateRequest struct {
+	Name       string   `json:"name" example:"Velocity Bank"`
+	APIBaseURL string   `json:"api_base_url" example:"https://vbank.open.bankingapi.ru"`
+	Adapter    string   `json:"adapter,omitempty" example:"sandbox"` // sandbox if empty
+	RateLimit  *float64 `json:"rate_limit,omitempty" example:"5"`    // requests per second, 0 = unlimited, 5 if null
+	RateBurst  *int     `json:"rate_burst,omitempty" example:"10"`   // 10 if null
+}
+
+func (r BankUpdateRequest) ToDomain() domain.Bank {
+	b := domain.Bank{
+		Name:       r.Name,
+		APIBaseURL: r.APIBaseURL,
+		Adapter:    r.Adapter,
+		RateLimit:  domain.DefaultBankRateLimit,
+		RateBurst:  domain.DefaultBankRateBurst,
+	}
+	if r.RateLimit != nil {
+		b.RateLimit = *r.RateLimit
+	}
+	if r.RateBurst != nil {
+		b.RateBurst = *r.RateBurst
+	}
+	return b
+}
+
+type BankCreateRequest struct {
+	Code string `json:"code" example:"vbank"`
+	BankUpdateRequest
+	BankCredentialsRequest
+	IsEnabled *bool `json:"is_enabled,omitempty"` // true if null
+}
+
+func (r BankCreateRequest) ToDomain() domain.Bank {
+	b := r.BankUpdateRequest.ToDomain()
+	b.Code = r.Code
+	b.Login = r.Login
+	b.Password = r.Password
+	b.IsEnabled = r.IsEnabled == nil || *r.IsEnabled
+	return b
+}
+
+// BankCredentialsRequest — team credentials in the bank API (client_id / client_secret)
+type BankCredentialsRequest struct {
+	Login    string `json:"login" example:"team014"`
+	Password string `json:"password"`
+}
@@ -28,6 +28,7 @@ type ConsentResponse struct {
 	CreationDateTime     *time.Time `json:"creation_datetime,omitempty"`
 	StatusUpdateDateTime *time.Time `json:"status_update_datetime,omitempty"`
 	ExpirationDateTime   *time.Time `json:"expiration_datetime,omitempty"`
+	RevokedAt            *time.Time `json:"revoked_at,omitempty"`
 
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
@@ -0,0 +1,70 @@
+// internal/http-server/dto/fx.go
+
+package dto
+
+import (
+	"multibank/backend/internal/domain"
+	"strconv"
+)
+
+type NetWorthResponse struct {
+	BaseCurrency string                    `json:"base_currency" example:"RUB"`
+	Total        string                    `json:"total" example:"152300.50"` // sum of included accounts in base_currency
+	Accounts     []NetWorthAccountResponse `json:"accounts"`
+	Rates        []NetWorthRateResponse    `json:"rates"`   // rate and date used for each currency
+	Missing      []string                  `json:"missing"` // currencies without a known rate (not in total)
+}
+
+type NetWorthAccountResponse struct {
+	AccountID   string `json:"account_id"`
+	BankCode    string `json:"bank_code"`
+	Nickname    string `json:"nickname"`
+	BalanceType string `json:"balance_type" example:"InterimAvailable"`
+	Amount      string `json:"amount" example:"1000.00"` // in the account currency
+	Currency    string `json:"currency" example:"USD"`
+	Converted   string `json:"converted" example:"81234.50"` // in base_currency
+	Included    bool   `json:"included"`
+}
+
+type NetWorthRateResponse struct {
+	Currency string  `json:"currency" example:"USD"`
+	Rate     float64 `json:"rate" example:"81.2345"` // base_currency for 1 unit of currency
+	Date     string  `json:"date" example:"2025-10-17"`
+}
+
+func NetWorthResponseFromDomain(d domain.NetWorth) NetWorthResponse {
+	out := NetWorthResponse{
+		BaseCurrency: d.BaseCurrency,
+		Total:        formatMoney(d.Total),
+		Accounts:     make([]NetWorthAccountResponse, 0, len(d.Accounts)),
+		Rates:        make([]NetWorthRateResponse, 0, len(d.Rates)),
+		Missing:      d.Missing,
+	}
+	if out.Missing == nil {
+		out.Missing = []string{}
+	}
+	for _, a := range d.Accounts {
+		out.Accounts = append(out.Accounts, NetWorthAccountResponse{
+			AccountID:   a.AccountID,
+			BankCode:    a.BankCode,
+			Nickname:    a.Nickname,
+			BalanceType: a.BalanceType,
+			Amount:      formatMoney(a.Amount),
+			Currency:    a.Currency,
+			Converted:   formatMoney(a.Converted),
+			Included:    a.Included,
+		})
+	}
+	for _, r := range d.Rates {
+		out.Rates = append(out.Rates, NetWorthRateResponse{
+			Currency: r.Currency,
+			Rate:     r.Rate,
+			Date:     r.Date.Format("2006-01-02"),
+		})
+	}
+	return out
+}
+
+func formatMoney(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
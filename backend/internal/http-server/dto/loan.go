@@ -0,0 +1,59 @@
+// internal/http-server/dto/loan.go
+package dto
+
+import "multibank/backend/internal/domain"
+
+type SchedulePaymentResponse struct {
+	Month     int     `json:"month" example:"1"`
+	Payment   float64 `json:"payment" example:"9025.83"`
+	Interest  float64 `json:"interest" example:"1250"`
+	Principal float64 `json:"principal" example:"7775.83"`
+	Balance   float64 `json:"balance" example:"92224.17"` // debt left after the payment
+}
+
+type PaymentScheduleResponse struct {
+	Product      ProductResponse           `json:"product"`
+	Type         domain.ScheduleType       `json:"type" example:"annuity"` // annuity | differentiated
+	Amount       float64                   `json:"amount"`
+	TermMonths   int                       `json:"term_months"`
+	Payments     []SchedulePaymentResponse `json:"payments"`
+	TotalPayment float64                   `json:"total_payment"`
+	Overpayment  float64                   `json:"overpayment"` // total interest
+}
+
+type LoanCompareRequest struct {
+	Amount     float64 `json:"amount" example:"300000"`
+	TermMonths int     `json:"term_months" example:"24"`
+	Currency   string  `json:"currency,omitempty" example:"RUB"` // RUB if empty
+}
+
+type ScheduleSummaryResponse struct {
+	FirstPayment float64 `json:"first_payment"`
+	LastPayment  float64 `json:"last_payment"`
+	TotalPayment float64 `json:"total_payment"`
+	Overpayment  float64 `json:"overpayment"`
+}
+
+type LoanOfferResponse struct {
+	Rank    int             `json:"rank" example:"1"` // 1 = the cheapest
+	Product ProductResponse `json:"product"`
+
+	Annuity        ScheduleSummaryResponse `json:"annuity"`
+	Differentiated ScheduleSummaryResponse `json:"differentiated"`
+}
+
+type LoanCompareResponse struct {
+	Amount     float64             `json:"amount"`
+	TermMonths int                 `json:"term_months"`
+	Currency   string              `json:"currency"`
+	Offers     []LoanOfferResponse `json:"offers"`
+}
+
+func ScheduleSummaryResponseFromDomain(s domain.ScheduleSummary) ScheduleSummaryResponse {
+	return ScheduleSummaryResponse{
+		FirstPayment: s.FirstPayment,
+		LastPayment:  s.LastPayment,
+		TotalPayment: s.TotalPayment,
+		Overpayment:  s.Overpayment,
+	}
+}
@@ -0,0 +1,119 @@
+// internal/http-server/dto/payment.go
+package dto
+
+import (
+	"multibank/backend/internal/domain"
+	"time"
+)
+
+type PaymentConsentCreateRequest struct {
+	BankID           *int64 `json:"bank_id,omitempty"`                   // optional
+	AccountID        string `json:"account_id" example:"acc-1010"`       // debtor account of the user
+	Amount           string `json:"amount" example:"1500.00"`            // > 0
+	Currency         string `json:"currency,omitempty" example:"RUB"`    // default: account currency
+	CreditorAccount  string `json:"creditor_account" example:"40817810"` // account number of the recipient
+	CreditorName     string `json:"creditor_name,omitempty" example:"Ivan Petrov"`
+	CreditorBankCode string `json:"creditor_bank_code,omitempty" example:"sbank"` // empty = the same bank
+	Reference        string `json:"reference,omitempty" example:"Rent for October"`
+}
+
+type PaymentConsentResponse struct {
+	ID           int64                `json:"id"`
+	BankCode     string               `json:"bank_code"`
+	ClientID     string               `json:"client_id"`
+	RequestID    string               `json:"request_id"`
+	ConsentID    *string              `json:"consent_id,omitempty"`
+	Status       domain.ConsentStatus `json:"status"`
+	AutoApproved *bool                `json:"auto_approved,omitempty"`
+	ConsentType  string               `json:"consent_type" example:"single_use"`
+
+	Amount           string `json:"amount"`
+	Currency         string `json:"currency"`
+	DebtorAccount    string `json:"debtor_account"`
+	CreditorAccount  string `json:"creditor_account"`
+	CreditorName     string `json:"creditor_name,omitempty"`
+	CreditorBankCode string `json:"creditor_bank_code,omitempty"`
+	Reference        string `json:"reference,omitempty"`
+
+	CreationDateTime     *time.Time `json:"creation_datetime,omitempty"`
+	StatusUpdateDateTime *time.Time `json:"status_update_datetime,omitempty"`
+	ExpirationDateTime   *time.Time `json:"expiration_datetime,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type PaymentCreateRequest struct {
+	PaymentConsentID int64  `json:"payment_consent_id" example:"1"` // id from POST /payments/consents
+	Comment          string `json:"comment,omitempty"`
+}
+
+type PaymentResponse struct {
+	ID               int64                `json:"id"`
+	BankCode         string               `json:"bank_code"`
+	PaymentConsentID int64                `json:"payment_consent_id"`
+	PaymentID        string               `json:"payment_id"`
+	Status           domain.PaymentStatus `json:"status" example:"AcceptedSettlementInProcess"`
+	Final            bool                 `json:"final"` // the status will not change anymore
+
+	Amount           string `json:"amount"`
+	Currency         string `json:"currency"`
+	DebtorAccount    string `json:"debtor_account"`
+	CreditorAccount  string `json:"creditor_account"`
+	CreditorName     string `json:"creditor_name,omitempty"`
+	CreditorBankCode string `json:"creditor_bank_code,omitempty"`
+	Comment          string `json:"comment,omitempty"`
+
+	CreationDateTime     *time.Time `json:"creation_datetime,omitempty"`
+	StatusUpdateDateTime *time.Time `json:"status_update_datetime,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func PaymentConsentResponseFromDomain(c domain.PaymentConsent) PaymentConsentResponse {
+	return PaymentConsentResponse{
+		ID:                   c.ID,
+		BankCode:             c.BankCode,
+		ClientID:             c.ClientID,
+		RequestID:            c.RequestID,
+		ConsentID:            c.ConsentID,
+		Status:               c.Status,
+		AutoApproved:         c.AutoApproved,
+		ConsentType:          c.ConsentType,
+		Amount:               c.Amount,
+		Currency:             c.Currency,
+		DebtorAccount:        c.DebtorAccount,
+		CreditorAccount:      c.CreditorAccount,
+		CreditorName:         c.CreditorName,
+		CreditorBankCode:     c.CreditorBankCode,
+		Reference:            c.Reference,
+		CreationDateTime:     c.CreationDateTime,
+		StatusUpdateDateTime: c.StatusUpdateDateTime,
+		ExpirationDateTime:   c.ExpirationDateTime,
+		CreatedAt:            c.CreatedAt,
+		UpdatedAt:            c.UpdatedAt,
+	}
+}
+
+func PaymentResponseFromDomain(p domain.Payment) PaymentResponse {
+	return PaymentResponse{
+		ID:                   p.ID,
+		BankCode:             p.BankCode,
+		PaymentConsentID:     p.PaymentConsentID,
+		PaymentID:            p.PaymentID,
+		Status:               p.Status,
+		Final:                p.Status.IsFinal(),
+		Amount:               p.Amount,
+		Currency:             p.Currency,
+		DebtorAccount:        p.DebtorAccount,
+		CreditorAccount:      p.CreditorAccount,
+		CreditorName:         p.CreditorName,
+		CreditorBankCode:     p.CreditorBankCode,
+		Comment:              p.Comment,
+		CreationDateTime:     p.CreationDateTime,
+		StatusUpdateDateTime: p.StatusUpdateDateTime,
+		CreatedAt:            p.CreatedAt,
+		UpdatedAt:            p.UpdatedAt,
+	}
+}
@@ -2,6 +2,7 @@
 package dto
 
 import (
+	"multibank/backend/internal/domain"
 	"time"
 )
 
@@ -20,5 +21,64 @@ type ProductResponse struct {
 	BankName  string    `json:"bank_name"`
 	FetchedAt time.Time `json:"fetched_at"`
 
-	IsRecommended bool `json:"is_recommended"`
+	BankHealth domain.BankHealth `json:"bank_health" example:"ok"` // ok | down | unknown
+
+	IsRecommended        bool   `json:"is_recommended"`
+	RecommendationReason string `json:"recommendation_reason,omitempty"` // why the product is recommended to the user
+}
+
+func ProductResponseFromDomain(p domain.Product) ProductResponse {
+	return ProductResponse{
+		ProductID:     p.ProductID,
+		ProductType:   p.ProductType,
+		ProductName:   p.ProductName,
+		Description:   p.Description,
+		InterestRate:  p.InterestRate,
+		MinAmount:     p.MinAmount,
+		MaxAmount:     p.MaxAmount,
+		TermMonths:    p.TermMonths,
+		BankID:        p.BankID,
+		BankCode:      p.BankCode,
+		BankName:      p.BankName,
+		FetchedAt:     p.FetchedAt,
+		BankHealth:    p.BankHealth,
+		IsRecommended: p.IsRecommended,
+
+		RecommendationReason: p.RecommendationReason,
+	}
+}
+
+type ProductSourceResponse struct {
+	BankID        int64             `json:"bank_id"`
+	BankCode      string            `json:"bank_code"`
+	BankName      string            `json:"bank_name"`
+	Health        domain.BankHealth `json:"health" example:"ok"` // ok | down | unknown
+	LastAttemptAt *time.Time        `json:"last_attempt_at,omitempty"`
+	LastSuccessAt *time.Time        `json:"last_success_at,omitempty"`
+	LastError     string            `json:"last_error,omitempty"`
+	Failures      int               `json:"failures"` // consecutive failed fetches
+	ProductsCount int               `json:"products_count"`
+}
+
+type DepositCompareRequest struct {
+	Amount     float64 `json:"amount" example:"100000"`
+	TermMonths int     `json:"term_months" example:"12"`
+	Currency   string  `json:"currency,omitempty" example:"RUB"` // RUB if empty
+}
+
+type DepositOfferResponse struct {
+	Rank    int             `json:"rank" example:"1"` // 1 = the best payout
+	Product ProductResponse `json:"product"`
+
+	SimpleInterest      float64 `json:"simple_interest" example:"9000"` // interest paid at the end of the term
+	SimplePayout        float64 `json:"simple_payout" example:"109000"`
+	CapitalizedInterest float64 `json:"capitalized_interest" example:"9380.69"` // interest capitalized monthly
+	CapitalizedPayout   float64 `json:"capitalized_payout" example:"109380.69"`
+}
+
+type DepositCompareResponse struct {
+	Amount     float64                `json:"amount"`
+	TermMonths int                    `json:"term_months"`
+	Currency   string                 `json:"currency"`
+	Offers     []DepositOfferResponse `json:"offers"`
 }
@@ -0,0 +1,51 @@
+// internal/http-server/dto/product_history.go
+package dto
+
+import (
+	"multibank/backend/internal/domain"
+	"time"
+)
+
+type ProductChangeResponse struct {
+	Field     domain.ProductField `json:"field" example:"interest_rate"` // interest_rate | min_amount | max_amount | term_months
+	OldValue  float64             `json:"old_value" example:"8.5"`
+	NewValue  float64             `json:"new_value" example:"9"`
+	ChangedAt time.Time           `json:"changed_at"`
+}
+
+type ProductHistoryResponse struct {
+	BankCode  string                  `json:"bank_code"`
+	ProductID string                  `json:"product_id"`
+	Changes   []ProductChangeResponse `json:"changes"`
+}
+
+type ProductSubscriptionResponse struct {
+	BankCode    string    `json:"bank_code"`
+	ProductID   string    `json:"product_id"`
+	ProductName string    `json:"product_name,omitempty"` // empty if the bank does not offer the product anymore
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type NotificationResponse struct {
+	ID        int64                   `json:"id"`
+	Kind      domain.NotificationKind `json:"kind" example:"product_rate_changed"`
+	Title     string                  `json:"title"`
+	Message   string                  `json:"message,omitempty"`
+	BankCode  string                  `json:"bank_code,omitempty"`
+	ProductID string                  `json:"product_id,omitempty"`
+	CreatedAt time.Time               `json:"created_at"`
+	ReadAt    *time.Time              `json:"read_at,omitempty"`
+}
+
+func NotificationResponseFromDomain(n domain.Notification) NotificationResponse {
+	return NotificationResponse{
+		ID:        n.ID,
+		Kind:      n.Kind,
+		Title:     n.Title,
+		Message:   n.Message,
+		BankCode:  n.BankCode,
+		ProductID: n.ProductID,
+		CreatedAt: n.CreatedAt,
+		ReadAt:    n.ReadAt,
+	}
+}
@@ -1,7 +1,10 @@
 // internal/http-server/dto/recommended.go
 package dto
 
-import "time"
+import (
+	"multibank/backend/internal/domain"
+	"time"
+)
 
 type RecommendedRule struct {
 	ProductID   string    `json:"product_id"`
@@ -15,3 +18,88 @@ type RecommendedUpsertRequest struct {
 	BankCode    string `json:"bank_code" example:"abank"`
 	ProductType string `json:"product_type" example:"credit_card"`
 }
+
+type RecommendationRuleRequest struct {
+	Name string `json:"name" example:"Deposits for pensioners"`
+
+	// target, empty = any
+	BankCode    string `json:"bank_code,omitempty" example:"abank"`
+	ProductID   string `json:"product_id,omitempty"`
+	ProductType string `json:"product_type,omitempty" example:"deposit"`
+
+	// conditions, null = not checked
+	MinAge         *int                  `json:"min_age,omitempty" example:"60"`
+	MaxAge         *int                  `json:"max_age,omitempty"`
+	MinBalance     *float64              `json:"min_balance,omitempty" example:"100000"` // total balance in RUB
+	MaxBalance     *float64              `json:"max_balance,omitempty"`
+	BankConnection domain.BankConnection `json:"bank_connection,omitempty" example:"not_connected"` // connected | not_connected
+
+	Priority   int        `json:"priority" example:"10"`
+	ActiveFrom *time.Time `json:"active_from,omitempty"`
+	ActiveTo   *time.Time `json:"active_to,omitempty"`
+	Reason     string     `json:"reason,omitempty" example:"Higher rate for clients over 60"`
+}
+
+func (r RecommendationRuleRequest) ToDomain() domain.RecommendationRule {
+	return domain.RecommendationRule{
+		Name:           r.Name,
+		BankCode:       r.BankCode,
+		ProductID:      r.ProductID,
+		ProductType:    r.ProductType,
+		MinAge:         r.MinAge,
+		MaxAge:         r.MaxAge,
+		MinBalance:     r.MinBalance,
+		MaxBalance:     r.MaxBalance,
+		BankConnection: r.BankConnection,
+		Priority:       r.Priority,
+		ActiveFrom:     r.ActiveFrom,
+		ActiveTo:       r.ActiveTo,
+		Reason:         r.Reason,
+	}
+}
+
+type RecommendationRuleResponse struct {
+	ID int64 `json:"id"`
+	RecommendationRuleRequest
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func RecommendationRuleResponseFromDomain(r domain.RecommendationRule) RecommendationRuleResponse {
+	return RecommendationRuleResponse{
+		ID: r.ID,
+		RecommendationRuleRequest: RecommendationRuleRequest{
+			Name:           r.Name,
+			BankCode:       r.BankCode,
+			ProductID:      r.ProductID,
+			ProductType:    r.ProductType,
+			MinAge:         r.MinAge,
+			MaxAge:         r.MaxAge,
+			MinBalance:     r.MinBalance,
+			MaxBalance:     r.MaxBalance,
+			BankConnection: r.BankConnection,
+			Priority:       r.Priority,
+			ActiveFrom:     r.ActiveFrom,
+			ActiveTo:       r.ActiveTo,
+			Reason:         r.Reason,
+		},
+		CreatedAt: r.CreatedAt,
+		UpdatedAt: r.UpdatedAt,
+	}
+}
+
+type RecommendationResponse struct {
+	Rank    int             `json:"rank" example:"1"`
+	Reason  string          `json:"reason,omitempty"` // empty for products of the static recommended list
+	Product ProductResponse `json:"product"`
+}
+
+type RecommendationStatResponse struct {
+	Day         string  `json:"day" example:"2025-11-01"`
+	RuleID      *int64  `json:"rule_id,omitempty"` // empty for the static recommended list
+	RuleName    string  `json:"rule_name,omitempty"`
+	BankCode    string  `json:"bank_code" example:"abank"`
+	Impressions int     `json:"impressions"`
+	Clicks      int     `json:"clicks"`
+	CTR         float64 `json:"ctr" example:"0.042"` // clicks / impressions
+}
@@ -0,0 +1,54 @@
+// internal/http-server/dto/transfer.go
+package dto
+
+import (
+	"multibank/backend/internal/domain"
+	"time"
+)
+
+type TransferAccountRef struct {
+	BankID    *int64 `json:"bank_id,omitempty"`
+	AccountID string `json:"account_id" example:"acc-1010"`
+}
+
+type TransferCreateRequest struct {
+	Source TransferAccountRef `json:"source"`
+	Target TransferAccountRef `json:"target"`
+	Amount string             `json:"amount" example:"1500.00"`
+}
+
+type TransferResponse struct {
+	ID               int64                 `json:"id"`
+	SourceBankCode   string                `json:"source_bank_code"`
+	SourceAccountID  string                `json:"source_account_id"`
+	TargetBankCode   string                `json:"target_bank_code"`
+	TargetAccountID  string                `json:"target_account_id"`
+	Amount           string                `json:"amount"`
+	Currency         string                `json:"currency"`
+	Status           domain.TransferStatus `json:"status" example:"Processing"`
+	PaymentConsentID *int64                `json:"payment_consent_id,omitempty"`
+	PaymentID        *int64                `json:"payment_id,omitempty"`
+	Error            string                `json:"error,omitempty"`
+	CreatedAt        time.Time             `json:"created_at"`
+	UpdatedAt        time.Time             `json:"updated_at"`
+	CompletedAt      *time.Time            `json:"completed_at,omitempty"` // when the source bank settled the payment
+}
+
+func TransferResponseFromDomain(t domain.Transfer) TransferResponse {
+	return TransferResponse{
+		ID:               t.ID,
+		SourceBankCode:   t.SourceBankCode,
+		SourceAccountID:  t.SourceAccountID,
+		TargetBankCode:   t.TargetBankCode,
+		TargetAccountID:  t.TargetAccountID,
+		Amount:           t.Amount,
+		Currency:         t.Currency,
+		Status:           t.Status,
+		PaymentConsentID: t.PaymentConsentID,
+		PaymentID:        t.PaymentID,
+		Error:            t.Error,
+		CreatedAt:        t.CreatedAt,
+		UpdatedAt:        t.UpdatedAt,
+		CompletedAt:      t.CompletedAt,
+	}
+}
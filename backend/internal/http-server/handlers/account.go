@@ -2,18 +2,25 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"multibank/backend/internal/domain"
 	"multibank/backend/internal/http-server/dto"
 	httputils "multibank/backend/internal/http-server/utils"
+	"multibank/backend/internal/service/account"
 	authmw "multibank/backend/internal/service/auth/middleware"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 )
 
 type Account interface {
 	ListUserAccounts(ctx context.Context, userID int64, bankID *int64) ([]domain.AccountShort, error)
+	ListAccountTransactions(ctx context.Context, userID int64, bankID *int64, accountID string, f domain.TransactionFilter) (account.TransactionsPage, error)
+
+	SyncAccounts(ctx context.Context, workers int) (int, error)
+	SyncTransactions(ctx context.Context, workers int) (int, error)
 }
 
 type AccountsHandler struct {
@@ -23,12 +30,16 @@ type AccountsHandler struct {
 func RegisterAccountRoutes(r chi.Router, svc Account) {
 	h := &AccountsHandler{svc: svc}
 	r.Get("/", h.list) // /accounts?bank_id=...
+	r.Get("/{accountId}/transactions", h.transactions)
 }
 
 // list returns a list of user accounts aggregated from connected banks.
 // @Summary      List user accounts
 // @Description  Returns the list of accounts available for the authorized user, aggregated across all connected banks.
-// @Description  Each account includes nickname, status, subtype, opening date, and current balance (InterimAvailable).
+// @Description  Each account includes nickname, status, subtype, opening date, all balances reported by the bank
+// @Description  (signed, negative for Debit) and the primary balance (InterimAvailable if present) in amount/currency.
+// @Description  Data is served from the local store refreshed in background: last_synced_at shows the refresh time,
+// @Description  stale=true means the bank has not been reachable for a while.
 // @Tags         accounts
 // @Security     BearerAuth
 // @Produce      json
@@ -65,11 +76,164 @@ func (h *AccountsHandler) list(w http.ResponseWriter, r *http.Request) {
 			Status:         it.Status,
 			AccountSubType: it.AccountSubType,
 			OpeningDate:    it.OpeningDate,
+			Identification: it.Identification,
 			Amount:         it.Amount,
 			Currency:       it.Currency,
+			BalanceType:    it.BalanceType,
+			Balances:       toBalanceResponses(it.Balances),
+			BankID:         it.BankID,
 			BankCode:       it.BankCode,
 			ClientID:       it.ClientID,
+			LastSyncedAt:   it.LastSyncedAt,
+			Stale:          it.Stale,
 		})
 	}
 	httputils.WriteJSON(w, http.StatusOK, out)
 }
+
+func toBalanceResponses(items []domain.Balance) []dto.BalanceResponse {
+	out := make([]dto.BalanceResponse, 0, len(items))
+	for _, b := range items {
+		var lines []dto.CreditLineResponse
+		for _, cl := range b.CreditLines {
+			lines = append(lines, dto.CreditLineResponse{
+				Included: cl.Included,
+				Type:     cl.Type,
+				Amount:   cl.Amount,
+				Currency: cl.Currency,
+			})
+		}
+		out = append(out, dto.BalanceResponse{
+			Type:                 b.Type,
+			Amount:               b.Amount,
+			Currency:             b.Currency,
+			CreditDebitIndicator: b.CreditDebitIndicator,
+			DateTime:             b.DateTime,
+			CreditLines:          lines,
+		})
+	}
+	return out
+}
+
+// transactions returns transaction history of one user account.
+// @Summary      List account transactions
+// @Description  Returns transaction history of the account from the local store (synced from the bank in background).
+// @Description  Dates accept YYYY-MM-DD or RFC3339. Newest transactions first.
+// @Tags         accounts
+// @Security     BearerAuth
+// @Produce      json
+// @Param        accountId  path      string  true   "Account ID (from /accounts)"
+// @Param        bank_id    query     int64   false  "Bank ID of the account (optional)"
+// @Param        from       query     string  false  "Booking date from (YYYY-MM-DD or RFC3339)"
+// @Param        to         query     string  false  "Booking date to (YYYY-MM-DD or RFC3339)"
+// @Param        page       query     int     false  "Page number (from 1)"
+// @Param        limit      query     int     false  "Page size (default 50, max 500)"
+// @Success      200        {object}  dto.TransactionsPageResponse
+// @Failure      400        {object}  dto.ErrorResponse
+// @Failure      401        {object}  dto.ErrorResponse
+// @Failure      404        {object}  dto.ErrorResponse
+// @Failure      500        {object}  dto.ErrorResponse
+// @Router       /accounts/{accountId}/transactions [get]
+func (h *AccountsHandler) transactions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authmw.UserIDFromContext(r.Context())
+	if !ok {
+		httputils.WriteError(w, http.StatusUnauthorized, "missing user in context")
+		return
+	}
+
+	accountID := chi.URLParam(r, "accountId")
+	if accountID == "" {
+		httputils.WriteError(w, http.StatusBadRequest, "invalid account id")
+		return
+	}
+
+	q := r.URL.Query()
+
+	var bankID *int64
+	if s := q.Get("bank_id"); s != "" {
+		if v, err := strconv.ParseInt(s, 10, 64); err == nil {
+			bankID = &v
+		}
+	}
+
+	var f domain.TransactionFilter
+	var err error
+	if f.From, err = parseDateParam(q.Get("from"), false); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "invalid from")
+		return
+	}
+	if f.To, err = parseDateParam(q.Get("to"), true); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "invalid to")
+		return
+	}
+	if f.From != nil && f.To != nil && f.To.Before(*f.From) {
+		httputils.WriteError(w, http.StatusBadRequest, "to is before from")
+		return
+	}
+	if s := q.Get("page"); s != "" {
+		if f.Page, err = strconv.Atoi(s); err != nil || f.Page <= 0 {
+			httputils.WriteError(w, http.StatusBadRequest, "invalid page")
+			return
+		}
+	}
+	if s := q.Get("limit"); s != "" {
+		if f.Limit, err = strconv.Atoi(s); err != nil || f.Limit <= 0 {
+			httputils.WriteError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+	}
+
+	page, err := h.svc.ListAccountTransactions(r.Context(), userID, bankID, accountID, f)
+	if err != nil {
+		if errors.Is(err, account.ErrAccountNotFound) {
+			httputils.WriteError(w, http.StatusNotFound, "account not found")
+			return
+		}
+		httputils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	out := dto.TransactionsPageResponse{
+		Transactions: make([]dto.TransactionResponse, 0, len(page.Transactions)),
+		Page:         page.Page,
+		Limit:        page.Limit,
+		Total:        page.Total,
+		TotalPages:   page.TotalPages,
+		LastSyncedAt: page.LastSyncedAt,
+	}
+	for _, t := range page.Transactions {
+		out.Transactions = append(out.Transactions, dto.TransactionResponse{
+			TransactionID:        t.TransactionID,
+			AccountID:            t.AccountID,
+			Amount:               t.Amount,
+			Currency:             t.Currency,
+			CreditDebitIndicator: t.CreditDebitIndicator,
+			Status:               t.Status,
+			BookingDateTime:      t.BookingDateTime,
+			ValueDateTime:        t.ValueDateTime,
+			Info:                 t.Info,
+			BankTransactionCode:  t.BankTransactionCode,
+			BankCode:             t.BankCode,
+		})
+	}
+	httputils.WriteJSON(w, http.StatusOK, out)
+}
+
+// parseDateParam accepts YYYY-MM-DD or RFC3339, blank => nil.
+// endOfDay moves a date-only value to the end of that day (for inclusive "to").
+func parseDateParam(s string, endOfDay bool) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		if endOfDay {
+			t = t.Add(24*time.Hour - time.Nanosecond)
+		}
+		return &t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
@@ -0,0 +1,172 @@
+// internal/http-server/handlers/agreement.go
+
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/http-server/dto"
+	httputils "multibank/backend/internal/http-server/utils"
+	"multibank/backend/internal/service/agreement"
+	authmw "multibank/backend/internal/service/auth/middleware"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Agreement interface {
+	Apply(ctx context.Context, in agreement.ApplyInput) (domain.ProductAgreement, error)
+	List(ctx context.Context, userID int64, bankID *int64) ([]domain.ProductAgreement, error)
+	Close(ctx context.Context, userID, id int64) (domain.ProductAgreement, error)
+}
+
+// Apply godoc
+// @Summary      Open a bank product
+// @Description  Opens the product (deposit, card, loan) for the current user through the product-agreements flow.
+// @Description  The bank must be connected (account consent with client_id). A product agreement consent is requested
+// @Description  on the first call; if the bank does not approve it automatically, 409 is returned until it is approved.
+// @Tags         products
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        bank       path      string                     true   "Bank code"
+// @Param        productId  path      string                     true   "Product ID in the bank"
+// @Param        input      body      dto.AgreementApplyRequest  false  "Amount, term and source account"
+// @Success      201        {object}  dto.AgreementResponse
+// @Failure      400        {object}  dto.ErrorResponse
+// @Failure      401        {object}  dto.ErrorResponse
+// @Failure      404        {object}  dto.ErrorResponse
+// @Failure      409        {object}  dto.ErrorResponse
+// @Failure      502        {object}  dto.ErrorResponse
+// @Router       /products/{bank}/{productId}/apply [post]
+func (h *ProductHandler) Apply(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authmw.UserIDFromContext(r.Context())
+	if !ok {
+		httputils.WriteError(w, http.StatusUnauthorized, "missing user in context")
+		return
+	}
+
+	// the body is optional (cards are opened without amount)
+	var req dto.AgreementApplyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		httputils.WriteError(w, http.StatusBadRequest, "bad json: "+err.Error())
+		return
+	}
+
+	a, err := h.agreements.Apply(r.Context(), agreement.ApplyInput{
+		UserID:          userID,
+		BankCode:        chi.URLParam(r, "bank"),
+		ProductID:       chi.URLParam(r, "productId"),
+		Amount:          req.Amount,
+		TermMonths:      req.TermMonths,
+		SourceAccountID: req.SourceAccountID,
+	})
+	if err != nil {
+		writeAgreementError(w, err)
+		return
+	}
+	httputils.WriteJSON(w, http.StatusCreated, dto.AgreementResponseFromDomain(a))
+}
+
+// ListAgreements godoc
+// @Summary      My product agreements
+// @Description  Returns products opened by the current user; agreements are refreshed from connected banks first.
+// @Tags         me
+// @Security     BearerAuth
+// @Produce      json
+// @Param        bank_id  query     int  false  "Filter by bank id"
+// @Success      200      {array}   dto.AgreementResponse
+// @Failure      400      {object}  dto.ErrorResponse
+// @Failure      401      {object}  dto.ErrorResponse
+// @Failure      500      {object}  dto.ErrorResponse
+// @Router       /me/agreements [get]
+func (h *MeHandler) ListAgreements(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authmw.UserIDFromContext(r.Context())
+	if !ok {
+		httputils.WriteError(w, http.StatusUnauthorized, "missing user in context")
+		return
+	}
+
+	var bankID *int64
+	if v := r.URL.Query().Get("bank_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			httputils.WriteError(w, http.StatusBadRequest, "invalid bank_id")
+			return
+		}
+		bankID = &id
+	}
+
+	items, err := h.agreements.List(r.Context(), userID, bankID)
+	if err != nil {
+		httputils.WriteError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	out := make([]dto.AgreementResponse, 0, len(items))
+	for _, it := range items {
+		out = append(out, dto.AgreementResponseFromDomain(it))
+	}
+	httputils.WriteJSON(w, http.StatusOK, out)
+}
+
+// CloseAgreement godoc
+// @Summary      Close product agreement
+// @Description  Closes the agreement in the bank; it is kept in the list with status closed.
+// @Tags         me
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      int64  true  "Agreement ID (internal)"
+// @Success      200  {object}  dto.AgreementResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      409  {object}  dto.ErrorResponse
+// @Failure      502  {object}  dto.ErrorResponse
+// @Router       /me/agreements/{id} [delete]
+func (h *MeHandler) CloseAgreement(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authmw.UserIDFromContext(r.Context())
+	if !ok {
+		httputils.WriteError(w, http.StatusUnauthorized, "missing user in context")
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	a, err := h.agreements.Close(r.Context(), userID, id)
+	if err != nil {
+		writeAgreementError(w, err)
+		return
+	}
+	httputils.WriteJSON(w, http.StatusOK, dto.AgreementResponseFromDomain(a))
+}
+
+// writeAgreementError maps agreement service errors to HTTP statuses (other errors are bank failures)
+func writeAgreementError(w http.ResponseWriter, err error) {
+	statuses := []struct {
+		err  error
+		code int
+	}{
+		{agreement.ErrInvalidProduct, http.StatusBadRequest},
+		{agreement.ErrInvalidAmount, http.StatusBadRequest},
+		{agreement.ErrInvalidTerm, http.StatusBadRequest},
+		{agreement.ErrBankNotFound, http.StatusNotFound},
+		{agreement.ErrAgreementNotFound, http.StatusNotFound},
+		{agreement.ErrBankNotConnected, http.StatusConflict},
+		{agreement.ErrConsentNotAuthorized, http.StatusConflict},
+		{agreement.ErrAgreementClosed, http.StatusConflict},
+	}
+	for _, s := range statuses {
+		if errors.Is(err, s.err) {
+			httputils.WriteError(w, s.code, s.err.Error())
+			return
+		}
+	}
+	httputils.WriteError(w, http.StatusBadGateway, err.Error())
+}
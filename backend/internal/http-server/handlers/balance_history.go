@@ -0,0 +1,71 @@
+// internal/http-server/handlers/balance_history.go
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/http-server/dto"
+	httputils "multibank/backend/internal/http-server/utils"
+	authmw "multibank/backend/internal/service/auth/middleware"
+	balancesvc "multibank/backend/internal/service/balance"
+	"net/http"
+	"time"
+)
+
+type BalanceHistory interface {
+	History(ctx context.Context, userID int64, from, to *time.Time, interval domain.HistoryInterval, base string) (domain.BalanceHistory, error)
+}
+
+// BalanceHistory godoc
+// @Summary      Balances over time
+// @Description  Returns balance series built from daily snapshots taken by the background account sync:
+// @Description  per account (account currency), per bank and total (converted to base with the rate of each point).
+// @Description  Every point is the balance at the end of the period; the period starts at point date.
+// @Tags         me
+// @Produce      json
+// @Security     BearerAuth
+// @Param        from      query     string  false  "From date (YYYY-MM-DD), default to-29 days"
+// @Param        to        query     string  false  "To date (YYYY-MM-DD), default today"
+// @Param        interval  query     string  false  "day | week | month (default day)"
+// @Param        base      query     string  false  "Currency of bank and total series, default RUB"
+// @Success      200       {object}  dto.BalanceHistoryResponse
+// @Failure      400       {object}  dto.ErrorResponse
+// @Failure      401       {object}  dto.ErrorResponse
+// @Failure      500       {object}  dto.ErrorResponse
+// @Router       /me/balances/history [get]
+func (h *MeHandler) BalanceHistory(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authmw.UserIDFromContext(r.Context())
+	if !ok {
+		httputils.WriteError(w, http.StatusUnauthorized, "missing user in context")
+		return
+	}
+
+	q := r.URL.Query()
+	from, err := parseDateParam(q.Get("from"), false)
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "invalid from")
+		return
+	}
+	to, err := parseDateParam(q.Get("to"), false)
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "invalid to")
+		return
+	}
+
+	res, err := h.balances.History(r.Context(), userID, from, to, domain.HistoryInterval(q.Get("interval")), q.Get("base"))
+	if err != nil {
+		switch {
+		case errors.Is(err, balancesvc.ErrInvalidInterval):
+			httputils.WriteError(w, http.StatusBadRequest, "interval must be day, week or month")
+		case errors.Is(err, balancesvc.ErrInvalidRange):
+			httputils.WriteError(w, http.StatusBadRequest, "invalid date range")
+		default:
+			httputils.WriteError(w, http.StatusInternalServerError, "internal error")
+		}
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, dto.BalanceHistoryResponseFromDomain(res))
+}
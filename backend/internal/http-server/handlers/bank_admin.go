@@ -0,0 +1,305 @@
+// internal/http-server/handlers/bank_admin.go
+
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/http-server/dto"
+	httputils "multibank/backend/internal/http-server/utils"
+	"multibank/backend/internal/service/bank"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// BankBreakers — circuit breakers of the openbanking transport
+type BankBreakers interface {
+	Breakers() []domain.BankBreaker
+}
+
+// BankAdmin — management of banks and their credentials
+type BankAdmin interface {
+	ListAll(ctx context.Context) ([]domain.Bank, error)
+	CreateBank(ctx context.Context, b domain.Bank) (domain.Bank, error)
+	UpdateBank(ctx context.Context, b domain.Bank) (domain.Bank, error)
+	SetEnabled(ctx context.Context, id int64, enabled bool) (domain.Bank, error)
+	RotateCredentials(ctx context.Context, id int64, login, password string) (domain.Bank, error)
+	RefreshToken(ctx context.Context, id int64) (time.Time, error)
+	TokenStatus(ctx context.Context, bankID int64) (bool, time.Time, error)
+}
+
+type BankAdminHandler struct {
+	svc      BankAdmin
+	breakers BankBreakers
+}
+
+// RegisterBankAdminRoutes registers admin handlers of banks
+// JWT is attached in server.go to the /admin/banks
+func RegisterBankAdminRoutes(r chi.Router, svc BankAdmin, breakers BankBreakers) {
+	h := &BankAdminHandler{svc: svc, breakers: breakers}
+	r.Get("/", h.ListBanks)
+	r.Post("/", h.CreateBank)
+	r.Put("/{id}", h.UpdateBank)
+	r.Post("/{id}/enable", h.EnableBank)
+	r.Post("/{id}/disable", h.DisableBank)
+	r.Put("/{id}/credentials", h.RotateCredentials)
+	r.Post("/{id}/token", h.RefreshToken)
+	r.Get("/breakers", h.ListBreakers)
+}
+
+// ListBanks godoc
+// @Summary      List all banks
+// @Description  Returns all banks, disabled ones too, with their settings and token status. Credentials are never returned.
+// @Tags         admin/banks
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {array}   dto.BankAdminResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Router       /admin/banks [get]
+func (h *BankAdminHandler) ListBanks(w http.ResponseWriter, r *http.Request) {
+	items, err := h.svc.ListAll(r.Context())
+	if err != nil {
+		httputils.WriteError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	out := make([]dto.BankAdminResponse, 0, len(items))
+	for _, b := range items {
+		out = append(out, h.bankResponse(r.Context(), b))
+	}
+	httputils.WriteJSON(w, http.StatusOK, out)
+}
+
+// CreateBank godoc
+// @Summary      Create bank
+// @Description  Adds a bank with the team credentials. Adapter selects the API dialect of the bank (sandbox by default).
+// @Tags         admin/banks
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        input  body      dto.BankCreateRequest  true  "Bank"
+// @Success      201    {object}  dto.BankAdminResponse
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      409    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Router       /admin/banks [post]
+func (h *BankAdminHandler) CreateBank(w http.ResponseWriter, r *http.Request) {
+	var req dto.BankCreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "bad json: "+err.Error())
+		return
+	}
+
+	b, err := h.svc.CreateBank(r.Context(), req.ToDomain())
+	if err != nil {
+		writeBankAdminError(w, err)
+		return
+	}
+	httputils.WriteJSON(w, http.StatusCreated, h.bankResponse(r.Context(), b))
+}
+
+// UpdateBank godoc
+// @Summary      Update bank
+// @Description  Replaces name, api_base_url, adapter and rate limit of the bank. The cached bank token is dropped.
+// @Tags         admin/banks
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int64                  true  "Bank ID"
+// @Param        input  body      dto.BankUpdateRequest  true  "Bank settings"
+// @Success      200    {object}  dto.BankAdminResponse
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Router       /admin/banks/{id} [put]
+func (h *BankAdminHandler) UpdateBank(w http.ResponseWriter, r *http.Request) {
+	id, ok := bankIDParam(w, r)
+	if !ok {
+		return
+	}
+	var req dto.BankUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "bad json: "+err.Error())
+		return
+	}
+
+	in := req.ToDomain()
+	in.ID = id
+	b, err := h.svc.UpdateBank(r.Context(), in)
+	if err != nil {
+		writeBankAdminError(w, err)
+		return
+	}
+	httputils.WriteJSON(w, http.StatusOK, h.bankResponse(r.Context(), b))
+}
+
+// EnableBank godoc
+// @Summary      Enable bank
+// @Tags         admin/banks
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      int64  true  "Bank ID"
+// @Success      200  {object}  dto.BankAdminResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Router       /admin/banks/{id}/enable [post]
+func (h *BankAdminHandler) EnableBank(w http.ResponseWriter, r *http.Request) {
+	h.setEnabled(w, r, true)
+}
+
+// DisableBank godoc
+// @Summary      Disable bank
+// @Description  A disabled bank is hidden from users and skipped by background jobs; its data is kept.
+// @Tags         admin/banks
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      int64  true  "Bank ID"
+// @Success      200  {object}  dto.BankAdminResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Router       /admin/banks/{id}/disable [post]
+func (h *BankAdminHandler) DisableBank(w http.ResponseWriter, r *http.Request) {
+	h.setEnabled(w, r, false)
+}
+
+func (h *BankAdminHandler) setEnabled(w http.ResponseWriter, r *http.Request, enabled bool) {
+	id, ok := bankIDParam(w, r)
+	if !ok {
+		return
+	}
+	b, err := h.svc.SetEnabled(r.Context(), id, enabled)
+	if err != nil {
+		writeBankAdminError(w, err)
+		return
+	}
+	httputils.WriteJSON(w, http.StatusOK, h.bankResponse(r.Context(), b))
+}
+
+// RotateCredentials godoc
+// @Summary      Rotate bank credentials
+// @Description  Replaces the team login and secret in the bank API and drops the cached bank token.
+// @Tags         admin/banks
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int64                       true  "Bank ID"
+// @Param        input  body      dto.BankCredentialsRequest  true  "Credentials"
+// @Success      200    {object}  dto.BankAdminResponse
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Router       /admin/banks/{id}/credentials [put]
+func (h *BankAdminHandler) RotateCredentials(w http.ResponseWriter, r *http.Request) {
+	id, ok := bankIDParam(w, r)
+	if !ok {
+		return
+	}
+	var req dto.BankCredentialsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "bad json: "+err.Error())
+		return
+	}
+
+	b, err := h.svc.RotateCredentials(r.Context(), id, req.Login, req.Password)
+	if err != nil {
+		writeBankAdminError(w, err)
+		return
+	}
+	httputils.WriteJSON(w, http.StatusOK, h.bankResponse(r.Context(), b))
+}
+
+// RefreshToken godoc
+// @Summary      Refresh bank token
+// @Description  Requests a new bank token even if the cached one is still valid.
+// @Tags         admin/banks
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      int64  true  "Bank ID"
+// @Success      200  {object}  dto.BankAuthorizeResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      502  {object}  dto.ErrorResponse
+// @Router       /admin/banks/{id}/token [post]
+func (h *BankAdminHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	id, ok := bankIDParam(w, r)
+	if !ok {
+		return
+	}
+	exp, err := h.svc.RefreshToken(r.Context(), id)
+	if err != nil {
+		writeBankAdminError(w, err)
+		return
+	}
+	httputils.WriteJSON(w, http.StatusOK, dto.BankAuthorizeResponse{
+		Status:       "authorized",
+		TokenExpires: exp,
+	})
+}
+
+func (h *BankAdminHandler) bankResponse(ctx context.Context, b domain.Bank) dto.BankAdminResponse {
+	out := dto.BankAdminResponseFromDomain(b)
+	out.Authorized, out.TokenExpires, _ = h.svc.TokenStatus(ctx, b.ID)
+	return out
+}
+
+func bankIDParam(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id <= 0 {
+		httputils.WriteError(w, http.StatusBadRequest, "invalid id")
+		return 0, false
+	}
+	return id, true
+}
+
+func writeBankAdminError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, bank.ErrInvalidBank):
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, bank.ErrBankNotFound):
+		httputils.WriteError(w, http.StatusNotFound, bank.ErrBankNotFound.Error())
+	case errors.Is(err, bank.ErrBankExists):
+		httputils.WriteError(w, http.StatusConflict, bank.ErrBankExists.Error())
+	case errors.Is(err, bank.ErrBankAuth):
+		httputils.WriteError(w, http.StatusBadGateway, bank.ErrBankAuth.Error())
+	default:
+		httputils.WriteError(w, http.StatusInternalServerError, "internal error")
+	}
+}
+
+// ListBreakers godoc
+// @Summary      Circuit breakers of bank APIs
+// @Description  Returns the circuit breaker state of banks whose calls have failed since start. While the circuit is open,
+// @Description  calls to the bank fail fast until retry_at, then a single probe call decides whether to close it. Admins only.
+// @Tags         admin/banks
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {array}   dto.BankBreakerResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Router       /admin/banks/breakers [get]
+func (h *BankAdminHandler) ListBreakers(w http.ResponseWriter, r *http.Request) {
+	items := h.breakers.Breakers()
+	out := make([]dto.BankBreakerResponse, 0, len(items))
+	for _, b := range items {
+		out = append(out, dto.BankBreakerResponse{
+			BankCode:  b.BankCode,
+			State:     b.State,
+			Failures:  b.Failures,
+			OpenedAt:  b.OpenedAt,
+			RetryAt:   b.RetryAt,
+			LastError: b.LastError,
+		})
+	}
+	httputils.WriteJSON(w, http.StatusOK, out)
+}
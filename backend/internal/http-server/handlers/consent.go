@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"multibank/backend/internal/domain"
 	"multibank/backend/internal/http-server/dto"
 	httputils "multibank/backend/internal/http-server/utils"
@@ -16,10 +17,10 @@ import (
 
 type Consent interface {
 	Request(ctx context.Context, in consent.CreateInput) (int64, error)
-	Refresh(ctx context.Context, id int64) (domain.AccountConsent, error) // мы сделали (domain.AccountConsent, error), ниже приведём к dto
-	Get(ctx context.Context, id int64) (domain.AccountConsent, error)
+	Refresh(ctx context.Context, userID, id int64) (domain.AccountConsent, error) // мы сделали (domain.AccountConsent, error), ниже приведём к dto
+	Get(ctx context.Context, userID, id int64) (domain.AccountConsent, error)
 	ListMine(ctx context.Context, userID int64, bankID *int64) ([]domain.AccountConsent, error)
-	Delete(ctx context.Context, id int64) error
+	Delete(ctx context.Context, userID, id int64) error
 
 	RefreshStale(ctx context.Context, batchLimit, workers int) (int, error)
 }
@@ -76,7 +77,7 @@ func (h *ConsentHandler) request(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	c, err := h.svc.Get(r.Context(), id)
+	c, err := h.svc.Get(r.Context(), userID, id)
 	if err != nil {
 		httputils.WriteError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -129,14 +130,25 @@ func (h *ConsentHandler) list(w http.ResponseWriter, r *http.Request) {
 // @Produce      json
 // @Param        id   path      int64  true  "Consent ID (internal)"
 // @Success      200  {object}  dto.ConsentResponse
+// @Failure      400  {object}  dto.ErrorResponse
 // @Failure      401  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
 // @Failure      500  {object}  dto.ErrorResponse
 // @Router       /consents/{id} [get]
 func (h *ConsentHandler) get(w http.ResponseWriter, r *http.Request) {
-	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
-	c, err := h.svc.Get(r.Context(), id)
+	userID, ok := authmw.UserIDFromContext(r.Context())
+	if !ok {
+		httputils.WriteError(w, http.StatusUnauthorized, "missing user in context")
+		return
+	}
+	id, ok := consentIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	c, err := h.svc.Get(r.Context(), userID, id)
 	if err != nil {
-		httputils.WriteError(w, http.StatusInternalServerError, err.Error())
+		writeConsentError(w, err)
 		return
 	}
 	httputils.WriteJSON(w, http.StatusOK, toConsentResponse(c))
@@ -150,37 +162,79 @@ func (h *ConsentHandler) get(w http.ResponseWriter, r *http.Request) {
 // @Produce      json
 // @Param        id   path      int64  true  "Consent ID (internal)"
 // @Success      200  {object}  dto.ConsentResponse
+// @Failure      400  {object}  dto.ErrorResponse
 // @Failure      401  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
 // @Failure      500  {object}  dto.ErrorResponse
 // @Router       /consents/{id}/refresh [post]
 func (h *ConsentHandler) refresh(w http.ResponseWriter, r *http.Request) {
-	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
-	c, err := h.svc.Refresh(r.Context(), id)
+	userID, ok := authmw.UserIDFromContext(r.Context())
+	if !ok {
+		httputils.WriteError(w, http.StatusUnauthorized, "missing user in context")
+		return
+	}
+	id, ok := consentIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	c, err := h.svc.Refresh(r.Context(), userID, id)
 	if err != nil {
-		httputils.WriteError(w, http.StatusInternalServerError, err.Error())
+		writeConsentError(w, err)
 		return
 	}
 	httputils.WriteJSON(w, http.StatusOK, toConsentResponse(c))
 }
 
-// delete deletes the consent from us (without calling the bank).
-// @Summary      Delete consent
+// delete revokes the consent at the bank and keeps it with status Revoked.
+// @Summary      Revoke consent
+// @Description  Revokes the consent at the bank (DELETE /account-consents/{id}), then keeps it with status Revoked
+// @Description  and revoked_at as a proof that access was withdrawn. If the bank call fails the consent is not changed.
 // @Tags         Consents
 // @Security     BearerAuth
 // @Param        id   path  int64  true  "Consent ID (internal)"
 // @Success      204  "No Content"
+// @Failure      400  {object}  dto.ErrorResponse
 // @Failure      401  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
 // @Failure      500  {object}  dto.ErrorResponse
 // @Router       /consents/{id} [delete]
 func (h *ConsentHandler) delete(w http.ResponseWriter, r *http.Request) {
-	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
-	if err := h.svc.Delete(r.Context(), id); err != nil {
-		httputils.WriteError(w, http.StatusInternalServerError, err.Error())
+	userID, ok := authmw.UserIDFromContext(r.Context())
+	if !ok {
+		httputils.WriteError(w, http.StatusUnauthorized, "missing user in context")
+		return
+	}
+	id, ok := consentIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.Delete(r.Context(), userID, id); err != nil {
+		writeConsentError(w, err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func consentIDParam(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id <= 0 {
+		httputils.WriteError(w, http.StatusBadRequest, "invalid id")
+		return 0, false
+	}
+	return id, true
+}
+
+// writeConsentError — consents of other users are not found as well
+func writeConsentError(w http.ResponseWriter, err error) {
+	if errors.Is(err, consent.ErrConsentNotFound) {
+		httputils.WriteError(w, http.StatusNotFound, consent.ErrConsentNotFound.Error())
+		return
+	}
+	httputils.WriteError(w, http.StatusInternalServerError, err.Error())
+}
+
 func toConsentResponse(c domain.AccountConsent) dto.ConsentResponse {
 	return dto.ConsentResponse{
 		ID:        c.ID,
@@ -200,6 +254,7 @@ func toConsentResponse(c domain.AccountConsent) dto.ConsentResponse {
 		CreationDateTime:     c.CreationDateTime,
 		StatusUpdateDateTime: c.StatusUpdateDateTime,
 		ExpirationDateTime:   c.ExpirationDateTime,
+		RevokedAt:            c.RevokedAt,
 		CreatedAt:            c.CreatedAt,
 		UpdatedAt:            c.UpdatedAt,
 	}
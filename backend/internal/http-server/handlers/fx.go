@@ -0,0 +1,54 @@
+// internal/http-server/handlers/fx.go
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/http-server/dto"
+	httputils "multibank/backend/internal/http-server/utils"
+	authmw "multibank/backend/internal/service/auth/middleware"
+	fxsvc "multibank/backend/internal/service/fx"
+	"net/http"
+)
+
+type FX interface {
+	NetWorth(ctx context.Context, userID int64, base string) (domain.NetWorth, error)
+	Import(ctx context.Context) (int, error)
+}
+
+// NetWorth godoc
+// @Summary      Net worth in one currency
+// @Description  Converts the primary balance of every user's account into the base currency
+// @Description  using the Bank of Russia rates (latest rate on or before today).
+// @Description  rates lists the rate and its date used for each currency, missing — currencies without a rate
+// @Description  (such accounts are returned with included=false and are not counted in total).
+// @Tags         me
+// @Produce      json
+// @Security     BearerAuth
+// @Param        base  query     string  false  "Base currency (ISO code), default RUB"  example(USD)
+// @Success      200   {object}  dto.NetWorthResponse
+// @Failure      400   {object}  dto.ErrorResponse
+// @Failure      401   {object}  dto.ErrorResponse
+// @Failure      500   {object}  dto.ErrorResponse
+// @Router       /me/net-worth [get]
+func (h *MeHandler) NetWorth(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authmw.UserIDFromContext(r.Context())
+	if !ok {
+		httputils.WriteError(w, http.StatusUnauthorized, "missing user in context")
+		return
+	}
+
+	nw, err := h.fx.NetWorth(r.Context(), userID, r.URL.Query().Get("base"))
+	if err != nil {
+		if errors.Is(err, fxsvc.ErrUnknownCurrency) {
+			httputils.WriteError(w, http.StatusBadRequest, "unknown base currency")
+			return
+		}
+		httputils.WriteError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, dto.NetWorthResponseFromDomain(nw))
+}
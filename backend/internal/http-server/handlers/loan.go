@@ -0,0 +1,172 @@
+// internal/http-server/handlers/loan.go
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/http-server/dto"
+	httputils "multibank/backend/internal/http-server/utils"
+	authmw "multibank/backend/internal/service/auth/middleware"
+	productsvc "multibank/backend/internal/service/product"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Schedule godoc
+// @Summary      Loan repayment schedule
+// @Description  Calculates the monthly repayment schedule of a loan or a credit card for the amount and term:
+// @Description  payment, interest and principal parts of every month and the total overpayment.
+// @Tags         products
+// @Security     BearerAuth
+// @Produce      json
+// @Param        bank       path      string  true   "Bank code"
+// @Param        productId  path      string  true   "Product ID in the bank"
+// @Param        amount     query     number  true   "Loan amount"
+// @Param        term       query     int     false  "Term in months (the product term by default)"
+// @Param        type       query     string  false  "annuity (default) | differentiated"
+// @Success      200        {object}  dto.PaymentScheduleResponse
+// @Failure      400        {object}  dto.ErrorResponse
+// @Failure      403        {object}  dto.ErrorResponse
+// @Failure      404        {object}  dto.ErrorResponse
+// @Failure      500        {object}  dto.ErrorResponse
+// @Router       /products/{bank}/{productId}/schedule [get]
+func (h *ProductHandler) Schedule(w http.ResponseWriter, r *http.Request) {
+	if _, ok := authmw.UserIDFromContext(r.Context()); !ok {
+		httputils.WriteError(w, http.StatusForbidden, "access denied")
+		return
+	}
+
+	q := r.URL.Query()
+	amount, err := strconv.ParseFloat(q.Get("amount"), 64)
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "invalid amount")
+		return
+	}
+	var term int
+	if s := q.Get("term"); s != "" {
+		if term, err = strconv.Atoi(s); err != nil || term <= 0 {
+			httputils.WriteError(w, http.StatusBadRequest, "invalid term")
+			return
+		}
+	}
+
+	s, err := h.svc.Schedule(r.Context(), productsvc.ScheduleInput{
+		BankCode:   chi.URLParam(r, "bank"),
+		ProductID:  chi.URLParam(r, "productId"),
+		Amount:     amount,
+		TermMonths: term,
+		Type:       domain.ScheduleType(strings.ToLower(q.Get("type"))),
+	})
+	if err != nil {
+		writeLoanError(w, err)
+		return
+	}
+
+	out := dto.PaymentScheduleResponse{
+		Product:      dto.ProductResponseFromDomain(s.Product),
+		Type:         s.Type,
+		Amount:       s.Amount,
+		TermMonths:   s.TermMonths,
+		Payments:     make([]dto.SchedulePaymentResponse, 0, len(s.Payments)),
+		TotalPayment: s.TotalPayment,
+		Overpayment:  s.Overpayment,
+	}
+	for _, p := range s.Payments {
+		out.Payments = append(out.Payments, dto.SchedulePaymentResponse{
+			Month:     p.Month,
+			Payment:   p.Payment,
+			Interest:  p.Interest,
+			Principal: p.Principal,
+			Balance:   p.Balance,
+		})
+	}
+	httputils.WriteJSON(w, http.StatusOK, out)
+}
+
+// CompareLoans godoc
+// @Summary      Compare loans
+// @Description  Returns loans and credit cards of all enabled banks that accept the amount for the term with the total
+// @Description  cost for annuity and differentiated payments side by side. Ranked by the annuity overpayment, rank 1 is the cheapest.
+// @Tags         products
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        input  body      dto.LoanCompareRequest  true  "Amount, term and currency"
+// @Success      200    {object}  dto.LoanCompareResponse
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Router       /products/compare-loans [post]
+func (h *ProductHandler) CompareLoans(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authmw.UserIDFromContext(r.Context())
+	if !ok {
+		httputils.WriteError(w, http.StatusForbidden, "access denied")
+		return
+	}
+
+	var req dto.LoanCompareRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "bad json: "+err.Error())
+		return
+	}
+	currency := strings.ToUpper(strings.TrimSpace(req.Currency))
+	if currency == "" {
+		currency = "RUB"
+	}
+
+	offers, err := h.svc.CompareLoans(r.Context(), productsvc.CompareInput{
+		Amount:     req.Amount,
+		TermMonths: req.TermMonths,
+		Currency:   currency,
+		UserID:     userID,
+	})
+	if err != nil {
+		writeLoanError(w, err)
+		return
+	}
+
+	out := dto.LoanCompareResponse{
+		Amount:     req.Amount,
+		TermMonths: req.TermMonths,
+		Currency:   currency,
+		Offers:     make([]dto.LoanOfferResponse, 0, len(offers)),
+	}
+	for _, o := range offers {
+		out.Offers = append(out.Offers, dto.LoanOfferResponse{
+			Rank:           o.Rank,
+			Product:        dto.ProductResponseFromDomain(o.Product),
+			Annuity:        dto.ScheduleSummaryResponseFromDomain(o.Annuity),
+			Differentiated: dto.ScheduleSummaryResponseFromDomain(o.Differentiated),
+		})
+	}
+	httputils.WriteJSON(w, http.StatusOK, out)
+}
+
+// writeLoanError maps schedule and comparison errors to HTTP statuses
+func writeLoanError(w http.ResponseWriter, err error) {
+	statuses := []struct {
+		err  error
+		code int
+	}{
+		{productsvc.ErrInvalidAmount, http.StatusBadRequest},
+		{productsvc.ErrInvalidTerm, http.StatusBadRequest},
+		{productsvc.ErrUnsupportedCurrency, http.StatusBadRequest},
+		{productsvc.ErrInvalidScheduleType, http.StatusBadRequest},
+		{productsvc.ErrNotCreditProduct, http.StatusBadRequest},
+		{productsvc.ErrAmountOutOfRange, http.StatusBadRequest},
+		{productsvc.ErrTermOutOfRange, http.StatusBadRequest},
+		{productsvc.ErrProductNotFound, http.StatusNotFound},
+	}
+	for _, s := range statuses {
+		if errors.Is(err, s.err) {
+			httputils.WriteError(w, s.code, s.err.Error())
+			return
+		}
+	}
+	httputils.WriteError(w, http.StatusInternalServerError, "internal")
+}
@@ -14,14 +14,29 @@ import (
 )
 
 type MeHandler struct {
-	svc User
+	svc           User
+	fx            FX
+	balances      BalanceHistory
+	agreements    Agreement
+	products      Product
+	notifications Notification
 }
 
 // RegisterMeRoutes registers ME handlers
 // JWT is attached in server.go to the /me
-func RegisterMeRoutes(r chi.Router, svc User) {
-	h := &MeHandler{svc: svc}
+func RegisterMeRoutes(r chi.Router, svc User, fx FX, balances BalanceHistory, agreements Agreement,
+	products Product, notifications Notification) {
+	h := &MeHandler{svc: svc, fx: fx, balances: balances, agreements: agreements,
+		products: products, notifications: notifications}
 	r.Get("/", h.GetMe)
+	r.Get("/net-worth", h.NetWorth)
+	r.Get("/balances/history", h.BalanceHistory)
+	r.Get("/agreements", h.ListAgreements)
+	r.Delete("/agreements/{id}", h.CloseAgreement)
+	r.Get("/subscriptions", h.ListSubscriptions)
+	r.Get("/recommendations", h.Recommendations)
+	r.Get("/notifications", h.ListNotifications)
+	r.Post("/notifications/{id}/read", h.ReadNotification)
 }
 
 // GetMe godoc
@@ -0,0 +1,87 @@
+// internal/http-server/handlers/notification.go
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/http-server/dto"
+	httputils "multibank/backend/internal/http-server/utils"
+	authmw "multibank/backend/internal/service/auth/middleware"
+	notificationsvc "multibank/backend/internal/service/notification"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Notification interface {
+	List(ctx context.Context, userID int64, unreadOnly bool) ([]domain.Notification, error)
+	MarkRead(ctx context.Context, userID, id int64) error
+}
+
+// ListNotifications godoc
+// @Summary      My notifications
+// @Description  Returns the latest 100 notifications of the current user (e.g. rate changes of subscribed products).
+// @Tags         me
+// @Security     BearerAuth
+// @Produce      json
+// @Param        unread  query     bool  false  "Only unread notifications"
+// @Success      200     {array}   dto.NotificationResponse
+// @Failure      401     {object}  dto.ErrorResponse
+// @Failure      500     {object}  dto.ErrorResponse
+// @Router       /me/notifications [get]
+func (h *MeHandler) ListNotifications(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authmw.UserIDFromContext(r.Context())
+	if !ok {
+		httputils.WriteError(w, http.StatusUnauthorized, "missing user in context")
+		return
+	}
+
+	unread, _ := strconv.ParseBool(r.URL.Query().Get("unread"))
+	items, err := h.notifications.List(r.Context(), userID, unread)
+	if err != nil {
+		httputils.WriteError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	out := make([]dto.NotificationResponse, 0, len(items))
+	for _, n := range items {
+		out = append(out, dto.NotificationResponseFromDomain(n))
+	}
+	httputils.WriteJSON(w, http.StatusOK, out)
+}
+
+// ReadNotification godoc
+// @Summary      Mark notification as read
+// @Tags         me
+// @Security     BearerAuth
+// @Param        id   path  int64  true  "Notification ID"
+// @Success      204
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Router       /me/notifications/{id}/read [post]
+func (h *MeHandler) ReadNotification(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authmw.UserIDFromContext(r.Context())
+	if !ok {
+		httputils.WriteError(w, http.StatusUnauthorized, "missing user in context")
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	if err := h.notifications.MarkRead(r.Context(), userID, id); err != nil {
+		if errors.Is(err, notificationsvc.ErrNotificationNotFound) {
+			httputils.WriteError(w, http.StatusNotFound, "notification not found")
+			return
+		}
+		httputils.WriteError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,280 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/http-server/dto"
+	httputils "multibank/backend/internal/http-server/utils"
+	authmw "multibank/backend/internal/service/auth/middleware"
+	"multibank/backend/internal/service/payment"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Payment interface {
+	CreateConsent(ctx context.Context, in payment.CreateConsentInput) (domain.PaymentConsent, error)
+	GetConsent(ctx context.Context, userID, id int64) (domain.PaymentConsent, error)
+	ListConsents(ctx context.Context, userID int64) ([]domain.PaymentConsent, error)
+
+	InitiatePayment(ctx context.Context, in payment.InitiateInput) (domain.Payment, error)
+	GetPayment(ctx context.Context, userID, id int64) (domain.Payment, error)
+	ListPayments(ctx context.Context, userID int64) ([]domain.Payment, error)
+	ResumePayments(ctx context.Context, batchLimit int) (int, error)
+}
+
+type PaymentHandler struct {
+	svc Payment
+}
+
+func RegisterPaymentRoutes(r chi.Router, svc Payment) {
+	h := &PaymentHandler{svc: svc}
+
+	// Base prefix is outside: r.Route("/payments", ...) in server.go
+	r.Post("/consents", h.createConsent)
+	r.Get("/consents", h.listConsents)
+	r.Get("/consents/{id}", h.getConsent)
+	r.Post("/", h.initiate)
+	r.Get("/", h.list)
+	r.Get("/{id}", h.get)
+}
+
+// createConsent requests a payment consent in the bank of the debtor account
+// @Summary      Create payment consent
+// @Description  Requests a single-use payment consent in the bank of the user's account (account_id from /accounts).
+// @Description  If the bank auto-approves it, status is Authorized and the payment can be initiated right away,
+// @Description  otherwise poll GET /payments/consents/{id} until it is Authorized.
+// @Tags         Payments
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        input  body      dto.PaymentConsentCreateRequest  true  "Payment consent payload"
+// @Success      201    {object}  dto.PaymentConsentResponse
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Failure      502    {object}  dto.ErrorResponse
+// @Router       /payments/consents [post]
+func (h *PaymentHandler) createConsent(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authmw.UserIDFromContext(r.Context())
+	if !ok {
+		httputils.WriteError(w, http.StatusUnauthorized, "missing user in context")
+		return
+	}
+
+	var req dto.PaymentConsentCreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "bad json: "+err.Error())
+		return
+	}
+
+	c, err := h.svc.CreateConsent(r.Context(), payment.CreateConsentInput{
+		UserID:           userID,
+		BankID:           req.BankID,
+		AccountID:        req.AccountID,
+		Amount:           req.Amount,
+		Currency:         req.Currency,
+		CreditorAccount:  req.CreditorAccount,
+		CreditorName:     req.CreditorName,
+		CreditorBankCode: req.CreditorBankCode,
+		Reference:        req.Reference,
+	})
+	if err != nil {
+		writePaymentError(w, err)
+		return
+	}
+	httputils.WriteJSON(w, http.StatusCreated, dto.PaymentConsentResponseFromDomain(c))
+}
+
+// listConsents returns payment consents of the current user
+// @Summary      List my payment consents
+// @Tags         Payments
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {array}   dto.PaymentConsentResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Router       /payments/consents [get]
+func (h *PaymentHandler) listConsents(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authmw.UserIDFromContext(r.Context())
+	if !ok {
+		httputils.WriteError(w, http.StatusUnauthorized, "missing user in context")
+		return
+	}
+
+	items, err := h.svc.ListConsents(r.Context(), userID)
+	if err != nil {
+		httputils.WriteError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	out := make([]dto.PaymentConsentResponse, 0, len(items))
+	for _, it := range items {
+		out = append(out, dto.PaymentConsentResponseFromDomain(it))
+	}
+	httputils.WriteJSON(w, http.StatusOK, out)
+}
+
+// getConsent returns the payment consent (re-checked in the bank while it awaits authorization)
+// @Summary      Get payment consent
+// @Tags         Payments
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      int64  true  "Payment consent ID (internal)"
+// @Success      200  {object}  dto.PaymentConsentResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      502  {object}  dto.ErrorResponse
+// @Router       /payments/consents/{id} [get]
+func (h *PaymentHandler) getConsent(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authmw.UserIDFromContext(r.Context())
+	if !ok {
+		httputils.WriteError(w, http.StatusUnauthorized, "missing user in context")
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	c, err := h.svc.GetConsent(r.Context(), userID, id)
+	if err != nil {
+		writePaymentError(w, err)
+		return
+	}
+	httputils.WriteJSON(w, http.StatusOK, dto.PaymentConsentResponseFromDomain(c))
+}
+
+// initiate makes the payment by an authorized payment consent
+// @Summary      Initiate payment
+// @Description  Makes the payment described by the authorized payment consent (each consent can be used once).
+// @Description  Poll GET /payments/{id} to follow the status. If the bank did not answer, the payment is sent again
+// @Description  in the background with the same idempotency key. 503 means the bank was not called (it is failing
+// @Description  and temporarily cut off): the consent stays free and the request can be repeated later.
+// @Tags         Payments
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        input  body      dto.PaymentCreateRequest  true  "Payment payload"
+// @Success      201    {object}  dto.PaymentResponse
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Failure      409    {object}  dto.ErrorResponse
+// @Failure      422    {object}  dto.ErrorResponse
+// @Failure      502    {object}  dto.ErrorResponse
+// @Failure      503    {object}  dto.ErrorResponse
+// @Router       /payments [post]
+func (h *PaymentHandler) initiate(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authmw.UserIDFromContext(r.Context())
+	if !ok {
+		httputils.WriteError(w, http.StatusUnauthorized, "missing user in context")
+		return
+	}
+
+	var req dto.PaymentCreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "bad json: "+err.Error())
+		return
+	}
+
+	p, err := h.svc.InitiatePayment(r.Context(), payment.InitiateInput{
+		UserID:           userID,
+		PaymentConsentID: req.PaymentConsentID,
+		Comment:          req.Comment,
+	})
+	if err != nil {
+		writePaymentError(w, err)
+		return
+	}
+	httputils.WriteJSON(w, http.StatusCreated, dto.PaymentResponseFromDomain(p))
+}
+
+// list returns payments of the current user
+// @Summary      List my payments
+// @Tags         Payments
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {array}   dto.PaymentResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Router       /payments [get]
+func (h *PaymentHandler) list(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authmw.UserIDFromContext(r.Context())
+	if !ok {
+		httputils.WriteError(w, http.StatusUnauthorized, "missing user in context")
+		return
+	}
+
+	items, err := h.svc.ListPayments(r.Context(), userID)
+	if err != nil {
+		httputils.WriteError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	out := make([]dto.PaymentResponse, 0, len(items))
+	for _, it := range items {
+		out = append(out, dto.PaymentResponseFromDomain(it))
+	}
+	httputils.WriteJSON(w, http.StatusOK, out)
+}
+
+// get returns the payment, its status is polled from the bank until it is final
+// @Summary      Get payment status
+// @Tags         Payments
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      int64  true  "Payment ID (internal)"
+// @Success      200  {object}  dto.PaymentResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      502  {object}  dto.ErrorResponse
+// @Router       /payments/{id} [get]
+func (h *PaymentHandler) get(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authmw.UserIDFromContext(r.Context())
+	if !ok {
+		httputils.WriteError(w, http.StatusUnauthorized, "missing user in context")
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	p, err := h.svc.GetPayment(r.Context(), userID, id)
+	if err != nil {
+		writePaymentError(w, err)
+		return
+	}
+	httputils.WriteJSON(w, http.StatusOK, dto.PaymentResponseFromDomain(p))
+}
+
+// writePaymentError maps payment service errors to HTTP statuses (other errors are bank failures)
+func writePaymentError(w http.ResponseWriter, err error) {
+	statuses := []struct {
+		err  error
+		code int
+	}{
+		{payment.ErrInvalidAmount, http.StatusBadRequest},
+		{payment.ErrInvalidCreditor, http.StatusBadRequest},
+		{payment.ErrCurrencyMismatch, http.StatusBadRequest},
+		{payment.ErrAccountNotFound, http.StatusNotFound},
+		{payment.ErrConsentNotFound, http.StatusNotFound},
+		{payment.ErrPaymentNotFound, http.StatusNotFound},
+		{payment.ErrConsentNotAuthorized, http.StatusConflict},
+		{payment.ErrConsentAlreadyUsed, http.StatusConflict},
+		{payment.ErrPaymentInProgress, http.StatusConflict},
+		{payment.ErrPaymentRejected, http.StatusUnprocessableEntity},
+		{payment.ErrBankUnavailable, http.StatusServiceUnavailable},
+	}
+	for _, s := range statuses {
+		if errors.Is(err, s.err) {
+			httputils.WriteError(w, s.code, s.err.Error())
+			return
+		}
+	}
+	httputils.WriteError(w, http.StatusBadGateway, err.Error())
+}
@@ -3,6 +3,7 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
@@ -13,45 +14,87 @@ import (
 	"multibank/backend/internal/http-server/dto"
 	httputils "multibank/backend/internal/http-server/utils"
 	authmw "multibank/backend/internal/service/auth/middleware"
+	productsvc "multibank/backend/internal/service/product"
 )
 
 type Product interface {
-	List(ctx context.Context, f domain.ProductFilter) ([]domain.Product, error)
+	List(ctx context.Context, f domain.ProductFilter) (domain.ProductPage, error)
+	Sources(ctx context.Context) ([]domain.ProductSource, error)
+
+	Refresh(ctx context.Context, workers int) (int, error)
+
+	CompareDeposits(ctx context.Context, in productsvc.CompareInput) ([]domain.DepositOffer, error)
+	CompareLoans(ctx context.Context, in productsvc.CompareInput) ([]domain.LoanOffer, error)
+	Schedule(ctx context.Context, in productsvc.ScheduleInput) (domain.PaymentSchedule, error)
+
+	History(ctx context.Context, bankCode, productID string) ([]domain.ProductChange, error)
+	Subscribe(ctx context.Context, userID int64, bankCode, productID string) error
+	Unsubscribe(ctx context.Context, userID int64, bankCode, productID string) error
+	ListSubscriptions(ctx context.Context, userID int64) ([]domain.ProductSubscription, error)
+
+	Recommendations(ctx context.Context, userID int64) ([]domain.Product, error)
+	Click(ctx context.Context, userID int64, bankCode, productID string) error
 }
 
 type ProductHandler struct {
-	svc Product
+	svc        Product
+	agreements Agreement
 }
 
-func RegisterProductRoutes(r chi.Router, svc Product) {
-	h := &ProductHandler{svc: svc}
+func RegisterProductRoutes(r chi.Router, svc Product, agreements Agreement) {
+	h := &ProductHandler{svc: svc, agreements: agreements}
 	r.Get("/", h.List)
+	r.Get("/sources", h.Sources)
+	r.Post("/compare", h.Compare)
+	r.Post("/compare-loans", h.CompareLoans)
+	r.Post("/{bank}/{productId}/apply", h.Apply)
+	r.Get("/{bank}/{productId}/history", h.History)
+	r.Get("/{bank}/{productId}/schedule", h.Schedule)
+	r.Post("/{bank}/{productId}/click", h.Click)
+	r.Post("/{bank}/{productId}/subscription", h.Subscribe)
+	r.Delete("/{bank}/{productId}/subscription", h.Unsubscribe)
 }
 
 // List godoc
 // @Summary      Get aggregated products
-// @Description  Returns a merged list of products from all enabled banks with optional filters.
+// @Description  Returns a merged list of products of all enabled banks from the local catalogue (refreshed in background).
+// @Description  fetched_at is the time of the last successful fetch from the bank, bank_health is "down" if the bank
+// @Description  API failed on the last refresh (its products are then served from the previous fetch).
+// @Description  Without limit all matching products are returned. With limit the list is paginated by cursor:
+// @Description  if there are more products, the X-Next-Cursor header and Link (rel="next") point to the next page.
 // @Tags         products
 // @Security     BearerAuth
 // @Accept       json
 // @Produce      json
-// @Param        product_type  query   string  false  "Type: deposit|loan|card|account"
-// @Param        bank_id       query   []int   false  "Repeatable bank id filter" collectionFormat=multi
+// @Param        product_type  query   string   false  "Type: deposit|loan|card|account"
+// @Param        bank_id       query   []int    false  "Repeatable bank id filter" collectionFormat=multi
+// @Param        min_rate      query   number   false  "Minimum interest rate, % per year"
+// @Param        term_from     query   int      false  "Minimum term, months"
+// @Param        term_to       query   int      false  "Maximum term, months"
+// @Param        amount        query   number   false  "Sum that must fit into min/max amount of the product"
+// @Param        q             query   string   false  "Text search in name and description"
+// @Param        sort          query   string   false  "Sort: bank (default) | rate | term"
+// @Param        order         query   string   false  "asc | desc (default desc for rate, asc otherwise)"
+// @Param        limit         query   int      false  "Page size (max 200)"
+// @Param        cursor        query   string   false  "Cursor from X-Next-Cursor of the previous page"
 // @Success      200  {array}  dto.ProductResponse
+// @Header       200  {string} X-Next-Cursor "Cursor of the next page (absent on the last page)"
+// @Failure      400  {object} dto.ErrorResponse
 // @Failure      403  {object} dto.ErrorResponse
 // @Failure      500  {object} dto.ErrorResponse
 // @Router       /products [get]
 func (h *ProductHandler) List(w http.ResponseWriter, r *http.Request) {
-	_, ok := authmw.UserIDFromContext(r.Context())
+	userID, ok := authmw.UserIDFromContext(r.Context())
 	if !ok {
 		httputils.WriteError(w, http.StatusForbidden, "access denied")
 		return
 	}
 
-	f := domain.ProductFilter{}
+	q := r.URL.Query()
+	f := domain.ProductFilter{UserID: userID}
 
-	f.ProductType = r.URL.Query().Get("product_type")
-	if vals, ok := r.URL.Query()["bank_id"]; ok {
+	f.ProductType = q.Get("product_type")
+	if vals, ok := q["bank_id"]; ok {
 		for _, v := range vals {
 			if v == "" {
 				continue
@@ -64,28 +107,125 @@ func (h *ProductHandler) List(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	items, err := h.svc.List(r.Context(), f)
+	var err error
+	if f.MinRate, err = parseFloatParam(q.Get("min_rate")); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "invalid min_rate")
+		return
+	}
+	if f.Amount, err = parseFloatParam(q.Get("amount")); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "invalid amount")
+		return
+	}
+	if f.MinTerm, err = parseIntParam(q.Get("term_from")); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "invalid term_from")
+		return
+	}
+	if f.MaxTerm, err = parseIntParam(q.Get("term_to")); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "invalid term_to")
+		return
+	}
+	if s := q.Get("limit"); s != "" {
+		if f.Limit, err = strconv.Atoi(s); err != nil || f.Limit <= 0 {
+			httputils.WriteError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+	}
+	f.Query = q.Get("q")
+	f.Cursor = q.Get("cursor")
+	f.Sort = domain.ProductSort(q.Get("sort"))
+	switch q.Get("order") {
+	case "":
+		f.Desc = f.Sort == domain.ProductSortRate // the best rate first
+	case "asc":
+	case "desc":
+		f.Desc = true
+	default:
+		httputils.WriteError(w, http.StatusBadRequest, "invalid order")
+		return
+	}
+
+	page, err := h.svc.List(r.Context(), f)
+	if err != nil {
+		switch {
+		case errors.Is(err, productsvc.ErrInvalidFilter), errors.Is(err, productsvc.ErrInvalidCursor):
+			httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		default:
+			httputils.WriteError(w, http.StatusInternalServerError, "internal")
+		}
+		return
+	}
+
+	if page.NextCursor != "" {
+		next := r.URL.Query()
+		next.Set("cursor", page.NextCursor)
+		w.Header().Set("X-Next-Cursor", page.NextCursor)
+		w.Header().Set("Link", `<?`+next.Encode()+`>; rel="next"`)
+	}
+
+	out := make([]dto.ProductResponse, 0, len(page.Items))
+	for _, p := range page.Items {
+		out = append(out, dto.ProductResponseFromDomain(p))
+	}
+	httputils.WriteJSON(w, http.StatusOK, out)
+}
+
+func parseFloatParam(s string) (*float64, error) {
+	if s == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func parseIntParam(s string) (*int, error) {
+	if s == "" {
+		return nil, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// Sources godoc
+// @Summary      Product sources health
+// @Description  Returns the state of the product catalogue fetch for every enabled bank.
+// @Tags         products
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {array}  dto.ProductSourceResponse
+// @Failure      403  {object} dto.ErrorResponse
+// @Failure      500  {object} dto.ErrorResponse
+// @Router       /products/sources [get]
+func (h *ProductHandler) Sources(w http.ResponseWriter, r *http.Request) {
+	_, ok := authmw.UserIDFromContext(r.Context())
+	if !ok {
+		httputils.WriteError(w, http.StatusForbidden, "access denied")
+		return
+	}
+
+	items, err := h.svc.Sources(r.Context())
 	if err != nil {
 		httputils.WriteError(w, http.StatusInternalServerError, "internal")
 		return
 	}
 
-	out := make([]dto.ProductResponse, 0, len(items))
-	for _, p := range items {
-		out = append(out, dto.ProductResponse{
-			ProductID:     p.ProductID,
-			ProductType:   p.ProductType,
-			ProductName:   p.ProductName,
-			Description:   p.Description,
-			InterestRate:  p.InterestRate,
-			MinAmount:     p.MinAmount,
-			MaxAmount:     p.MaxAmount,
-			TermMonths:    p.TermMonths,
-			BankID:        p.BankID,
-			BankCode:      p.BankCode,
-			BankName:      p.BankName,
-			FetchedAt:     p.FetchedAt,
-			IsRecommended: p.IsRecommended,
+	out := make([]dto.ProductSourceResponse, 0, len(items))
+	for _, s := range items {
+		out = append(out, dto.ProductSourceResponse{
+			BankID:        s.BankID,
+			BankCode:      s.BankCode,
+			BankName:      s.BankName,
+			Health:        s.Health(),
+			LastAttemptAt: s.LastAttemptAt,
+			LastSuccessAt: s.LastSuccessAt,
+			LastError:     s.LastError,
+			Failures:      s.Failures,
+			ProductsCount: s.ProductsCount,
 		})
 	}
 	httputils.WriteJSON(w, http.StatusOK, out)
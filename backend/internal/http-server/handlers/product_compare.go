@@ -0,0 +1,82 @@
+// internal/http-server/handlers/product_compare.go
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"multibank/backend/internal/http-server/dto"
+	httputils "multibank/backend/internal/http-server/utils"
+	authmw "multibank/backend/internal/service/auth/middleware"
+	productsvc "multibank/backend/internal/service/product"
+	"net/http"
+	"strings"
+)
+
+// Compare godoc
+// @Summary      Compare deposits
+// @Description  Returns deposits of all enabled banks that accept the amount for the term (deposits without a fixed term
+// @Description  or with exactly this term) with projected payouts: simple interest paid at the end of the term and
+// @Description  monthly capitalization. Offers are ranked by the capitalized payout, rank 1 is the best one.
+// @Tags         products
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        input  body      dto.DepositCompareRequest  true  "Amount, term and currency"
+// @Success      200    {object}  dto.DepositCompareResponse
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Router       /products/compare [post]
+func (h *ProductHandler) Compare(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authmw.UserIDFromContext(r.Context())
+	if !ok {
+		httputils.WriteError(w, http.StatusForbidden, "access denied")
+		return
+	}
+
+	var req dto.DepositCompareRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "bad json: "+err.Error())
+		return
+	}
+	currency := strings.ToUpper(strings.TrimSpace(req.Currency))
+	if currency == "" {
+		currency = "RUB"
+	}
+
+	offers, err := h.svc.CompareDeposits(r.Context(), productsvc.CompareInput{
+		Amount:     req.Amount,
+		TermMonths: req.TermMonths,
+		Currency:   currency,
+		UserID:     userID,
+	})
+	if err != nil {
+		for _, e := range []error{productsvc.ErrInvalidAmount, productsvc.ErrInvalidTerm, productsvc.ErrUnsupportedCurrency} {
+			if errors.Is(err, e) {
+				httputils.WriteError(w, http.StatusBadRequest, e.Error())
+				return
+			}
+		}
+		httputils.WriteError(w, http.StatusInternalServerError, "internal")
+		return
+	}
+
+	out := dto.DepositCompareResponse{
+		Amount:     req.Amount,
+		TermMonths: req.TermMonths,
+		Currency:   currency,
+		Offers:     make([]dto.DepositOfferResponse, 0, len(offers)),
+	}
+	for _, o := range offers {
+		out.Offers = append(out.Offers, dto.DepositOfferResponse{
+			Rank:                o.Rank,
+			Product:             dto.ProductResponseFromDomain(o.Product),
+			SimpleInterest:      o.SimpleInterest,
+			SimplePayout:        o.SimplePayout,
+			CapitalizedInterest: o.CapitalizedInterest,
+			CapitalizedPayout:   o.CapitalizedPayout,
+		})
+	}
+	httputils.WriteJSON(w, http.StatusOK, out)
+}
@@ -0,0 +1,175 @@
+// internal/http-server/handlers/product_history.go
+
+package handlers
+
+import (
+	"errors"
+	"multibank/backend/internal/http-server/dto"
+	httputils "multibank/backend/internal/http-server/utils"
+	authmw "multibank/backend/internal/service/auth/middleware"
+	productsvc "multibank/backend/internal/service/product"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// History godoc
+// @Summary      Product parameters history
+// @Description  Returns changes of interest rate, min/max amount and term of the product found between catalogue refreshes.
+// @Tags         products
+// @Security     BearerAuth
+// @Produce      json
+// @Param        bank       path      string  true  "Bank code"
+// @Param        productId  path      string  true  "Product ID in the bank"
+// @Success      200        {object}  dto.ProductHistoryResponse
+// @Failure      401        {object}  dto.ErrorResponse
+// @Failure      404        {object}  dto.ErrorResponse
+// @Failure      500        {object}  dto.ErrorResponse
+// @Router       /products/{bank}/{productId}/history [get]
+func (h *ProductHandler) History(w http.ResponseWriter, r *http.Request) {
+	if _, ok := authmw.UserIDFromContext(r.Context()); !ok {
+		httputils.WriteError(w, http.StatusUnauthorized, "missing user in context")
+		return
+	}
+
+	bankCode, productID := chi.URLParam(r, "bank"), chi.URLParam(r, "productId")
+	items, err := h.svc.History(r.Context(), bankCode, productID)
+	if err != nil {
+		writeProductError(w, err)
+		return
+	}
+
+	out := dto.ProductHistoryResponse{
+		BankCode:  bankCode,
+		ProductID: productID,
+		Changes:   make([]dto.ProductChangeResponse, 0, len(items)),
+	}
+	for _, c := range items {
+		out.Changes = append(out.Changes, dto.ProductChangeResponse{
+			Field:     c.Field,
+			OldValue:  c.OldValue,
+			NewValue:  c.NewValue,
+			ChangedAt: c.ChangedAt,
+		})
+	}
+	httputils.WriteJSON(w, http.StatusOK, out)
+}
+
+// Subscribe godoc
+// @Summary      Subscribe to product rate changes
+// @Description  The user gets a notification (GET /me/notifications) when the interest rate of the product changes.
+// @Tags         products
+// @Security     BearerAuth
+// @Param        bank       path  string  true  "Bank code"
+// @Param        productId  path  string  true  "Product ID in the bank"
+// @Success      204
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Router       /products/{bank}/{productId}/subscription [post]
+func (h *ProductHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authmw.UserIDFromContext(r.Context())
+	if !ok {
+		httputils.WriteError(w, http.StatusUnauthorized, "missing user in context")
+		return
+	}
+
+	if err := h.svc.Subscribe(r.Context(), userID, chi.URLParam(r, "bank"), chi.URLParam(r, "productId")); err != nil {
+		writeProductError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Unsubscribe godoc
+// @Summary      Unsubscribe from product rate changes
+// @Tags         products
+// @Security     BearerAuth
+// @Param        bank       path  string  true  "Bank code"
+// @Param        productId  path  string  true  "Product ID in the bank"
+// @Success      204
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Router       /products/{bank}/{productId}/subscription [delete]
+func (h *ProductHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authmw.UserIDFromContext(r.Context())
+	if !ok {
+		httputils.WriteError(w, http.StatusUnauthorized, "missing user in context")
+		return
+	}
+
+	if err := h.svc.Unsubscribe(r.Context(), userID, chi.URLParam(r, "bank"), chi.URLParam(r, "productId")); err != nil {
+		writeProductError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Click godoc
+// @Summary      Track click on a recommended product
+// @Description  Records a click of the current user on the product for the recommended products report.
+// @Description  Clicks on products that are not recommended to the user are accepted but not recorded.
+// @Tags         products
+// @Security     BearerAuth
+// @Param        bank       path  string  true  "Bank code"
+// @Param        productId  path  string  true  "Product ID in the bank"
+// @Success      204
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Router       /products/{bank}/{productId}/click [post]
+func (h *ProductHandler) Click(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authmw.UserIDFromContext(r.Context())
+	if !ok {
+		httputils.WriteError(w, http.StatusUnauthorized, "missing user in context")
+		return
+	}
+
+	if err := h.svc.Click(r.Context(), userID, chi.URLParam(r, "bank"), chi.URLParam(r, "productId")); err != nil {
+		writeProductError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListSubscriptions godoc
+// @Summary      My product subscriptions
+// @Tags         me
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {array}   dto.ProductSubscriptionResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Router       /me/subscriptions [get]
+func (h *MeHandler) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authmw.UserIDFromContext(r.Context())
+	if !ok {
+		httputils.WriteError(w, http.StatusUnauthorized, "missing user in context")
+		return
+	}
+
+	items, err := h.products.ListSubscriptions(r.Context(), userID)
+	if err != nil {
+		httputils.WriteError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	out := make([]dto.ProductSubscriptionResponse, 0, len(items))
+	for _, s := range items {
+		out = append(out, dto.ProductSubscriptionResponse{
+			BankCode:    s.BankCode,
+			ProductID:   s.ProductID,
+			ProductName: s.ProductName,
+			CreatedAt:   s.CreatedAt,
+		})
+	}
+	httputils.WriteJSON(w, http.StatusOK, out)
+}
+
+func writeProductError(w http.ResponseWriter, err error) {
+	if errors.Is(err, productsvc.ErrProductNotFound) {
+		httputils.WriteError(w, http.StatusNotFound, productsvc.ErrProductNotFound.Error())
+		return
+	}
+	httputils.WriteError(w, http.StatusInternalServerError, "internal error")
+}
@@ -0,0 +1,190 @@
+// internal/http-server/handlers/recommendation.go
+
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/http-server/dto"
+	httputils "multibank/backend/internal/http-server/utils"
+	authmw "multibank/backend/internal/service/auth/middleware"
+	"multibank/backend/internal/service/recommendation"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type RecommendationRules interface {
+	ListRules(ctx context.Context) ([]domain.RecommendationRule, error)
+	CreateRule(ctx context.Context, r domain.RecommendationRule) (domain.RecommendationRule, error)
+	UpdateRule(ctx context.Context, r domain.RecommendationRule) (domain.RecommendationRule, error)
+	DeleteRule(ctx context.Context, id int64) error
+}
+
+type RecommendationRulesHandler struct {
+	svc RecommendationRules
+}
+
+func RegisterRecommendationRuleRoutes(r chi.Router, svc RecommendationRules) {
+	h := &RecommendationRulesHandler{svc: svc}
+	r.Get("/", h.list)
+	r.Post("/", h.create)
+	r.Put("/{id}", h.update)
+	r.Delete("/{id}", h.delete)
+}
+
+// @Summary      List recommendation rules
+// @Description  Targeting rules of recommended products, the highest priority first.
+// @Tags         admin/products
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {array}   dto.RecommendationRuleResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Router       /admin/recommendation-rules [get]
+func (h *RecommendationRulesHandler) list(w http.ResponseWriter, r *http.Request) {
+	items, err := h.svc.ListRules(r.Context())
+	if err != nil {
+		httputils.WriteError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	out := make([]dto.RecommendationRuleResponse, 0, len(items))
+	for _, it := range items {
+		out = append(out, dto.RecommendationRuleResponseFromDomain(it))
+	}
+	httputils.WriteJSON(w, http.StatusOK, out)
+}
+
+// @Summary      Create recommendation rule
+// @Description  A product is recommended to the user if it is a target of an active rule and the user meets
+// @Description  all conditions of the rule (age, total balance in RUB, connection of the product bank).
+// @Tags         admin/products
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        input  body      dto.RecommendationRuleRequest  true  "Rule"
+// @Success      201    {object}  dto.RecommendationRuleResponse
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Router       /admin/recommendation-rules [post]
+func (h *RecommendationRulesHandler) create(w http.ResponseWriter, r *http.Request) {
+	var req dto.RecommendationRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "bad json: "+err.Error())
+		return
+	}
+
+	rule, err := h.svc.CreateRule(r.Context(), req.ToDomain())
+	if err != nil {
+		writeRuleError(w, err)
+		return
+	}
+	httputils.WriteJSON(w, http.StatusCreated, dto.RecommendationRuleResponseFromDomain(rule))
+}
+
+// @Summary      Update recommendation rule
+// @Tags         admin/products
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int64                          true  "Rule ID"
+// @Param        input  body      dto.RecommendationRuleRequest  true  "Rule"
+// @Success      200    {object}  dto.RecommendationRuleResponse
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Router       /admin/recommendation-rules/{id} [put]
+func (h *RecommendationRulesHandler) update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+	var req dto.RecommendationRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "bad json: "+err.Error())
+		return
+	}
+
+	in := req.ToDomain()
+	in.ID = id
+	rule, err := h.svc.UpdateRule(r.Context(), in)
+	if err != nil {
+		writeRuleError(w, err)
+		return
+	}
+	httputils.WriteJSON(w, http.StatusOK, dto.RecommendationRuleResponseFromDomain(rule))
+}
+
+// @Summary      Delete recommendation rule
+// @Tags         admin/products
+// @Security     BearerAuth
+// @Param        id   path  int64  true  "Rule ID"
+// @Success      204  "No Content"
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Router       /admin/recommendation-rules/{id} [delete]
+func (h *RecommendationRulesHandler) delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+	if err := h.svc.DeleteRule(r.Context(), id); err != nil {
+		writeRuleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeRuleError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, recommendation.ErrInvalidRule):
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, recommendation.ErrRuleNotFound):
+		httputils.WriteError(w, http.StatusNotFound, recommendation.ErrRuleNotFound.Error())
+	default:
+		httputils.WriteError(w, http.StatusInternalServerError, "internal error")
+	}
+}
+
+// Recommendations godoc
+// @Summary      Products recommended to me
+// @Description  Products matched by recommendation rules for the current user (by priority of the rule, with the reason),
+// @Description  followed by products of the static recommended list.
+// @Tags         me
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {array}   dto.RecommendationResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Router       /me/recommendations [get]
+func (h *MeHandler) Recommendations(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authmw.UserIDFromContext(r.Context())
+	if !ok {
+		httputils.WriteError(w, http.StatusUnauthorized, "missing user in context")
+		return
+	}
+
+	items, err := h.products.Recommendations(r.Context(), userID)
+	if err != nil {
+		httputils.WriteError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	out := make([]dto.RecommendationResponse, 0, len(items))
+	for i, p := range items {
+		out = append(out, dto.RecommendationResponse{
+			Rank:    i + 1,
+			Reason:  p.RecommendationReason,
+			Product: dto.ProductResponseFromDomain(p),
+		})
+	}
+	httputils.WriteJSON(w, http.StatusOK, out)
+}
@@ -4,10 +4,12 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"multibank/backend/internal/domain"
 	"multibank/backend/internal/http-server/dto"
 	httputils "multibank/backend/internal/http-server/utils"
 	"multibank/backend/internal/service/product"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -17,6 +19,7 @@ type Recommended interface {
 	List(ctx context.Context) ([]product.Rule, error)
 	Upsert(ctx context.Context, productID, bankCode, productType string) error
 	Delete(ctx context.Context, productID, bankCode, productType string) error
+	Stats(ctx context.Context, from, to *time.Time) ([]domain.RecommendationStat, error)
 }
 
 type RecommendedHandler struct {
@@ -30,6 +33,7 @@ func RegisterRecommendedRoutes(r chi.Router, svc Recommended) {
 	r.Get("/", h.list)
 	r.Post("/", h.upsert)
 	r.Delete("/", h.delete)
+	r.Get("/stats", h.stats)
 }
 
 // @Summary      List recommended product rules
@@ -37,6 +41,7 @@ func RegisterRecommendedRoutes(r chi.Router, svc Recommended) {
 // @Security     BearerAuth
 // @Produce      json
 // @Success      200  {array}  dto.RecommendedRule
+// @Failure      403  {object}  dto.ErrorResponse
 // @Router       /admin/recommended-products [get]
 func (h *RecommendedHandler) list(w http.ResponseWriter, r *http.Request) {
 	rows, err := h.svc.List(r.Context())
@@ -64,6 +69,7 @@ func (h *RecommendedHandler) list(w http.ResponseWriter, r *http.Request) {
 // @Param        input  body  dto.RecommendedUpsertRequest  true  "Rule triplet"
 // @Success      204    "No Content"
 // @Failure      400    {object} dto.ErrorResponse
+// @Failure      403    {object} dto.ErrorResponse
 // @Failure      500    {object} dto.ErrorResponse
 // @Router       /admin/recommended-products [post]
 func (h *RecommendedHandler) upsert(w http.ResponseWriter, r *http.Request) {
@@ -90,6 +96,7 @@ func (h *RecommendedHandler) upsert(w http.ResponseWriter, r *http.Request) {
 // @Param        input  body  dto.RecommendedUpsertRequest  true  "Rule triplet to delete"
 // @Success      204    "No Content"
 // @Failure      400    {object} dto.ErrorResponse
+// @Failure      403    {object} dto.ErrorResponse
 // @Failure      500    {object} dto.ErrorResponse
 // @Router       /admin/recommended-products [delete]
 func (h *RecommendedHandler) delete(w http.ResponseWriter, r *http.Request) {
@@ -108,3 +115,49 @@ func (h *RecommendedHandler) delete(w http.ResponseWriter, r *http.Request) {
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// @Summary      Recommended products report
+// @Description  Impressions, clicks and CTR of recommended products per day (UTC), rule and bank.
+// @Description  rule_id is empty for products of the static recommended list.
+// @Tags         admin/products
+// @Security     BearerAuth
+// @Produce      json
+// @Param        from  query     string  false  "Date from (YYYY-MM-DD or RFC3339)"
+// @Param        to    query     string  false  "Date to (YYYY-MM-DD or RFC3339)"
+// @Success      200   {array}   dto.RecommendationStatResponse
+// @Failure      400   {object}  dto.ErrorResponse
+// @Failure      403   {object}  dto.ErrorResponse
+// @Failure      500   {object}  dto.ErrorResponse
+// @Router       /admin/recommended-products/stats [get]
+func (h *RecommendedHandler) stats(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	from, err := parseDateParam(q.Get("from"), false)
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "invalid from")
+		return
+	}
+	to, err := parseDateParam(q.Get("to"), true)
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "invalid to")
+		return
+	}
+
+	rows, err := h.svc.Stats(r.Context(), from, to)
+	if err != nil {
+		httputils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	out := make([]dto.RecommendationStatResponse, 0, len(rows))
+	for _, s := range rows {
+		out = append(out, dto.RecommendationStatResponse{
+			Day:         s.Day,
+			RuleID:      s.RuleID,
+			RuleName:    s.RuleName,
+			BankCode:    s.BankCode,
+			Impressions: s.Impressions,
+			Clicks:      s.Clicks,
+			CTR:         s.CTR(),
+		})
+	}
+	httputils.WriteJSON(w, http.StatusOK, out)
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/http-server/dto"
+	httputils "multibank/backend/internal/http-server/utils"
+	authmw "multibank/backend/internal/service/auth/middleware"
+	"multibank/backend/internal/service/transfer"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Transfer interface {
+	Create(ctx context.Context, in transfer.CreateInput) (domain.Transfer, error)
+	Get(ctx context.Context, userID, id int64) (domain.Transfer, error)
+	List(ctx context.Context, userID int64) ([]domain.Transfer, error)
+
+	ProcessInFlight(ctx context.Context, batchLimit int) (int, error)
+}
+
+type TransferHandler struct {
+	svc Transfer
+}
+
+func RegisterTransferRoutes(r chi.Router, svc Transfer) {
+	h := &TransferHandler{svc: svc}
+
+	// Base prefix is outside: r.Route("/transfers", ...) in server.go
+	r.Post("/", h.create)
+	r.Get("/", h.list)
+	r.Get("/{id}", h.get)
+}
+
+// create starts a transfer between two accounts of the current user
+// @Summary      Transfer between own accounts
+// @Description  Moves money between two accounts of the user (from /accounts), also in different banks.
+// @Description  The source account must have the same currency as the target and enough balance.
+// @Description  A payment consent and a payment are made at the source bank; follow the status with GET /transfers/{id}:
+// @Description  AwaitingAuthorization -> Initiating -> Processing -> Completed | Failed. Completed (completed_at) is reported
+// @Description  by the source bank: the payment is settled there, the target bank may show the money a bit later.
+// @Description  A transfer stays Initiating while the source bank does not answer the payment, it is retried in the background.
+// @Tags         Transfers
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        input  body      dto.TransferCreateRequest  true  "Transfer payload"
+// @Success      201    {object}  dto.TransferResponse
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Failure      422    {object}  dto.ErrorResponse
+// @Failure      502    {object}  dto.ErrorResponse
+// @Router       /transfers [post]
+func (h *TransferHandler) create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authmw.UserIDFromContext(r.Context())
+	if !ok {
+		httputils.WriteError(w, http.StatusUnauthorized, "missing user in context")
+		return
+	}
+
+	var req dto.TransferCreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "bad json: "+err.Error())
+		return
+	}
+
+	t, err := h.svc.Create(r.Context(), transfer.CreateInput{
+		UserID: userID,
+		Source: transfer.AccountRef{BankID: req.Source.BankID, AccountID: req.Source.AccountID},
+		Target: transfer.AccountRef{BankID: req.Target.BankID, AccountID: req.Target.AccountID},
+		Amount: req.Amount,
+	})
+	if err != nil {
+		writeTransferError(w, err)
+		return
+	}
+	httputils.WriteJSON(w, http.StatusCreated, dto.TransferResponseFromDomain(t))
+}
+
+// list returns transfers of the current user
+// @Summary      List my transfers
+// @Tags         Transfers
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {array}   dto.TransferResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Router       /transfers [get]
+func (h *TransferHandler) list(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authmw.UserIDFromContext(r.Context())
+	if !ok {
+		httputils.WriteError(w, http.StatusUnauthorized, "missing user in context")
+		return
+	}
+
+	items, err := h.svc.List(r.Context(), userID)
+	if err != nil {
+		httputils.WriteError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	out := make([]dto.TransferResponse, 0, len(items))
+	for _, it := range items {
+		out = append(out, dto.TransferResponseFromDomain(it))
+	}
+	httputils.WriteJSON(w, http.StatusOK, out)
+}
+
+// get returns the transfer (unfinished transfers are checked in the bank)
+// @Summary      Get transfer status
+// @Tags         Transfers
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      int64  true  "Transfer ID"
+// @Success      200  {object}  dto.TransferResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Router       /transfers/{id} [get]
+func (h *TransferHandler) get(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authmw.UserIDFromContext(r.Context())
+	if !ok {
+		httputils.WriteError(w, http.StatusUnauthorized, "missing user in context")
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	t, err := h.svc.Get(r.Context(), userID, id)
+	if err != nil {
+		if errors.Is(err, transfer.ErrTransferNotFound) {
+			httputils.WriteError(w, http.StatusNotFound, "transfer not found")
+			return
+		}
+		httputils.WriteError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	httputils.WriteJSON(w, http.StatusOK, dto.TransferResponseFromDomain(t))
+}
+
+// writeTransferError maps transfer validation errors to HTTP statuses (other errors are bank failures,
+// the transfer is saved as Failed)
+func writeTransferError(w http.ResponseWriter, err error) {
+	statuses := []struct {
+		err  error
+		code int
+	}{
+		{transfer.ErrInvalidAmount, http.StatusBadRequest},
+		{transfer.ErrSameAccount, http.StatusBadRequest},
+		{transfer.ErrSourceAccountNotFound, http.StatusNotFound},
+		{transfer.ErrTargetAccountNotFound, http.StatusNotFound},
+		{transfer.ErrCurrencyMismatch, http.StatusUnprocessableEntity},
+		{transfer.ErrInsufficientFunds, http.StatusUnprocessableEntity},
+	}
+	for _, s := range statuses {
+		if errors.Is(err, s.err) {
+			httputils.WriteError(w, s.code, s.err.Error())
+			return
+		}
+	}
+	httputils.WriteError(w, http.StatusBadGateway, err.Error())
+}
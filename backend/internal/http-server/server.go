@@ -25,18 +25,31 @@ type Server struct {
 	mux      *chi.Mux
 	logger   *slog.Logger
 	shutdown chan struct{} // channel for graceful completing background cycles
+
+	// base context of background cycles, cancelled by Stop to abort bank calls in flight
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
 type Deps struct {
-	Logger             *slog.Logger
-	UserService        handlers.User
-	AuthService        handlers.Auth
-	BankService        handlers.Bank
-	ProductService     handlers.Product
-	RecommendedService handlers.Recommended
-	ConsentService     handlers.Consent
-	AccountService     handlers.Account
-	JWT                *jwt.Manager
+	Logger              *slog.Logger
+	UserService         handlers.User
+	AuthService         handlers.Auth
+	BankService         handlers.Bank
+	BankAdmin           handlers.BankAdmin
+	BankBreakers        handlers.BankBreakers
+	ProductService      handlers.Product
+	RecommendedService  handlers.Recommended
+	RecommendationRules handlers.RecommendationRules
+	ConsentService      handlers.Consent
+	AccountService      handlers.Account
+	FXService           handlers.FX
+	BalanceService      handlers.BalanceHistory
+	PaymentService      handlers.Payment
+	TransferService     handlers.Transfer
+	AgreementService    handlers.Agreement
+	NotificationService handlers.Notification
+	JWT                 *jwt.Manager
 }
 
 type Options struct {
@@ -49,6 +62,25 @@ type Options struct {
 	ConsentEnsureOnStart  bool
 	ConsentEnsureInterval time.Duration
 	ConsentEnsureWorkers  int
+
+	AccountSyncOnStart  bool
+	AccountSyncInterval time.Duration
+	AccountSyncWorkers  int
+
+	TransactionSyncOnStart  bool
+	TransactionSyncInterval time.Duration
+	TransactionSyncWorkers  int
+
+	FXImportOnStart  bool
+	FXImportInterval time.Duration // 0 = disable
+
+	TransferPollInterval time.Duration // 0 = disable (transfers are then advanced only by GET /transfers/{id})
+
+	PaymentResumeInterval time.Duration // 0 = disable (payments with an unknown outcome are then resent only by POST /payments)
+
+	ProductRefreshOnStart  bool
+	ProductRefreshInterval time.Duration // 0 = disable (the catalogue is then filled once on first GET /products)
+	ProductRefreshWorkers  int
 }
 
 func New(deps Deps, opts Options) *Server {
@@ -59,7 +91,7 @@ func New(deps Deps, opts Options) *Server {
 		AllowedOrigins:   []string{"http://localhost:5173", "http://127.0.0.1:5173"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		ExposedHeaders:   []string{"Link", "X-Next-Cursor"},
 		AllowCredentials: true,
 		MaxAge:           300, // cache preflight in seconds
 	}))
@@ -94,7 +126,7 @@ func New(deps Deps, opts Options) *Server {
 	// Protected routes /me/*
 	r.Route("/me", func(rr chi.Router) {
 		rr.Use(authmw.Auth(deps.JWT))
-		handlers.RegisterMeRoutes(rr, deps.UserService)
+		handlers.RegisterMeRoutes(rr, deps.UserService, deps.FXService, deps.BalanceService, deps.AgreementService, deps.ProductService, deps.NotificationService)
 	})
 
 	// Protected routes /banks
@@ -103,19 +135,33 @@ func New(deps Deps, opts Options) *Server {
 		handlers.RegisterBankRoutes(rr, deps.BankService)
 	})
 
+	// Admin routes /admin/banks
+	r.Route("/admin/banks", func(rr chi.Router) {
+		rr.Use(authmw.Auth(deps.JWT))
+		rr.Use(authmw.Admin(deps.UserService))
+		handlers.RegisterBankAdminRoutes(rr, deps.BankAdmin, deps.BankBreakers)
+	})
+
 	// Protected routes /products
 	r.Route("/products", func(rr chi.Router) {
 		rr.Use(authmw.Auth(deps.JWT))
-		handlers.RegisterProductRoutes(rr, deps.ProductService)
+		handlers.RegisterProductRoutes(rr, deps.ProductService, deps.AgreementService)
 	})
 
-	// Protected routes /recommended-products
+	// Admin routes /admin/recommended-products
 	r.Route("/admin/recommended-products", func(rr chi.Router) {
 		rr.Use(authmw.Auth(deps.JWT))
-		// TODO: protect for Admin
+		rr.Use(authmw.Admin(deps.UserService))
 		handlers.RegisterRecommendedRoutes(rr, deps.RecommendedService)
 	})
 
+	// Admin routes /admin/recommendation-rules
+	r.Route("/admin/recommendation-rules", func(rr chi.Router) {
+		rr.Use(authmw.Auth(deps.JWT))
+		rr.Use(authmw.Admin(deps.UserService))
+		handlers.RegisterRecommendationRuleRoutes(rr, deps.RecommendationRules)
+	})
+
 	// Protected routes /consents
 	r.Route("/consents", func(rr chi.Router) {
 		rr.Use(authmw.Auth(deps.JWT))
@@ -128,13 +174,28 @@ func New(deps Deps, opts Options) *Server {
 		handlers.RegisterAccountRoutes(rr, deps.AccountService) // передай в Deps
 	})
 
+	// Protected routes /payments
+	r.Route("/payments", func(rr chi.Router) {
+		rr.Use(authmw.Auth(deps.JWT))
+		handlers.RegisterPaymentRoutes(rr, deps.PaymentService)
+	})
+
+	// Protected routes /transfers
+	r.Route("/transfers", func(rr chi.Router) {
+		rr.Use(authmw.Auth(deps.JWT))
+		handlers.RegisterTransferRoutes(rr, deps.TransferService)
+	})
+
 	// swagger ui
 	r.Get("/swagger/*", httpSwagger.WrapHandler)
 
+	ctx, cancel := context.WithCancel(context.Background())
 	srv := &Server{
 		mux:      r,
 		logger:   deps.Logger,
 		shutdown: make(chan struct{}),
+		ctx:      ctx,
+		cancel:   cancel,
 	}
 
 	// ensure Banks TOKENS
@@ -149,6 +210,34 @@ func New(deps Deps, opts Options) *Server {
 	if opts.ConsentEnsureOnStart || opts.ConsentEnsureInterval > 0 {
 		go srv.runConsentEnsureLoop(deps, opts)
 	}
+
+	// sync Accounts and balances into the local store
+	if opts.AccountSyncOnStart || opts.AccountSyncInterval > 0 {
+		go srv.runAccountSyncLoop(deps, opts)
+	}
+
+	// sync Transactions into the local store
+	if opts.TransactionSyncOnStart || opts.TransactionSyncInterval > 0 {
+		go srv.runTransactionSyncLoop(deps, opts)
+	}
+
+	// import FX rates
+	if opts.FXImportOnStart || opts.FXImportInterval > 0 {
+		go srv.runFXImportLoop(deps, opts)
+	}
+
+	// move unfinished transfers forward
+	if opts.TransferPollInterval > 0 {
+		go srv.runTransferPollLoop(deps, opts)
+	}
+	// resend payments with an unknown outcome
+	if opts.PaymentResumeInterval > 0 {
+		go srv.runPaymentResumeLoop(deps, opts)
+	}
+	// refresh the product catalogue
+	if opts.ProductRefreshOnStart || opts.ProductRefreshInterval > 0 {
+		go srv.runProductRefreshLoop(deps, opts)
+	}
 	return srv
 }
 
@@ -161,6 +250,7 @@ func (s *Server) Stop() {
 		// already closed
 	default:
 		close(s.shutdown)
+		s.cancel()
 	}
 }
 
@@ -169,7 +259,7 @@ func (s *Server) ensureBanksOnce(deps Deps, timeout time.Duration, workers int)
 	if workers <= 0 {
 		workers = 1
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := context.WithTimeout(s.ctx, timeout)
 	defer cancel()
 
 	if err := deps.BankService.EnsureTokensForEnabledWithWorkers(ctx, workers); err != nil {
@@ -203,7 +293,7 @@ func (s *Server) runBankEnsureLoop(deps Deps, opt Options) {
 				workers = 1
 			}
 			// даём половину интервала на выполнение цикла
-			ctx, cancel := context.WithTimeout(context.Background(), opt.BankEnsureInterval/2)
+			ctx, cancel := context.WithTimeout(s.ctx, opt.BankEnsureInterval/2)
 			if err := deps.BankService.EnsureTokensForEnabledWithWorkers(ctx, workers); err != nil {
 				s.logger.Warn("scheduled bank token ensure failed",
 					slog.Any("err", err),
@@ -228,7 +318,7 @@ func (s *Server) runConsentEnsureLoop(deps Deps, opt Options) {
 
 	// разово на старте
 	if opt.ConsentEnsureOnStart {
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		ctx, cancel := context.WithTimeout(s.ctx, 30*time.Second)
 		n, err := deps.ConsentService.RefreshStale(ctx, 100, workers)
 		cancel()
 		if err != nil {
@@ -251,7 +341,7 @@ func (s *Server) runConsentEnsureLoop(deps Deps, opt Options) {
 			log.Info("stopping consent ensure loop")
 			return
 		case <-ticker.C:
-			ctx, cancel := context.WithTimeout(context.Background(), opt.ConsentEnsureInterval/2)
+			ctx, cancel := context.WithTimeout(s.ctx, opt.ConsentEnsureInterval/2)
 			n, err := deps.ConsentService.RefreshStale(ctx, 100, workers)
 			cancel()
 			if err != nil {
@@ -262,3 +352,223 @@ func (s *Server) runConsentEnsureLoop(deps Deps, opt Options) {
 		}
 	}
 }
+
+func (s *Server) runAccountSyncLoop(deps Deps, opt Options) {
+	log := s.logger.With(slog.String("component", "account-sync"))
+	workers := opt.AccountSyncWorkers
+	if workers <= 0 {
+		workers = 2
+	}
+
+	// разово на старте
+	if opt.AccountSyncOnStart {
+		ctx, cancel := context.WithTimeout(s.ctx, time.Minute)
+		n, err := deps.AccountService.SyncAccounts(ctx, workers)
+		cancel()
+		if err != nil {
+			log.Warn("initial account sync failed", logger.Err(err))
+		} else {
+			log.Info("initial account sync done", slog.Int("synced", n))
+		}
+	}
+
+	if opt.AccountSyncInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(opt.AccountSyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.shutdown:
+			log.Info("stopping account sync loop")
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(s.ctx, opt.AccountSyncInterval/2)
+			n, err := deps.AccountService.SyncAccounts(ctx, workers)
+			cancel()
+			if err != nil {
+				log.Warn("periodic account sync failed", logger.Err(err))
+			} else if n > 0 {
+				log.Info("periodic account sync", slog.Int("synced", n))
+			}
+		}
+	}
+}
+
+func (s *Server) runTransactionSyncLoop(deps Deps, opt Options) {
+	log := s.logger.With(slog.String("component", "transaction-sync"))
+	workers := opt.TransactionSyncWorkers
+	if workers <= 0 {
+		workers = 2
+	}
+
+	// разово на старте
+	if opt.TransactionSyncOnStart {
+		ctx, cancel := context.WithTimeout(s.ctx, 2*time.Minute)
+		n, err := deps.AccountService.SyncTransactions(ctx, workers)
+		cancel()
+		if err != nil {
+			log.Warn("initial transaction sync failed", logger.Err(err))
+		} else {
+			log.Info("initial transaction sync done", slog.Int("changed", n))
+		}
+	}
+
+	if opt.TransactionSyncInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(opt.TransactionSyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.shutdown:
+			log.Info("stopping transaction sync loop")
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(s.ctx, opt.TransactionSyncInterval/2)
+			n, err := deps.AccountService.SyncTransactions(ctx, workers)
+			cancel()
+			if err != nil {
+				log.Warn("periodic transaction sync failed", logger.Err(err))
+			} else if n > 0 {
+				log.Info("periodic transaction sync", slog.Int("changed", n))
+			}
+		}
+	}
+}
+
+func (s *Server) runFXImportLoop(deps Deps, opt Options) {
+	log := s.logger.With(slog.String("component", "fx-import"))
+
+	// разово на старте
+	if opt.FXImportOnStart {
+		ctx, cancel := context.WithTimeout(s.ctx, 30*time.Second)
+		n, err := deps.FXService.Import(ctx)
+		cancel()
+		if err != nil {
+			log.Warn("initial fx import failed", logger.Err(err))
+		} else {
+			log.Info("initial fx import done", slog.Int("rates", n))
+		}
+	}
+
+	if opt.FXImportInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(opt.FXImportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.shutdown:
+			log.Info("stopping fx import loop")
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(s.ctx, time.Minute)
+			n, err := deps.FXService.Import(ctx)
+			cancel()
+			if err != nil {
+				log.Warn("periodic fx import failed", logger.Err(err))
+			} else {
+				log.Info("periodic fx import", slog.Int("rates", n))
+			}
+		}
+	}
+}
+
+func (s *Server) runTransferPollLoop(deps Deps, opt Options) {
+	log := s.logger.With(slog.String("component", "transfer-poll"))
+
+	ticker := time.NewTicker(opt.TransferPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.shutdown:
+			log.Info("stopping transfer poll loop")
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(s.ctx, time.Minute)
+			n, err := deps.TransferService.ProcessInFlight(ctx, 50)
+			cancel()
+			if err != nil {
+				log.Warn("periodic transfer poll failed", logger.Err(err))
+			} else if n > 0 {
+				log.Info("periodic transfer poll", slog.Int("finished", n))
+			}
+		}
+	}
+}
+
+func (s *Server) runPaymentResumeLoop(deps Deps, opt Options) {
+	log := s.logger.With(slog.String("component", "payment-resume"))
+
+	ticker := time.NewTicker(opt.PaymentResumeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.shutdown:
+			log.Info("stopping payment resume loop")
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(s.ctx, time.Minute)
+			n, err := deps.PaymentService.ResumePayments(ctx, 50)
+			cancel()
+			if err != nil {
+				log.Warn("periodic payment resume failed", logger.Err(err))
+			} else if n > 0 {
+				log.Info("periodic payment resume", slog.Int("answered", n))
+			}
+		}
+	}
+}
+
+func (s *Server) runProductRefreshLoop(deps Deps, opt Options) {
+	log := s.logger.With(slog.String("component", "product-refresh"))
+	workers := opt.ProductRefreshWorkers
+	if workers <= 0 {
+		workers = 2
+	}
+
+	// разово на старте
+	if opt.ProductRefreshOnStart {
+		ctx, cancel := context.WithTimeout(s.ctx, time.Minute)
+		n, err := deps.ProductService.Refresh(ctx, workers)
+		cancel()
+		if err != nil {
+			log.Warn("initial product refresh failed", logger.Err(err))
+		} else {
+			log.Info("initial product refresh done", slog.Int("products", n))
+		}
+	}
+
+	if opt.ProductRefreshInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(opt.ProductRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.shutdown:
+			log.Info("stopping product refresh loop")
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(s.ctx, opt.ProductRefreshInterval/2)
+			n, err := deps.ProductService.Refresh(ctx, workers)
+			cancel()
+			if err != nil {
+				log.Warn("periodic product refresh failed", logger.Err(err))
+			} else {
+				log.Info("periodic product refresh", slog.Int("products", n))
+			}
+		}
+	}
+}
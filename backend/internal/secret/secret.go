@@ -0,0 +1,228 @@
+// internal/secret/secret.go
+
+// Package secret implements envelope encryption of secrets kept in the DB: every value is encrypted
+// by its own random data key (AES-256-GCM), and the data key is encrypted by the master key.
+// Rotating the master key re-encrypts only the data keys. A value is bound to its place in the DB
+// (additional authenticated data, e.g. "banks.password:3"): copied to another row it does not open.
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// prefix of sealed values: enc:v2:<master key id>:<encrypted data key>:<encrypted value>
+const prefix = "enc:v2:"
+
+// legacyPrefix — values of earlier versions, sealed without additional data (opened, not written)
+const legacyPrefix = "enc:v1:"
+
+const keySize = 32 // AES-256
+
+var (
+	ErrInvalidKey = errors.New("invalid master key")
+	ErrUnknownKey = errors.New("value is encrypted by an unknown master key")
+	ErrCorrupted  = errors.New("encrypted value is corrupted")
+)
+
+type masterKey struct {
+	id   string
+	aead cipher.AEAD
+}
+
+// Box seals values by the current master key and opens values sealed by the current or old keys
+type Box struct {
+	current masterKey
+	keys    map[string]masterKey // by id, current and old
+}
+
+// NewBox takes master keys as base64 of 32 bytes: the current one and the previous ones (kept until rotation is done)
+func NewBox(current string, old ...string) (*Box, error) {
+	cur, err := parseKey(current)
+	if err != nil {
+		return nil, fmt.Errorf("current: %w", err)
+	}
+	b := &Box{current: cur, keys: map[string]masterKey{cur.id: cur}}
+	for i, s := range old {
+		if strings.TrimSpace(s) == "" {
+			continue
+		}
+		k, err := parseKey(s)
+		if err != nil {
+			return nil, fmt.Errorf("old #%d: %w", i+1, err)
+		}
+		b.keys[k.id] = k
+	}
+	return b, nil
+}
+
+// GenerateKey returns a new random master key in the NewBox format
+func GenerateKey() string {
+	k := make([]byte, keySize)
+	_, _ = rand.Read(k)
+	return base64.StdEncoding.EncodeToString(k)
+}
+
+// KeyID is the id of the current master key written into sealed values
+func (b *Box) KeyID() string { return b.current.id }
+
+// Seal encrypts the value by a new data key, aad names the place of the value (it is not stored)
+func (b *Box) Seal(plaintext, aad string) (string, error) {
+	dek := make([]byte, keySize)
+	if _, err := rand.Read(dek); err != nil {
+		return "", err
+	}
+	aead, err := newAEAD(dek)
+	if err != nil {
+		return "", err
+	}
+	return b.format(b.current.id, seal(b.current.aead, dek, nil), seal(aead, []byte(plaintext), []byte(aad))), nil
+}
+
+// Open decrypts a sealed value with the aad it was sealed with (ErrCorrupted if it differs).
+// Values without the prefix are plaintext written before encryption was enabled and are returned as is,
+// values of the legacy format are opened without aad.
+func (b *Box) Open(stored, aad string) (string, error) {
+	if !IsSealed(stored) {
+		return stored, nil
+	}
+	_, dek, body, err := b.unpack(stored)
+	if err != nil {
+		return "", err
+	}
+	aead, err := newAEAD(dek)
+	if err != nil {
+		return "", err
+	}
+	var ad []byte
+	if !isLegacy(stored) {
+		ad = []byte(aad)
+	}
+	plain, err := open(aead, body, ad)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+// Current reports whether the value is sealed in the current format by the current master key
+func (b *Box) Current(stored string) bool {
+	if !strings.HasPrefix(stored, prefix) {
+		return false
+	}
+	id, _, _ := strings.Cut(strings.TrimPrefix(stored, prefix), ":")
+	return id == b.current.id
+}
+
+// Rewrap brings the value to the current format and master key: plaintext and legacy values are sealed
+// with aad, a value sealed by an old key gets its data key re-encrypted, the value itself is not touched.
+// A value that does not open with aad (moved from another place) is ErrCorrupted.
+func (b *Box) Rewrap(stored, aad string) (string, error) {
+	if !IsSealed(stored) || isLegacy(stored) {
+		plain, err := b.Open(stored, aad)
+		if err != nil {
+			return "", err
+		}
+		return b.Seal(plain, aad)
+	}
+	if b.Current(stored) {
+		return stored, nil
+	}
+	if _, err := b.Open(stored, aad); err != nil {
+		return "", err
+	}
+	_, dek, body, err := b.unpack(stored)
+	if err != nil {
+		return "", err
+	}
+	return b.format(b.current.id, seal(b.current.aead, dek, nil), body), nil
+}
+
+// IsSealed reports whether the value was written by Seal (in the current or the legacy format)
+func IsSealed(stored string) bool {
+	return strings.HasPrefix(stored, prefix) || isLegacy(stored)
+}
+
+func isLegacy(stored string) bool {
+	return strings.HasPrefix(stored, legacyPrefix)
+}
+
+func (b *Box) format(keyID string, wrappedDEK, body []byte) string {
+	enc := base64.RawStdEncoding
+	return prefix + keyID + ":" + enc.EncodeToString(wrappedDEK) + ":" + enc.EncodeToString(body)
+}
+
+// unpack returns the master key id, the decrypted data key and the encrypted value
+func (b *Box) unpack(stored string) (string, []byte, []byte, error) {
+	rest, ok := strings.CutPrefix(stored, prefix)
+	if !ok {
+		rest = strings.TrimPrefix(stored, legacyPrefix)
+	}
+	parts := strings.Split(rest, ":")
+	if len(parts) != 3 {
+		return "", nil, nil, ErrCorrupted
+	}
+	k, ok := b.keys[parts[0]]
+	if !ok {
+		return "", nil, nil, fmt.Errorf("%w: %s", ErrUnknownKey, parts[0])
+	}
+	wrapped, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", nil, nil, ErrCorrupted
+	}
+	body, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", nil, nil, ErrCorrupted
+	}
+	dek, err := open(k.aead, wrapped, nil)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return k.id, dek, body, nil
+}
+
+func parseKey(s string) (masterKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil || len(raw) != keySize {
+		return masterKey{}, fmt.Errorf("%w: want base64 of %d bytes", ErrInvalidKey, keySize)
+	}
+	aead, err := newAEAD(raw)
+	if err != nil {
+		return masterKey{}, err
+	}
+	sum := sha256.Sum256(raw)
+	return masterKey{id: hex.EncodeToString(sum[:4]), aead: aead}, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal returns nonce || ciphertext
+func seal(aead cipher.AEAD, plaintext, ad []byte) []byte {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	_, _ = rand.Read(nonce)
+	return aead.Seal(nonce, nonce, plaintext, ad)
+}
+
+func open(aead cipher.AEAD, data, ad []byte) ([]byte, error) {
+	if len(data) < aead.NonceSize() {
+		return nil, ErrCorrupted
+	}
+	plain, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], ad)
+	if err != nil {
+		return nil, ErrCorrupted
+	}
+	return plain, nil
+}
@@ -3,15 +3,40 @@ package account
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"multibank/backend/internal/domain"
 	"multibank/backend/internal/logger"
 	ob "multibank/backend/internal/service/openbanking"
+	"sync"
 	"time"
+
+	"golang.org/x/sync/errgroup"
 )
 
 type ConsentRepo interface {
 	ListByUser(ctx context.Context, userID int64, bankID *int64) ([]domain.AccountConsent, error)
+	ListAuthorized(ctx context.Context) ([]domain.AccountConsent, error)
+}
+
+type TransactionRepo interface {
+	UpsertMany(ctx context.Context, bankID int64, items []domain.Transaction) (int, error)
+	LastBookingDate(ctx context.Context, bankID int64, accountID string) (*time.Time, error)
+	MarkSynced(ctx context.Context, userID, bankID int64, accountID string, at time.Time) error
+	LastSyncedAt(ctx context.Context, userID int64, bankID *int64, accountID string) (*time.Time, error)
+	ListByUserAccount(ctx context.Context, userID int64, bankID *int64, accountID string, f domain.TransactionFilter) ([]domain.Transaction, int, error)
+}
+
+type AccountRepo interface {
+	Upsert(ctx context.Context, a domain.BankAccount) (int64, error)
+	ReplaceBalances(ctx context.Context, bankAccountID int64, items []domain.Balance, syncedAt time.Time) error
+	DeleteMissing(ctx context.Context, consentID int64, keep []string) (int64, error)
+	ListByUser(ctx context.Context, userID int64, bankID *int64) ([]domain.AccountShort, error)
+}
+
+type SnapshotRepo interface {
+	Record(ctx context.Context, s domain.BalanceSnapshot) error
 }
 
 type BankService interface {
@@ -20,79 +45,237 @@ type BankService interface {
 }
 
 type OBAccountsClient interface {
-	ListAccounts(bank domain.Bank, clientID, bearer, consentID, requestingBank string) ([]ob.ListAccountsRespData, error)
-	GetInterimAvailableBalance(bank domain.Bank, accountID, bearer, consentID, requestingBank string) (amount, currency string, err error)
+	ListAccounts(ctx context.Context, bank domain.Bank, clientID, bearer, consentID, requestingBank string) ([]ob.ListAccountsRespData, error)
+	GetBalances(ctx context.Context, bank domain.Bank, accountID, bearer, consentID, requestingBank string) ([]domain.Balance, error)
+	ListTransactions(ctx context.Context, bank domain.Bank, accountID, bearer, consentID, requestingBank string, f domain.TransactionFilter) ([]domain.Transaction, int, error)
 }
 
+var (
+	ErrAccountNotFound = errors.New("account not found")
+)
+
 type Service struct {
-	log     *slog.Logger
-	consent ConsentRepo
-	banks   BankService
-	client  OBAccountsClient
+	log      *slog.Logger
+	consent  ConsentRepo
+	banks    BankService
+	client   OBAccountsClient
+	txs      TransactionRepo
+	accounts AccountRepo
+	snaps    SnapshotRepo
+
+	staleAfter time.Duration // accounts not refreshed for this long are marked as stale
 }
 
-func New(log *slog.Logger, consentRepo ConsentRepo, banks BankService, client OBAccountsClient) *Service {
-	return &Service{log: log, consent: consentRepo, banks: banks, client: client}
+func New(log *slog.Logger, consentRepo ConsentRepo, banks BankService, client OBAccountsClient,
+	txRepo TransactionRepo, accountRepo AccountRepo, snapRepo SnapshotRepo, staleAfter time.Duration) *Service {
+	return &Service{log: log, consent: consentRepo, banks: banks, client: client,
+		txs: txRepo, accounts: accountRepo, snaps: snapRepo, staleAfter: staleAfter}
 }
 
-// ListUserAccounts collects accounts based on the user's consent.
+// ListUserAccounts returns accounts of the user from the local store (refreshed by SyncAccounts).
+// If nothing is stored yet, the user's authorized consents are synced once on demand.
 // If bankID != nil filter by 1 bank
 func (s *Service) ListUserAccounts(ctx context.Context, userID int64, bankID *int64) ([]domain.AccountShort, error) {
-	// 1) Берём согласия пользователя (лучше фильтровать по статусу Authorized, но можно и все)
-	consents, err := s.consent.ListByUser(ctx, userID, bankID)
+	const op = "service.account.ListUserAccounts"
+
+	out, err := s.accounts.ListByUser(ctx, userID, bankID)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	out := make([]domain.AccountShort, 0, 16)
 
-	for _, c := range consents {
-		// check consent nil
-		if c.ConsentID == nil || *c.ConsentID == "" {
-			continue
+	// first access — fill the store right now
+	if len(out) == 0 {
+		consents, err := s.consent.ListByUser(ctx, userID, bankID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		synced := false
+		for _, c := range consents {
+			if c.Status != domain.Authorised || c.ConsentID == nil || *c.ConsentID == "" {
+				continue
+			}
+			if _, err := s.syncConsentAccounts(ctx, c); err != nil {
+				s.log.Warn("on-demand accounts sync failed", logger.Err(err), slog.Int64("consent_id", c.ID))
+				continue
+			}
+			synced = true
+		}
+		if synced {
+			if out, err = s.accounts.ListByUser(ctx, userID, bankID); err != nil {
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
 		}
+	}
 
-		bank, err := s.banks.GetBankByID(ctx, c.BankID)
-		if err != nil {
-			s.log.Warn("get bank failed", logger.Err(err), slog.Int64("bank_id", c.BankID))
-			continue
+	s.fillShort(out)
+	return out, nil
+}
+
+// ListStoredAccounts returns accounts of the user from the local store only, without the on-demand sync.
+// For callers that must not reach the banks, e.g. recommendation targeting.
+func (s *Service) ListStoredAccounts(ctx context.Context, userID int64) ([]domain.AccountShort, error) {
+	const op = "service.account.ListStoredAccounts"
+
+	out, err := s.accounts.ListByUser(ctx, userID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	s.fillShort(out)
+	return out, nil
+}
+
+// fillShort marks stale accounts and copies the primary balance into the short view
+func (s *Service) fillShort(out []domain.AccountShort) {
+	now := time.Now()
+	for i := range out {
+		out[i].Stale = out[i].LastSyncedAt == nil || now.Sub(*out[i].LastSyncedAt) > s.staleAfter
+		if b, ok := domain.PrimaryBalance(out[i].Balances); ok {
+			out[i].Amount = b.Amount
+			out[i].Currency = b.Currency
+			out[i].BalanceType = b.Type
+		}
+	}
+}
+
+// SyncAccounts goes through all authorized consents and refreshes stored accounts and balances.
+// Returns the number of refreshed accounts.
+func (s *Service) SyncAccounts(ctx context.Context, workers int) (int, error) {
+	return s.syncAuthorized(ctx, workers, "accounts", s.syncConsentAccounts)
+}
+
+// syncAuthorized runs syncConsent for every authorized consent, at most workers at a time.
+// A failed consent is logged and skipped. Returns the sum of synced items.
+func (s *Service) syncAuthorized(ctx context.Context, workers int, what string,
+	syncConsent func(ctx context.Context, c domain.AccountConsent) (int, error)) (int, error) {
+	if workers <= 0 {
+		workers = 1
+	}
+
+	items, err := s.consent.ListAuthorized(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		mu    sync.Mutex
+		total int
+	)
+
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(workers)
+
+	for _, it := range items {
+		if egCtx.Err() != nil {
+			break
 		}
-		token, _, err := s.banks.GetOrRefreshToken(ctx, bank.ID)
+		it := it
+		eg.Go(func() error {
+			n, err := syncConsent(egCtx, it)
+			if err != nil {
+				s.log.Warn(what+" sync failed", slog.Int64("consent_id", it.ID), logger.Err(err))
+			}
+
+			mu.Lock()
+			total += n
+			mu.Unlock()
+			return nil // мягкий пропуск
+		})
+	}
+	_ = eg.Wait()
+
+	return total, ctx.Err()
+}
+
+// syncConsentAccounts refreshes accounts and balances available by one consent.
+// If the bank is unavailable the stored data stays as is (and becomes stale).
+func (s *Service) syncConsentAccounts(ctx context.Context, c domain.AccountConsent) (int, error) {
+	const op = "service.account.syncConsentAccounts"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("consent_id", c.ID),
+		slog.Int64("bank_id", c.BankID),
+	)
+
+	bank, err := s.banks.GetBankByID(ctx, c.BankID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: get bank: %w", op, err)
+	}
+	token, _, err := s.banks.GetOrRefreshToken(ctx, bank.ID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: get token: %w", op, err)
+	}
+
+	// 1) list of accounts by client_id + consent headers
+	accs, err := s.client.ListAccounts(ctx, bank, c.ClientID, token, *c.ConsentID, c.RequestingBank)
+	if err != nil {
+		return 0, fmt.Errorf("%s: list accounts: %w", op, err)
+	}
+
+	synced := 0
+	keep := make([]string, 0, len(accs))
+	for _, a := range accs {
+		keep = append(keep, a.AccountID)
+
+		id, err := s.accounts.Upsert(ctx, domain.BankAccount{
+			UserID:         c.UserID,
+			BankID:         bank.ID,
+			ConsentID:      c.ID,
+			AccountID:      a.AccountID,
+			ClientID:       c.ClientID,
+			Status:         a.Status,
+			Currency:       a.Currency,
+			AccountType:    a.AccountType,
+			AccountSubType: a.AccountSubType,
+			Nickname:       a.Nickname,
+			OpeningDate:    a.OpeningDate,
+			Identification: a.Identification,
+		})
 		if err != nil {
-			s.log.Warn("get token failed", logger.Err(err), slog.Int64("bank_id", c.BankID))
-			continue
+			return synced, fmt.Errorf("%s: %w", op, err)
 		}
 
-		// 2) list of accounts by client_id + consent headers
-		accs, err := s.client.ListAccounts(bank, c.ClientID, token, *c.ConsentID, c.RequestingBank)
+		// 2) all balances of the account
+		balances, err := s.client.GetBalances(ctx, bank, a.AccountID, token, *c.ConsentID, c.RequestingBank)
 		if err != nil {
-			s.log.Warn("list accounts failed", logger.Err(err), slog.Int64("bank_id", c.BankID))
+			// keep the previous balance, the account becomes stale
+			log.Warn("get balances failed", logger.Err(err), slog.String("account_id", a.AccountID))
 			continue
 		}
 
-		// 3) the balance is InterimAvailable (упрощение)
-		for _, a := range accs {
-			amount, currency, err := s.client.GetInterimAvailableBalance(bank, a.AccountID, token, *c.ConsentID, c.RequestingBank)
+		now := time.Now()
+		if err := s.accounts.ReplaceBalances(ctx, id, balances, now); err != nil {
+			return synced, fmt.Errorf("%s: %w", op, err)
+		}
+		synced++
+
+		// 3) daily snapshot of the primary balance for history charts
+		if b, ok := domain.PrimaryBalance(balances); ok {
+			amount, err := domain.ParseKopecks(b.Amount)
 			if err != nil {
-				s.log.Warn("get balance failed",
-					logger.Err(err),
-					slog.String("account_id", a.AccountID),
-					slog.Int64("bank_id", c.BankID),
-				)
-				// skip - do not add blank balance
+				log.Warn("bad balance amount", slog.String("account_id", a.AccountID), slog.String("amount", b.Amount))
+				continue
+			}
+			if err := s.snaps.Record(ctx, domain.BalanceSnapshot{
+				UserID:      c.UserID,
+				BankID:      bank.ID,
+				AccountID:   a.AccountID,
+				Amount:      amount,
+				Currency:    b.Currency,
+				BalanceType: b.Type,
+				TakenAt:     now,
+			}); err != nil {
+				log.Warn("record balance snapshot failed", logger.Err(err), slog.String("account_id", a.AccountID))
 			}
-
-			out = append(out, domain.AccountShort{
-				AccountID:      a.AccountID,
-				Nickname:       a.Nickname,
-				Status:         a.Status,
-				AccountSubType: a.AccountSubType,
-				OpeningDate:    a.OpeningDate,
-				Amount:         amount,
-				Currency:       currency,
-				BankCode:       c.BankCode,
-				ClientID:       c.ClientID,
-			})
 		}
 	}
-	return out, nil
+
+	// 4) the bank does not return these accounts anymore
+	if n, err := s.accounts.DeleteMissing(ctx, c.ID, keep); err != nil {
+		log.Warn("delete missing accounts failed", logger.Err(err))
+	} else if n > 0 {
+		log.Info("removed accounts missing in the bank", slog.Int64("count", n))
+	}
+
+	return synced, nil
 }
@@ -0,0 +1,200 @@
+// internal/service/account/transactions.go
+package account
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/logger"
+	"time"
+)
+
+const (
+	defaultTransactionsLimit = 50
+	maxTransactionsLimit     = 500
+
+	syncPageLimit = 100 // page size for requests to the bank
+	syncMaxPages  = 50  // protection from endless paging
+)
+
+// TransactionsPage is a page of account transaction history
+type TransactionsPage struct {
+	Transactions []domain.Transaction
+	Page         int
+	Limit        int
+	Total        int
+	TotalPages   int
+	LastSyncedAt *time.Time
+}
+
+// ListAccountTransactions returns transaction history of the user's account from the local store.
+// If the account was never synced for the user, the user's consents are synced once on demand.
+// Returns ErrAccountNotFound if none of the user's consents gives access to the account.
+func (s *Service) ListAccountTransactions(ctx context.Context, userID int64, bankID *int64, accountID string, f domain.TransactionFilter) (TransactionsPage, error) {
+	const op = "service.account.ListAccountTransactions"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("account_id", accountID),
+	)
+
+	if f.Page <= 0 {
+		f.Page = 1
+	}
+	if f.Limit <= 0 {
+		f.Limit = defaultTransactionsLimit
+	}
+	if f.Limit > maxTransactionsLimit {
+		f.Limit = maxTransactionsLimit
+	}
+
+	synced, err := s.txs.LastSyncedAt(ctx, userID, bankID, accountID)
+	if err != nil {
+		return TransactionsPage{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// first access — sync user's consents right now
+	if synced == nil {
+		log.Info("account is not synced yet, syncing user transactions")
+
+		consents, err := s.consent.ListByUser(ctx, userID, bankID)
+		if err != nil {
+			return TransactionsPage{}, fmt.Errorf("%s: %w", op, err)
+		}
+		for _, c := range consents {
+			if c.Status != domain.Authorised || c.ConsentID == nil || *c.ConsentID == "" {
+				continue
+			}
+			if _, err := s.syncConsentTransactions(ctx, c); err != nil {
+				log.Warn("on-demand transactions sync failed", logger.Err(err), slog.Int64("consent_id", c.ID))
+			}
+		}
+
+		if synced, err = s.txs.LastSyncedAt(ctx, userID, bankID, accountID); err != nil {
+			return TransactionsPage{}, fmt.Errorf("%s: %w", op, err)
+		}
+		if synced == nil {
+			log.Warn("account not found in user consents")
+			return TransactionsPage{}, fmt.Errorf("%s: %w", op, ErrAccountNotFound)
+		}
+	}
+
+	items, total, err := s.txs.ListByUserAccount(ctx, userID, bankID, accountID, f)
+	if err != nil {
+		return TransactionsPage{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return TransactionsPage{
+		Transactions: items,
+		Page:         f.Page,
+		Limit:        f.Limit,
+		Total:        total,
+		TotalPages:   (total + f.Limit - 1) / f.Limit,
+		LastSyncedAt: synced,
+	}, nil
+}
+
+// SyncTransactions goes through all authorized consents and pulls new transactions into the local store.
+// Returns the number of inserted/changed transactions.
+func (s *Service) SyncTransactions(ctx context.Context, workers int) (int, error) {
+	return s.syncAuthorized(ctx, workers, "transactions", s.syncConsentTransactions)
+}
+
+// syncConsentTransactions syncs all accounts available by one consent.
+// For each account only transactions newer than the last stored booking date are requested.
+func (s *Service) syncConsentTransactions(ctx context.Context, c domain.AccountConsent) (int, error) {
+	const op = "service.account.syncConsentTransactions"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("consent_id", c.ID),
+		slog.Int64("bank_id", c.BankID),
+	)
+
+	bank, err := s.banks.GetBankByID(ctx, c.BankID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: get bank: %w", op, err)
+	}
+	token, _, err := s.banks.GetOrRefreshToken(ctx, bank.ID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: get token: %w", op, err)
+	}
+
+	accs, err := s.client.ListAccounts(ctx, bank, c.ClientID, token, *c.ConsentID, c.RequestingBank)
+	if err != nil {
+		return 0, fmt.Errorf("%s: list accounts: %w", op, err)
+	}
+
+	total := 0
+	for _, a := range accs {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		from, err := s.txs.LastBookingDate(ctx, bank.ID, a.AccountID)
+		if err != nil {
+			return total, fmt.Errorf("%s: %w", op, err)
+		}
+
+		n, err := s.syncAccountTransactions(ctx, bank, c, a.AccountID, from)
+		total += n
+		if err != nil {
+			// do not mark as synced, try the next account
+			log.Warn("account transactions sync failed", slog.String("account_id", a.AccountID), logger.Err(err))
+			continue
+		}
+
+		if err := s.txs.MarkSynced(ctx, c.UserID, bank.ID, a.AccountID, time.Now()); err != nil {
+			return total, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if total > 0 {
+		log.Info("transactions synced", slog.Int("changed", total))
+	}
+	return total, nil
+}
+
+// syncAccountTransactions pages through /accounts/{id}/transactions starting from `from` (inclusive,
+// rows with the same booking date are deduplicated by the store) and saves them.
+func (s *Service) syncAccountTransactions(ctx context.Context, bank domain.Bank, c domain.AccountConsent, accountID string, from *time.Time) (int, error) {
+	token, _, err := s.banks.GetOrRefreshToken(ctx, bank.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for page := 1; page <= syncMaxPages; page++ {
+		txs, totalPages, err := s.client.ListTransactions(ctx, bank, accountID, token, *c.ConsentID, c.RequestingBank, domain.TransactionFilter{
+			From:  from,
+			Page:  page,
+			Limit: syncPageLimit,
+		})
+		if err != nil {
+			return total, err
+		}
+		items := make([]domain.Transaction, 0, len(txs))
+		for _, t := range txs {
+			// without id the transaction can not be deduplicated
+			if t.TransactionID == "" {
+				continue
+			}
+			if t.AccountID == "" {
+				t.AccountID = accountID
+			}
+			items = append(items, t)
+		}
+
+		n, err := s.txs.UpsertMany(ctx, bank.ID, items)
+		if err != nil {
+			return total, err
+		}
+		total += n
+
+		if len(txs) < syncPageLimit || (totalPages > 0 && page >= totalPages) {
+			break
+		}
+	}
+	return total, nil
+}
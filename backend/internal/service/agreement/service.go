@@ -0,0 +1,424 @@
+// internal/service/agreement/service.go
+package agreement
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/logger"
+	ob "multibank/backend/internal/service/openbanking"
+	"multibank/backend/internal/storage"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Repo interface {
+	CreateConsent(ctx context.Context, c *domain.ProductAgreementConsent) (int64, error)
+	UpdateConsentAfterCheck(ctx context.Context, id int64, upd *domain.ProductAgreementConsent) error
+	GetConsentByID(ctx context.Context, id int64) (domain.ProductAgreementConsent, error)
+	LatestConsent(ctx context.Context, userID, bankID int64) (domain.ProductAgreementConsent, error)
+	ListAuthorizedConsents(ctx context.Context, userID int64, bankID *int64) ([]domain.ProductAgreementConsent, error)
+
+	Upsert(ctx context.Context, a *domain.ProductAgreement) (int64, error)
+	GetByID(ctx context.Context, id int64) (domain.ProductAgreement, error)
+	ListByUser(ctx context.Context, userID int64, bankID *int64) ([]domain.ProductAgreement, error)
+	MarkClosed(ctx context.Context, id int64, at time.Time) error
+}
+
+// AccountConsentRepo gives the client_id of the user in the bank (known from account consents)
+type AccountConsentRepo interface {
+	ListByUser(ctx context.Context, userID int64, bankID *int64) ([]domain.AccountConsent, error)
+}
+
+type BankService interface {
+	GetBankByID(ctx context.Context, id int64) (domain.Bank, error)
+	GetBankByCode(ctx context.Context, code string) (domain.Bank, error)
+	GetOrRefreshToken(ctx context.Context, bankID int64) (string, time.Time, error)
+}
+
+type OBAgreementClient interface {
+	RequestAgreementConsent(ctx context.Context, bank domain.Bank, clientID, bearer string) (*ob.AgreementConsentRequestResp, error)
+	GetAgreementConsent(ctx context.Context, bank domain.Bank, requestOrConsentID, bearer string) (*ob.AgreementConsentViewWrapper, error)
+	OpenAgreement(ctx context.Context, bank domain.Bank, in ob.AgreementInput, bearer, consentID string) (*ob.AgreementView, error)
+	ListAgreements(ctx context.Context, bank domain.Bank, clientID, bearer, consentID string) ([]ob.AgreementView, error)
+	CloseAgreement(ctx context.Context, bank domain.Bank, agreementID, clientID, bearer, consentID string) error
+}
+
+var (
+	ErrBankNotFound         = errors.New("bank not found")
+	ErrBankNotConnected     = errors.New("bank is not connected: request an account consent first")
+	ErrInvalidProduct       = errors.New("invalid product id")
+	ErrInvalidAmount        = errors.New("invalid amount")
+	ErrInvalidTerm          = errors.New("invalid term")
+	ErrConsentNotAuthorized = errors.New("product agreement consent is awaiting authorization in the bank")
+	ErrAgreementNotFound    = errors.New("product agreement not found")
+	ErrAgreementClosed      = errors.New("product agreement is already closed")
+	errMissingAgreementID   = errors.New("bank returned no agreement id")
+)
+
+type Service struct {
+	log      *slog.Logger
+	repo     Repo
+	consents AccountConsentRepo
+	banks    BankService
+	client   OBAgreementClient
+
+	reqBankCode string
+}
+
+func New(log *slog.Logger, repo Repo, consents AccountConsentRepo, banks BankService, client OBAgreementClient, reqBankCode string) *Service {
+	return &Service{log: log, repo: repo, consents: consents, banks: banks, client: client, reqBankCode: reqBankCode}
+}
+
+type ApplyInput struct {
+	UserID          int64
+	BankCode        string
+	ProductID       string
+	Amount          string // optional, e.g. "50000.00" (deposits, loans)
+	TermMonths      int    // optional
+	SourceAccountID string // optional, account to fund the deposit from
+}
+
+// Apply opens the product for the user in the bank.
+// The product agreement consent is requested on the first call and reused afterwards;
+// if the bank does not approve it automatically, ErrConsentNotAuthorized is returned until it is approved.
+func (s *Service) Apply(ctx context.Context, in ApplyInput) (domain.ProductAgreement, error) {
+	const op = "service.agreement.Apply"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", in.UserID),
+		slog.String("bank_code", in.BankCode),
+		slog.String("product_id", in.ProductID),
+	)
+
+	if strings.TrimSpace(in.ProductID) == "" {
+		return domain.ProductAgreement{}, fmt.Errorf("%s: %w", op, ErrInvalidProduct)
+	}
+	var amount *float64
+	if strings.TrimSpace(in.Amount) != "" {
+		v, err := strconv.ParseFloat(strings.TrimSpace(in.Amount), 64)
+		if err != nil || v <= 0 {
+			return domain.ProductAgreement{}, fmt.Errorf("%s: %w", op, ErrInvalidAmount)
+		}
+		amount = &v
+	}
+	var term *int
+	if in.TermMonths < 0 {
+		return domain.ProductAgreement{}, fmt.Errorf("%s: %w", op, ErrInvalidTerm)
+	}
+	if in.TermMonths > 0 {
+		term = &in.TermMonths
+	}
+
+	bank, err := s.banks.GetBankByCode(ctx, in.BankCode)
+	if err != nil {
+		log.Warn("failed to get bank", logger.Err(err))
+		return domain.ProductAgreement{}, fmt.Errorf("%s: %w", op, ErrBankNotFound)
+	}
+	clientID, err := s.clientID(ctx, in.UserID, bank.ID)
+	if err != nil {
+		return domain.ProductAgreement{}, fmt.Errorf("%s: %w", op, err)
+	}
+	token, _, err := s.banks.GetOrRefreshToken(ctx, bank.ID)
+	if err != nil {
+		return domain.ProductAgreement{}, fmt.Errorf("%s: get token: %w", op, err)
+	}
+
+	c, err := s.ensureConsent(ctx, in.UserID, bank, clientID, token)
+	if err != nil {
+		return domain.ProductAgreement{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	v, err := s.client.OpenAgreement(ctx, bank, ob.AgreementInput{
+		ClientID:        clientID,
+		ProductID:       in.ProductID,
+		Amount:          amount,
+		TermMonths:      term,
+		SourceAccountID: in.SourceAccountID,
+	}, token, *c.ConsentID)
+	if err != nil {
+		log.Warn("failed to open product agreement", logger.Err(err))
+		return domain.ProductAgreement{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if v.AgreementID == "" {
+		return domain.ProductAgreement{}, fmt.Errorf("%s: %w", op, errMissingAgreementID)
+	}
+
+	a := agreementFromView(*v, in.UserID, bank.ID, c.ID)
+	if a.ProductID == "" {
+		a.ProductID = in.ProductID
+	}
+	if a.Amount == "" && amount != nil {
+		a.Amount = strconv.FormatFloat(*amount, 'f', 2, 64)
+	}
+	if a.TermMonths == 0 {
+		a.TermMonths = in.TermMonths
+	}
+	a.SourceAccountID = in.SourceAccountID
+
+	id, err := s.repo.Upsert(ctx, &a)
+	if err != nil {
+		return domain.ProductAgreement{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("product agreement opened", slog.Int64("id", id), slog.String("agreement_id", a.AgreementID))
+	return s.repo.GetByID(ctx, id)
+}
+
+// List returns agreements of the user. Agreements are first refreshed from every bank
+// where the user has an authorized product agreement consent; unavailable banks are skipped.
+// If bankID != nil filter by 1 bank.
+func (s *Service) List(ctx context.Context, userID int64, bankID *int64) ([]domain.ProductAgreement, error) {
+	const op = "service.agreement.List"
+
+	log := s.log.With(slog.String("op", op), slog.Int64("user_id", userID))
+
+	consents, err := s.repo.ListAuthorizedConsents(ctx, userID, bankID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	seen := make(map[int64]struct{}, len(consents))
+	for _, c := range consents {
+		// newest consent of the bank only
+		if _, ok := seen[c.BankID]; ok {
+			continue
+		}
+		seen[c.BankID] = struct{}{}
+
+		if err := s.syncBank(ctx, c); err != nil {
+			log.Warn("product agreements sync failed", slog.Int64("bank_id", c.BankID), logger.Err(err))
+		}
+	}
+
+	out, err := s.repo.ListByUser(ctx, userID, bankID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return out, nil
+}
+
+// Close closes the user's agreement in the bank and keeps it with status closed
+func (s *Service) Close(ctx context.Context, userID, id int64) (domain.ProductAgreement, error) {
+	const op = "service.agreement.Close"
+
+	log := s.log.With(slog.String("op", op), slog.Int64("user_id", userID), slog.Int64("id", id))
+
+	a, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrAgreementNotFound) {
+			return domain.ProductAgreement{}, fmt.Errorf("%s: %w", op, ErrAgreementNotFound)
+		}
+		return domain.ProductAgreement{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if a.UserID != userID {
+		return domain.ProductAgreement{}, fmt.Errorf("%s: %w", op, ErrAgreementNotFound)
+	}
+	if a.Status == domain.AgreementClosed {
+		return domain.ProductAgreement{}, fmt.Errorf("%s: %w", op, ErrAgreementClosed)
+	}
+
+	bank, err := s.banks.GetBankByID(ctx, a.BankID)
+	if err != nil {
+		return domain.ProductAgreement{}, fmt.Errorf("%s: get bank: %w", op, err)
+	}
+	token, _, err := s.banks.GetOrRefreshToken(ctx, bank.ID)
+	if err != nil {
+		return domain.ProductAgreement{}, fmt.Errorf("%s: get token: %w", op, err)
+	}
+
+	// the agreement was opened by this consent, the client_id is the same
+	opened, err := s.repo.GetConsentByID(ctx, a.ConsentID)
+	if err != nil {
+		return domain.ProductAgreement{}, fmt.Errorf("%s: %w", op, err)
+	}
+	c, err := s.ensureConsent(ctx, userID, bank, opened.ClientID, token)
+	if err != nil {
+		return domain.ProductAgreement{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.client.CloseAgreement(ctx, bank, a.AgreementID, c.ClientID, token, *c.ConsentID); err != nil {
+		log.Warn("failed to close product agreement", logger.Err(err))
+		return domain.ProductAgreement{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := s.repo.MarkClosed(ctx, a.ID, time.Now()); err != nil {
+		return domain.ProductAgreement{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("product agreement closed", slog.String("agreement_id", a.AgreementID))
+	return s.repo.GetByID(ctx, a.ID)
+}
+
+// syncBank pulls agreements of the consent's client from the bank into the store
+func (s *Service) syncBank(ctx context.Context, c domain.ProductAgreementConsent) error {
+	bank, err := s.banks.GetBankByID(ctx, c.BankID)
+	if err != nil {
+		return err
+	}
+	token, _, err := s.banks.GetOrRefreshToken(ctx, bank.ID)
+	if err != nil {
+		return err
+	}
+
+	items, err := s.client.ListAgreements(ctx, bank, c.ClientID, token, *c.ConsentID)
+	if err != nil {
+		return err
+	}
+	for _, v := range items {
+		if v.AgreementID == "" {
+			continue
+		}
+		a := agreementFromView(v, c.UserID, bank.ID, c.ID)
+		if _, err := s.repo.Upsert(ctx, &a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureConsent returns an authorized product agreement consent of the user in the bank.
+// A new consent is requested if there is no usable one; a pending one is re-checked in the bank.
+func (s *Service) ensureConsent(ctx context.Context, userID int64, bank domain.Bank, clientID, token string) (domain.ProductAgreementConsent, error) {
+	c, err := s.repo.LatestConsent(ctx, userID, bank.ID)
+	switch {
+	case errors.Is(err, storage.ErrAgreementConsentNotFound):
+		if c, err = s.requestConsent(ctx, userID, bank, clientID, token); err != nil {
+			return domain.ProductAgreementConsent{}, err
+		}
+	case err != nil:
+		return domain.ProductAgreementConsent{}, err
+	case c.ClientID != clientID || expired(c):
+		if c, err = s.requestConsent(ctx, userID, bank, clientID, token); err != nil {
+			return domain.ProductAgreementConsent{}, err
+		}
+	}
+
+	if c.Status == domain.AwaitingAuthorisation {
+		if c, err = s.refreshConsent(ctx, c, bank, token); err != nil {
+			return domain.ProductAgreementConsent{}, err
+		}
+	}
+	if c.Status != domain.Authorised || c.ConsentID == nil || *c.ConsentID == "" {
+		return domain.ProductAgreementConsent{}, ErrConsentNotAuthorized
+	}
+	return c, nil
+}
+
+func (s *Service) requestConsent(ctx context.Context, userID int64, bank domain.Bank, clientID, token string) (domain.ProductAgreementConsent, error) {
+	resp, err := s.client.RequestAgreementConsent(ctx, bank, clientID, token)
+	if err != nil {
+		return domain.ProductAgreementConsent{}, err
+	}
+
+	c := &domain.ProductAgreementConsent{
+		UserID:         userID,
+		BankID:         bank.ID,
+		ConsentState:   resp.State(),
+		ClientID:       clientID,
+		RequestingBank: s.reqBankCode,
+	}
+
+	// auto-approved — take dates (and consent id) from the detailed view
+	if resp.AutoApproved != nil && *resp.AutoApproved {
+		if v, err := s.client.GetAgreementConsent(ctx, bank, c.Key(), token); err == nil {
+			c.Apply(v.State())
+		} else {
+			s.log.Warn("auto-approved but failed to fetch detailed product agreement consent", logger.Err(err))
+		}
+	}
+
+	id, err := s.repo.CreateConsent(ctx, c)
+	if err != nil {
+		return domain.ProductAgreementConsent{}, err
+	}
+	s.log.Info("product agreement consent created",
+		slog.Int64("id", id),
+		slog.Int64("bank_id", bank.ID),
+		slog.String("status", string(c.Status)),
+	)
+	return s.repo.GetConsentByID(ctx, id)
+}
+
+func (s *Service) refreshConsent(ctx context.Context, c domain.ProductAgreementConsent, bank domain.Bank, token string) (domain.ProductAgreementConsent, error) {
+	v, err := s.client.GetAgreementConsent(ctx, bank, c.Key(), token)
+	if err != nil {
+		return domain.ProductAgreementConsent{}, err
+	}
+
+	upd := c
+	upd.Apply(v.State())
+	if err := s.repo.UpdateConsentAfterCheck(ctx, c.ID, &upd); err != nil {
+		return domain.ProductAgreementConsent{}, err
+	}
+	return s.repo.GetConsentByID(ctx, c.ID)
+}
+
+// clientID returns the client_id of the user in the bank (from the newest account consent)
+func (s *Service) clientID(ctx context.Context, userID, bankID int64) (string, error) {
+	consents, err := s.consents.ListByUser(ctx, userID, &bankID)
+	if err != nil {
+		return "", err
+	}
+	for _, c := range consents {
+		if c.ClientID != "" && c.Status != domain.Rejected {
+			return c.ClientID, nil
+		}
+	}
+	return "", ErrBankNotConnected
+}
+
+func agreementFromView(v ob.AgreementView, userID, bankID, consentID int64) domain.ProductAgreement {
+	a := domain.ProductAgreement{
+		UserID:        userID,
+		BankID:        bankID,
+		ConsentID:     consentID,
+		AgreementID:   v.AgreementID,
+		ProductID:     v.ProductID,
+		ProductType:   v.ProductType,
+		ProductName:   v.ProductName,
+		AccountNumber: v.AccountNumber,
+		Status:        normalizeAgreementStatus(v.Status),
+		StartDate:     parseDate(v.StartDate),
+		EndDate:       parseDate(v.EndDate),
+	}
+	if f, err := v.Amount.Float64(); err == nil && f > 0 {
+		a.Amount = strconv.FormatFloat(f, 'f', 2, 64)
+	}
+	if v.TermMonths != nil {
+		a.TermMonths = *v.TermMonths
+	}
+	return a
+}
+
+func normalizeAgreementStatus(raw string) domain.AgreementStatus {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "", "active", "open", "opened":
+		return domain.AgreementActive
+	case "closed", "terminated":
+		return domain.AgreementClosed
+	default:
+		return domain.AgreementStatus(strings.ToLower(raw))
+	}
+}
+
+// parseDate accepts a date-time (RFC3339) or a plain date
+func parseDate(s string) *time.Time {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+func expired(c domain.ProductAgreementConsent) bool {
+	return c.ExpirationDateTime != nil && c.ExpirationDateTime.Before(time.Now())
+}
@@ -0,0 +1,45 @@
+// internal/service/auth/middleware/admin.go
+
+package mw
+
+import (
+	"context"
+	"errors"
+	"multibank/backend/internal/domain"
+	usersvc "multibank/backend/internal/service/user"
+	"net/http"
+)
+
+// UserLookup reads the user of the token
+type UserLookup interface {
+	GetByID(ctx context.Context, id int64) (domain.User, error)
+}
+
+// Admin — middleware после Auth, пропускающий только пользователей с users.is_admin.
+// The flag is read on every request, so revoked rights apply to tokens already issued.
+func Admin(users UserLookup) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := UserIDFromContext(r.Context())
+			if !ok {
+				http.Error(w, `{"error":"missing or invalid token"}`, http.StatusUnauthorized)
+				return
+			}
+
+			u, err := users.GetByID(r.Context(), userID)
+			switch {
+			case errors.Is(err, usersvc.ErrUserNotFound):
+				http.Error(w, `{"error":"admin rights required"}`, http.StatusForbidden)
+				return
+			case err != nil:
+				http.Error(w, `{"error":"internal error"}`, http.StatusInternalServerError)
+				return
+			case !u.IsAdmin:
+				http.Error(w, `{"error":"admin rights required"}`, http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
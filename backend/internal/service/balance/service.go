@@ -0,0 +1,287 @@
+// internal/service/balance/service.go
+package balance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"multibank/backend/internal/domain"
+	fxsvc "multibank/backend/internal/service/fx"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	defaultHistoryDays = 30
+	maxHistoryPoints   = 400 // protection from huge responses (e.g. interval=day for 10 years)
+)
+
+type SnapshotRepo interface {
+	ListByUser(ctx context.Context, userID int64, from, to time.Time) ([]domain.BalanceSnapshot, error)
+	LastBefore(ctx context.Context, userID int64, before time.Time) ([]domain.BalanceSnapshot, error)
+}
+
+type RateService interface {
+	Rate(ctx context.Context, currency, base string, at time.Time) (domain.NetWorthRate, error)
+}
+
+var (
+	ErrInvalidInterval = errors.New("invalid interval")
+	ErrInvalidRange    = errors.New("invalid date range")
+)
+
+type Service struct {
+	log   *slog.Logger
+	snaps SnapshotRepo
+	rates RateService
+}
+
+func New(log *slog.Logger, snaps SnapshotRepo, rates RateService) *Service {
+	return &Service{log: log, snaps: snaps, rates: rates}
+}
+
+// History returns balances over time between from and to (dates, inclusive; nil = last 30 days).
+// Every point is the balance at the end of the period (the last snapshot in it); an account without
+// a snapshot in the period keeps its previous value, starting from its last snapshot before from.
+// Accounts that are no longer synced (deleted at the bank or under a revoked consent) are carried forward
+// only up to the period of their last snapshot. Account series are in the account currency,
+// bank and total series are converted to base with the rate of the point date.
+func (s *Service) History(ctx context.Context, userID int64, from, to *time.Time, interval domain.HistoryInterval, base string) (domain.BalanceHistory, error) {
+	const op = "service.balance.History"
+
+	if interval == "" {
+		interval = domain.IntervalDay
+	}
+	if interval != domain.IntervalDay && interval != domain.IntervalWeek && interval != domain.IntervalMonth {
+		return domain.BalanceHistory{}, fmt.Errorf("%s: %w", op, ErrInvalidInterval)
+	}
+	base = strings.ToUpper(strings.TrimSpace(base))
+	if base == "" {
+		base = domain.BaseCurrency
+	}
+
+	end := truncateDay(time.Now().UTC())
+	if to != nil {
+		end = truncateDay(to.UTC())
+	}
+	start := end.AddDate(0, 0, -defaultHistoryDays+1)
+	if from != nil {
+		start = truncateDay(from.UTC())
+	}
+	if start.After(end) {
+		return domain.BalanceHistory{}, fmt.Errorf("%s: %w", op, ErrInvalidRange)
+	}
+
+	buckets := periods(start, end, interval)
+	if len(buckets) > maxHistoryPoints {
+		return domain.BalanceHistory{}, fmt.Errorf("%s: %w: too many points (%d)", op, ErrInvalidRange, len(buckets))
+	}
+
+	seeds, err := s.snaps.LastBefore(ctx, userID, start)
+	if err != nil {
+		return domain.BalanceHistory{}, fmt.Errorf("%s: %w", op, err)
+	}
+	snaps, err := s.snaps.ListByUser(ctx, userID, start, end)
+	if err != nil {
+		return domain.BalanceHistory{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	out := domain.BalanceHistory{
+		From:         start,
+		To:           end,
+		Interval:     interval,
+		BaseCurrency: base,
+		Accounts:     []domain.BalanceSeries{},
+		Banks:        []domain.BalanceSeries{},
+		Total:        make([]domain.BalancePoint, 0, len(buckets)),
+		Missing:      []string{},
+	}
+
+	// 1) per account: last snapshot in each period, carried forward to the following periods
+	type accKey struct {
+		bankID    int64
+		accountID string
+	}
+	type accSeries struct {
+		series   domain.BalanceSeries
+		seed     *int64    // the last value before start
+		values   []*int64  // by bucket index, nil = no data yet
+		active   bool      // still synced, carried forward to the end
+		lastSeen time.Time // date of the last snapshot
+	}
+	accs := make(map[accKey]*accSeries, 8)
+	order := make([]accKey, 0, 8)
+	account := func(sn domain.BalanceSnapshot) *accSeries {
+		k := accKey{sn.BankID, sn.AccountID}
+		a, ok := accs[k]
+		if !ok {
+			a = &accSeries{
+				series: domain.BalanceSeries{AccountID: sn.AccountID, BankID: sn.BankID, BankCode: sn.BankCode},
+				values: make([]*int64, len(buckets)),
+				active: sn.Active,
+			}
+			accs[k] = a
+			order = append(order, k)
+		}
+		a.series.Currency = sn.Currency
+		a.lastSeen = sn.Date
+		return a
+	}
+
+	for _, sn := range seeds {
+		v := sn.Amount
+		account(sn).seed = &v
+	}
+	for _, sn := range snaps {
+		a := account(sn)
+		i := bucketIndex(buckets, sn.Date)
+		if i < 0 {
+			continue
+		}
+		v := sn.Amount
+		a.values[i] = &v // snapshots are ordered by date, the last one of the period wins
+	}
+
+	// 2) bank and total series in the base currency
+	type rateKey struct {
+		currency string
+		date     time.Time
+	}
+	rates := make(map[rateKey]*float64, 16)
+	missing := make(map[string]struct{}, 2)
+	rate := func(currency string, at time.Time) (*float64, error) {
+		k := rateKey{currency, at}
+		if r, ok := rates[k]; ok {
+			return r, nil
+		}
+		r, err := s.rates.Rate(ctx, currency, base, at)
+		if err != nil {
+			if errors.Is(err, fxsvc.ErrUnknownCurrency) {
+				missing[currency] = struct{}{}
+				rates[k] = nil
+				return nil, nil
+			}
+			return nil, err
+		}
+		rates[k] = &r.Rate
+		return &r.Rate, nil
+	}
+
+	// converted sums are kept in fractional kopecks and rounded once per point
+	bankTotals := make(map[int64][]float64, 4)
+	bankCodes := make(map[int64]string, 4)
+	total := make([]float64, len(buckets))
+
+	for _, k := range order {
+		a := accs[k]
+		last := a.seed
+		for i, b := range buckets {
+			if !a.active && b.After(a.lastSeen) {
+				break // the account is gone since its last snapshot
+			}
+			if a.values[i] != nil {
+				last = a.values[i]
+			}
+			if last == nil {
+				continue // the account has no data yet
+			}
+			a.series.Points = append(a.series.Points, domain.BalancePoint{Date: b, Amount: *last})
+
+			r, err := rate(a.series.Currency, periodEnd(b, interval, end))
+			if err != nil {
+				return domain.BalanceHistory{}, fmt.Errorf("%s: %w", op, err)
+			}
+			if r == nil {
+				continue
+			}
+			if bankTotals[k.bankID] == nil {
+				bankTotals[k.bankID] = make([]float64, len(buckets))
+				bankCodes[k.bankID] = a.series.BankCode
+			}
+			bankTotals[k.bankID][i] += float64(*last) * *r
+			total[i] += float64(*last) * *r
+		}
+		if len(a.series.Points) > 0 {
+			out.Accounts = append(out.Accounts, a.series)
+		}
+	}
+
+	bankIDs := make([]int64, 0, len(bankTotals))
+	for id := range bankTotals {
+		bankIDs = append(bankIDs, id)
+	}
+	sort.Slice(bankIDs, func(i, j int) bool { return bankCodes[bankIDs[i]] < bankCodes[bankIDs[j]] })
+	for _, id := range bankIDs {
+		bs := domain.BalanceSeries{BankID: id, BankCode: bankCodes[id], Currency: base,
+			Points: make([]domain.BalancePoint, 0, len(buckets))}
+		for i, b := range buckets {
+			bs.Points = append(bs.Points, domain.BalancePoint{Date: b, Amount: int64(math.Round(bankTotals[id][i]))})
+		}
+		out.Banks = append(out.Banks, bs)
+	}
+	for i, b := range buckets {
+		out.Total = append(out.Total, domain.BalancePoint{Date: b, Amount: int64(math.Round(total[i]))})
+	}
+
+	for c := range missing {
+		out.Missing = append(out.Missing, c)
+	}
+	sort.Strings(out.Missing)
+	return out, nil
+}
+
+// periods returns start dates of the periods covering [start, end]
+func periods(start, end time.Time, interval domain.HistoryInterval) []time.Time {
+	out := make([]time.Time, 0, 32)
+	for p := periodStart(start, interval); !p.After(end); p = nextPeriod(p, interval) {
+		out = append(out, p)
+		if len(out) > maxHistoryPoints {
+			break
+		}
+	}
+	return out
+}
+
+func periodStart(t time.Time, interval domain.HistoryInterval) time.Time {
+	t = truncateDay(t)
+	switch interval {
+	case domain.IntervalWeek:
+		// weeks start on Monday
+		return t.AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+	case domain.IntervalMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return t
+}
+
+func nextPeriod(t time.Time, interval domain.HistoryInterval) time.Time {
+	switch interval {
+	case domain.IntervalWeek:
+		return t.AddDate(0, 0, 7)
+	case domain.IntervalMonth:
+		return t.AddDate(0, 1, 0)
+	}
+	return t.AddDate(0, 0, 1)
+}
+
+// periodEnd returns the last day of the period (not later than the end of the range)
+func periodEnd(start time.Time, interval domain.HistoryInterval, limit time.Time) time.Time {
+	e := nextPeriod(start, interval).AddDate(0, 0, -1)
+	if e.After(limit) {
+		return limit
+	}
+	return e
+}
+
+// bucketIndex returns the index of the period containing the date (-1 if out of range)
+func bucketIndex(buckets []time.Time, d time.Time) int {
+	i := sort.Search(len(buckets), func(i int) bool { return buckets[i].After(d) }) - 1
+	return i
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,174 @@
+// internal/service/bank/admin.go
+
+package bank
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/logger"
+	"multibank/backend/internal/storage"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	ErrBankNotFound = errors.New("bank not found")
+	ErrBankExists   = errors.New("bank already exists")
+	ErrInvalidBank  = errors.New("invalid bank")
+	ErrBankAuth     = errors.New("bank auth failed")
+)
+
+// AdapterRegistry knows the registered bank adapters (openbanking.Adapters)
+type AdapterRegistry interface {
+	Has(name string) bool
+}
+
+var bankCodeRe = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ListAll returns all banks, disabled ones too
+func (s *Service) ListAll(ctx context.Context) ([]domain.Bank, error) {
+	const op = "service.bank.ListAll"
+
+	banks, err := s.repo.ListBanks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return banks, nil
+}
+
+func (s *Service) CreateBank(ctx context.Context, b domain.Bank) (domain.Bank, error) {
+	const op = "service.bank.CreateBank"
+
+	b.Code = strings.TrimSpace(b.Code)
+	b.Login = strings.TrimSpace(b.Login)
+	switch {
+	case !bankCodeRe.MatchString(b.Code):
+		return domain.Bank{}, fmt.Errorf("%s: %w: code must be lowercase letters, digits, '-' or '_'", op, ErrInvalidBank)
+	case b.Login == "" || b.Password == "":
+		return domain.Bank{}, fmt.Errorf("%s: %w: login and password are required", op, ErrInvalidBank)
+	}
+	if err := s.validateBank(&b); err != nil {
+		return domain.Bank{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := s.repo.CreateBank(ctx, &b)
+	if err != nil {
+		if errors.Is(err, storage.ErrBankExists) {
+			return domain.Bank{}, fmt.Errorf("%s: %w", op, ErrBankExists)
+		}
+		return domain.Bank{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.log.Info("bank created", slog.String("op", op), slog.Int64("bank_id", id), slog.String("code", b.Code))
+	return s.getBank(ctx, op, id)
+}
+
+// UpdateBank changes name, api_base_url, rate limit and adapter of the bank.
+// The cached token is dropped, as it may belong to the old API.
+func (s *Service) UpdateBank(ctx context.Context, b domain.Bank) (domain.Bank, error) {
+	const op = "service.bank.UpdateBank"
+
+	if err := s.validateBank(&b); err != nil {
+		return domain.Bank{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := s.repo.UpdateBank(ctx, &b); err != nil {
+		return domain.Bank{}, fmt.Errorf("%s: %w", op, bankErr(err))
+	}
+	if err := s.repo.DeleteBankToken(ctx, b.ID); err != nil {
+		return domain.Bank{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.log.Info("bank updated", slog.String("op", op), slog.Int64("bank_id", b.ID))
+	return s.getBank(ctx, op, b.ID)
+}
+
+// SetEnabled turns the bank on or off: disabled banks are not listed to users and skipped by background jobs
+func (s *Service) SetEnabled(ctx context.Context, id int64, enabled bool) (domain.Bank, error) {
+	const op = "service.bank.SetEnabled"
+
+	if err := s.repo.SetBankEnabled(ctx, id, enabled); err != nil {
+		return domain.Bank{}, fmt.Errorf("%s: %w", op, bankErr(err))
+	}
+
+	s.log.Info("bank switched", slog.String("op", op), slog.Int64("bank_id", id), slog.Bool("enabled", enabled))
+	return s.getBank(ctx, op, id)
+}
+
+// RotateCredentials replaces login and secret of the team in the bank and drops the cached token
+func (s *Service) RotateCredentials(ctx context.Context, id int64, login, password string) (domain.Bank, error) {
+	const op = "service.bank.RotateCredentials"
+
+	login = strings.TrimSpace(login)
+	if login == "" || password == "" {
+		return domain.Bank{}, fmt.Errorf("%s: %w: login and password are required", op, ErrInvalidBank)
+	}
+	if err := s.repo.UpdateBankCredentials(ctx, id, login, password); err != nil {
+		return domain.Bank{}, fmt.Errorf("%s: %w", op, bankErr(err))
+	}
+	if err := s.repo.DeleteBankToken(ctx, id); err != nil {
+		return domain.Bank{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.log.Info("bank credentials rotated", slog.String("op", op), slog.Int64("bank_id", id))
+	return s.getBank(ctx, op, id)
+}
+
+// RefreshToken requests a new token even if the cached one is still valid
+func (s *Service) RefreshToken(ctx context.Context, id int64) (time.Time, error) {
+	const op = "service.bank.RefreshToken"
+
+	b, err := s.getBank(ctx, op, id)
+	if err != nil {
+		return time.Time{}, err
+	}
+	tok, err := s.refreshToken(ctx, b)
+	if err != nil {
+		s.log.Warn("failed to refresh bank token", slog.String("op", op), slog.Int64("bank_id", id), logger.Err(err))
+		return time.Time{}, fmt.Errorf("%s: %w: %w", op, ErrBankAuth, err)
+	}
+	return tok.ExpiresAt, nil
+}
+
+func (s *Service) getBank(ctx context.Context, op string, id int64) (domain.Bank, error) {
+	b, err := s.repo.GetBankByID(ctx, id)
+	if err != nil {
+		return domain.Bank{}, fmt.Errorf("%s: %w", op, bankErr(err))
+	}
+	return b, nil
+}
+
+// validateBank checks settings shared by create and update
+func (s *Service) validateBank(b *domain.Bank) error {
+	b.Name = strings.TrimSpace(b.Name)
+	b.APIBaseURL = strings.TrimRight(strings.TrimSpace(b.APIBaseURL), "/")
+	b.Adapter = strings.TrimSpace(b.Adapter)
+	if b.Adapter == "" {
+		b.Adapter = domain.BankAdapterSandbox
+	}
+
+	u, err := url.Parse(b.APIBaseURL)
+	switch {
+	case b.Name == "":
+		return fmt.Errorf("%w: name is required", ErrInvalidBank)
+	case err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "":
+		return fmt.Errorf("%w: api_base_url must be an http(s) url", ErrInvalidBank)
+	case b.RateLimit < 0 || b.RateBurst < 0:
+		return fmt.Errorf("%w: rate_limit and rate_burst must not be negative", ErrInvalidBank)
+	}
+	if reg, ok := s.tokens.(AdapterRegistry); ok && !reg.Has(b.Adapter) {
+		return fmt.Errorf("%w: unknown adapter %q", ErrInvalidBank, b.Adapter)
+	}
+	return nil
+}
+
+func bankErr(err error) error {
+	if errors.Is(err, storage.ErrBankNotFound) {
+		return ErrBankNotFound
+	}
+	return err
+}
@@ -0,0 +1,137 @@
+// internal/service/bank/reconcile.go
+
+package bank
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/storage"
+	"strings"
+)
+
+// Reconcile brings the banks declared in the config into the DB on start: missing banks are created,
+// declared fields of existing ones are updated. Banks that are not declared are left to /admin/banks.
+func (s *Service) Reconcile(ctx context.Context, decls []domain.BankDeclaration) error {
+	const op = "service.bank.Reconcile"
+
+	seen := make(map[string]struct{}, len(decls))
+	for _, d := range decls {
+		d.Code = strings.TrimSpace(d.Code)
+		d.Login = strings.TrimSpace(d.Login)
+		switch {
+		case !bankCodeRe.MatchString(d.Code):
+			return fmt.Errorf("%s: %w: code %q must be lowercase letters, digits, '-' or '_'", op, ErrInvalidBank, d.Code)
+		case d.Login == "":
+			return fmt.Errorf("%s: %w: bank %s: client_id is required", op, ErrInvalidBank, d.Code)
+		}
+		if _, dup := seen[d.Code]; dup {
+			return fmt.Errorf("%s: %w: bank %s is declared twice", op, ErrInvalidBank, d.Code)
+		}
+		seen[d.Code] = struct{}{}
+
+		existing, err := s.repo.GetBankByCode(ctx, d.Code)
+		switch {
+		case errors.Is(err, storage.ErrBankNotFound):
+			err = s.createDeclared(ctx, d)
+		case err == nil:
+			err = s.updateDeclared(ctx, existing, d)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: bank %s: %w", op, d.Code, err)
+		}
+	}
+	return nil
+}
+
+func (s *Service) createDeclared(ctx context.Context, d domain.BankDeclaration) error {
+	b := domain.Bank{
+		Name:       d.Name,
+		Code:       d.Code,
+		APIBaseURL: d.APIBaseURL,
+		Login:      d.Login,
+		IsEnabled:  true,
+		RateLimit:  domain.DefaultBankRateLimit,
+		RateBurst:  domain.DefaultBankRateBurst,
+		Adapter:    d.Adapter,
+	}
+	applyDeclared(&b, d)
+	if err := s.validateBank(&b); err != nil {
+		return err
+	}
+	if _, err := s.repo.CreateBank(ctx, &b); err != nil {
+		return err
+	}
+
+	log := s.log.With(slog.String("code", b.Code))
+	log.Info("bank from the config created")
+	if d.Password == nil {
+		log.Warn("no client secret in the config, set it by PUT /admin/banks/{id}/credentials")
+	}
+	return nil
+}
+
+func (s *Service) updateDeclared(ctx context.Context, existing domain.Bank, d domain.BankDeclaration) error {
+	next := existing
+	next.Name = d.Name
+	next.APIBaseURL = d.APIBaseURL
+	if d.Adapter != "" {
+		next.Adapter = d.Adapter
+	}
+	applyDeclared(&next, d)
+	if err := s.validateBank(&next); err != nil {
+		return err
+	}
+
+	apiChanged := next.APIBaseURL != existing.APIBaseURL || next.Adapter != existing.Adapter
+	settingsChanged := apiChanged || next.Name != existing.Name ||
+		next.RateLimit != existing.RateLimit || next.RateBurst != existing.RateBurst
+	credsChanged := d.Login != existing.Login || next.Password != existing.Password
+	enabledChanged := next.IsEnabled != existing.IsEnabled
+	if !settingsChanged && !credsChanged && !enabledChanged {
+		return nil
+	}
+
+	if settingsChanged {
+		if err := s.repo.UpdateBank(ctx, &next); err != nil {
+			return err
+		}
+	}
+	if credsChanged {
+		if err := s.repo.UpdateBankCredentials(ctx, existing.ID, d.Login, next.Password); err != nil {
+			return err
+		}
+	}
+	if enabledChanged {
+		if err := s.repo.SetBankEnabled(ctx, existing.ID, next.IsEnabled); err != nil {
+			return err
+		}
+	}
+	// the cached token was issued by another API or for other credentials
+	if apiChanged || credsChanged {
+		if err := s.repo.DeleteBankToken(ctx, existing.ID); err != nil {
+			return err
+		}
+	}
+
+	s.log.Info("bank from the config updated", slog.String("code", existing.Code))
+	return nil
+}
+
+// applyDeclared sets the optional fields declared in the config
+func applyDeclared(b *domain.Bank, d domain.BankDeclaration) {
+	if d.Password != nil {
+		b.Password = *d.Password
+	}
+	if d.RateLimit != nil {
+		b.RateLimit = *d.RateLimit
+	}
+	if d.RateBurst != nil {
+		b.RateBurst = *d.RateBurst
+	}
+	if d.Enabled != nil {
+		b.IsEnabled = *d.Enabled
+	}
+}
@@ -4,15 +4,11 @@ package bank
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
-	httputils "multibank/backend/internal/http-server/utils"
 	"multibank/backend/internal/logger"
+	"multibank/backend/internal/service/openbanking"
 	"multibank/backend/internal/storage"
-	"net/http"
-	"net/url"
 	"time"
 
 	"multibank/backend/internal/domain"
@@ -23,7 +19,7 @@ import (
 type Service struct {
 	log        *slog.Logger
 	repo       Repository
-	httpClient *http.Client
+	tokens     TokenSource
 	expirySkew time.Duration // time reserve, so as not to hit the expiration end-to-end
 }
 
@@ -32,31 +28,35 @@ type Repository interface {
 	GetBankByID(ctx context.Context, id int64) (domain.Bank, error)
 	GetBankByCode(ctx context.Context, code string) (domain.Bank, error)
 
+	ListBanks(ctx context.Context) ([]domain.Bank, error)
+	CreateBank(ctx context.Context, b *domain.Bank) (int64, error)
+	UpdateBank(ctx context.Context, b *domain.Bank) error
+	SetBankEnabled(ctx context.Context, id int64, enabled bool) error
+	UpdateBankCredentials(ctx context.Context, id int64, login, password string) error
+
 	UpsertBankToken(ctx context.Context, t domain.BankToken) error
 	GetBankToken(ctx context.Context, bankID int64) (domain.BankToken, error)
+	DeleteBankToken(ctx context.Context, bankID int64) error
+}
+
+// TokenSource issues team tokens in the bank API (openbanking.Adapters picks the flow of the bank)
+type TokenSource interface {
+	Token(ctx context.Context, bank domain.Bank) (openbanking.Token, error)
 }
 
 var (
 	ErrBanksNotFound = errors.New("banks not found")
 )
 
-func New(log *slog.Logger, repository Repository) *Service {
+func New(log *slog.Logger, repository Repository, tokens TokenSource) *Service {
 	return &Service{
 		log:        log,
 		repo:       repository,
-		httpClient: &http.Client{Timeout: 10 * time.Second},
+		tokens:     tokens,
 		expirySkew: 2 * time.Minute,
 	}
 }
 
-type bankTokenResp struct {
-	AccessToken string `json:"access_token"`
-	TokenType   string `json:"token_type"`
-	ClientID    string `json:"client_id"`
-	Algorithm   string `json:"algorithm"`
-	ExpiresIn   int64  `json:"expires_in"` // seconds
-}
-
 func (s *Service) GetOrRefreshToken(ctx context.Context, bankID int64) (string, time.Time, error) {
 
 	const op = "service.bank.GetOrRefreshToken"
@@ -81,68 +81,31 @@ func (s *Service) GetOrRefreshToken(ctx context.Context, bankID int64) (string,
 		log.Warn("failed to get bank details", logger.Err(err))
 		return "", time.Time{}, err
 	}
-	base, err := httputils.NormalizeURL(b.APIBaseURL)
-	if err != nil {
-		log.Warn("invalid bank api_base_url", slog.String("api_base_url", b.APIBaseURL), logger.Err(err))
-		return "", time.Time{}, err
-	}
-
-	// /auth/bank-token + query ?client_id=...&client_secret=...
-	tokenURL := base.ResolveReference(&url.URL{Path: "/auth/bank-token"})
-	q := tokenURL.Query()
-	q.Set("client_id", b.Login)
-	q.Set("client_secret", b.Password)
-	tokenURL.RawQuery = q.Encode()
-
-	// log w/o secret
-	masked := *tokenURL
-	mq := masked.Query()
-	mq.Set("client_secret", "******")
-	masked.RawQuery = mq.Encode()
-	log.Info("requesting bank token", slog.String("url", masked.String()))
-
-	// POST w/o body
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL.String(), http.NoBody)
+	tok, err := s.refreshToken(ctx, b)
 	if err != nil {
-		log.Warn("unable to create http request", logger.Err(err))
+		log.Warn("failed to get a bank token", logger.Err(err))
 		return "", time.Time{}, err
 	}
-	req.Header.Set("Accept", "application/json")
 
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		log.Warn("unable send req for a bank token", logger.Err(err))
-		return "", time.Time{}, err
-	}
-	defer resp.Body.Close()
+	log.Info("bank token successfully refreshed")
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		log.Warn("token endpoint returned non-200", slog.String("status", resp.Status))
-		return "", time.Time{}, fmt.Errorf("bank-token %d: %s", resp.StatusCode, string(body))
-	}
+	return tok.AccessToken, tok.ExpiresAt, nil
+}
 
-	var tr bankTokenResp
-	if err := json.NewDecoder(resp.Body).Decode(&tr); err != nil {
-		log.Warn("failed to decode bank token response", logger.Err(err))
-		return "", time.Time{}, err
-	}
-	if tr.AccessToken == "" || tr.ExpiresIn <= 0 {
-		return "", time.Time{}, fmt.Errorf("bank-token: invalid response")
+// refreshToken requests a new token (the flow depends on the bank adapter) and caches it
+func (s *Service) refreshToken(ctx context.Context, b domain.Bank) (openbanking.Token, error) {
+	tok, err := s.tokens.Token(ctx, b)
+	if err != nil {
+		return openbanking.Token{}, err
 	}
-
-	expiresAt := time.Now().Add(time.Duration(tr.ExpiresIn) * time.Second)
 	if err := s.repo.UpsertBankToken(ctx, domain.BankToken{
 		BankID:      b.ID,
-		AccessToken: tr.AccessToken,
-		ExpiresAt:   expiresAt,
+		AccessToken: tok.AccessToken,
+		ExpiresAt:   tok.ExpiresAt,
 	}); err != nil {
-		return "", time.Time{}, err
+		return openbanking.Token{}, err
 	}
-
-	log.Info("bank token successfully refreshed")
-
-	return tr.AccessToken, expiresAt, nil
+	return tok, nil
 }
 
 // ListEnabled return a list of all banks where IsEnabled == true
@@ -3,11 +3,12 @@ package consent
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"multibank/backend/internal/domain"
 	"multibank/backend/internal/logger"
-	ob "multibank/backend/internal/service/openbanking"
-	"strings"
+	"multibank/backend/internal/storage"
 	"time"
 )
 
@@ -17,6 +18,7 @@ type ConsentRepo interface {
 	GetByID(ctx context.Context, id int64) (domain.AccountConsent, error)
 	ListByUser(ctx context.Context, userID int64, bankID *int64) ([]domain.AccountConsent, error)
 	DeleteByID(ctx context.Context, id int64) error
+	MarkRevoked(ctx context.Context, id int64, at time.Time) error
 	ListNeedingRefresh(ctx context.Context, limit int) ([]domain.AccountConsent, error)
 }
 
@@ -28,10 +30,13 @@ type BankService interface {
 }
 
 type OBConsentClient interface {
-	RequestConsent(bank domain.Bank, clientID string, perms []domain.Permission, bearer string) (*ob.ConsentRequestResp, error)
-	GetConsent(bank domain.Bank, requestOrConsentID, bearer, xFapi string) (*ob.ConsentViewWrapper, error)
+	RequestConsent(ctx context.Context, bank domain.Bank, clientID string, perms []domain.Permission, bearer string) (domain.ConsentState, error)
+	GetConsent(ctx context.Context, bank domain.Bank, requestOrConsentID, bearer, xFapi string) (domain.ConsentState, error)
+	DeleteConsent(ctx context.Context, bank domain.Bank, consentID, bearer string) error
 }
 
+var ErrConsentNotFound = errors.New("consent not found")
+
 type Service struct {
 	log    *slog.Logger
 	repo   ConsentRepo
@@ -84,7 +89,7 @@ func (s *Service) Request(ctx context.Context, in CreateInput) (int64, error) {
 	// фиксированный набор разрешений
 	perms := s.defaultPerms
 
-	resp, err := s.client.RequestConsent(bank, in.ClientID, perms, token)
+	state, err := s.client.RequestConsent(ctx, bank, in.ClientID, perms, token)
 	if err != nil {
 		log.Warn("failed to request consent", logger.Err(err))
 		return 0, err
@@ -92,34 +97,13 @@ func (s *Service) Request(ctx context.Context, in CreateInput) (int64, error) {
 
 	now := time.Now()
 
-	status := normalizeRequestStatus(resp.Status, resp.AutoApproved)
-
-	var (
-		creation  *time.Time
-		updated   *time.Time
-		expire    *time.Time
-		consentID *string = resp.ConsentID
-	)
-
 	// Если автоодобрено — подтянем детальный вид, чтобы заполнить даты.
-	if resp.AutoApproved != nil && *resp.AutoApproved {
-		key := resp.RequestID
-		if consentID != nil && *consentID != "" {
-			key = *consentID
-		}
-
+	if state.AutoApproved != nil && *state.AutoApproved {
 		// получить токен и пробросить в GetConsent
 		token, _, errTok := s.banks.GetOrRefreshToken(ctx, bank.ID)
 		if errTok == nil {
-			if v, err := s.client.GetConsent(bank, key, token, s.reqBankCode); err == nil {
-				status = domain.ConsentStatus(v.Data.Status)
-				creation = &v.Data.CreationDateTime
-				updated = &v.Data.StatusUpdateDateTime
-				expire = &v.Data.ExpirationDateTime
-				if v.Data.ConsentID != "" {
-					cid := v.Data.ConsentID
-					consentID = &cid
-				}
+			if v, err := s.client.GetConsent(ctx, bank, state.Key(), token, s.reqBankCode); err == nil {
+				state.Apply(v)
 			} else {
 				log.Warn("auto-approved but failed to fetch detailed consent", logger.Err(err))
 			}
@@ -131,10 +115,7 @@ func (s *Service) Request(ctx context.Context, in CreateInput) (int64, error) {
 	c := &domain.AccountConsent{
 		UserID:             in.UserID,
 		BankID:             bank.ID,
-		RequestID:          resp.RequestID,
-		ConsentID:          consentID,
-		Status:             status,
-		AutoApproved:       resp.AutoApproved,
+		ConsentState:       state,
 		ClientID:           in.ClientID,
 		Permissions:        perms,
 		Reason:             s.defaultReason,
@@ -142,38 +123,22 @@ func (s *Service) Request(ctx context.Context, in CreateInput) (int64, error) {
 		RequestingBankName: s.reqBankName,
 		CreatedAt:          now,
 		UpdatedAt:          now,
-
-		CreationDateTime:     creation,
-		StatusUpdateDateTime: updated,
-		ExpirationDateTime:   expire,
 	}
 	return s.repo.Create(ctx, c)
 }
 
-func normalizeRequestStatus(raw string, auto *bool) domain.ConsentStatus {
-	if auto != nil && *auto {
-		return domain.Authorised
-	}
-	switch strings.ToLower(strings.TrimSpace(raw)) {
-	case "approved", "authorised", "authorized":
-		return domain.Authorised
-	case "pending", "awaitingauthorization", "awaitingauthorisation":
-		return domain.AwaitingAuthorisation
-	case "rejected":
-		return domain.Rejected
-	case "revoked":
-		return domain.Revoked
-	default:
-		return domain.ConsentStatus(raw)
-	}
-}
+// Refresh asks the bank for the status of the user's consent
+func (s *Service) Refresh(ctx context.Context, userID, id int64) (domain.AccountConsent, error) {
+	const op = "service.consent.Refresh"
 
-func (s *Service) Refresh(ctx context.Context, id int64) (domain.AccountConsent, error) {
-	c, err := s.repo.GetByID(ctx, id)
+	c, err := s.userConsent(ctx, userID, id)
 	if err != nil {
-		return domain.AccountConsent{}, err
+		return domain.AccountConsent{}, fmt.Errorf("%s: %w", op, err)
 	}
+	return s.refresh(ctx, c)
+}
 
+func (s *Service) refresh(ctx context.Context, c domain.AccountConsent) (domain.AccountConsent, error) {
 	bank, err := s.banks.GetBankByID(ctx, c.BankID)
 	if err != nil {
 		return domain.AccountConsent{}, err
@@ -185,26 +150,13 @@ func (s *Service) Refresh(ctx context.Context, id int64) (domain.AccountConsent,
 		return domain.AccountConsent{}, err
 	}
 
-	key := c.RequestID
-	if c.ConsentID != nil && *c.ConsentID != "" {
-		key = *c.ConsentID
-	}
-
 	// передаём bearer
-	v, err := s.client.GetConsent(bank, key, token, s.reqBankCode)
+	v, err := s.client.GetConsent(ctx, bank, c.Key(), token, s.reqBankCode)
 	if err != nil {
 		return domain.AccountConsent{}, err
 	}
 
-	upd := domain.AccountConsent{
-		Status:               domain.ConsentStatus(v.Data.Status),
-		CreationDateTime:     &v.Data.CreationDateTime,
-		StatusUpdateDateTime: &v.Data.StatusUpdateDateTime,
-		ExpirationDateTime:   &v.Data.ExpirationDateTime,
-		AutoApproved:         nil,
-	}
-	cid := v.Data.ConsentID
-	upd.ConsentID = &cid
+	upd := domain.AccountConsent{ConsentState: v}
 
 	if err := s.repo.UpdateAfterCheck(ctx, c.ID, &upd); err != nil {
 		return domain.AccountConsent{}, err
@@ -212,17 +164,61 @@ func (s *Service) Refresh(ctx context.Context, id int64) (domain.AccountConsent,
 	return s.repo.GetByID(ctx, c.ID)
 }
 
-func (s *Service) Get(ctx context.Context, id int64) (domain.AccountConsent, error) {
-	return s.repo.GetByID(ctx, id)
+// Get returns the user's consent
+func (s *Service) Get(ctx context.Context, userID, id int64) (domain.AccountConsent, error) {
+	const op = "service.consent.Get"
+
+	c, err := s.userConsent(ctx, userID, id)
+	if err != nil {
+		return domain.AccountConsent{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return c, nil
 }
 
 func (s *Service) ListMine(ctx context.Context, userID int64, bankID *int64) ([]domain.AccountConsent, error) {
 	return s.repo.ListByUser(ctx, userID, bankID)
 }
 
-func (s *Service) Delete(ctx context.Context, id int64) error {
-	// по идее нужно вызывать DeleteByID из openbanking
-	return s.repo.DeleteByID(ctx, id)
+// Delete revokes the user's consent at the bank and keeps the local row with status Revoked and revoked_at.
+// If the bank call fails nothing is changed locally. A consent that was never issued by the bank
+// (no consent_id yet) is only marked as revoked.
+func (s *Service) Delete(ctx context.Context, userID, id int64) error {
+	const op = "service.consent.Delete"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("id", id),
+	)
+
+	c, err := s.userConsent(ctx, userID, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if c.Status == domain.Revoked && c.RevokedAt != nil {
+		return nil
+	}
+
+	if c.ConsentID != nil && *c.ConsentID != "" && c.Status != domain.Revoked && c.Status != domain.Rejected {
+		bank, err := s.banks.GetBankByID(ctx, c.BankID)
+		if err != nil {
+			return fmt.Errorf("%s: get bank: %w", op, err)
+		}
+		token, _, err := s.banks.GetOrRefreshToken(ctx, bank.ID)
+		if err != nil {
+			return fmt.Errorf("%s: get token: %w", op, err)
+		}
+		if err := s.client.DeleteConsent(ctx, bank, *c.ConsentID, token); err != nil {
+			log.Warn("failed to revoke consent at the bank", logger.Err(err))
+			return fmt.Errorf("%s: revoke at bank: %w", op, err)
+		}
+	}
+
+	if err := s.repo.MarkRevoked(ctx, c.ID, time.Now()); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("consent revoked", slog.Int64("bank_id", c.BankID))
+	return nil
 }
 
 // RefreshStale finds and updates a bundle of consents. Returns the number of successfully updated ones.
@@ -256,7 +252,7 @@ func (s *Service) RefreshStale(ctx context.Context, batchLimit, workers int) (in
 		go func() {
 			defer func() { <-sem }()
 			// используем уже готовую логику Refresh
-			if _, err := s.Refresh(ctx, it.ID); err == nil {
+			if _, err := s.refresh(ctx, it); err == nil {
 				done <- 1
 			} else {
 				s.log.Warn("consent refresh failed", slog.Int64("id", it.ID), logger.Err(err))
@@ -278,3 +274,18 @@ func (s *Service) RefreshStale(ctx context.Context, batchLimit, workers int) (in
 	}
 	return total, nil
 }
+
+// userConsent returns the consent if it belongs to the user
+func (s *Service) userConsent(ctx context.Context, userID, id int64) (domain.AccountConsent, error) {
+	c, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrConsentNotFound) {
+			return domain.AccountConsent{}, ErrConsentNotFound
+		}
+		return domain.AccountConsent{}, err
+	}
+	if c.UserID != userID {
+		return domain.AccountConsent{}, ErrConsentNotFound
+	}
+	return c, nil
+}
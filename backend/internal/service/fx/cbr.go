@@ -0,0 +1,144 @@
+// internal/service/fx/cbr.go
+package fx
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log/slog"
+	"multibank/backend/internal/domain"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// valCurs — Bank of Russia daily rates (https://www.cbr.ru/scripts/XML_daily.asp), windows-1251
+type valCurs struct {
+	Date    string   `xml:"Date,attr"` // DD.MM.YYYY
+	Valutes []valute `xml:"Valute"`
+}
+
+type valute struct {
+	CharCode string `xml:"CharCode"`
+	Nominal  string `xml:"Nominal"`
+	Value    string `xml:"Value"` // decimal comma: "81,2345"
+}
+
+// Import loads the daily rates from the configured source and saves them.
+// Returns the number of saved rates.
+func (s *Service) Import(ctx context.Context) (int, error) {
+	return s.ImportFrom(ctx, s.source)
+}
+
+// ImportFrom loads the daily rates file from a URL (http/https) or a local path and saves them.
+func (s *Service) ImportFrom(ctx context.Context, source string) (int, error) {
+	const op = "service.fx.ImportFrom"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("source", source),
+	)
+
+	if source == "" {
+		return 0, fmt.Errorf("%s: empty source", op)
+	}
+
+	rc, err := s.open(ctx, source)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rc.Close()
+
+	rates, err := parseCBR(rc, source)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := s.repo.UpsertRates(ctx, rates)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(rates) > 0 {
+		log.Info("fx rates imported", slog.Int("count", n), slog.String("date", rates[0].Date.Format("2006-01-02")))
+	}
+	return n, nil
+}
+
+func (s *Service) open(ctx context.Context, source string) (io.ReadCloser, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		return os.Open(source)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+	// cbr.ru rejects requests without a browser-like User-Agent
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; multibank)")
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(b))
+	}
+	return resp.Body, nil
+}
+
+// parseCBR parses ValCurs XML into rates (RUB per 1 unit)
+func parseCBR(r io.Reader, source string) ([]domain.FXRate, error) {
+	dec := xml.NewDecoder(r)
+	dec.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		switch strings.ToLower(charset) {
+		case "windows-1251", "cp1251":
+			return charmap.Windows1251.NewDecoder().Reader(input), nil
+		case "utf-8", "":
+			return input, nil
+		}
+		return nil, fmt.Errorf("unsupported charset %q", charset)
+	}
+
+	var vc valCurs
+	if err := dec.Decode(&vc); err != nil {
+		return nil, fmt.Errorf("decode xml: %w", err)
+	}
+
+	date, err := time.Parse("02.01.2006", vc.Date)
+	if err != nil {
+		return nil, fmt.Errorf("parse date %q: %w", vc.Date, err)
+	}
+
+	out := make([]domain.FXRate, 0, len(vc.Valutes))
+	for _, v := range vc.Valutes {
+		code := strings.ToUpper(strings.TrimSpace(v.CharCode))
+		nominal, err := strconv.Atoi(strings.TrimSpace(v.Nominal))
+		if err != nil || nominal <= 0 || code == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(v.Value), ",", "."), 64)
+		if err != nil || value <= 0 {
+			continue
+		}
+		out = append(out, domain.FXRate{
+			Date:     date,
+			Currency: code,
+			Nominal:  nominal,
+			Value:    value,
+			Rate:     value / float64(nominal),
+			Source:   source,
+		})
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no rates in the file")
+	}
+	return out, nil
+}
@@ -0,0 +1,211 @@
+// internal/service/fx/service.go
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/storage"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Repo interface {
+	UpsertRates(ctx context.Context, items []domain.FXRate) (int, error)
+	LatestRate(ctx context.Context, currency string, onOrBefore time.Time) (domain.FXRate, error)
+}
+
+type AccountService interface {
+	ListUserAccounts(ctx context.Context, userID int64, bankID *int64) ([]domain.AccountShort, error)
+	ListStoredAccounts(ctx context.Context, userID int64) ([]domain.AccountShort, error)
+}
+
+var (
+	ErrUnknownCurrency = errors.New("unknown currency")
+)
+
+type Service struct {
+	log      *slog.Logger
+	repo     Repo
+	accounts AccountService
+	http     *http.Client
+
+	source string // URL or local path of the Bank of Russia daily XML
+}
+
+func New(log *slog.Logger, repo Repo, accounts AccountService, httpClient *http.Client, source string) *Service {
+	return &Service{log: log, repo: repo, accounts: accounts, http: httpClient, source: source}
+}
+
+// NetWorth converts the primary balance of every user's account into the base currency.
+// Rates are taken from the local table (latest rate on or before today). Accounts in currencies
+// without a known rate are returned with Included=false and their currency is listed in Missing.
+func (s *Service) NetWorth(ctx context.Context, userID int64, base string) (domain.NetWorth, error) {
+	const op = "service.fx.NetWorth"
+
+	return s.netWorth(ctx, op, userID, base, func() ([]domain.AccountShort, error) {
+		return s.accounts.ListUserAccounts(ctx, userID, nil)
+	})
+}
+
+// StoredNetWorth is NetWorth over the stored balances only: accounts are never synced on demand,
+// a user without stored accounts has zero total.
+func (s *Service) StoredNetWorth(ctx context.Context, userID int64, base string) (domain.NetWorth, error) {
+	const op = "service.fx.StoredNetWorth"
+
+	return s.netWorth(ctx, op, userID, base, func() ([]domain.AccountShort, error) {
+		return s.accounts.ListStoredAccounts(ctx, userID)
+	})
+}
+
+func (s *Service) netWorth(ctx context.Context, op string, userID int64, base string,
+	listAccounts func() ([]domain.AccountShort, error)) (domain.NetWorth, error) {
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	base = strings.ToUpper(strings.TrimSpace(base))
+	if base == "" {
+		base = domain.BaseCurrency
+	}
+
+	now := time.Now().UTC()
+
+	baseRate, err := s.rateToRUB(ctx, base, now)
+	if err != nil {
+		if errors.Is(err, storage.ErrRateNotFound) {
+			return domain.NetWorth{}, fmt.Errorf("%s: %w", op, ErrUnknownCurrency)
+		}
+		return domain.NetWorth{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	accs, err := listAccounts()
+	if err != nil {
+		return domain.NetWorth{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	out := domain.NetWorth{
+		BaseCurrency: base,
+		Accounts:     make([]domain.NetWorthAccount, 0, len(accs)),
+		Rates:        []domain.NetWorthRate{},
+		Missing:      []string{},
+	}
+
+	rates := make(map[string]*domain.NetWorthRate, 4) // currency -> rate to base, nil = no rate
+	for _, a := range accs {
+		item := domain.NetWorthAccount{
+			AccountID:   a.AccountID,
+			BankCode:    a.BankCode,
+			Nickname:    a.Nickname,
+			BalanceType: a.BalanceType,
+			Currency:    strings.ToUpper(a.Currency),
+		}
+
+		amount, err := strconv.ParseFloat(a.Amount, 64)
+		if err != nil || item.Currency == "" {
+			// the account has no balance yet
+			out.Accounts = append(out.Accounts, item)
+			continue
+		}
+		item.Amount = amount
+
+		if item.Currency == base {
+			item.Converted = amount
+			item.Included = true
+			out.Total += amount
+			out.Accounts = append(out.Accounts, item)
+			continue
+		}
+
+		r, seen := rates[item.Currency]
+		if !seen {
+			cur, err := s.rateToRUB(ctx, item.Currency, now)
+			switch {
+			case err == nil:
+				r = &domain.NetWorthRate{
+					Currency: item.Currency,
+					Rate:     cur.Rate / baseRate.Rate,
+					Date:     olderDate(cur.Date, baseRate.Date),
+				}
+				out.Rates = append(out.Rates, *r)
+			case errors.Is(err, storage.ErrRateNotFound):
+				log.Warn("no fx rate for currency", slog.String("currency", item.Currency))
+				out.Missing = append(out.Missing, item.Currency)
+			default:
+				return domain.NetWorth{}, fmt.Errorf("%s: %w", op, err)
+			}
+			rates[item.Currency] = r
+		}
+
+		if r != nil {
+			item.Converted = amount * r.Rate
+			item.Included = true
+			out.Total += item.Converted
+		}
+		out.Accounts = append(out.Accounts, item)
+	}
+
+	sort.Slice(out.Rates, func(i, j int) bool { return out.Rates[i].Currency < out.Rates[j].Currency })
+	sort.Strings(out.Missing)
+	return out, nil
+}
+
+// rateToRUB returns RUB for 1 unit of the currency. RUB itself has rate 1 and no date.
+func (s *Service) rateToRUB(ctx context.Context, currency string, at time.Time) (domain.FXRate, error) {
+	if currency == domain.BaseCurrency {
+		return domain.FXRate{Currency: domain.BaseCurrency, Nominal: 1, Value: 1, Rate: 1}, nil
+	}
+	r, err := s.repo.LatestRate(ctx, currency, at)
+	if err != nil {
+		return domain.FXRate{}, err
+	}
+	if r.Rate <= 0 {
+		return domain.FXRate{}, fmt.Errorf("invalid rate %v for %s", r.Rate, currency)
+	}
+	return r, nil
+}
+
+// olderDate returns the older of two rate dates (zero date = RUB, does not count)
+func olderDate(a, b time.Time) time.Time {
+	if a.IsZero() {
+		return b
+	}
+	if b.IsZero() || a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// Rate returns how many units of base are given for 1 unit of currency on the date
+// (latest rates on or before it). Returns ErrUnknownCurrency if one of the currencies has no rate.
+func (s *Service) Rate(ctx context.Context, currency, base string, at time.Time) (domain.NetWorthRate, error) {
+	const op = "service.fx.Rate"
+
+	currency = strings.ToUpper(currency)
+	base = strings.ToUpper(base)
+	if currency == base {
+		return domain.NetWorthRate{Currency: currency, Rate: 1, Date: at}, nil
+	}
+
+	cur, err := s.rateToRUB(ctx, currency, at)
+	if err == nil {
+		var b domain.FXRate
+		if b, err = s.rateToRUB(ctx, base, at); err == nil {
+			return domain.NetWorthRate{
+				Currency: currency,
+				Rate:     cur.Rate / b.Rate,
+				Date:     olderDate(cur.Date, b.Date),
+			}, nil
+		}
+	}
+	if errors.Is(err, storage.ErrRateNotFound) {
+		return domain.NetWorthRate{}, fmt.Errorf("%s: %w", op, ErrUnknownCurrency)
+	}
+	return domain.NetWorthRate{}, fmt.Errorf("%s: %w", op, err)
+}
@@ -0,0 +1,65 @@
+// internal/service/notification/service.go
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/storage"
+	"time"
+)
+
+type Repo interface {
+	Create(ctx context.Context, n *domain.Notification) (int64, error)
+	ListByUser(ctx context.Context, userID int64, unreadOnly bool, limit int) ([]domain.Notification, error)
+	MarkRead(ctx context.Context, userID, id int64, at time.Time) error
+}
+
+const listLimit = 100
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+type Service struct {
+	log  *slog.Logger
+	repo Repo
+}
+
+func New(log *slog.Logger, repo Repo) *Service {
+	return &Service{log: log, repo: repo}
+}
+
+// Notify saves the notification for the user
+func (s *Service) Notify(ctx context.Context, n domain.Notification) error {
+	const op = "service.notification.Notify"
+
+	id, err := s.repo.Create(ctx, &n)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	s.log.Info("notification created",
+		slog.Int64("id", id),
+		slog.Int64("user_id", n.UserID),
+		slog.String("kind", string(n.Kind)),
+	)
+	return nil
+}
+
+// List returns the latest notifications of the user
+func (s *Service) List(ctx context.Context, userID int64, unreadOnly bool) ([]domain.Notification, error) {
+	return s.repo.ListByUser(ctx, userID, unreadOnly, listLimit)
+}
+
+// MarkRead marks the user's notification as read
+func (s *Service) MarkRead(ctx context.Context, userID, id int64) error {
+	const op = "service.notification.MarkRead"
+
+	if err := s.repo.MarkRead(ctx, userID, id, time.Now()); err != nil {
+		if errors.Is(err, storage.ErrNotificationNotFound) {
+			return fmt.Errorf("%s: %w", op, ErrNotificationNotFound)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
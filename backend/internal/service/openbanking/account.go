@@ -1,6 +1,7 @@
 package openbanking
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,6 +11,9 @@ import (
 	"multibank/backend/internal/logger"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type AccountClient struct {
@@ -28,13 +32,17 @@ type listAccountsResp struct {
 	Data struct {
 		Account []struct {
 			AccountID      string `json:"accountId"`
-			Status         string `json:"status"` // do not need for us
+			Status         string `json:"status"`
 			Currency       string `json:"currency"`
 			AccountType    string `json:"accountType"`
 			AccountSubType string `json:"accountSubType"`
 			Nickname       string `json:"nickname"`
 			OpeningDate    string `json:"openingDate"`
-			// account[...] нам не нужен для выдачи сейчас
+			Account        []struct {
+				SchemeName     string `json:"schemeName"`     // RU.CBR.PAN
+				Identification string `json:"identification"` // account number
+				Name           string `json:"name"`
+			} `json:"account"`
 		} `json:"account"`
 	} `json:"data"`
 }
@@ -43,28 +51,63 @@ type balancesResp struct {
 	Data struct {
 		Balance []struct {
 			AccountID string `json:"accountId"`
-			Type      string `json:"type"` // look for "InterimAvailable"
+			Type      string `json:"type"` // InterimAvailable, InterimBooked, ClosingAvailable ...
 			DateTime  string `json:"dateTime"`
 			Amount    struct {
 				Amount   string `json:"amount"`
 				Currency string `json:"currency"`
 			} `json:"amount"`
 			CreditDebitIndicator string `json:"creditDebitIndicator"`
+			CreditLine           []struct {
+				Included bool   `json:"included"`
+				Type     string `json:"type"`
+				Amount   struct {
+					Amount   string `json:"amount"`
+					Currency string `json:"currency"`
+				} `json:"amount"`
+			} `json:"creditLine"`
 		} `json:"balance"`
 	} `json:"data"`
 }
 
+type transactionsResp struct {
+	Data struct {
+		Transaction []struct {
+			AccountID     string `json:"accountId"`
+			TransactionID string `json:"transactionId"`
+			Amount        struct {
+				Amount   string `json:"amount"`
+				Currency string `json:"currency"`
+			} `json:"amount"`
+			CreditDebitIndicator   string `json:"creditDebitIndicator"`
+			Status                 string `json:"status"`
+			BookingDateTime        string `json:"bookingDateTime"` // parsed leniently, see parseDateTime
+			ValueDateTime          string `json:"valueDateTime"`
+			TransactionInformation string `json:"transactionInformation"`
+			BankTransactionCode    struct {
+				Code string `json:"code"`
+			} `json:"bankTransactionCode"`
+		} `json:"transaction"`
+	} `json:"data"`
+	Meta struct {
+		TotalPages int `json:"totalPages"`
+	} `json:"meta"`
+}
+
 // internal struct for not using dependencies
 type ListAccountsRespData struct {
 	AccountID      string
 	Nickname       string
 	Status         string
+	Currency       string
+	AccountType    string
 	AccountSubType string
 	OpeningDate    string
+	Identification string // account number (used in payments), empty if not reported
 }
 
 // ListAccounts calls GET /accounts?client_id=... with HEADERS with Auth token and consent_id
-func (c *AccountClient) ListAccounts(bank domain.Bank, clientID, bearer, consentID, requestingBank string) ([]ListAccountsRespData, error) {
+func (c *AccountClient) ListAccounts(ctx context.Context, bank domain.Bank, clientID, bearer, consentID, requestingBank string) ([]ListAccountsRespData, error) {
 	const op = "openbanking.accounts.ListAccounts"
 	log := c.log.With(slog.String("op", op))
 
@@ -81,7 +124,7 @@ func (c *AccountClient) ListAccounts(bank domain.Bank, clientID, bearer, consent
 	q.Set("client_id", clientID)
 	uu.RawQuery = q.Encode()
 
-	req, _ := http.NewRequest("GET", uu.String(), nil)
+	req, _ := http.NewRequestWithContext(WithBank(ctx, bank), "GET", uu.String(), nil)
 	req.Header.Set("Authorization", "Bearer "+bearer)
 	req.Header.Set("x-consent-id", consentID)
 	req.Header.Set("x-requesting-bank", requestingBank)
@@ -108,30 +151,39 @@ func (c *AccountClient) ListAccounts(bank domain.Bank, clientID, bearer, consent
 	// Приведём к более удобной внутренней структуре
 	out := make([]ListAccountsRespData, 0, len(v.Data.Account))
 	for _, a := range v.Data.Account {
+		var ident string
+		if len(a.Account) > 0 {
+			ident = a.Account[0].Identification
+		}
 		out = append(out, ListAccountsRespData{
 			AccountID:      a.AccountID,
 			Nickname:       a.Nickname,
 			Status:         a.Status,
+			Currency:       a.Currency,
+			AccountType:    a.AccountType,
 			AccountSubType: a.AccountSubType,
 			OpeningDate:    a.OpeningDate,
+			Identification: ident,
 		})
 	}
 	return out, nil
 }
 
-func (c *AccountClient) GetInterimAvailableBalance(bank domain.Bank, accountID, bearer, consentID, requestingBank string) (amount, currency string, err error) {
-	const op = "openbanking.accounts.GetInterimAvailableBalance"
+// GetBalances calls GET /accounts/{id}/balances and returns every balance entry.
+// Amounts are signed according to creditDebitIndicator (Debit => negative).
+func (c *AccountClient) GetBalances(ctx context.Context, bank domain.Bank, accountID, bearer, consentID, requestingBank string) ([]domain.Balance, error) {
+	const op = "openbanking.accounts.GetBalances"
 	log := c.log.With(slog.String("op", op))
 
 	base, err := httputils.NormalizeURL(bank.APIBaseURL)
 	if err != nil {
 		log.Warn("invalid bank api_base_url", slog.String("api_base_url", bank.APIBaseURL), logger.Err(err))
-		return "", "", err
+		return nil, err
 	}
 
 	u, _ := url.JoinPath(base.String(), "accounts", accountID, "balances")
 
-	req, _ := http.NewRequest("GET", u, nil)
+	req, _ := http.NewRequestWithContext(WithBank(ctx, bank), "GET", u, nil)
 	req.Header.Set("Authorization", "Bearer "+bearer)
 	req.Header.Set("x-consent-id", consentID)
 	req.Header.Set("x-requesting-bank", requestingBank)
@@ -140,26 +192,142 @@ func (c *AccountClient) GetInterimAvailableBalance(bank domain.Bank, accountID,
 	resp, err := c.HTTP.Do(req)
 	if err != nil {
 		log.Warn("get balances request failed", logger.Err(err))
-		return "", "", err
+		return nil, err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		all, _ := io.ReadAll(resp.Body)
 		log.Warn("get balances non-ok", slog.Int("code", resp.StatusCode), slog.String("body", string(all)))
-		return "", "", fmt.Errorf("get balances %d: %s", resp.StatusCode, string(all))
+		return nil, fmt.Errorf("get balances %d: %s", resp.StatusCode, string(all))
 	}
 
 	var v balancesResp
 	if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
 		log.Warn("get balances decode failed", logger.Err(err))
-		return "", "", err
+		return nil, err
+	}
+
+	out := make([]domain.Balance, 0, len(v.Data.Balance))
+	for _, b := range v.Data.Balance {
+		lines := make([]domain.CreditLine, 0, len(b.CreditLine))
+		for _, cl := range b.CreditLine {
+			lines = append(lines, domain.CreditLine{
+				Included: cl.Included,
+				Type:     cl.Type,
+				Amount:   cl.Amount.Amount,
+				Currency: cl.Amount.Currency,
+			})
+		}
+
+		out = append(out, domain.Balance{
+			Type:                 b.Type,
+			Amount:               signedAmount(b.Amount.Amount, b.CreditDebitIndicator),
+			Currency:             b.Amount.Currency,
+			CreditDebitIndicator: b.CreditDebitIndicator,
+			DateTime:             parseDateTime(b.DateTime),
+			CreditLines:          lines,
+		})
 	}
+	return out, nil
+}
+
+// dateTimeLayouts — banks send RFC 3339, some omit the zone (UTC then) or the time
+var dateTimeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02 15:04:05", "2006-01-02"}
 
-	for _, b := range v.Data.Balance { // ← было v.Data.balance
-		if b.Type == "InterimAvailable" {
-			return b.Amount.Amount, b.Amount.Currency, nil
+// parseDateTime returns nil for an empty or unknown date, so one bad field does not fail the whole response
+func parseDateTime(s string) *time.Time {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	for _, layout := range dateTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return &t
 		}
 	}
-	// If no InterimAvailable return blank str
-	return "", "", nil
+	return nil
+}
+
+// signedAmount makes Debit amounts negative (the bank always sends a positive amount + indicator)
+func signedAmount(amount, creditDebitIndicator string) string {
+	amount = strings.TrimSpace(amount)
+	if amount == "" || strings.HasPrefix(amount, "-") {
+		return amount
+	}
+	if strings.EqualFold(creditDebitIndicator, "Debit") {
+		return "-" + amount
+	}
+	return amount
+}
+
+// ListTransactions calls GET /accounts/{id}/transactions with date range and pagination.
+// Returns transactions of the page and total pages (0 if the bank does not report it).
+func (c *AccountClient) ListTransactions(ctx context.Context, bank domain.Bank, accountID, bearer, consentID, requestingBank string, f domain.TransactionFilter) ([]domain.Transaction, int, error) {
+	const op = "openbanking.accounts.ListTransactions"
+	log := c.log.With(slog.String("op", op))
+
+	base, err := httputils.NormalizeURL(bank.APIBaseURL)
+	if err != nil {
+		log.Warn("invalid bank api_base_url", slog.String("api_base_url", bank.APIBaseURL), logger.Err(err))
+		return nil, 0, err
+	}
+
+	// /accounts/{id}/transactions?from_booking_date_time=...&to_booking_date_time=...&page=...&limit=...
+	u, _ := url.JoinPath(base.String(), "accounts", accountID, "transactions")
+	uu, _ := url.Parse(u)
+	q := uu.Query()
+	if f.From != nil {
+		q.Set("from_booking_date_time", f.From.UTC().Format(time.RFC3339))
+	}
+	if f.To != nil {
+		q.Set("to_booking_date_time", f.To.UTC().Format(time.RFC3339))
+	}
+	if f.Page > 0 {
+		q.Set("page", strconv.Itoa(f.Page))
+	}
+	if f.Limit > 0 {
+		q.Set("limit", strconv.Itoa(f.Limit))
+	}
+	uu.RawQuery = q.Encode()
+
+	req, _ := http.NewRequestWithContext(WithBank(ctx, bank), "GET", uu.String(), nil)
+	req.Header.Set("Authorization", "Bearer "+bearer)
+	req.Header.Set("x-consent-id", consentID)
+	req.Header.Set("x-requesting-bank", requestingBank)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		log.Warn("list transactions request failed", logger.Err(err))
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		all, _ := io.ReadAll(resp.Body)
+		log.Warn("list transactions non-ok", slog.Int("code", resp.StatusCode), slog.String("body", string(all)))
+		return nil, 0, fmt.Errorf("list transactions %d: %s", resp.StatusCode, string(all))
+	}
+
+	var v transactionsResp
+	if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
+		log.Warn("list transactions decode failed", logger.Err(err))
+		return nil, 0, err
+	}
+
+	out := make([]domain.Transaction, 0, len(v.Data.Transaction))
+	for _, t := range v.Data.Transaction {
+		out = append(out, domain.Transaction{
+			TransactionID:        t.TransactionID,
+			AccountID:            t.AccountID,
+			Amount:               t.Amount.Amount,
+			Currency:             t.Amount.Currency,
+			CreditDebitIndicator: t.CreditDebitIndicator,
+			Status:               t.Status,
+			BookingDateTime:      parseDateTime(t.BookingDateTime),
+			ValueDateTime:        parseDateTime(t.ValueDateTime),
+			Info:                 t.TransactionInformation,
+			BankTransactionCode:  t.BankTransactionCode.Code,
+		})
+	}
+	return out, v.Meta.TotalPages, nil
 }
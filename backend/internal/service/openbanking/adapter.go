@@ -0,0 +1,165 @@
+// internal/service/openbanking/adapter.go
+
+package openbanking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"multibank/backend/internal/domain"
+	"net/http"
+)
+
+var ErrUnknownAdapter = errors.New("unknown bank adapter")
+
+// BankAdapter — API dialect of a bank: auth flow and request/response layouts of consents, accounts,
+// balances and products. Services work with domain types, the adapter of the bank (banks.adapter) does the wire format.
+//
+// Payments (PaymentClient) and product agreements (ProductAgreementClient) are not part of the adapter:
+// they are available in the sandbox layout only, for banks of every adapter.
+type BankAdapter interface {
+	Token(ctx context.Context, bank domain.Bank) (Token, error)
+
+	RequestConsent(ctx context.Context, bank domain.Bank, clientID string, perms []domain.Permission, bearer string) (domain.ConsentState, error)
+	GetConsent(ctx context.Context, bank domain.Bank, requestOrConsentID, bearer, xFapi string) (domain.ConsentState, error)
+	DeleteConsent(ctx context.Context, bank domain.Bank, consentID, bearer string) error
+
+	ListAccounts(ctx context.Context, bank domain.Bank, clientID, bearer, consentID, requestingBank string) ([]ListAccountsRespData, error)
+	GetBalances(ctx context.Context, bank domain.Bank, accountID, bearer, consentID, requestingBank string) ([]domain.Balance, error)
+	ListTransactions(ctx context.Context, bank domain.Bank, accountID, bearer, consentID, requestingBank string, f domain.TransactionFilter) ([]domain.Transaction, int, error)
+
+	GetProducts(ctx context.Context, bank domain.Bank, bearer, productType string) ([]domain.Product, error)
+}
+
+// SandboxAdapter — the hackathon sandbox API (abank, sbank, vbank): token by query parameters
+type SandboxAdapter struct {
+	*TokenClient
+	*ConsentClient
+	*AccountClient
+	*ProductClient
+}
+
+func NewSandboxAdapter(log *slog.Logger, httpClient *http.Client, reqBank, reqBankName, reason string) *SandboxAdapter {
+	return &SandboxAdapter{
+		TokenClient:   NewTokenClient(log, httpClient),
+		ConsentClient: NewConsentClient(log, httpClient, reqBank, reqBankName, reason),
+		AccountClient: NewAccountClient(log, httpClient),
+		ProductClient: NewProductClient(log, httpClient),
+	}
+}
+
+// OAuth2Adapter — sandbox layout with OAuth2 client_credentials token (JSON body to /oauth2/token)
+type OAuth2Adapter struct {
+	*SandboxAdapter
+}
+
+func (a *OAuth2Adapter) Token(ctx context.Context, bank domain.Bank) (Token, error) {
+	return a.OAuth2Token(ctx, bank)
+}
+
+// Adapters picks the adapter of the bank by banks.adapter; it is a BankAdapter itself
+type Adapters struct {
+	byName map[string]BankAdapter
+}
+
+func NewAdapters(sandbox *SandboxAdapter) *Adapters {
+	return &Adapters{byName: map[string]BankAdapter{
+		domain.BankAdapterSandbox: sandbox,
+		domain.BankAdapterOAuth2:  &OAuth2Adapter{SandboxAdapter: sandbox},
+	}}
+}
+
+// Register adds or replaces the adapter with the name
+func (a *Adapters) Register(name string, adapter BankAdapter) {
+	a.byName[name] = adapter
+}
+
+// Has reports whether the adapter with the name is registered
+func (a *Adapters) Has(name string) bool {
+	_, ok := a.byName[name]
+	return ok
+}
+
+// For returns the adapter of the bank, the sandbox one if not set
+func (a *Adapters) For(bank domain.Bank) (BankAdapter, error) {
+	name := bank.Adapter
+	if name == "" {
+		name = domain.BankAdapterSandbox
+	}
+	adapter, ok := a.byName[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q (bank %s)", ErrUnknownAdapter, name, bank.Code)
+	}
+	return adapter, nil
+}
+
+func (a *Adapters) Token(ctx context.Context, bank domain.Bank) (Token, error) {
+	adapter, err := a.For(bank)
+	if err != nil {
+		return Token{}, err
+	}
+	return adapter.Token(ctx, bank)
+}
+
+func (a *Adapters) RequestConsent(ctx context.Context, bank domain.Bank, clientID string, perms []domain.Permission, bearer string) (domain.ConsentState, error) {
+	adapter, err := a.For(bank)
+	if err != nil {
+		return domain.ConsentState{}, err
+	}
+	return adapter.RequestConsent(ctx, bank, clientID, perms, bearer)
+}
+
+func (a *Adapters) GetConsent(ctx context.Context, bank domain.Bank, requestOrConsentID, bearer, xFapi string) (domain.ConsentState, error) {
+	adapter, err := a.For(bank)
+	if err != nil {
+		return domain.ConsentState{}, err
+	}
+	return adapter.GetConsent(ctx, bank, requestOrConsentID, bearer, xFapi)
+}
+
+func (a *Adapters) DeleteConsent(ctx context.Context, bank domain.Bank, consentID, bearer string) error {
+	adapter, err := a.For(bank)
+	if err != nil {
+		return err
+	}
+	return adapter.DeleteConsent(ctx, bank, consentID, bearer)
+}
+
+func (a *Adapters) ListAccounts(ctx context.Context, bank domain.Bank, clientID, bearer, consentID, requestingBank string) ([]ListAccountsRespData, error) {
+	adapter, err := a.For(bank)
+	if err != nil {
+		return nil, err
+	}
+	return adapter.ListAccounts(ctx, bank, clientID, bearer, consentID, requestingBank)
+}
+
+func (a *Adapters) GetBalances(ctx context.Context, bank domain.Bank, accountID, bearer, consentID, requestingBank string) ([]domain.Balance, error) {
+	adapter, err := a.For(bank)
+	if err != nil {
+		return nil, err
+	}
+	return adapter.GetBalances(ctx, bank, accountID, bearer, consentID, requestingBank)
+}
+
+func (a *Adapters) ListTransactions(ctx context.Context, bank domain.Bank, accountID, bearer, consentID, requestingBank string, f domain.TransactionFilter) ([]domain.Transaction, int, error) {
+	adapter, err := a.For(bank)
+	if err != nil {
+		return nil, 0, err
+	}
+	return adapter.ListTransactions(ctx, bank, accountID, bearer, consentID, requestingBank, f)
+}
+
+func (a *Adapters) GetProducts(ctx context.Context, bank domain.Bank, bearer, productType string) ([]domain.Product, error) {
+	adapter, err := a.For(bank)
+	if err != nil {
+		return nil, err
+	}
+	return adapter.GetProducts(ctx, bank, bearer, productType)
+}
+
+var (
+	_ BankAdapter = (*SandboxAdapter)(nil)
+	_ BankAdapter = (*OAuth2Adapter)(nil)
+	_ BankAdapter = (*Adapters)(nil)
+)
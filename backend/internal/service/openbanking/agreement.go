@@ -0,0 +1,291 @@
+// internal/service/openbanking/agreement.go
+
+package openbanking
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"multibank/backend/internal/domain"
+	httputils "multibank/backend/internal/http-server/utils"
+	"multibank/backend/internal/logger"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ProductAgreementClient works with /product-agreement-consents and /product-agreements of the bank
+// (sandbox layout, not behind BankAdapter)
+type ProductAgreementClient struct {
+	log  *slog.Logger
+	HTTP *http.Client
+
+	// Constants - from app.New(...)
+	RequestingBank string // "team014"
+}
+
+func NewProductAgreementClient(log *slog.Logger, httpClient *http.Client, reqBank string) *ProductAgreementClient {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &ProductAgreementClient{log: log, HTTP: httpClient, RequestingBank: reqBank}
+}
+
+type agreementConsentRequestBody struct {
+	RequestingBank         string   `json:"requesting_bank"`
+	ClientID               string   `json:"client_id"`
+	ReadProductAgreements  bool     `json:"read_product_agreements"`
+	OpenProductAgreements  bool     `json:"open_product_agreements"`
+	CloseProductAgreements bool     `json:"close_product_agreements"`
+	AllowedProductTypes    []string `json:"allowed_product_types"`
+	Reason                 string   `json:"reason,omitempty"`
+}
+
+type AgreementConsentRequestResp struct {
+	RequestID    string  `json:"request_id"`
+	ConsentID    *string `json:"consent_id"` // can be blank
+	Status       string  `json:"status"`     // "approved" | "pending" ...
+	Message      string  `json:"message"`
+	AutoApproved *bool   `json:"auto_approved"`
+}
+
+type AgreementConsentViewWrapper struct {
+	Data struct {
+		ConsentID            string     `json:"consentId"`
+		Status               string     `json:"status"` // "Authorized" | "AwaitingAuthorization" ...
+		CreationDateTime     *time.Time `json:"creationDateTime"`
+		StatusUpdateDateTime *time.Time `json:"statusUpdateDateTime"`
+		ExpirationDateTime   *time.Time `json:"expirationDateTime"`
+	} `json:"data"`
+}
+
+// State returns the consent state from the answer to the consent request
+func (r *AgreementConsentRequestResp) State() domain.ConsentState {
+	return requestState(r.RequestID, r.ConsentID, r.Status, r.AutoApproved)
+}
+
+// State returns the consent state from the consent view
+func (v *AgreementConsentViewWrapper) State() domain.ConsentState {
+	return viewState(v.Data.ConsentID, v.Data.Status,
+		v.Data.CreationDateTime, v.Data.StatusUpdateDateTime, v.Data.ExpirationDateTime)
+}
+
+type agreementRequestBody struct {
+	ProductID       string   `json:"product_id"`
+	Amount          *float64 `json:"amount,omitempty"`
+	TermMonths      *int     `json:"term_months,omitempty"`
+	SourceAccountID string   `json:"source_account_id,omitempty"`
+}
+
+// AgreementInput — what product is opened and how
+type AgreementInput struct {
+	ClientID        string
+	ProductID       string
+	Amount          *float64 // nil for products without amount (cards)
+	TermMonths      *int
+	SourceAccountID string
+}
+
+// AgreementView — product agreement as the bank returns it.
+// Dates are plain strings: banks send either a date or a date-time.
+type AgreementView struct {
+	AgreementID   string      `json:"agreement_id"`
+	ProductID     string      `json:"product_id"`
+	ProductName   string      `json:"product_name"`
+	ProductType   string      `json:"product_type"`
+	Amount        json.Number `json:"amount"` // number or numeric string
+	TermMonths    *int        `json:"term_months"`
+	Status        string      `json:"status"` // active | closed
+	StartDate     string      `json:"start_date"`
+	EndDate       string      `json:"end_date"`
+	AccountNumber string      `json:"account_number"`
+}
+
+type agreementViewWrapper struct {
+	Data AgreementView `json:"data"`
+}
+
+type agreementListWrapper struct {
+	Data []AgreementView `json:"data"`
+}
+
+// RequestAgreementConsent calls POST /product-agreement-consents/request
+// asking for read, open and close permissions on all product types
+func (c *ProductAgreementClient) RequestAgreementConsent(ctx context.Context, bank domain.Bank, clientID, bearer string) (*AgreementConsentRequestResp, error) {
+	const op = "service.openbanking.RequestAgreementConsent"
+	log := c.log.With(slog.String("op", op))
+
+	base, err := httputils.NormalizeURL(bank.APIBaseURL)
+	if err != nil {
+		log.Warn("invalid bank api_base_url", slog.String("api_base_url", bank.APIBaseURL), logger.Err(err))
+		return nil, err
+	}
+	u, _ := url.JoinPath(base.String(), "product-agreement-consents", "request")
+	uu, _ := url.Parse(u)
+	q := uu.Query()
+	q.Set("client_id", clientID)
+	uu.RawQuery = q.Encode()
+
+	b, _ := json.Marshal(agreementConsentRequestBody{
+		RequestingBank:         c.RequestingBank,
+		ClientID:               clientID,
+		ReadProductAgreements:  true,
+		OpenProductAgreements:  true,
+		CloseProductAgreements: true,
+		AllowedProductTypes:    []string{"deposit", "card", "loan", "account"},
+	})
+
+	req, _ := http.NewRequestWithContext(WithBank(ctx, bank), "POST", uu.String(), bytes.NewReader(b))
+	req.Header.Set("Authorization", "Bearer "+bearer)
+	req.Header.Set("X-Requesting-Bank", c.RequestingBank)
+	req.Header.Set("Content-Type", "application/json")
+
+	var out AgreementConsentRequestResp
+	if err := c.do(req, &out); err != nil {
+		log.Warn("failed to request product agreement consent", logger.Err(err))
+		return nil, fmt.Errorf("product agreement consents request: %w", err)
+	}
+	return &out, nil
+}
+
+// GetAgreementConsent calls GET /product-agreement-consents/{id}
+func (c *ProductAgreementClient) GetAgreementConsent(ctx context.Context, bank domain.Bank, requestOrConsentID, bearer string) (*AgreementConsentViewWrapper, error) {
+	const op = "service.openbanking.GetAgreementConsent"
+	log := c.log.With(slog.String("op", op))
+
+	base, err := httputils.NormalizeURL(bank.APIBaseURL)
+	if err != nil {
+		log.Warn("invalid bank api_base_url", slog.String("api_base_url", bank.APIBaseURL), logger.Err(err))
+		return nil, err
+	}
+	u, _ := url.JoinPath(base.String(), "product-agreement-consents", requestOrConsentID)
+
+	req, _ := http.NewRequestWithContext(WithBank(ctx, bank), "GET", u, nil)
+	req.Header.Set("Authorization", "Bearer "+bearer)
+	req.Header.Set("X-Requesting-Bank", c.RequestingBank)
+
+	var out AgreementConsentViewWrapper
+	if err := c.do(req, &out); err != nil {
+		log.Warn("failed to get product agreement consent", logger.Err(err))
+		return nil, fmt.Errorf("product agreement consents get: %w", err)
+	}
+	return &out, nil
+}
+
+// OpenAgreement calls POST /product-agreements?client_id=... with the consent in X-Product-Agreement-Consent-Id
+func (c *ProductAgreementClient) OpenAgreement(ctx context.Context, bank domain.Bank, in AgreementInput, bearer, consentID string) (*AgreementView, error) {
+	const op = "service.openbanking.OpenAgreement"
+	log := c.log.With(slog.String("op", op))
+
+	uu, err := c.agreementsURL(bank, in.ClientID)
+	if err != nil {
+		log.Warn("invalid bank api_base_url", slog.String("api_base_url", bank.APIBaseURL), logger.Err(err))
+		return nil, err
+	}
+
+	b, _ := json.Marshal(agreementRequestBody{
+		ProductID:       in.ProductID,
+		Amount:          in.Amount,
+		TermMonths:      in.TermMonths,
+		SourceAccountID: in.SourceAccountID,
+	})
+
+	req, _ := http.NewRequestWithContext(WithBank(ctx, bank), "POST", uu.String(), bytes.NewReader(b))
+	c.setHeaders(req, bearer, consentID)
+	req.Header.Set("Content-Type", "application/json")
+
+	var out agreementViewWrapper
+	if err := c.do(req, &out); err != nil {
+		log.Warn("failed to open product agreement", logger.Err(err))
+		return nil, fmt.Errorf("product agreements create: %w", err)
+	}
+	return &out.Data, nil
+}
+
+// ListAgreements calls GET /product-agreements?client_id=...
+func (c *ProductAgreementClient) ListAgreements(ctx context.Context, bank domain.Bank, clientID, bearer, consentID string) ([]AgreementView, error) {
+	const op = "service.openbanking.ListAgreements"
+	log := c.log.With(slog.String("op", op))
+
+	uu, err := c.agreementsURL(bank, clientID)
+	if err != nil {
+		log.Warn("invalid bank api_base_url", slog.String("api_base_url", bank.APIBaseURL), logger.Err(err))
+		return nil, err
+	}
+
+	req, _ := http.NewRequestWithContext(WithBank(ctx, bank), "GET", uu.String(), nil)
+	c.setHeaders(req, bearer, consentID)
+
+	var out agreementListWrapper
+	if err := c.do(req, &out); err != nil {
+		log.Warn("failed to list product agreements", logger.Err(err))
+		return nil, fmt.Errorf("product agreements list: %w", err)
+	}
+	return out.Data, nil
+}
+
+// CloseAgreement calls DELETE /product-agreements/{id}?client_id=...
+func (c *ProductAgreementClient) CloseAgreement(ctx context.Context, bank domain.Bank, agreementID, clientID, bearer, consentID string) error {
+	const op = "service.openbanking.CloseAgreement"
+	log := c.log.With(slog.String("op", op))
+
+	uu, err := c.agreementsURL(bank, clientID, agreementID)
+	if err != nil {
+		log.Warn("invalid bank api_base_url", slog.String("api_base_url", bank.APIBaseURL), logger.Err(err))
+		return err
+	}
+
+	req, _ := http.NewRequestWithContext(WithBank(ctx, bank), "DELETE", uu.String(), nil)
+	c.setHeaders(req, bearer, consentID)
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		log.Warn("failed to close product agreement", logger.Err(err))
+		return err
+	}
+	defer resp.Body.Close()
+	if !isOK(resp.StatusCode) {
+		all, _ := io.ReadAll(resp.Body)
+		log.Warn("close product agreement non-ok", slog.Int("code", resp.StatusCode), slog.String("body", string(all)))
+		return fmt.Errorf("product agreements close %d: %s", resp.StatusCode, string(all))
+	}
+	return nil
+}
+
+// agreementsURL builds /product-agreements[/{id}]?client_id=...
+func (c *ProductAgreementClient) agreementsURL(bank domain.Bank, clientID string, elem ...string) (*url.URL, error) {
+	base, err := httputils.NormalizeURL(bank.APIBaseURL)
+	if err != nil {
+		return nil, err
+	}
+	u, _ := url.JoinPath(base.String(), append([]string{"product-agreements"}, elem...)...)
+	uu, _ := url.Parse(u)
+	q := uu.Query()
+	q.Set("client_id", clientID)
+	uu.RawQuery = q.Encode()
+	return uu, nil
+}
+
+func (c *ProductAgreementClient) setHeaders(req *http.Request, bearer, consentID string) {
+	req.Header.Set("Authorization", "Bearer "+bearer)
+	req.Header.Set("X-Requesting-Bank", c.RequestingBank)
+	req.Header.Set("X-Product-Agreement-Consent-Id", consentID)
+	req.Header.Set("Accept", "application/json")
+}
+
+// do sends the request and decodes a 2xx JSON response into out
+func (c *ProductAgreementClient) do(req *http.Request, out any) error {
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if !isOK(resp.StatusCode) {
+		all, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%d: %s", resp.StatusCode, string(all))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
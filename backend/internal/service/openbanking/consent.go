@@ -4,6 +4,7 @@ package openbanking
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -49,7 +50,7 @@ type requestBody struct {
 	RequestingBankName string              `json:"requesting_bank_name"`
 }
 
-type ConsentRequestResp struct {
+type consentRequestResp struct {
 	RequestID    string  `json:"request_id"`
 	ConsentID    *string `json:"consent_id"` // can be blank
 	Status       string  `json:"status"`     // "AwaitingAuthorisation" | "Authorised" ...
@@ -58,7 +59,7 @@ type ConsentRequestResp struct {
 	AutoApproved *bool   `json:"auto_approved"`
 }
 
-type ConsentViewWrapper struct {
+type consentViewWrapper struct {
 	Data struct {
 		ConsentID            string              `json:"consentId"`
 		Status               string              `json:"status"` // "Authorized" | "AwaitingAuthorization" ...
@@ -69,7 +70,56 @@ type ConsentViewWrapper struct {
 	} `json:"data"`
 }
 
-func (c *ConsentClient) RequestConsent(bank domain.Bank, clientID string, perms []domain.Permission, bearer string) (*ConsentRequestResp, error) {
+// State returns the consent state from the answer to the consent request
+func (r *consentRequestResp) State() domain.ConsentState {
+	return requestState(r.RequestID, r.ConsentID, r.Status, r.AutoApproved)
+}
+
+// State returns the consent state from the consent view
+func (v *consentViewWrapper) State() domain.ConsentState {
+	return viewState(v.Data.ConsentID, v.Data.Status,
+		timePtr(v.Data.CreationDateTime), timePtr(v.Data.StatusUpdateDateTime), timePtr(v.Data.ExpirationDateTime))
+}
+
+// requestState — the answers to account, payment and product agreement consent requests are the same.
+// Some banks answer an auto-approved request with the consent id only, it is used as the request id then.
+func requestState(requestID string, consentID *string, status string, auto *bool) domain.ConsentState {
+	if requestID == "" && consentID != nil {
+		requestID = *consentID
+	}
+	return domain.ConsentState{
+		RequestID:    requestID,
+		ConsentID:    consentID,
+		Status:       domain.ParseConsentStatus(status, auto),
+		AutoApproved: auto,
+	}
+}
+
+// viewState — so are the consent views
+func viewState(consentID, status string, creation, updated, expiration *time.Time) domain.ConsentState {
+	st := domain.ConsentState{
+		CreationDateTime:     creation,
+		StatusUpdateDateTime: updated,
+		ExpirationDateTime:   expiration,
+	}
+	if status != "" {
+		st.Status = domain.ParseConsentStatus(status, nil)
+	}
+	if consentID != "" {
+		st.ConsentID = &consentID
+	}
+	return st
+}
+
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// RequestConsent calls POST /account-consents/request and returns the state of the requested consent
+func (c *ConsentClient) RequestConsent(ctx context.Context, bank domain.Bank, clientID string, perms []domain.Permission, bearer string) (domain.ConsentState, error) {
 
 	const op = "service.openbanking.RequestConsent"
 
@@ -80,7 +130,7 @@ func (c *ConsentClient) RequestConsent(bank domain.Bank, clientID string, perms
 	base, err := httputils.NormalizeURL(bank.APIBaseURL)
 	if err != nil {
 		log.Warn("invalid bank api_base_url", slog.String("api_base_url", bank.APIBaseURL), logger.Err(err))
-		return nil, err
+		return domain.ConsentState{}, err
 	}
 
 	u, _ := url.JoinPath(base.String(), "account-consents", "request")
@@ -94,7 +144,7 @@ func (c *ConsentClient) RequestConsent(bank domain.Bank, clientID string, perms
 	}
 	b, _ := json.Marshal(body)
 
-	req, _ := http.NewRequest("POST", u, bytes.NewReader(b))
+	req, _ := http.NewRequestWithContext(WithBank(ctx, bank), "POST", u, bytes.NewReader(b))
 	req.Header.Set("Authorization", "Bearer "+bearer)
 	req.Header.Set("X-Requesting-Bank", c.RequestingBank)
 	req.Header.Set("Content-Type", "application/json")
@@ -102,7 +152,7 @@ func (c *ConsentClient) RequestConsent(bank domain.Bank, clientID string, perms
 	resp, err := c.HTTP.Do(req)
 	if err != nil {
 		log.Warn("failed to request consent", logger.Err(err))
-		return nil, err
+		return domain.ConsentState{}, err
 	}
 	defer resp.Body.Close()
 	if !isOK(resp.StatusCode) {
@@ -113,29 +163,30 @@ func (c *ConsentClient) RequestConsent(bank domain.Bank, clientID string, perms
 			slog.String("body", string(all)),
 		)
 
-		return nil, fmt.Errorf("consents request %d: %s", resp.StatusCode, string(all))
+		return domain.ConsentState{}, fmt.Errorf("consents request %d: %s", resp.StatusCode, string(all))
 	}
 
-	var out ConsentRequestResp
+	var out consentRequestResp
 	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
 		log.Warn("failed to decode consent response", logger.Err(err))
-		return nil, err
+		return domain.ConsentState{}, err
 	}
-	return &out, nil
+	return out.State(), nil
 }
 
-func (c *ConsentClient) GetConsent(bank domain.Bank, requestOrConsentID, bearer, xFapi string) (*ConsentViewWrapper, error) {
+// GetConsent calls GET /account-consents/{id} and returns the state of the consent at the bank
+func (c *ConsentClient) GetConsent(ctx context.Context, bank domain.Bank, requestOrConsentID, bearer, xFapi string) (domain.ConsentState, error) {
 	const op = "service.openbanking.GetConsent"
 	log := c.log.With(slog.String("op", op))
 
 	base, err := httputils.NormalizeURL(bank.APIBaseURL)
 	if err != nil {
 		log.Warn("invalid bank api_base_url", slog.String("api_base_url", bank.APIBaseURL), logger.Err(err))
-		return nil, err
+		return domain.ConsentState{}, err
 	}
 	u, _ := url.JoinPath(base.String(), "account-consents", requestOrConsentID)
 
-	req, _ := http.NewRequest("GET", u, nil)
+	req, _ := http.NewRequestWithContext(WithBank(ctx, bank), "GET", u, nil)
 
 	// ОБЯЗАТЕЛЬНО: авторизация
 	if bearer != "" {
@@ -157,7 +208,7 @@ func (c *ConsentClient) GetConsent(bank domain.Bank, requestOrConsentID, bearer,
 	resp, err := c.HTTP.Do(req)
 	if err != nil {
 		log.Warn("failed to get consent", logger.Err(err))
-		return nil, err
+		return domain.ConsentState{}, err
 	}
 	defer resp.Body.Close()
 	if !isOK(resp.StatusCode) {
@@ -166,12 +217,46 @@ func (c *ConsentClient) GetConsent(bank domain.Bank, requestOrConsentID, bearer,
 			slog.Int("code", resp.StatusCode),
 			slog.String("body", string(all)),
 		)
-		return nil, fmt.Errorf("consents get %d: %s", resp.StatusCode, string(all))
+		return domain.ConsentState{}, fmt.Errorf("consents get %d: %s", resp.StatusCode, string(all))
 	}
-	var v ConsentViewWrapper
+	var v consentViewWrapper
 	if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
 		log.Warn("failed to decode consent response", logger.Err(err))
-		return nil, err
+		return domain.ConsentState{}, err
+	}
+	return v.State(), nil
+}
+
+// DeleteConsent calls DELETE /account-consents/{id} — revokes the consent at the bank
+func (c *ConsentClient) DeleteConsent(ctx context.Context, bank domain.Bank, consentID, bearer string) error {
+	const op = "service.openbanking.DeleteConsent"
+	log := c.log.With(slog.String("op", op))
+
+	base, err := httputils.NormalizeURL(bank.APIBaseURL)
+	if err != nil {
+		log.Warn("invalid bank api_base_url", slog.String("api_base_url", bank.APIBaseURL), logger.Err(err))
+		return err
+	}
+	u, _ := url.JoinPath(base.String(), "account-consents", consentID)
+
+	req, _ := http.NewRequestWithContext(WithBank(ctx, bank), "DELETE", u, nil)
+	req.Header.Set("Authorization", "Bearer "+bearer)
+	req.Header.Set("X-Requesting-Bank", c.RequestingBank)
+	req.Header.Set("x-fapi-interaction-id", c.RequestingBank)
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		log.Warn("failed to delete consent", logger.Err(err))
+		return err
+	}
+	defer resp.Body.Close()
+	if !isOK(resp.StatusCode) {
+		all, _ := io.ReadAll(resp.Body)
+		log.Warn("got non-ok status code from request",
+			slog.Int("code", resp.StatusCode),
+			slog.String("body", string(all)),
+		)
+		return fmt.Errorf("consents delete %d: %s", resp.StatusCode, string(all))
 	}
-	return &v, nil
+	return nil
 }
@@ -0,0 +1,90 @@
+// internal/service/openbanking/limiter.go
+
+package openbanking
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter — token bucket per bank for outgoing requests. The bucket is kept as the time when it is
+// full again (GCRA form): every request moves it by 1/rate, a request waits while the bucket holds
+// more than burst requests.
+type Limiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	full   time.Time // when all tokens are back
+	paused time.Time // no requests until (Retry-After of the bank)
+}
+
+func NewLimiter() *Limiter {
+	return &Limiter{buckets: make(map[string]*bucket, 8)}
+}
+
+// Wait blocks until a request to the bank is allowed by its rate (requests per second) and burst.
+// rate <= 0 disables the limit, only pauses of the bank are applied.
+func (l *Limiter) Wait(ctx context.Context, key string, rate float64, burst int) error {
+	d := l.reserve(key, rate, burst, time.Now())
+	if d <= 0 {
+		return ctx.Err()
+	}
+	if !sleep(ctx, d) {
+		return ctx.Err()
+	}
+	return nil
+}
+
+// Pause stops requests to the bank for d (the bank replied 429), the bucket is refilled after it
+func (l *Limiter) Pause(key string, d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.bucket(key)
+	if until := time.Now().Add(d); until.After(b.paused) {
+		b.paused = until
+	}
+}
+
+// reserve takes a token and returns how long the request has to wait for it
+func (l *Limiter) reserve(key string, rate float64, burst int, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.bucket(key)
+	start := now
+	if b.paused.After(start) {
+		start = b.paused
+	}
+	if rate <= 0 {
+		return start.Sub(now)
+	}
+	if burst < 1 {
+		burst = 1
+	}
+
+	interval := time.Duration(float64(time.Second) / rate)
+	full := b.full
+	if full.Before(start) {
+		full = start
+	}
+	// the request may go when the bucket has room for it: full - burst*interval <= t
+	at := full.Add(interval - time.Duration(burst)*interval)
+	if at.Before(start) {
+		at = start
+	}
+	b.full = full.Add(interval)
+	return at.Sub(now)
+}
+
+func (l *Limiter) bucket(key string) *bucket {
+	b := l.buckets[key]
+	if b == nil {
+		b = &bucket{}
+		l.buckets[key] = b
+	}
+	return b
+}
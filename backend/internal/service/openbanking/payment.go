@@ -0,0 +1,278 @@
+// internal/service/openbanking/payment.go
+
+package openbanking
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"multibank/backend/internal/domain"
+	httputils "multibank/backend/internal/http-server/utils"
+	"multibank/backend/internal/logger"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// PaymentClient works with /payment-consents and /payments of the bank (sandbox layout, not behind BankAdapter)
+type PaymentClient struct {
+	log  *slog.Logger
+	HTTP *http.Client
+
+	// Constants - from app.New(...)
+	RequestingBank string // "team014"
+}
+
+func NewPaymentClient(log *slog.Logger, httpClient *http.Client, reqBank string) *PaymentClient {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &PaymentClient{log: log, HTTP: httpClient, RequestingBank: reqBank}
+}
+
+type paymentConsentRequestBody struct {
+	RequestingBank  string      `json:"requesting_bank"`
+	ClientID        string      `json:"client_id"`
+	ConsentType     string      `json:"consent_type"` // single_use
+	Amount          json.Number `json:"amount"`       // decimal as is, no float rounding
+	Currency        string      `json:"currency"`
+	DebtorAccount   string      `json:"debtor_account"`
+	CreditorAccount string      `json:"creditor_account"`
+	CreditorName    string      `json:"creditor_name,omitempty"`
+	Reference       string      `json:"reference,omitempty"`
+}
+
+// PaymentConsentInput — what the payment consent is requested for
+type PaymentConsentInput struct {
+	ClientID        string
+	Amount          string // decimal, e.g. "1500.00"
+	Currency        string
+	DebtorAccount   string
+	CreditorAccount string
+	CreditorName    string
+	Reference       string
+}
+
+type PaymentConsentRequestResp struct {
+	RequestID    string  `json:"request_id"`
+	ConsentID    *string `json:"consent_id"` // can be blank
+	Status       string  `json:"status"`     // "approved" | "pending" ...
+	Message      string  `json:"message"`
+	AutoApproved *bool   `json:"auto_approved"`
+}
+
+type PaymentConsentViewWrapper struct {
+	Data struct {
+		ConsentID            string     `json:"consentId"`
+		Status               string     `json:"status"` // "Authorized" | "AwaitingAuthorization" ...
+		CreationDateTime     *time.Time `json:"creationDateTime"`
+		StatusUpdateDateTime *time.Time `json:"statusUpdateDateTime"`
+		ExpirationDateTime   *time.Time `json:"expirationDateTime"`
+	} `json:"data"`
+}
+
+// State returns the consent state from the answer to the consent request
+func (r *PaymentConsentRequestResp) State() domain.ConsentState {
+	return requestState(r.RequestID, r.ConsentID, r.Status, r.AutoApproved)
+}
+
+// State returns the consent state from the consent view
+func (v *PaymentConsentViewWrapper) State() domain.ConsentState {
+	return viewState(v.Data.ConsentID, v.Data.Status,
+		v.Data.CreationDateTime, v.Data.StatusUpdateDateTime, v.Data.ExpirationDateTime)
+}
+
+type paymentAccount struct {
+	SchemeName     string `json:"schemeName"` // RU.CBR.PAN
+	Identification string `json:"identification"`
+	BankCode       string `json:"bank_code,omitempty"` // interbank payment
+}
+
+type paymentRequestBody struct {
+	Data struct {
+		Initiation struct {
+			InstructedAmount struct {
+				Amount   string `json:"amount"`
+				Currency string `json:"currency"`
+			} `json:"instructedAmount"`
+			DebtorAccount   paymentAccount `json:"debtorAccount"`
+			CreditorAccount paymentAccount `json:"creditorAccount"`
+			Comment         string         `json:"comment,omitempty"`
+		} `json:"initiation"`
+	} `json:"data"`
+}
+
+// PaymentInput — payment details, must match the payment consent
+type PaymentInput struct {
+	ClientID         string
+	Amount           string
+	Currency         string
+	DebtorAccount    string
+	CreditorAccount  string
+	CreditorBankCode string
+	Comment          string
+
+	// IdempotencyKey is the same for every attempt of the payment: the bank makes it once
+	// and the call can be retried when its outcome is unknown
+	IdempotencyKey string
+}
+
+type PaymentViewWrapper struct {
+	Data struct {
+		PaymentID            string     `json:"paymentId"`
+		Status               string     `json:"status"` // AcceptedSettlementInProcess | AcceptedSettlementCompleted | Rejected ...
+		CreationDateTime     *time.Time `json:"creationDateTime"`
+		StatusUpdateDateTime *time.Time `json:"statusUpdateDateTime"`
+	} `json:"data"`
+}
+
+// RequestPaymentConsent calls POST /payment-consents/request
+func (c *PaymentClient) RequestPaymentConsent(ctx context.Context, bank domain.Bank, in PaymentConsentInput, bearer string) (*PaymentConsentRequestResp, error) {
+	const op = "service.openbanking.RequestPaymentConsent"
+	log := c.log.With(slog.String("op", op))
+
+	base, err := httputils.NormalizeURL(bank.APIBaseURL)
+	if err != nil {
+		log.Warn("invalid bank api_base_url", slog.String("api_base_url", bank.APIBaseURL), logger.Err(err))
+		return nil, err
+	}
+	u, _ := url.JoinPath(base.String(), "payment-consents", "request")
+
+	b, _ := json.Marshal(paymentConsentRequestBody{
+		RequestingBank:  c.RequestingBank,
+		ClientID:        in.ClientID,
+		ConsentType:     "single_use",
+		Amount:          json.Number(in.Amount),
+		Currency:        in.Currency,
+		DebtorAccount:   in.DebtorAccount,
+		CreditorAccount: in.CreditorAccount,
+		CreditorName:    in.CreditorName,
+		Reference:       in.Reference,
+	})
+
+	req, _ := http.NewRequestWithContext(WithBank(ctx, bank), "POST", u, bytes.NewReader(b))
+	req.Header.Set("Authorization", "Bearer "+bearer)
+	req.Header.Set("X-Requesting-Bank", c.RequestingBank)
+	req.Header.Set("Content-Type", "application/json")
+
+	var out PaymentConsentRequestResp
+	if err := c.do(req, &out); err != nil {
+		log.Warn("failed to request payment consent", logger.Err(err))
+		return nil, fmt.Errorf("payment consents request: %w", err)
+	}
+	return &out, nil
+}
+
+// GetPaymentConsent calls GET /payment-consents/{id}
+func (c *PaymentClient) GetPaymentConsent(ctx context.Context, bank domain.Bank, requestOrConsentID, bearer string) (*PaymentConsentViewWrapper, error) {
+	const op = "service.openbanking.GetPaymentConsent"
+	log := c.log.With(slog.String("op", op))
+
+	base, err := httputils.NormalizeURL(bank.APIBaseURL)
+	if err != nil {
+		log.Warn("invalid bank api_base_url", slog.String("api_base_url", bank.APIBaseURL), logger.Err(err))
+		return nil, err
+	}
+	u, _ := url.JoinPath(base.String(), "payment-consents", requestOrConsentID)
+
+	req, _ := http.NewRequestWithContext(WithBank(ctx, bank), "GET", u, nil)
+	req.Header.Set("Authorization", "Bearer "+bearer)
+	req.Header.Set("X-Requesting-Bank", c.RequestingBank)
+
+	var out PaymentConsentViewWrapper
+	if err := c.do(req, &out); err != nil {
+		log.Warn("failed to get payment consent", logger.Err(err))
+		return nil, fmt.Errorf("payment consents get: %w", err)
+	}
+	return &out, nil
+}
+
+// CreatePayment calls POST /payments?client_id=... with the payment consent in X-Payment-Consent-Id
+func (c *PaymentClient) CreatePayment(ctx context.Context, bank domain.Bank, in PaymentInput, bearer, paymentConsentID string) (*PaymentViewWrapper, error) {
+	const op = "service.openbanking.CreatePayment"
+	log := c.log.With(slog.String("op", op))
+
+	base, err := httputils.NormalizeURL(bank.APIBaseURL)
+	if err != nil {
+		log.Warn("invalid bank api_base_url", slog.String("api_base_url", bank.APIBaseURL), logger.Err(err))
+		return nil, err
+	}
+	u, _ := url.JoinPath(base.String(), "payments")
+	uu, _ := url.Parse(u)
+	q := uu.Query()
+	q.Set("client_id", in.ClientID)
+	uu.RawQuery = q.Encode()
+
+	var body paymentRequestBody
+	body.Data.Initiation.InstructedAmount.Amount = in.Amount
+	body.Data.Initiation.InstructedAmount.Currency = in.Currency
+	body.Data.Initiation.DebtorAccount = paymentAccount{SchemeName: "RU.CBR.PAN", Identification: in.DebtorAccount}
+	body.Data.Initiation.CreditorAccount = paymentAccount{SchemeName: "RU.CBR.PAN", Identification: in.CreditorAccount, BankCode: in.CreditorBankCode}
+	body.Data.Initiation.Comment = in.Comment
+	b, _ := json.Marshal(body)
+
+	ctx = WithBank(ctx, bank)
+	if in.IdempotencyKey != "" {
+		ctx = WithIdempotent(ctx)
+	}
+	req, _ := http.NewRequestWithContext(ctx, "POST", uu.String(), bytes.NewReader(b))
+	req.Header.Set("Authorization", "Bearer "+bearer)
+	req.Header.Set("X-Requesting-Bank", c.RequestingBank)
+	req.Header.Set("X-Payment-Consent-Id", paymentConsentID)
+	if in.IdempotencyKey != "" {
+		req.Header.Set("X-Idempotency-Key", in.IdempotencyKey)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	var out PaymentViewWrapper
+	if err := c.do(req, &out); err != nil {
+		log.Warn("failed to create payment", logger.Err(err))
+		return nil, fmt.Errorf("payments create: %w", err)
+	}
+	return &out, nil
+}
+
+// GetPayment calls GET /payments/{id}
+func (c *PaymentClient) GetPayment(ctx context.Context, bank domain.Bank, paymentID, clientID, bearer string) (*PaymentViewWrapper, error) {
+	const op = "service.openbanking.GetPayment"
+	log := c.log.With(slog.String("op", op))
+
+	base, err := httputils.NormalizeURL(bank.APIBaseURL)
+	if err != nil {
+		log.Warn("invalid bank api_base_url", slog.String("api_base_url", bank.APIBaseURL), logger.Err(err))
+		return nil, err
+	}
+	u, _ := url.JoinPath(base.String(), "payments", paymentID)
+	uu, _ := url.Parse(u)
+	q := uu.Query()
+	q.Set("client_id", clientID)
+	uu.RawQuery = q.Encode()
+
+	req, _ := http.NewRequestWithContext(WithBank(ctx, bank), "GET", uu.String(), nil)
+	req.Header.Set("Authorization", "Bearer "+bearer)
+	req.Header.Set("X-Requesting-Bank", c.RequestingBank)
+
+	var out PaymentViewWrapper
+	if err := c.do(req, &out); err != nil {
+		log.Warn("failed to get payment", logger.Err(err))
+		return nil, fmt.Errorf("payments get: %w", err)
+	}
+	return &out, nil
+}
+
+// do sends the request and decodes a 2xx JSON response into out
+func (c *PaymentClient) do(req *http.Request, out any) error {
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if !isOK(resp.StatusCode) {
+		all, _ := io.ReadAll(resp.Body)
+		return &StatusError{Code: resp.StatusCode, Body: string(all)}
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
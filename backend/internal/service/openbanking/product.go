@@ -96,18 +96,19 @@ func (p bankProduct) ToDomain(bid int64, bcode, bname string) domain.Product {
 	}
 }
 
-func (c *ProductClient) GetProducts(ctx context.Context, apiBaseURL, bearer, productType string) ([]domain.Product, error) {
+// GetProducts calls GET /products?product_type=... (all types if empty)
+func (c *ProductClient) GetProducts(ctx context.Context, bank domain.Bank, bearer, productType string) ([]domain.Product, error) {
 	const op = "service.openbanking.GetProducts"
 
 	log := c.log.With(
 		slog.String("op", op),
-		slog.String("apiBaseURL", apiBaseURL),
+		slog.String("apiBaseURL", bank.APIBaseURL),
 		slog.String("productType", productType),
 	)
 
 	log.Info("getting products")
 
-	base, err := httputils.NormalizeURL(apiBaseURL)
+	base, err := httputils.NormalizeURL(bank.APIBaseURL)
 	if err != nil {
 		log.Warn("error normalizing base url", logger.Err(err))
 		return nil, err
@@ -120,7 +121,7 @@ func (c *ProductClient) GetProducts(ctx context.Context, apiBaseURL, bearer, pro
 		u.RawQuery = q.Encode()
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), http.NoBody)
+	req, err := http.NewRequestWithContext(WithBank(ctx, bank), http.MethodGet, u.String(), http.NoBody)
 	if err != nil {
 		log.Warn("error creating request", logger.Err(err))
 		return nil, err
@@ -0,0 +1,153 @@
+// internal/service/openbanking/token.go
+
+package openbanking
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"multibank/backend/internal/domain"
+	httputils "multibank/backend/internal/http-server/utils"
+	"multibank/backend/internal/logger"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Token — access token of the team in the bank API
+type Token struct {
+	AccessToken string
+	ExpiresAt   time.Time
+}
+
+type TokenClient struct {
+	log  *slog.Logger
+	HTTP *http.Client
+}
+
+func NewTokenClient(log *slog.Logger, httpClient *http.Client) *TokenClient {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &TokenClient{log: log, HTTP: httpClient}
+}
+
+type bankTokenResp struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ClientID    string `json:"client_id"`
+	Algorithm   string `json:"algorithm"`
+	ExpiresIn   int64  `json:"expires_in"` // seconds
+}
+
+// Token calls POST /auth/bank-token?client_id=...&client_secret=... (sandbox flow)
+func (c *TokenClient) Token(ctx context.Context, bank domain.Bank) (Token, error) {
+	const op = "openbanking.token.Token"
+	log := c.log.With(slog.String("op", op), slog.String("bank", bank.Code))
+
+	base, err := httputils.NormalizeURL(bank.APIBaseURL)
+	if err != nil {
+		log.Warn("invalid bank api_base_url", slog.String("api_base_url", bank.APIBaseURL), logger.Err(err))
+		return Token{}, err
+	}
+
+	// /auth/bank-token + query ?client_id=...&client_secret=...
+	tokenURL := base.ResolveReference(&url.URL{Path: "/auth/bank-token"})
+	q := tokenURL.Query()
+	q.Set("client_id", bank.Login)
+	q.Set("client_secret", bank.Password)
+	tokenURL.RawQuery = q.Encode()
+
+	// log w/o secret
+	log.Info("requesting bank token", slog.String("url", maskSecret(tokenURL)))
+
+	// POST w/o body, safe to retry: every call just issues a new token
+	reqCtx := WithIdempotent(WithBank(ctx, bank))
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodPost, tokenURL.String(), http.NoBody)
+	if err != nil {
+		log.Warn("unable to create http request", logger.Err(err))
+		return Token{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	return c.do(req, log)
+}
+
+// OAuth2Token calls POST /oauth2/token with client_credentials grant in a JSON body
+func (c *TokenClient) OAuth2Token(ctx context.Context, bank domain.Bank) (Token, error) {
+	const op = "openbanking.token.OAuth2Token"
+	log := c.log.With(slog.String("op", op), slog.String("bank", bank.Code))
+
+	base, err := httputils.NormalizeURL(bank.APIBaseURL)
+	if err != nil {
+		log.Warn("invalid bank api_base_url", slog.String("api_base_url", bank.APIBaseURL), logger.Err(err))
+		return Token{}, err
+	}
+	tokenURL := base.ResolveReference(&url.URL{Path: "/oauth2/token"})
+
+	b, _ := json.Marshal(map[string]string{
+		"grant_type":    "client_credentials",
+		"client_id":     bank.Login,
+		"client_secret": bank.Password,
+	})
+	log.Info("requesting bank token", slog.String("url", tokenURL.String()))
+
+	reqCtx := WithIdempotent(WithBank(ctx, bank))
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodPost, tokenURL.String(), bytes.NewReader(b))
+	if err != nil {
+		log.Warn("unable to create http request", logger.Err(err))
+		return Token{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	return c.do(req, log)
+}
+
+func (c *TokenClient) do(req *http.Request, log *slog.Logger) (Token, error) {
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		// transport errors carry the request url, do not let the secret into logs
+		var ue *url.Error
+		if errors.As(err, &ue) {
+			ue.URL = maskSecret(req.URL)
+		}
+		log.Warn("unable send req for a bank token", logger.Err(err))
+		return Token{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		log.Warn("token endpoint returned non-200", slog.String("status", resp.Status))
+		return Token{}, fmt.Errorf("bank-token %d: %s", resp.StatusCode, string(body))
+	}
+
+	var tr bankTokenResp
+	if err := json.NewDecoder(resp.Body).Decode(&tr); err != nil {
+		log.Warn("failed to decode bank token response", logger.Err(err))
+		return Token{}, err
+	}
+	if tr.AccessToken == "" || tr.ExpiresIn <= 0 {
+		return Token{}, fmt.Errorf("bank-token: invalid response")
+	}
+	return Token{
+		AccessToken: tr.AccessToken,
+		ExpiresAt:   time.Now().Add(time.Duration(tr.ExpiresIn) * time.Second),
+	}, nil
+}
+
+// maskSecret returns the url with client_secret hidden
+func maskSecret(u *url.URL) string {
+	masked := *u
+	q := masked.Query()
+	if q.Has("client_secret") {
+		q.Set("client_secret", "******")
+		masked.RawQuery = q.Encode()
+	}
+	return masked.String()
+}
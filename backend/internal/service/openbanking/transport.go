@@ -0,0 +1,391 @@
+// internal/service/openbanking/transport.go
+
+package openbanking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/logger"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without calling the bank while its circuit is open
+var ErrCircuitOpen = errors.New("bank circuit is open")
+
+// StatusError — non-2xx answer of the bank
+type StatusError struct {
+	Code int
+	Body string
+}
+
+func (e *StatusError) Error() string { return fmt.Sprintf("%d: %s", e.Code, e.Body) }
+
+// IsRejected reports whether the bank definitely refused the request (4xx except 408 and 429).
+// Network errors, timeouts and 5xx are not rejections: the outcome of such a call is unknown.
+// ErrCircuitOpen is neither: the bank was not called at all.
+func IsRejected(err error) bool {
+	var se *StatusError
+	if !errors.As(err, &se) {
+		return false
+	}
+	return se.Code >= 400 && se.Code < 500 &&
+		se.Code != http.StatusRequestTimeout && se.Code != http.StatusTooManyRequests
+}
+
+// maxRetryAfter — the longest pause of a bank requested by Retry-After
+const maxRetryAfter = time.Minute
+
+type ctxKey int
+
+const (
+	bankKey ctxKey = iota
+	idempotentKey
+)
+
+// WithBank sets the bank of the request: its code is the key of the circuit breaker and the rate limiter
+// (the request host otherwise), its rate limit is applied to the request
+func WithBank(ctx context.Context, bank domain.Bank) context.Context {
+	return context.WithValue(ctx, bankKey, bank)
+}
+
+// WithIdempotent allows retries of a non-idempotent method (e.g. POST /auth/bank-token)
+func WithIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentKey, true)
+}
+
+type TransportOptions struct {
+	MaxAttempts int           // attempts of an idempotent call, 1 = no retries
+	BaseDelay   time.Duration // backoff before the 2nd attempt, doubled every attempt
+	MaxDelay    time.Duration
+
+	FailureThreshold int           // consecutive failed calls to open the circuit
+	OpenTimeout      time.Duration // how long the circuit stays open before a probe
+}
+
+func DefaultTransportOptions() TransportOptions {
+	return TransportOptions{
+		MaxAttempts:      3,
+		BaseDelay:        200 * time.Millisecond,
+		MaxDelay:         2 * time.Second,
+		FailureThreshold: 5,
+		OpenTimeout:      30 * time.Second,
+	}
+}
+
+// Transport — http.RoundTripper shared by openbanking clients: retries idempotent calls with
+// jittered exponential backoff, keeps a circuit breaker and a rate limiter per bank.
+type Transport struct {
+	log     *slog.Logger
+	base    http.RoundTripper
+	opts    TransportOptions
+	limiter *Limiter
+
+	mu       sync.Mutex
+	breakers map[string]*breaker
+}
+
+type breaker struct {
+	state     domain.BreakerState
+	failures  int
+	openedAt  time.Time
+	lastError string
+	probing   bool // a half-open probe is in flight
+}
+
+func NewTransport(log *slog.Logger, base http.RoundTripper, opts TransportOptions) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if opts.MaxAttempts < 1 {
+		opts.MaxAttempts = 1
+	}
+	return &Transport{
+		log:      log,
+		base:     base,
+		opts:     opts,
+		limiter:  NewLimiter(),
+		breakers: make(map[string]*breaker, 8),
+	}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	bank, _ := req.Context().Value(bankKey).(domain.Bank)
+	key := bank.Code
+	if key == "" {
+		key = req.URL.Host
+	}
+
+	if err := t.allow(key); err != nil {
+		return nil, err
+	}
+
+	attempts := 1
+	if t.retryable(req) {
+		attempts = t.opts.MaxAttempts
+	}
+
+	var (
+		resp *http.Response
+		err  error
+	)
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 {
+			if req, err = rewind(req); err != nil {
+				resp = nil
+				break
+			}
+		}
+
+		if err := t.limiter.Wait(req.Context(), key, bank.RateLimit, bank.RateBurst); err != nil {
+			t.release(key) // the bank was not called
+			return nil, err
+		}
+
+		resp, err = t.base.RoundTrip(req)
+		if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+			// the bank quota is exceeded: slow down every request to the bank, the next attempt waits in the limiter
+			pause, ok := retryAfter(resp, time.Now())
+			if !ok {
+				pause = t.backoff(attempt)
+			}
+			t.limiter.Pause(key, pause)
+			t.log.Warn("bank rate limit exceeded",
+				slog.String("bank", key),
+				slog.String("path", req.URL.Path),
+				slog.Duration("pause", pause),
+			)
+			if attempt >= attempts {
+				break
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+			continue
+		}
+		if !failed(resp, err) || attempt >= attempts || req.Context().Err() != nil {
+			break
+		}
+
+		delay := t.backoff(attempt)
+		t.log.Debug("retrying bank request",
+			slog.String("bank", key),
+			slog.String("method", req.Method),
+			slog.String("path", req.URL.Path),
+			slog.Int("attempt", attempt),
+			slog.Duration("delay", delay),
+			slog.String("error", failure(resp, err)),
+		)
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+		if !sleep(req.Context(), delay) {
+			resp, err = nil, req.Context().Err()
+			break
+		}
+	}
+
+	switch {
+	case err == nil && resp.StatusCode == http.StatusTooManyRequests:
+		t.release(key) // the bank is up, only the quota is exceeded
+	case errors.Is(err, context.Canceled):
+		t.release(key) // the caller gave up, nothing is known about the bank
+	case failed(resp, err):
+		t.onFailure(key, failure(resp, err))
+	default:
+		t.onSuccess(key)
+	}
+	return resp, err
+}
+
+// retryable — idempotent methods and requests marked by WithIdempotent (the body must be replayable)
+func (t *Transport) retryable(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	v, _ := req.Context().Value(idempotentKey).(bool)
+	return v
+}
+
+// backoff — full jitter: random delay up to BaseDelay*2^(attempt-1), capped by MaxDelay
+func (t *Transport) backoff(attempt int) time.Duration {
+	d := t.opts.BaseDelay << (attempt - 1)
+	if d <= 0 || d > t.opts.MaxDelay {
+		d = t.opts.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int64N(int64(d)) + 1)
+}
+
+func rewind(req *http.Request) (*http.Request, error) {
+	if req.GetBody == nil {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	r := req.Clone(req.Context())
+	r.Body = body
+	return r, nil
+}
+
+// failed — network errors and 5xx responses of the bank
+func failed(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter parses the Retry-After header (seconds or HTTP date), capped by maxRetryAfter
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	var d time.Duration
+	if sec, err := strconv.Atoi(v); err == nil {
+		d = time.Duration(sec) * time.Second
+	} else if at, err := http.ParseTime(v); err == nil {
+		d = at.Sub(now)
+	} else {
+		return 0, false
+	}
+	return min(max(d, 0), maxRetryAfter), true
+}
+
+func failure(resp *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return resp.Status
+}
+
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// allow checks the circuit of the bank: an open circuit fails fast until OpenTimeout passes,
+// then one probe request is let through (half-open)
+func (t *Transport) allow(key string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	b := t.breakers[key]
+	if b == nil {
+		return nil
+	}
+	switch b.state {
+	case domain.BreakerOpen:
+		if time.Since(b.openedAt) < t.opts.OpenTimeout {
+			return fmt.Errorf("%w: %s", ErrCircuitOpen, key)
+		}
+		b.state = domain.BreakerHalfOpen
+		b.probing = true
+		return nil
+	case domain.BreakerHalfOpen:
+		if b.probing {
+			return fmt.Errorf("%w: %s", ErrCircuitOpen, key)
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+func (t *Transport) release(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if b := t.breakers[key]; b != nil {
+		b.probing = false
+	}
+}
+
+func (t *Transport) onSuccess(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	b := t.breakers[key]
+	if b == nil {
+		return
+	}
+	if b.state != domain.BreakerClosed {
+		t.log.Info("bank circuit closed", slog.String("bank", key))
+	}
+	b.state = domain.BreakerClosed
+	b.failures = 0
+	b.probing = false
+}
+
+func (t *Transport) onFailure(key, reason string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	b := t.breakers[key]
+	if b == nil {
+		b = &breaker{state: domain.BreakerClosed}
+		t.breakers[key] = b
+	}
+	b.failures++
+	b.lastError = reason
+	b.probing = false
+
+	if b.state == domain.BreakerHalfOpen || (b.state == domain.BreakerClosed && b.failures >= t.opts.FailureThreshold) {
+		b.state = domain.BreakerOpen
+		b.openedAt = time.Now()
+		t.log.Warn("bank circuit opened",
+			slog.String("bank", key),
+			slog.Int("failures", b.failures),
+			logger.Err(errors.New(reason)),
+		)
+	}
+}
+
+// Breakers returns circuit states of banks that have failed at least once (sorted by bank code)
+func (t *Transport) Breakers() []domain.BankBreaker {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	out := make([]domain.BankBreaker, 0, len(t.breakers))
+	for key, b := range t.breakers {
+		s := domain.BankBreaker{
+			BankCode:  key,
+			State:     b.state,
+			Failures:  b.failures,
+			LastError: b.lastError,
+		}
+		if b.state != domain.BreakerClosed {
+			openedAt, retryAt := b.openedAt, b.openedAt.Add(t.opts.OpenTimeout)
+			s.OpenedAt, s.RetryAt = &openedAt, &retryAt
+		}
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].BankCode < out[j].BankCode })
+	return out
+}
@@ -0,0 +1,568 @@
+// internal/service/payment/service.go
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/logger"
+	ob "multibank/backend/internal/service/openbanking"
+	"multibank/backend/internal/storage"
+	"strings"
+	"time"
+)
+
+type Repo interface {
+	CreateConsent(ctx context.Context, c *domain.PaymentConsent) (int64, error)
+	UpdateConsentAfterCheck(ctx context.Context, id int64, upd *domain.PaymentConsent) error
+	GetConsentByID(ctx context.Context, id int64) (domain.PaymentConsent, error)
+	ListConsentsByUser(ctx context.Context, userID int64) ([]domain.PaymentConsent, error)
+
+	ReservePayment(ctx context.Context, p *domain.Payment) (int64, error)
+	RetakePayment(ctx context.Context, id int64, staleAfter time.Duration) (bool, error)
+	ReleasePayment(ctx context.Context, id int64) error
+	ListStalePayments(ctx context.Context, staleAfter time.Duration, limit int) ([]domain.Payment, error)
+	ConfirmPayment(ctx context.Context, p *domain.Payment) error
+	UpdatePaymentStatus(ctx context.Context, id int64, status domain.PaymentStatus, statusUpdated *time.Time) error
+	GetPaymentByID(ctx context.Context, id int64) (domain.Payment, error)
+	GetPaymentByConsent(ctx context.Context, paymentConsentID int64) (domain.Payment, error)
+	ListPaymentsByUser(ctx context.Context, userID int64) ([]domain.Payment, error)
+}
+
+type BankService interface {
+	GetBankByID(ctx context.Context, id int64) (domain.Bank, error)
+	GetOrRefreshToken(ctx context.Context, bankID int64) (string, time.Time, error)
+}
+
+type AccountService interface {
+	ListUserAccounts(ctx context.Context, userID int64, bankID *int64) ([]domain.AccountShort, error)
+}
+
+type OBPaymentClient interface {
+	RequestPaymentConsent(ctx context.Context, bank domain.Bank, in ob.PaymentConsentInput, bearer string) (*ob.PaymentConsentRequestResp, error)
+	GetPaymentConsent(ctx context.Context, bank domain.Bank, requestOrConsentID, bearer string) (*ob.PaymentConsentViewWrapper, error)
+	CreatePayment(ctx context.Context, bank domain.Bank, in ob.PaymentInput, bearer, paymentConsentID string) (*ob.PaymentViewWrapper, error)
+	GetPayment(ctx context.Context, bank domain.Bank, paymentID, clientID, bearer string) (*ob.PaymentViewWrapper, error)
+}
+
+var (
+	ErrAccountNotFound        = errors.New("account not found")
+	ErrInvalidAmount          = errors.New("invalid amount")
+	ErrInvalidCreditor        = errors.New("invalid creditor account")
+	ErrConsentNotFound        = errors.New("payment consent not found")
+	ErrConsentNotAuthorized   = errors.New("payment consent is not authorized")
+	ErrConsentAlreadyUsed     = errors.New("payment consent is already used")
+	ErrPaymentInProgress      = errors.New("payment with the consent is being initiated")
+	ErrPaymentRejected        = errors.New("payment rejected by the bank")
+	ErrBankUnavailable        = errors.New("bank is temporarily unavailable")
+	ErrPaymentNotFound        = errors.New("payment not found")
+	ErrCurrencyMismatch       = errors.New("currency does not match the account currency")
+	errMissingBankPaymentData = errors.New("bank returned no payment id")
+)
+
+// staleReservation — a reserved payment not confirmed for this long is considered lost
+// and is sent again (with the same idempotency key) by InitiatePayment or ResumePayments
+const staleReservation = 2 * time.Minute
+
+type Service struct {
+	log      *slog.Logger
+	repo     Repo
+	banks    BankService
+	accounts AccountService
+	client   OBPaymentClient
+
+	reqBankCode string
+}
+
+func New(log *slog.Logger, repo Repo, banks BankService, accounts AccountService, client OBPaymentClient, reqBankCode string) *Service {
+	return &Service{log: log, repo: repo, banks: banks, accounts: accounts, client: client, reqBankCode: reqBankCode}
+}
+
+type CreateConsentInput struct {
+	UserID           int64
+	BankID           *int64 // optional, if account ids are not unique across banks
+	AccountID        string // debtor account (from ListUserAccounts)
+	Amount           string // e.g. "1500.00"
+	Currency         string // default: currency of the account
+	CreditorAccount  string // account number of the recipient
+	CreditorName     string
+	CreditorBankCode string // empty = the same bank
+	Reference        string
+}
+
+// CreateConsent requests a single-use payment consent in the bank of the debtor account.
+// If the bank auto-approves it, the consent can be used for InitiatePayment right away.
+func (s *Service) CreateConsent(ctx context.Context, in CreateConsentInput) (domain.PaymentConsent, error) {
+	const op = "service.payment.CreateConsent"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", in.UserID),
+		slog.String("account_id", in.AccountID),
+	)
+
+	amount, err := parseAmount(in.Amount)
+	if err != nil {
+		return domain.PaymentConsent{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if strings.TrimSpace(in.CreditorAccount) == "" {
+		return domain.PaymentConsent{}, fmt.Errorf("%s: %w", op, ErrInvalidCreditor)
+	}
+
+	acc, err := s.findAccount(ctx, in.UserID, in.BankID, in.AccountID)
+	if err != nil {
+		return domain.PaymentConsent{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	currency := strings.ToUpper(strings.TrimSpace(in.Currency))
+	if currency == "" {
+		currency = acc.Currency
+	}
+	if acc.Currency != "" && currency != acc.Currency {
+		return domain.PaymentConsent{}, fmt.Errorf("%s: %w", op, ErrCurrencyMismatch)
+	}
+
+	bank, err := s.banks.GetBankByID(ctx, acc.BankID)
+	if err != nil {
+		return domain.PaymentConsent{}, fmt.Errorf("%s: get bank: %w", op, err)
+	}
+	token, _, err := s.banks.GetOrRefreshToken(ctx, bank.ID)
+	if err != nil {
+		return domain.PaymentConsent{}, fmt.Errorf("%s: get token: %w", op, err)
+	}
+
+	debtor := acc.Number()
+	resp, err := s.client.RequestPaymentConsent(ctx, bank, ob.PaymentConsentInput{
+		ClientID:        acc.ClientID,
+		Amount:          domain.FormatKopecks(amount),
+		Currency:        currency,
+		DebtorAccount:   debtor,
+		CreditorAccount: in.CreditorAccount,
+		CreditorName:    in.CreditorName,
+		Reference:       in.Reference,
+	}, token)
+	if err != nil {
+		log.Warn("failed to request payment consent", logger.Err(err))
+		return domain.PaymentConsent{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	c := &domain.PaymentConsent{
+		UserID:           in.UserID,
+		BankID:           bank.ID,
+		ConsentState:     resp.State(),
+		ClientID:         acc.ClientID,
+		RequestingBank:   s.reqBankCode,
+		ConsentType:      "single_use",
+		Amount:           domain.FormatKopecks(amount),
+		Currency:         currency,
+		DebtorAccount:    debtor,
+		CreditorAccount:  in.CreditorAccount,
+		CreditorName:     in.CreditorName,
+		CreditorBankCode: in.CreditorBankCode,
+		Reference:        in.Reference,
+	}
+
+	// auto-approved — take dates (and consent id) from the detailed view
+	if resp.AutoApproved != nil && *resp.AutoApproved {
+		if v, err := s.client.GetPaymentConsent(ctx, bank, c.Key(), token); err == nil {
+			c.Apply(v.State())
+		} else {
+			log.Warn("auto-approved but failed to fetch detailed payment consent", logger.Err(err))
+		}
+	}
+
+	id, err := s.repo.CreateConsent(ctx, c)
+	if err != nil {
+		return domain.PaymentConsent{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("payment consent created", slog.Int64("id", id), slog.String("status", string(c.Status)))
+	return s.repo.GetConsentByID(ctx, id)
+}
+
+// GetConsent returns the user's payment consent. A consent awaiting authorization is re-checked in the bank.
+func (s *Service) GetConsent(ctx context.Context, userID, id int64) (domain.PaymentConsent, error) {
+	const op = "service.payment.GetConsent"
+
+	c, err := s.userConsent(ctx, userID, id)
+	if err != nil {
+		return domain.PaymentConsent{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if c.Status != domain.AwaitingAuthorisation {
+		return c, nil
+	}
+
+	if c, err = s.refreshConsent(ctx, c); err != nil {
+		return domain.PaymentConsent{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return c, nil
+}
+
+// ListConsents returns payment consents of the user
+func (s *Service) ListConsents(ctx context.Context, userID int64) ([]domain.PaymentConsent, error) {
+	return s.repo.ListConsentsByUser(ctx, userID)
+}
+
+type InitiateInput struct {
+	UserID           int64
+	PaymentConsentID int64 // payment_consents.id (internal)
+	Comment          string
+}
+
+// InitiatePayment makes the payment described by an authorized payment consent.
+// A single-use consent can be used only once.
+func (s *Service) InitiatePayment(ctx context.Context, in InitiateInput) (domain.Payment, error) {
+	const op = "service.payment.InitiatePayment"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", in.UserID),
+		slog.Int64("payment_consent_id", in.PaymentConsentID),
+	)
+
+	c, err := s.userConsent(ctx, in.UserID, in.PaymentConsentID)
+	if err != nil {
+		return domain.Payment{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if c.Status == domain.AwaitingAuthorisation {
+		if c, err = s.refreshConsent(ctx, c); err != nil {
+			return domain.Payment{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	if c.Status != domain.Authorised || c.ConsentID == nil || *c.ConsentID == "" {
+		return domain.Payment{}, fmt.Errorf("%s: %w", op, ErrConsentNotAuthorized)
+	}
+
+	bank, err := s.banks.GetBankByID(ctx, c.BankID)
+	if err != nil {
+		return domain.Payment{}, fmt.Errorf("%s: get bank: %w", op, err)
+	}
+	token, _, err := s.banks.GetOrRefreshToken(ctx, bank.ID)
+	if err != nil {
+		return domain.Payment{}, fmt.Errorf("%s: get token: %w", op, err)
+	}
+
+	comment := in.Comment
+	if comment == "" {
+		comment = c.Reference
+	}
+
+	// the payment row is reserved first: of concurrent calls only one gets to the bank
+	p, retaken, err := s.reserve(ctx, &domain.Payment{
+		UserID:           in.UserID,
+		BankID:           bank.ID,
+		PaymentConsentID: c.ID,
+		Amount:           c.Amount,
+		Currency:         c.Currency,
+		DebtorAccount:    c.DebtorAccount,
+		CreditorAccount:  c.CreditorAccount,
+		CreditorName:     c.CreditorName,
+		CreditorBankCode: c.CreditorBankCode,
+		Comment:          comment,
+	})
+	if err != nil {
+		return domain.Payment{}, fmt.Errorf("%s: %w", op, err)
+	}
+	log = log.With(slog.Int64("id", p.ID))
+
+	p, err = s.send(ctx, log, bank, token, c, p)
+	if err != nil {
+		// the bank was not called: a new reservation is dropped and the consent stays free.
+		// A retaken one is kept, its earlier call may have reached the bank.
+		if errors.Is(err, ErrBankUnavailable) && !retaken {
+			if rerr := s.repo.ReleasePayment(ctx, p.ID); rerr != nil {
+				return domain.Payment{}, fmt.Errorf("%s: %w", op, errors.Join(err, rerr))
+			}
+		}
+		return domain.Payment{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return p, nil
+}
+
+// ResumePayments repeats the bank call of reservations left with an unknown outcome (a timeout, 5xx
+// or a lost call) with the same idempotency key, so the bank makes each payment at most once.
+// Returns the number of payments answered by the bank.
+func (s *Service) ResumePayments(ctx context.Context, batchLimit int) (int, error) {
+	const op = "service.payment.ResumePayments"
+
+	if batchLimit <= 0 {
+		batchLimit = 50
+	}
+
+	items, err := s.repo.ListStalePayments(ctx, staleReservation, batchLimit)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	answered := 0
+	for _, p := range items {
+		if err := ctx.Err(); err != nil {
+			return answered, err
+		}
+		log := s.log.With(slog.String("op", op), slog.Int64("id", p.ID))
+
+		// InitiatePayment may be retrying it right now
+		ok, err := s.repo.RetakePayment(ctx, p.ID, staleReservation)
+		if err != nil {
+			log.Warn("failed to retake payment", logger.Err(err))
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		if _, err := s.resume(ctx, log, p); err != nil && !errors.Is(err, ErrPaymentRejected) {
+			log.Warn("payment resume failed", logger.Err(err))
+			continue
+		}
+		answered++
+	}
+	return answered, nil
+}
+
+// resume sends the reserved payment again, the reservation stays if the outcome is still unknown
+func (s *Service) resume(ctx context.Context, log *slog.Logger, p domain.Payment) (domain.Payment, error) {
+	c, err := s.repo.GetConsentByID(ctx, p.PaymentConsentID)
+	if err != nil {
+		return domain.Payment{}, err
+	}
+	if c.ConsentID == nil || *c.ConsentID == "" {
+		return domain.Payment{}, ErrConsentNotAuthorized
+	}
+
+	bank, err := s.banks.GetBankByID(ctx, p.BankID)
+	if err != nil {
+		return domain.Payment{}, err
+	}
+	token, _, err := s.banks.GetOrRefreshToken(ctx, bank.ID)
+	if err != nil {
+		return domain.Payment{}, err
+	}
+
+	log.Info("resuming payment initiation")
+	return s.send(ctx, log, bank, token, c, p)
+}
+
+// send calls the bank for the reserved payment and saves the answer. A rejected payment is saved
+// as Rejected (ErrPaymentRejected); ErrBankUnavailable means the bank was not called (its circuit is open).
+// Other errors leave the outcome unknown: the reservation stays for ResumePayments.
+func (s *Service) send(ctx context.Context, log *slog.Logger, bank domain.Bank, token string, c domain.PaymentConsent, p domain.Payment) (domain.Payment, error) {
+	v, err := s.client.CreatePayment(ctx, bank, ob.PaymentInput{
+		ClientID:         c.ClientID,
+		Amount:           p.Amount,
+		Currency:         p.Currency,
+		DebtorAccount:    p.DebtorAccount,
+		CreditorAccount:  p.CreditorAccount,
+		CreditorBankCode: p.CreditorBankCode,
+		Comment:          p.Comment,
+		IdempotencyKey:   idempotencyKey(c),
+	}, token, *c.ConsentID)
+	switch {
+	case err == nil:
+	case errors.Is(err, ob.ErrCircuitOpen):
+		log.Warn("bank is unavailable, payment not sent", logger.Err(err))
+		return p, fmt.Errorf("%w: %w", ErrBankUnavailable, err)
+	case ob.IsRejected(err):
+		log.Warn("bank rejected the payment", logger.Err(err))
+		if uerr := s.repo.UpdatePaymentStatus(ctx, p.ID, domain.PaymentRejected, nil); uerr != nil {
+			return p, errors.Join(err, uerr)
+		}
+		return p, fmt.Errorf("%w: %w", ErrPaymentRejected, err)
+	default:
+		log.Warn("failed to create payment", logger.Err(err))
+		return p, err
+	}
+	if v.Data.PaymentID == "" {
+		return p, errMissingBankPaymentData
+	}
+
+	p.PaymentID = v.Data.PaymentID
+	p.Status = domain.PaymentStatus(v.Data.Status)
+	if p.Status == "" {
+		p.Status = domain.PaymentPending
+	}
+	p.CreationDateTime = v.Data.CreationDateTime
+	p.StatusUpdateDateTime = v.Data.StatusUpdateDateTime
+	if err := s.repo.ConfirmPayment(ctx, &p); err != nil {
+		return p, err
+	}
+
+	log.Info("payment initiated", slog.String("payment_id", p.PaymentID), slog.String("status", string(p.Status)))
+	return s.repo.GetPaymentByID(ctx, p.ID)
+}
+
+// reserve saves the payment as Initiating. If the consent already has a payment, ErrConsentAlreadyUsed
+// is returned, or ErrPaymentInProgress while another call initiates it. A reservation left by a lost call
+// is taken over after staleReservation (retaken is then true).
+func (s *Service) reserve(ctx context.Context, p *domain.Payment) (_ domain.Payment, retaken bool, err error) {
+	id, err := s.repo.ReservePayment(ctx, p)
+	if err == nil {
+		res, err := s.repo.GetPaymentByID(ctx, id)
+		return res, false, err
+	}
+	if !errors.Is(err, storage.ErrPaymentExists) {
+		return domain.Payment{}, false, err
+	}
+
+	prev, err := s.repo.GetPaymentByConsent(ctx, p.PaymentConsentID)
+	if err != nil {
+		return domain.Payment{}, false, err
+	}
+	if prev.Status != domain.PaymentInitiating {
+		return domain.Payment{}, false, ErrConsentAlreadyUsed
+	}
+	ok, err := s.repo.RetakePayment(ctx, prev.ID, staleReservation)
+	if err != nil {
+		return domain.Payment{}, false, err
+	}
+	if !ok {
+		return domain.Payment{}, false, ErrPaymentInProgress
+	}
+	s.log.Info("retrying lost payment initiation", slog.Int64("id", prev.ID))
+	return prev, true, nil
+}
+
+// PaymentByConsent returns the user's payment made with the payment consent
+func (s *Service) PaymentByConsent(ctx context.Context, userID, paymentConsentID int64) (domain.Payment, error) {
+	const op = "service.payment.PaymentByConsent"
+
+	p, err := s.repo.GetPaymentByConsent(ctx, paymentConsentID)
+	if err != nil {
+		if errors.Is(err, storage.ErrPaymentNotFound) {
+			return domain.Payment{}, fmt.Errorf("%s: %w", op, ErrPaymentNotFound)
+		}
+		return domain.Payment{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if p.UserID != userID {
+		return domain.Payment{}, fmt.Errorf("%s: %w", op, ErrPaymentNotFound)
+	}
+	return p, nil
+}
+
+// GetPayment returns the user's payment. If the status is not final it is polled from the bank.
+func (s *Service) GetPayment(ctx context.Context, userID, id int64) (domain.Payment, error) {
+	const op = "service.payment.GetPayment"
+
+	p, err := s.repo.GetPaymentByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrPaymentNotFound) {
+			return domain.Payment{}, fmt.Errorf("%s: %w", op, ErrPaymentNotFound)
+		}
+		return domain.Payment{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if p.UserID != userID {
+		return domain.Payment{}, fmt.Errorf("%s: %w", op, ErrPaymentNotFound)
+	}
+	if p.Status.IsFinal() || p.Status == domain.PaymentInitiating {
+		return p, nil
+	}
+
+	if p, err = s.RefreshPayment(ctx, p); err != nil {
+		return domain.Payment{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return p, nil
+}
+
+// RefreshPayment asks the bank for the payment status and saves it
+func (s *Service) RefreshPayment(ctx context.Context, p domain.Payment) (domain.Payment, error) {
+	bank, err := s.banks.GetBankByID(ctx, p.BankID)
+	if err != nil {
+		return domain.Payment{}, err
+	}
+	token, _, err := s.banks.GetOrRefreshToken(ctx, bank.ID)
+	if err != nil {
+		return domain.Payment{}, err
+	}
+
+	c, err := s.repo.GetConsentByID(ctx, p.PaymentConsentID)
+	if err != nil {
+		return domain.Payment{}, err
+	}
+
+	v, err := s.client.GetPayment(ctx, bank, p.PaymentID, c.ClientID, token)
+	if err != nil {
+		return domain.Payment{}, err
+	}
+
+	status := domain.PaymentStatus(v.Data.Status)
+	if status == "" || (status == p.Status && v.Data.StatusUpdateDateTime == nil) {
+		return p, nil
+	}
+	if err := s.repo.UpdatePaymentStatus(ctx, p.ID, status, v.Data.StatusUpdateDateTime); err != nil {
+		return domain.Payment{}, err
+	}
+	return s.repo.GetPaymentByID(ctx, p.ID)
+}
+
+// ListPayments returns payments of the user
+func (s *Service) ListPayments(ctx context.Context, userID int64) ([]domain.Payment, error) {
+	return s.repo.ListPaymentsByUser(ctx, userID)
+}
+
+// userConsent returns the payment consent if it belongs to the user
+func (s *Service) userConsent(ctx context.Context, userID, id int64) (domain.PaymentConsent, error) {
+	c, err := s.repo.GetConsentByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrPaymentConsentNotFound) {
+			return domain.PaymentConsent{}, ErrConsentNotFound
+		}
+		return domain.PaymentConsent{}, err
+	}
+	if c.UserID != userID {
+		return domain.PaymentConsent{}, ErrConsentNotFound
+	}
+	return c, nil
+}
+
+func (s *Service) refreshConsent(ctx context.Context, c domain.PaymentConsent) (domain.PaymentConsent, error) {
+	bank, err := s.banks.GetBankByID(ctx, c.BankID)
+	if err != nil {
+		return domain.PaymentConsent{}, err
+	}
+	token, _, err := s.banks.GetOrRefreshToken(ctx, bank.ID)
+	if err != nil {
+		return domain.PaymentConsent{}, err
+	}
+
+	v, err := s.client.GetPaymentConsent(ctx, bank, c.Key(), token)
+	if err != nil {
+		return domain.PaymentConsent{}, err
+	}
+
+	upd := c
+	upd.Apply(v.State())
+	if err := s.repo.UpdateConsentAfterCheck(ctx, c.ID, &upd); err != nil {
+		return domain.PaymentConsent{}, err
+	}
+	return s.repo.GetConsentByID(ctx, c.ID)
+}
+
+// findAccount looks for the account among the user's accounts
+func (s *Service) findAccount(ctx context.Context, userID int64, bankID *int64, accountID string) (domain.AccountShort, error) {
+	accs, err := s.accounts.ListUserAccounts(ctx, userID, bankID)
+	if err != nil {
+		return domain.AccountShort{}, err
+	}
+	for _, a := range accs {
+		if a.AccountID == accountID {
+			return a, nil
+		}
+	}
+	return domain.AccountShort{}, ErrAccountNotFound
+}
+
+// idempotencyKey is the same for every attempt to pay with the consent
+func idempotencyKey(c domain.PaymentConsent) string {
+	return "payment-" + *c.ConsentID
+}
+
+// parseAmount returns a positive amount in kopecks (at most two fraction digits)
+func parseAmount(s string) (int64, error) {
+	v, err := domain.ParseKopecks(s)
+	if err != nil || v <= 0 {
+		return 0, ErrInvalidAmount
+	}
+	return v, nil
+}
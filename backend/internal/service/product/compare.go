@@ -0,0 +1,96 @@
+// internal/service/product/compare.go
+package product
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"multibank/backend/internal/domain"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrInvalidAmount       = errors.New("amount must be positive")
+	ErrInvalidTerm         = errors.New("term must be from 1 to 360 months")
+	ErrUnsupportedCurrency = errors.New("only RUB deposits are available")
+)
+
+// catalogueCurrency — banks publish product amounts in rubles
+const catalogueCurrency = "RUB"
+
+type CompareInput struct {
+	Amount     float64
+	TermMonths int
+	Currency   string // RUB if empty
+	UserID     int64  // to flag products recommended to the user
+}
+
+// CompareDeposits returns deposits accepting the amount for the term (products without a fixed term
+// or with exactly this term), with payouts for simple interest and for monthly capitalization.
+// Offers are ranked by the capitalized payout; recommended products go first among equal payouts.
+func (s *Service) CompareDeposits(ctx context.Context, in CompareInput) ([]domain.DepositOffer, error) {
+	const op = "service.product.CompareDeposits"
+
+	if in.Amount <= 0 || math.IsInf(in.Amount, 0) || math.IsNaN(in.Amount) {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidAmount)
+	}
+	if in.TermMonths < 1 || in.TermMonths > 360 {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidTerm)
+	}
+	if in.Currency != "" && !strings.EqualFold(in.Currency, catalogueCurrency) {
+		return nil, fmt.Errorf("%s: %w", op, ErrUnsupportedCurrency)
+	}
+
+	page, err := s.list(ctx, domain.ProductFilter{
+		ProductType: "deposit",
+		Amount:      &in.Amount,
+		Sort:        domain.ProductSortRate,
+		Desc:        true,
+		UserID:      in.UserID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	out := make([]domain.DepositOffer, 0, len(page.Items))
+	for _, p := range page.Items {
+		if p.TermMonths != 0 && p.TermMonths != in.TermMonths {
+			continue
+		}
+		out = append(out, depositOffer(p, in.Amount, in.TermMonths))
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].CapitalizedPayout != out[j].CapitalizedPayout {
+			return out[i].CapitalizedPayout > out[j].CapitalizedPayout
+		}
+		return out[i].Product.IsRecommended && !out[j].Product.IsRecommended
+	})
+	for i := range out {
+		out[i].Rank = i + 1
+	}
+	return out, nil
+}
+
+// depositOffer calculates payouts for the annual rate: simple interest is paid at the end of the term,
+// with monthly capitalization the interest is added to the deposit every month
+func depositOffer(p domain.Product, amount float64, term int) domain.DepositOffer {
+	rate := p.InterestRate / 100
+
+	simple := roundKopecks(amount * rate * float64(term) / 12)
+	capitalized := roundKopecks(amount*math.Pow(1+rate/12, float64(term)) - amount)
+
+	return domain.DepositOffer{
+		Product:             p,
+		Amount:              amount,
+		TermMonths:          term,
+		SimpleInterest:      simple,
+		SimplePayout:        roundKopecks(amount + simple),
+		CapitalizedInterest: capitalized,
+		CapitalizedPayout:   roundKopecks(amount + capitalized),
+	}
+}
+
+func roundKopecks(v float64) float64 { return math.Round(v*100) / 100 }
@@ -0,0 +1,115 @@
+// internal/service/product/history.go
+package product
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/logger"
+	"multibank/backend/internal/storage"
+	"strconv"
+)
+
+var ErrProductNotFound = errors.New("product not found")
+
+// Notifier delivers notifications to users
+type Notifier interface {
+	Notify(ctx context.Context, n domain.Notification) error
+}
+
+// History returns changes of the product rate, amounts and term between fetches (oldest first)
+func (s *Service) History(ctx context.Context, bankCode, productID string) ([]domain.ProductChange, error) {
+	const op = "service.product.History"
+
+	p, err := s.product(ctx, bankCode, productID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	out, err := s.products.History(ctx, p.BankID, p.ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return out, nil
+}
+
+// Subscribe makes the user notified when the product rate changes
+func (s *Service) Subscribe(ctx context.Context, userID int64, bankCode, productID string) error {
+	const op = "service.product.Subscribe"
+
+	p, err := s.product(ctx, bankCode, productID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := s.products.Subscribe(ctx, userID, p.BankID, p.ProductID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// Unsubscribe stops notifications about the product
+func (s *Service) Unsubscribe(ctx context.Context, userID int64, bankCode, productID string) error {
+	const op = "service.product.Unsubscribe"
+
+	p, err := s.product(ctx, bankCode, productID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := s.products.Unsubscribe(ctx, userID, p.BankID, p.ProductID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// ListSubscriptions returns products the user is subscribed to
+func (s *Service) ListSubscriptions(ctx context.Context, userID int64) ([]domain.ProductSubscription, error) {
+	return s.products.ListSubscriptions(ctx, userID)
+}
+
+// notifyRateChanges notifies subscribers of products whose interest rate changed
+func (s *Service) notifyRateChanges(ctx context.Context, changes []domain.ProductChange) {
+	for _, c := range changes {
+		if c.Field != domain.FieldInterestRate {
+			continue
+		}
+
+		users, err := s.products.ListSubscribers(ctx, c.BankID, c.ProductID)
+		if err != nil {
+			s.log.Warn("failed to list product subscribers", logger.Err(err), slog.String("product_id", c.ProductID))
+			continue
+		}
+		for _, userID := range users {
+			if err := s.notifier.Notify(ctx, rateChangedNotification(userID, c)); err != nil {
+				s.log.Warn("failed to notify about rate change", logger.Err(err), slog.Int64("user_id", userID))
+			}
+		}
+	}
+}
+
+func rateChangedNotification(userID int64, c domain.ProductChange) domain.Notification {
+	name := c.ProductName
+	if name == "" {
+		name = c.ProductID
+	}
+	return domain.Notification{
+		UserID: userID,
+		Kind:   domain.NotificationProductRateChanged,
+		Title:  "Interest rate changed: " + name,
+		Message: fmt.Sprintf("%s (%s): %s%% → %s%%", name, c.BankCode,
+			strconv.FormatFloat(c.OldValue, 'f', -1, 64), strconv.FormatFloat(c.NewValue, 'f', -1, 64)),
+		BankCode:  c.BankCode,
+		ProductID: c.ProductID,
+	}
+}
+
+func (s *Service) product(ctx context.Context, bankCode, productID string) (domain.Product, error) {
+	p, err := s.products.GetProduct(ctx, bankCode, productID)
+	if err != nil {
+		if errors.Is(err, storage.ErrProductNotFound) {
+			return domain.Product{}, ErrProductNotFound
+		}
+		return domain.Product{}, err
+	}
+	return p, nil
+}
@@ -0,0 +1,87 @@
+// internal/service/product/recommendations.go
+package product
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/logger"
+	"sort"
+	"time"
+)
+
+// Recommendations returns products recommended to the user: matched by recommendation rules
+// (the highest priority first) and then products of the static recommended list
+func (s *Service) Recommendations(ctx context.Context, userID int64) ([]domain.Product, error) {
+	const op = "service.product.Recommendations"
+
+	page, err := s.list(ctx, domain.ProductFilter{UserID: userID})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	out := make([]domain.Product, 0, 16)
+	for _, p := range page.Items {
+		if p.IsRecommended {
+			out = append(out, p)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if (a.RecommendationReason != "") != (b.RecommendationReason != "") {
+			return a.RecommendationReason != ""
+		}
+		return a.RecommendationPriority > b.RecommendationPriority
+	})
+	return out, nil
+}
+
+// Click records a click of the user on the product if the product is recommended to the user.
+// Clicks on other products are not tracked.
+func (s *Service) Click(ctx context.Context, userID int64, bankCode, productID string) error {
+	const op = "service.product.Click"
+
+	p, err := s.product(ctx, bankCode, productID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	items := []domain.Product{p}
+	s.markRecommended(ctx, userID, items)
+	if !items[0].IsRecommended {
+		return nil
+	}
+
+	if err := s.events.Add(ctx, []domain.RecommendationEvent{
+		recommendationEvent(domain.RecommendationClick, userID, items[0], time.Now()),
+	}); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// trackImpressions records impressions of recommended products shown to the user
+func (s *Service) trackImpressions(ctx context.Context, userID int64, items []domain.Product) {
+	now := time.Now()
+	events := make([]domain.RecommendationEvent, 0, 8)
+	for _, p := range items {
+		if p.IsRecommended {
+			events = append(events, recommendationEvent(domain.RecommendationImpression, userID, p, now))
+		}
+	}
+	if err := s.events.Add(ctx, events); err != nil {
+		s.log.Warn("failed to track impressions", logger.Err(err), slog.Int64("user_id", userID))
+	}
+}
+
+func recommendationEvent(kind domain.RecommendationEventKind, userID int64, p domain.Product, at time.Time) domain.RecommendationEvent {
+	return domain.RecommendationEvent{
+		Kind:        kind,
+		UserID:      userID,
+		BankCode:    p.BankCode,
+		ProductID:   p.ProductID,
+		ProductType: p.ProductType,
+		RuleID:      p.RecommendationRuleID,
+		CreatedAt:   at,
+	}
+}
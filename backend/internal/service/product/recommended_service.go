@@ -3,15 +3,21 @@ package product
 
 import (
 	"context"
+	"multibank/backend/internal/domain"
 	"time"
 )
 
 type RecommendedService struct {
-	repo RecommendedRepo
+	repo   RecommendedRepo
+	events EventStatsRepo
 }
 
-func NewRecommendedService(repo RecommendedRepo) *RecommendedService {
-	return &RecommendedService{repo: repo}
+type EventStatsRepo interface {
+	Stats(ctx context.Context, from, to *time.Time) ([]domain.RecommendationStat, error)
+}
+
+func NewRecommendedService(repo RecommendedRepo, events EventStatsRepo) *RecommendedService {
+	return &RecommendedService{repo: repo, events: events}
 }
 
 type Rule struct {
@@ -47,3 +53,8 @@ func (s *RecommendedService) Upsert(ctx context.Context, productID, bankCode, pr
 func (s *RecommendedService) Delete(ctx context.Context, productID, bankCode, productType string) error {
 	return s.repo.Delete(ctx, productID, bankCode, productType)
 }
+
+// Stats returns impressions and clicks of recommended products per day, rule and bank
+func (s *RecommendedService) Stats(ctx context.Context, from, to *time.Time) ([]domain.RecommendationStat, error) {
+	return s.events.Stats(ctx, from, to)
+}
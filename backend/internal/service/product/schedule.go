@@ -0,0 +1,203 @@
+// internal/service/product/schedule.go
+package product
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"multibank/backend/internal/domain"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrNotCreditProduct    = errors.New("product is not a loan or a credit card")
+	ErrAmountOutOfRange    = errors.New("amount is out of the product range")
+	ErrTermOutOfRange      = errors.New("term exceeds the product term")
+	ErrInvalidScheduleType = errors.New("schedule type must be annuity or differentiated")
+)
+
+// creditProductTypes — product types repaid by monthly payments
+var creditProductTypes = map[string]struct{}{
+	"loan":        {},
+	"credit_card": {},
+}
+
+type ScheduleInput struct {
+	BankCode   string
+	ProductID  string
+	Amount     float64
+	TermMonths int                 // the product term if 0
+	Type       domain.ScheduleType // annuity if empty
+}
+
+// Schedule builds the repayment schedule of the credit product for the amount and term
+func (s *Service) Schedule(ctx context.Context, in ScheduleInput) (domain.PaymentSchedule, error) {
+	const op = "service.product.Schedule"
+
+	p, err := s.product(ctx, in.BankCode, in.ProductID)
+	if err != nil {
+		return domain.PaymentSchedule{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if _, ok := creditProductTypes[strings.ToLower(p.ProductType)]; !ok {
+		return domain.PaymentSchedule{}, fmt.Errorf("%s: %w", op, ErrNotCreditProduct)
+	}
+
+	typ := in.Type
+	switch typ {
+	case "":
+		typ = domain.ScheduleAnnuity
+	case domain.ScheduleAnnuity, domain.ScheduleDifferentiated:
+	default:
+		return domain.PaymentSchedule{}, fmt.Errorf("%s: %w", op, ErrInvalidScheduleType)
+	}
+
+	term := in.TermMonths
+	if term == 0 {
+		term = p.TermMonths
+	}
+	if err := validateLoan(in.Amount, term); err != nil {
+		return domain.PaymentSchedule{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if !amountFits(p, in.Amount) {
+		return domain.PaymentSchedule{}, fmt.Errorf("%s: %w", op, ErrAmountOutOfRange)
+	}
+	if p.TermMonths > 0 && term > p.TermMonths {
+		return domain.PaymentSchedule{}, fmt.Errorf("%s: %w", op, ErrTermOutOfRange)
+	}
+
+	return buildSchedule(p, typ, in.Amount, term), nil
+}
+
+// CompareLoans returns credit products of all enabled banks accepting the amount for the term
+// with annuity and differentiated totals; offers are ranked by the annuity overpayment (cheapest first).
+func (s *Service) CompareLoans(ctx context.Context, in CompareInput) ([]domain.LoanOffer, error) {
+	const op = "service.product.CompareLoans"
+
+	if err := validateLoan(in.Amount, in.TermMonths); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if in.Currency != "" && !strings.EqualFold(in.Currency, catalogueCurrency) {
+		return nil, fmt.Errorf("%s: %w", op, ErrUnsupportedCurrency)
+	}
+
+	page, err := s.list(ctx, domain.ProductFilter{
+		ProductTypes: creditTypes(),
+		Amount:       &in.Amount,
+		Sort:         domain.ProductSortRate,
+		UserID:       in.UserID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	out := make([]domain.LoanOffer, 0, len(page.Items))
+	for _, p := range page.Items {
+		if p.TermMonths > 0 && in.TermMonths > p.TermMonths {
+			continue
+		}
+		out = append(out, domain.LoanOffer{
+			Product:        p,
+			Amount:         in.Amount,
+			TermMonths:     in.TermMonths,
+			Annuity:        summarize(buildSchedule(p, domain.ScheduleAnnuity, in.Amount, in.TermMonths)),
+			Differentiated: summarize(buildSchedule(p, domain.ScheduleDifferentiated, in.Amount, in.TermMonths)),
+		})
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Annuity.Overpayment != out[j].Annuity.Overpayment {
+			return out[i].Annuity.Overpayment < out[j].Annuity.Overpayment
+		}
+		return out[i].Product.IsRecommended && !out[j].Product.IsRecommended
+	})
+	for i := range out {
+		out[i].Rank = i + 1
+	}
+	return out, nil
+}
+
+// creditTypes lists creditProductTypes for the catalogue filter
+func creditTypes() []string {
+	out := make([]string, 0, len(creditProductTypes))
+	for t := range creditProductTypes {
+		out = append(out, t)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func validateLoan(amount float64, term int) error {
+	if amount <= 0 || math.IsInf(amount, 0) || math.IsNaN(amount) {
+		return ErrInvalidAmount
+	}
+	if term < 1 || term > 360 {
+		return ErrInvalidTerm
+	}
+	return nil
+}
+
+func amountFits(p domain.Product, amount float64) bool {
+	return amount >= p.MinAmount && (p.MaxAmount == 0 || amount <= p.MaxAmount)
+}
+
+// buildSchedule calculates monthly payments in kopecks (the annual rate is split evenly over months).
+// Rounding differences are settled by the last payment so that the debt is repaid exactly.
+func buildSchedule(p domain.Product, typ domain.ScheduleType, amount float64, term int) domain.PaymentSchedule {
+	rate := p.InterestRate / 100 / 12
+	debt := math.Round(amount * 100) // kopecks
+
+	var annuity, principalPart float64
+	if typ == domain.ScheduleAnnuity {
+		if rate == 0 {
+			annuity = math.Round(debt / float64(term))
+		} else {
+			annuity = math.Round(debt * rate / (1 - math.Pow(1+rate, -float64(term))))
+		}
+	} else {
+		principalPart = math.Round(debt / float64(term))
+	}
+
+	out := domain.PaymentSchedule{
+		Product:    p,
+		Type:       typ,
+		Amount:     amount,
+		TermMonths: term,
+		Payments:   make([]domain.SchedulePayment, 0, term),
+	}
+	var total, interestTotal float64
+	for m := 1; m <= term; m++ {
+		interest := math.Round(debt * rate)
+		principal := principalPart
+		if typ == domain.ScheduleAnnuity {
+			principal = annuity - interest
+		}
+		if m == term || principal > debt {
+			principal = debt
+		}
+		debt -= principal
+
+		total += principal + interest
+		interestTotal += interest
+		out.Payments = append(out.Payments, domain.SchedulePayment{
+			Month:     m,
+			Payment:   (principal + interest) / 100,
+			Interest:  interest / 100,
+			Principal: principal / 100,
+			Balance:   debt / 100,
+		})
+	}
+	out.TotalPayment = total / 100
+	out.Overpayment = interestTotal / 100
+	return out
+}
+
+func summarize(s domain.PaymentSchedule) domain.ScheduleSummary {
+	out := domain.ScheduleSummary{TotalPayment: s.TotalPayment, Overpayment: s.Overpayment}
+	if n := len(s.Payments); n > 0 {
+		out.FirstPayment = s.Payments[0].Payment
+		out.LastPayment = s.Payments[n-1].Payment
+	}
+	return out
+}
@@ -3,14 +3,16 @@ package product
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"multibank/backend/internal/logger"
+	"multibank/backend/internal/storage"
 	"sync"
 
 	"golang.org/x/sync/errgroup"
 
 	"multibank/backend/internal/domain"
 	"multibank/backend/internal/service/bank"
-	"multibank/backend/internal/service/openbanking"
 
 	"log/slog"
 	"time"
@@ -24,6 +26,10 @@ type BankTokens interface {
 	GetOrRefreshToken(ctx context.Context, bankID int64) (string, time.Time, error)
 }
 
+type OBProductsClient interface {
+	GetProducts(ctx context.Context, bank domain.Bank, bearer, productType string) ([]domain.Product, error)
+}
+
 type RecommendedRepo interface {
 	Snapshot(ctx context.Context) (map[string]struct{}, error)
 	List(ctx context.Context) ([]struct {
@@ -34,12 +40,46 @@ type RecommendedRepo interface {
 	Delete(ctx context.Context, productID, bankCode, productType string) error
 }
 
+// Targeting evaluates recommendation rules for the user
+type Targeting interface {
+	Apply(ctx context.Context, userID int64, items []domain.Product) error
+}
+
+type EventRepo interface {
+	Add(ctx context.Context, events []domain.RecommendationEvent) error
+}
+
+type ProductRepo interface {
+	ReplaceBank(ctx context.Context, bankID int64, items []domain.Product, fetchedAt time.Time) ([]domain.ProductChange, error)
+	MarkFetchFailed(ctx context.Context, bankID int64, at time.Time, reason string) error
+	List(ctx context.Context, f domain.ProductFilter) (domain.ProductPage, error)
+	ListSources(ctx context.Context) ([]domain.ProductSource, error)
+	GetProduct(ctx context.Context, bankCode, productID string) (domain.Product, error)
+
+	History(ctx context.Context, bankID int64, productID string) ([]domain.ProductChange, error)
+	Subscribe(ctx context.Context, userID, bankID int64, productID string) error
+	Unsubscribe(ctx context.Context, userID, bankID int64, productID string) error
+	ListSubscriptions(ctx context.Context, userID int64) ([]domain.ProductSubscription, error)
+	ListSubscribers(ctx context.Context, bankID int64, productID string) ([]int64, error)
+}
+
+var (
+	ErrInvalidFilter = errors.New("invalid product filter")
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+const maxPageLimit = 200
+
 type Service struct {
 	log         *slog.Logger
 	repo        BanksRepo       // to get a list of banks
 	recommended RecommendedRepo // to set IsRecommended
+	products    ProductRepo     // local catalogue
 	tokens      BankTokens      // to get the bank token
-	client      *openbanking.ProductClient
+	notifier    Notifier        // rate changes for subscribers
+	targeting   Targeting       // recommendation rules per user
+	events      EventRepo       // impressions and clicks of recommended products
+	client      OBProductsClient
 }
 
 func New(
@@ -47,85 +87,177 @@ func New(
 	repo BanksRepo,
 	tokens *bank.Service,
 	recommended RecommendedRepo,
-	httpClient *openbanking.ProductClient,
+	products ProductRepo,
+	notifier Notifier,
+	targeting Targeting,
+	events EventRepo,
+	client OBProductsClient,
 ) *Service {
 	return &Service{
 		log:         log,
 		repo:        repo,
 		tokens:      tokens,
 		recommended: recommended,
-		client:      httpClient,
+		products:    products,
+		notifier:    notifier,
+		targeting:   targeting,
+		events:      events,
+		client:      client,
 	}
 }
 
-func (s *Service) List(ctx context.Context, f domain.ProductFilter) ([]domain.Product, error) {
+// List returns a page of products from the local catalogue (refreshed by Refresh) as shown to the user
+// in the catalogue: recommended products of the page are counted as impressions.
+// Products of a bank whose API is down are served from its last successful fetch.
+// If no bank was ever fetched, the catalogue is filled once on demand.
+func (s *Service) List(ctx context.Context, f domain.ProductFilter) (domain.ProductPage, error) {
+	page, err := s.list(ctx, f)
+	if err != nil {
+		return domain.ProductPage{}, err
+	}
+	if f.UserID != 0 {
+		s.trackImpressions(ctx, f.UserID, page.Items)
+	}
+	return page, nil
+}
 
+// list is List without impressions, for comparisons and other internal reads of the catalogue
+func (s *Service) list(ctx context.Context, f domain.ProductFilter) (domain.ProductPage, error) {
 	const op = "service.product.List"
 
 	log := s.log.With(slog.String("op", op))
 
-	// 1) банки
-	banks, err := s.repo.ListEnabledBanks(ctx)
+	if err := validateFilter(&f); err != nil {
+		return domain.ProductPage{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	page, err := s.products.List(ctx, f)
 	if err != nil {
-		log.Warn("failed to list enabled banks")
-		return nil, err
+		if errors.Is(err, storage.ErrInvalidCursor) {
+			return domain.ProductPage{}, fmt.Errorf("%s: %w", op, ErrInvalidCursor)
+		}
+		log.Warn("failed to list stored products", logger.Err(err))
+		return domain.ProductPage{}, err
 	}
 
-	// если фильтр по банкам — отфильтруем
-	if len(f.BankIDs) > 0 {
-		set := make(map[int64]struct{}, len(f.BankIDs))
-		for _, id := range f.BankIDs {
-			set[id] = struct{}{}
+	// first access — fill the catalogue right now
+	if len(page.Items) == 0 && f.Cursor == "" {
+		fetched, err := s.fetchedOnce(ctx)
+		if err != nil {
+			return domain.ProductPage{}, err
 		}
-		tmp := banks[:0]
-		for _, b := range banks {
-			if _, ok := set[b.ID]; ok {
-				tmp = append(tmp, b)
+		if !fetched {
+			if _, err := s.Refresh(ctx, 8); err != nil {
+				log.Warn("on-demand products refresh failed", logger.Err(err))
+			}
+			if page, err = s.products.List(ctx, f); err != nil {
+				return domain.ProductPage{}, err
 			}
 		}
-		banks = tmp
 	}
 
-	// 2) parallel requests to banks
-	var mu sync.Mutex
-	out := make([]domain.Product, 0, len(banks)*8)
+	s.markRecommended(ctx, f.UserID, page.Items)
+	return page, nil
+}
+
+// markRecommended sets IsRecommended from the static list and, for the user, from recommendation rules
+func (s *Service) markRecommended(ctx context.Context, userID int64, items []domain.Product) {
+	log := s.log.With(slog.String("op", "service.product.markRecommended"))
+
+	// set IsRecommended from snapshot
+	set, err := s.recommended.Snapshot(ctx)
+	if err != nil {
+		log.Warn("recommended snapshot failed", logger.Err(err))
+		set = map[string]struct{}{}
+	}
+
+	for i := range items {
+		key := recKey(items[i].ProductID, items[i].BankCode, items[i].ProductType)
+		if _, ok := set[key]; ok {
+			items[i].IsRecommended = true
+		}
+	}
+
+	if userID != 0 {
+		if err := s.targeting.Apply(ctx, userID, items); err != nil {
+			log.Warn("recommendation rules failed", logger.Err(err))
+		}
+	}
+}
+
+// validateFilter checks ranges and the sort, the limit is capped by maxPageLimit
+func validateFilter(f *domain.ProductFilter) error {
+	switch f.Sort {
+	case "":
+		f.Sort = domain.ProductSortBank
+	case domain.ProductSortBank, domain.ProductSortRate, domain.ProductSortTerm:
+	default:
+		return fmt.Errorf("%w: unknown sort %q", ErrInvalidFilter, f.Sort)
+	}
+	if f.MinTerm != nil && f.MaxTerm != nil && *f.MaxTerm < *f.MinTerm {
+		return fmt.Errorf("%w: term_to is less than term_from", ErrInvalidFilter)
+	}
+	if (f.MinRate != nil && *f.MinRate < 0) || (f.Amount != nil && *f.Amount < 0) ||
+		(f.MinTerm != nil && *f.MinTerm < 0) || (f.MaxTerm != nil && *f.MaxTerm < 0) {
+		return fmt.Errorf("%w: negative value", ErrInvalidFilter)
+	}
+	if f.Limit < 0 {
+		return fmt.Errorf("%w: negative limit", ErrInvalidFilter)
+	}
+	if f.Limit > maxPageLimit {
+		f.Limit = maxPageLimit
+	}
+	return nil
+}
+
+// Sources returns the product fetch state of every enabled bank
+func (s *Service) Sources(ctx context.Context) ([]domain.ProductSource, error) {
+	return s.products.ListSources(ctx)
+}
+
+// Refresh pulls the catalogue from every enabled bank into the local store.
+// A failing bank keeps its previously stored products. Returns the number of stored products.
+func (s *Service) Refresh(ctx context.Context, workers int) (int, error) {
+	const op = "service.product.Refresh"
 
-	eg, egCtx := errgroup.WithContext(ctx) // <-- НЕ затеняем исходный ctx
-	eg.SetLimit(8)
+	log := s.log.With(slog.String("op", op))
+
+	if workers <= 0 {
+		workers = 1
+	}
+
+	banks, err := s.repo.ListEnabledBanks(ctx)
+	if err != nil {
+		log.Warn("failed to list enabled banks")
+		return 0, err
+	}
+
+	var (
+		mu    sync.Mutex
+		total int
+	)
+
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(workers)
 
 	for _, b := range banks {
 		b := b
 		eg.Go(func() error {
-			token, _, err := s.tokens.GetOrRefreshToken(egCtx, b.ID)
-			if err != nil {
-				log.Warn("cannot get token for products",
-					slog.Int64("bank_id", b.ID),
-					slog.String("bank", b.Code),
-					logger.Err(err),
-				)
-				return nil // мягкий пропуск
-			}
-
-			items, err := s.client.GetProducts(egCtx, b.APIBaseURL, token, f.ProductType)
+			n, err := s.refreshBank(egCtx, b)
 			if err != nil {
 				log.Warn("products fetch failed",
 					slog.Int64("bank_id", b.ID),
 					slog.String("bank", b.Code),
 					logger.Err(err),
 				)
+				if err := s.products.MarkFetchFailed(egCtx, b.ID, time.Now(), err.Error()); err != nil {
+					log.Warn("failed to record products fetch failure", logger.Err(err))
+				}
 				return nil // мягкий пропуск
 			}
 
-			loc := make([]domain.Product, 0, len(items))
-			for _, it := range items {
-				it.BankID = b.ID
-				it.BankCode = b.Code
-				it.BankName = b.Name
-				loc = append(loc, it)
-			}
-
 			mu.Lock()
-			out = append(out, loc...)
+			total += n
 			mu.Unlock()
 			return nil
 		})
@@ -136,31 +268,52 @@ func (s *Service) List(ctx context.Context, f domain.ProductFilter) ([]domain.Pr
 		log.Warn("product fetch group finished with error", logger.Err(err))
 	}
 
-	// 3) set IsRecommended from snapshot
-	snapCtx := context.Background()
+	return total, nil
+}
 
-	set, err := s.recommended.Snapshot(snapCtx)
+// refreshBank fetches products of one bank and replaces its part of the catalogue
+func (s *Service) refreshBank(ctx context.Context, b domain.Bank) (int, error) {
+	token, _, err := s.tokens.GetOrRefreshToken(ctx, b.ID)
 	if err != nil {
-		log.Warn("recommended snapshot failed", logger.Err(err))
-		set = map[string]struct{}{}
-	} else {
-		log.Info("got recommend map", slog.Int("count", len(set)))
+		return 0, fmt.Errorf("get token: %w", err)
 	}
 
-	for i := range out {
-		key := recKey(out[i].ProductID, out[i].BankCode, out[i].ProductType)
-		if _, ok := set[key]; ok {
-			out[i].IsRecommended = true
-			// debug
-			log.Info("product is in recommended list",
-				slog.String("productId", out[i].ProductID),
-				slog.String("productType", out[i].ProductType),
-				slog.String("bank_code", out[i].BankCode),
-			)
-		}
+	items, err := s.client.GetProducts(ctx, b, token, "")
+	if err != nil {
+		return 0, err
 	}
 
-	return out, nil
+	now := time.Now()
+	for i := range items {
+		items[i].BankID = b.ID
+		items[i].BankCode = b.Code
+		items[i].BankName = b.Name
+		items[i].FetchedAt = now
+	}
+
+	changes, err := s.products.ReplaceBank(ctx, b.ID, items, now)
+	if err != nil {
+		return 0, err
+	}
+	if len(changes) > 0 {
+		s.log.Info("product parameters changed", slog.String("bank", b.Code), slog.Int("changes", len(changes)))
+		s.notifyRateChanges(ctx, changes)
+	}
+	return len(items), nil
+}
+
+// fetchedOnce reports whether any bank catalogue was ever requested
+func (s *Service) fetchedOnce(ctx context.Context) (bool, error) {
+	sources, err := s.products.ListSources(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, src := range sources {
+		if src.LastAttemptAt != nil {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 // key compatible with the repository
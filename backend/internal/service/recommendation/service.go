@@ -0,0 +1,209 @@
+// internal/service/recommendation/service.go
+package recommendation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/logger"
+	"multibank/backend/internal/storage"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidRule  = errors.New("invalid recommendation rule")
+	ErrRuleNotFound = errors.New("recommendation rule not found")
+)
+
+type RuleRepo interface {
+	List(ctx context.Context) ([]domain.RecommendationRule, error)
+	GetByID(ctx context.Context, id int64) (domain.RecommendationRule, error)
+	Create(ctx context.Context, r *domain.RecommendationRule) (int64, error)
+	Update(ctx context.Context, r *domain.RecommendationRule) error
+	Delete(ctx context.Context, id int64) error
+}
+
+type UserRepo interface {
+	GetByID(ctx context.Context, id int64) (domain.User, error)
+}
+
+type ConsentRepo interface {
+	ListByUser(ctx context.Context, userID int64, bankID *int64) ([]domain.AccountConsent, error)
+}
+
+type NetWorth interface {
+	StoredNetWorth(ctx context.Context, userID int64, base string) (domain.NetWorth, error)
+}
+
+type Service struct {
+	log      *slog.Logger
+	rules    RuleRepo
+	users    UserRepo
+	consents ConsentRepo // connected banks
+	netWorth NetWorth    // total of the stored balances
+}
+
+func New(log *slog.Logger, rules RuleRepo, users UserRepo, consents ConsentRepo, netWorth NetWorth) *Service {
+	return &Service{log: log, rules: rules, users: users, consents: consents, netWorth: netWorth}
+}
+
+func (s *Service) ListRules(ctx context.Context) ([]domain.RecommendationRule, error) {
+	return s.rules.List(ctx)
+}
+
+func (s *Service) CreateRule(ctx context.Context, r domain.RecommendationRule) (domain.RecommendationRule, error) {
+	const op = "service.recommendation.CreateRule"
+
+	if err := validateRule(&r); err != nil {
+		return domain.RecommendationRule{}, fmt.Errorf("%s: %w", op, err)
+	}
+	id, err := s.rules.Create(ctx, &r)
+	if err != nil {
+		return domain.RecommendationRule{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return s.getRule(ctx, op, id)
+}
+
+func (s *Service) UpdateRule(ctx context.Context, r domain.RecommendationRule) (domain.RecommendationRule, error) {
+	const op = "service.recommendation.UpdateRule"
+
+	if err := validateRule(&r); err != nil {
+		return domain.RecommendationRule{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := s.rules.Update(ctx, &r); err != nil {
+		if errors.Is(err, storage.ErrRuleNotFound) {
+			return domain.RecommendationRule{}, fmt.Errorf("%s: %w", op, ErrRuleNotFound)
+		}
+		return domain.RecommendationRule{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return s.getRule(ctx, op, r.ID)
+}
+
+func (s *Service) DeleteRule(ctx context.Context, id int64) error {
+	const op = "service.recommendation.DeleteRule"
+
+	if err := s.rules.Delete(ctx, id); err != nil {
+		if errors.Is(err, storage.ErrRuleNotFound) {
+			return fmt.Errorf("%s: %w", op, ErrRuleNotFound)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (s *Service) getRule(ctx context.Context, op string, id int64) (domain.RecommendationRule, error) {
+	r, err := s.rules.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrRuleNotFound) {
+			return domain.RecommendationRule{}, fmt.Errorf("%s: %w", op, ErrRuleNotFound)
+		}
+		return domain.RecommendationRule{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return r, nil
+}
+
+func validateRule(r *domain.RecommendationRule) error {
+	r.Name = strings.TrimSpace(r.Name)
+	r.BankCode = strings.TrimSpace(r.BankCode)
+	r.ProductID = strings.TrimSpace(r.ProductID)
+	r.ProductType = strings.TrimSpace(r.ProductType)
+
+	switch {
+	case r.Name == "":
+		return fmt.Errorf("%w: name is required", ErrInvalidRule)
+	case r.MinAge != nil && r.MaxAge != nil && *r.MaxAge < *r.MinAge:
+		return fmt.Errorf("%w: max_age is less than min_age", ErrInvalidRule)
+	case r.MinBalance != nil && r.MaxBalance != nil && *r.MaxBalance < *r.MinBalance:
+		return fmt.Errorf("%w: max_balance is less than min_balance", ErrInvalidRule)
+	case r.ActiveFrom != nil && r.ActiveTo != nil && !r.ActiveTo.After(*r.ActiveFrom):
+		return fmt.Errorf("%w: active_to must be after active_from", ErrInvalidRule)
+	}
+	switch r.BankConnection {
+	case domain.BankConnectionAny, domain.BankConnectionConnected, domain.BankConnectionNotConnected:
+	default:
+		return fmt.Errorf("%w: bank_connection must be connected or not_connected", ErrInvalidRule)
+	}
+	return nil
+}
+
+// Profile collects facts about the user checked by rules. Facts that cannot be read are left unknown.
+// Only the local store is read: the profile is built on every catalogue request and must not reach the banks.
+func (s *Service) Profile(ctx context.Context, userID int64) (domain.UserProfile, error) {
+	const op = "service.recommendation.Profile"
+
+	log := s.log.With(slog.String("op", op), slog.Int64("user_id", userID))
+
+	p := domain.UserProfile{UserID: userID, ConnectedBanks: map[string]struct{}{}}
+
+	u, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return domain.UserProfile{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if age, ok := domain.AgeAt(u.BirthDate, time.Now()); ok {
+		p.Age = &age
+	}
+
+	consents, err := s.consents.ListByUser(ctx, userID, nil)
+	if err != nil {
+		return domain.UserProfile{}, fmt.Errorf("%s: %w", op, err)
+	}
+	for _, c := range consents {
+		if c.Status == domain.Authorised {
+			p.ConnectedBanks[strings.ToLower(c.BankCode)] = struct{}{}
+		}
+	}
+
+	if nw, err := s.netWorth.StoredNetWorth(ctx, userID, domain.BaseCurrency); err != nil {
+		log.Warn("failed to get total balance", logger.Err(err))
+	} else {
+		p.TotalBalance = &nw.Total
+	}
+	return p, nil
+}
+
+// Apply marks products recommended to the user by active rules. The matched rule with the highest
+// priority gives its reason and priority to the product.
+func (s *Service) Apply(ctx context.Context, userID int64, items []domain.Product) error {
+	const op = "service.recommendation.Apply"
+
+	rules, err := s.rules.List(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	now := time.Now()
+	active := rules[:0]
+	for _, r := range rules {
+		if r.Active(now) {
+			active = append(active, r)
+		}
+	}
+	if len(active) == 0 {
+		return nil
+	}
+
+	profile, err := s.Profile(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for i := range items {
+		// rules are sorted by priority: the first match is the best one
+		for _, r := range active {
+			if !r.Matches(profile, items[i]) {
+				continue
+			}
+			items[i].IsRecommended = true
+			items[i].RecommendationPriority = r.Priority
+			items[i].RecommendationRuleID = &r.ID
+			items[i].RecommendationReason = r.Reason
+			if items[i].RecommendationReason == "" {
+				items[i].RecommendationReason = r.Name
+			}
+			break
+		}
+	}
+	return nil
+}
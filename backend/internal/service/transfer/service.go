@@ -0,0 +1,339 @@
+// internal/service/transfer/service.go
+package transfer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/logger"
+	"multibank/backend/internal/service/payment"
+	"multibank/backend/internal/storage"
+	"strings"
+	"time"
+)
+
+type Repo interface {
+	Create(ctx context.Context, t *domain.Transfer) (int64, error)
+	Update(ctx context.Context, t domain.Transfer) error
+	ClaimInitiation(ctx context.Context, id int64) (bool, error)
+	GetByID(ctx context.Context, id int64) (domain.Transfer, error)
+	ListByUser(ctx context.Context, userID int64) ([]domain.Transfer, error)
+	ListInFlight(ctx context.Context, limit int) ([]domain.Transfer, error)
+}
+
+type AccountService interface {
+	ListUserAccounts(ctx context.Context, userID int64, bankID *int64) ([]domain.AccountShort, error)
+}
+
+type PaymentService interface {
+	CreateConsent(ctx context.Context, in payment.CreateConsentInput) (domain.PaymentConsent, error)
+	GetConsent(ctx context.Context, userID, id int64) (domain.PaymentConsent, error)
+	InitiatePayment(ctx context.Context, in payment.InitiateInput) (domain.Payment, error)
+	GetPayment(ctx context.Context, userID, id int64) (domain.Payment, error)
+	PaymentByConsent(ctx context.Context, userID, paymentConsentID int64) (domain.Payment, error)
+}
+
+var (
+	ErrTransferNotFound      = errors.New("transfer not found")
+	ErrSourceAccountNotFound = errors.New("source account not found")
+	ErrTargetAccountNotFound = errors.New("target account not found")
+	ErrSameAccount           = errors.New("source and target accounts are the same")
+	ErrInvalidAmount         = errors.New("invalid amount")
+	ErrCurrencyMismatch      = errors.New("source and target accounts have different currencies")
+	ErrInsufficientFunds     = errors.New("insufficient funds on the source account")
+)
+
+type Service struct {
+	log      *slog.Logger
+	repo     Repo
+	accounts AccountService
+	payments PaymentService
+}
+
+func New(log *slog.Logger, repo Repo, accounts AccountService, payments PaymentService) *Service {
+	return &Service{log: log, repo: repo, accounts: accounts, payments: payments}
+}
+
+// AccountRef points to one of the user's accounts (as returned by ListUserAccounts)
+type AccountRef struct {
+	BankID    *int64 // optional, if account ids are not unique across banks
+	AccountID string
+}
+
+type CreateInput struct {
+	UserID int64
+	Source AccountRef
+	Target AccountRef
+	Amount string
+}
+
+// Create validates the transfer and starts it: requests a payment consent at the source bank and,
+// if it is approved right away, initiates the payment. Further steps are made by Get and ProcessInFlight.
+// If the bank refuses, the transfer is saved as Failed and returned together with the error;
+// if the bank does not answer the payment, the transfer stays Initiating.
+func (s *Service) Create(ctx context.Context, in CreateInput) (domain.Transfer, error) {
+	const op = "service.transfer.Create"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", in.UserID),
+	)
+
+	amount, err := domain.ParseKopecks(in.Amount)
+	if err != nil || amount <= 0 {
+		return domain.Transfer{}, fmt.Errorf("%s: %w", op, ErrInvalidAmount)
+	}
+
+	accs, err := s.accounts.ListUserAccounts(ctx, in.UserID, nil)
+	if err != nil {
+		return domain.Transfer{}, fmt.Errorf("%s: %w", op, err)
+	}
+	src, ok := findAccount(accs, in.Source)
+	if !ok {
+		return domain.Transfer{}, fmt.Errorf("%s: %w", op, ErrSourceAccountNotFound)
+	}
+	dst, ok := findAccount(accs, in.Target)
+	if !ok {
+		return domain.Transfer{}, fmt.Errorf("%s: %w", op, ErrTargetAccountNotFound)
+	}
+	if src.BankID == dst.BankID && src.AccountID == dst.AccountID {
+		return domain.Transfer{}, fmt.Errorf("%s: %w", op, ErrSameAccount)
+	}
+	if src.Currency == "" || src.Currency != dst.Currency {
+		return domain.Transfer{}, fmt.Errorf("%s: %w", op, ErrCurrencyMismatch)
+	}
+	if balance, err := domain.ParseKopecks(src.Amount); err != nil || balance < amount {
+		return domain.Transfer{}, fmt.Errorf("%s: %w", op, ErrInsufficientFunds)
+	}
+
+	t := domain.Transfer{
+		UserID:          in.UserID,
+		SourceBankID:    src.BankID,
+		SourceAccountID: src.AccountID,
+		TargetBankID:    dst.BankID,
+		TargetAccountID: dst.AccountID,
+		Amount:          domain.FormatKopecks(amount),
+		Currency:        src.Currency,
+		Status:          domain.TransferAwaitingAuthorization,
+	}
+	if t.ID, err = s.repo.Create(ctx, &t); err != nil {
+		return domain.Transfer{}, fmt.Errorf("%s: %w", op, err)
+	}
+	log = log.With(slog.Int64("transfer_id", t.ID))
+
+	// 1) payment consent at the source bank
+	creditorBank := ""
+	if dst.BankID != src.BankID {
+		creditorBank = dst.BankCode
+	}
+	srcBankID := src.BankID
+	c, err := s.payments.CreateConsent(ctx, payment.CreateConsentInput{
+		UserID:           in.UserID,
+		BankID:           &srcBankID,
+		AccountID:        src.AccountID,
+		Amount:           t.Amount,
+		Currency:         t.Currency,
+		CreditorAccount:  dst.Number(),
+		CreditorName:     dst.Nickname,
+		CreditorBankCode: creditorBank,
+		Reference:        fmt.Sprintf("Multibank transfer #%d", t.ID),
+	})
+	if err != nil {
+		log.Warn("payment consent for transfer failed", logger.Err(err))
+		return s.fail(ctx, t, err)
+	}
+	t.PaymentConsentID = &c.ID
+
+	// 2) payment, if the consent is already approved
+	if t, err = s.advanceConsent(ctx, t, c); err != nil {
+		if t.Status == domain.TransferFailed {
+			return t, fmt.Errorf("%s: %w", op, err)
+		}
+		// the outcome is unknown, ProcessInFlight repeats the step
+		log.Warn("payment initiation postponed", logger.Err(err))
+	}
+	return s.get(ctx, t.ID)
+}
+
+// Get returns the user's transfer, moving it forward if it is not finished (polls the bank)
+func (s *Service) Get(ctx context.Context, userID, id int64) (domain.Transfer, error) {
+	const op = "service.transfer.Get"
+
+	t, err := s.get(ctx, id)
+	if err != nil {
+		return domain.Transfer{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if t.UserID != userID {
+		return domain.Transfer{}, fmt.Errorf("%s: %w", op, ErrTransferNotFound)
+	}
+	if t.Status.IsFinal() {
+		return t, nil
+	}
+
+	if err := s.advance(ctx, t); err != nil {
+		// the bank is not reachable now — show the last known state
+		s.log.Warn("transfer advance failed", slog.Int64("transfer_id", t.ID), logger.Err(err))
+	}
+	return s.get(ctx, id)
+}
+
+// List returns transfers of the user
+func (s *Service) List(ctx context.Context, userID int64) ([]domain.Transfer, error) {
+	return s.repo.ListByUser(ctx, userID)
+}
+
+// ProcessInFlight moves forward unfinished transfers (consent approvals, payment statuses).
+// Returns the number of transfers that reached a final status.
+func (s *Service) ProcessInFlight(ctx context.Context, batchLimit int) (int, error) {
+	if batchLimit <= 0 {
+		batchLimit = 50
+	}
+
+	items, err := s.repo.ListInFlight(ctx, batchLimit)
+	if err != nil {
+		return 0, err
+	}
+
+	finished := 0
+	for _, t := range items {
+		if err := ctx.Err(); err != nil {
+			return finished, err
+		}
+		if err := s.advance(ctx, t); err != nil {
+			s.log.Warn("transfer advance failed", slog.Int64("transfer_id", t.ID), logger.Err(err))
+			continue
+		}
+		if v, err := s.get(ctx, t.ID); err == nil && v.Status.IsFinal() {
+			finished++
+		}
+	}
+	return finished, nil
+}
+
+// advance makes the next step of the transfer lifecycle
+func (s *Service) advance(ctx context.Context, t domain.Transfer) error {
+	switch {
+	case t.Status == domain.TransferAwaitingAuthorization && t.PaymentConsentID != nil:
+		c, err := s.payments.GetConsent(ctx, t.UserID, *t.PaymentConsentID)
+		if err != nil {
+			return err
+		}
+		_, err = s.advanceConsent(ctx, t, c)
+		return err
+
+	case t.Status == domain.TransferInitiating && t.PaymentConsentID != nil:
+		_, err := s.initiate(ctx, t)
+		return err
+
+	case t.Status == domain.TransferProcessing && t.PaymentID != nil:
+		p, err := s.payments.GetPayment(ctx, t.UserID, *t.PaymentID)
+		if err != nil {
+			return err
+		}
+		_, err = s.applyPayment(ctx, t, p)
+		return err
+	}
+	return nil
+}
+
+// advanceConsent initiates the payment if the consent is approved and fails the transfer if it is rejected
+func (s *Service) advanceConsent(ctx context.Context, t domain.Transfer, c domain.PaymentConsent) (domain.Transfer, error) {
+	switch c.Status {
+	case domain.Authorised:
+		// Get and ProcessInFlight may see the approval at the same time: only the one that moves
+		// the transfer to Initiating goes on
+		claimed, err := s.repo.ClaimInitiation(ctx, t.ID)
+		if err != nil {
+			return t, err
+		}
+		if !claimed {
+			return s.get(ctx, t.ID)
+		}
+		t.Status = domain.TransferInitiating
+		return s.initiate(ctx, t)
+
+	case domain.Rejected, domain.Revoked:
+		return s.fail(ctx, t, fmt.Errorf("payment consent %s", strings.ToLower(string(c.Status))))
+	}
+
+	// still awaiting authorization in the bank
+	t.Status = domain.TransferAwaitingAuthorization
+	return t, s.repo.Update(ctx, t)
+}
+
+// initiate makes the payment of an Initiating transfer. Only a definite refusal fails the transfer:
+// when the outcome is unknown (network, timeout, open circuit, 5xx) the transfer stays Initiating
+// and the next pass repeats the call, the payment service keeps it to one payment per consent.
+func (s *Service) initiate(ctx context.Context, t domain.Transfer) (domain.Transfer, error) {
+	p, err := s.payments.InitiatePayment(ctx, payment.InitiateInput{
+		UserID:           t.UserID,
+		PaymentConsentID: *t.PaymentConsentID,
+	})
+	switch {
+	case err == nil:
+	case errors.Is(err, payment.ErrConsentAlreadyUsed):
+		// made by an earlier pass that did not get to save the transfer
+		if p, err = s.payments.PaymentByConsent(ctx, t.UserID, *t.PaymentConsentID); err != nil {
+			return t, err
+		}
+	case errors.Is(err, payment.ErrPaymentRejected), errors.Is(err, payment.ErrConsentNotAuthorized):
+		return s.fail(ctx, t, err)
+	default:
+		return t, err
+	}
+	t.PaymentID = &p.ID
+	return s.applyPayment(ctx, t, p)
+}
+
+// applyPayment maps the payment status to the transfer status. Completed is decided by the source bank alone:
+// its final payment status means the money left the account, the target account is not checked
+// (an interbank credit may show up there a bit later).
+func (s *Service) applyPayment(ctx context.Context, t domain.Transfer, p domain.Payment) (domain.Transfer, error) {
+	switch {
+	case p.Status == domain.PaymentRejected:
+		return s.fail(ctx, t, errors.New("payment rejected by the bank"))
+	case p.Status.IsFinal():
+		now := time.Now()
+		if p.StatusUpdateDateTime != nil {
+			now = *p.StatusUpdateDateTime
+		}
+		t.Status = domain.TransferCompleted
+		t.CompletedAt = &now
+		s.log.Info("transfer completed", slog.Int64("transfer_id", t.ID))
+	default:
+		t.Status = domain.TransferProcessing
+	}
+	return t, s.repo.Update(ctx, t)
+}
+
+// fail saves the transfer as Failed and returns the cause
+func (s *Service) fail(ctx context.Context, t domain.Transfer, cause error) (domain.Transfer, error) {
+	t.Status = domain.TransferFailed
+	t.Error = cause.Error()
+	if err := s.repo.Update(ctx, t); err != nil {
+		return t, errors.Join(cause, err)
+	}
+	return t, cause
+}
+
+func (s *Service) get(ctx context.Context, id int64) (domain.Transfer, error) {
+	t, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrTransferNotFound) {
+			return domain.Transfer{}, ErrTransferNotFound
+		}
+		return domain.Transfer{}, err
+	}
+	return t, nil
+}
+
+func findAccount(accs []domain.AccountShort, ref AccountRef) (domain.AccountShort, bool) {
+	for _, a := range accs {
+		if a.AccountID == ref.AccountID && (ref.BankID == nil || *ref.BankID == a.BankID) {
+			return a, true
+		}
+	}
+	return domain.AccountShort{}, false
+}
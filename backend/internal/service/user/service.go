@@ -21,6 +21,7 @@ type Repository interface {
 	Create(ctx context.Context, u domain.User) (int64, error)
 	GetByID(ctx context.Context, id int64) (domain.User, error)
 	GetByEmail(ctx context.Context, email string) (domain.User, error)
+	SetAdmin(ctx context.Context, email string, admin bool) error
 }
 
 var (
@@ -112,3 +113,27 @@ func (s *Service) GetByEmail(ctx context.Context, email string) (domain.User, er
 	return u, nil
 
 }
+
+// SetAdmin grants or revokes admin rights of the user with the email
+// Returns ErrUserNotFound if there is no such User
+func (s *Service) SetAdmin(ctx context.Context, email string, admin bool) error {
+	const op = "service.user.SetAdmin"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("email", email),
+		slog.Bool("admin", admin),
+	)
+
+	if err := s.repo.SetAdmin(ctx, email, admin); err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("user not found", logger.Err(err))
+			return fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+		log.Error("failed to set admin rights", logger.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("admin rights changed")
+	return nil
+}
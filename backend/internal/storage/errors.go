@@ -10,4 +10,23 @@ var (
 	ErrUserExists    = errors.New("user already exists")
 	ErrUserNotFound  = errors.New("user not found")
 	ErrBanksNotFound = errors.New("banks not found")
+	ErrBankNotFound  = errors.New("bank not found")
+	ErrBankExists    = errors.New("bank already exists")
+	ErrRateNotFound  = errors.New("fx rate not found")
+
+	ErrConsentNotFound = errors.New("consent not found")
+
+	ErrPaymentConsentNotFound = errors.New("payment consent not found")
+	ErrPaymentNotFound        = errors.New("payment not found")
+	ErrPaymentExists          = errors.New("payment for the consent already exists")
+	ErrTransferNotFound       = errors.New("transfer not found")
+
+	ErrAgreementConsentNotFound = errors.New("product agreement consent not found")
+	ErrAgreementNotFound        = errors.New("product agreement not found")
+
+	ErrProductNotFound      = errors.New("product not found")
+	ErrInvalidCursor        = errors.New("invalid cursor")
+	ErrNotificationNotFound = errors.New("notification not found")
+
+	ErrRuleNotFound = errors.New("recommendation rule not found")
 )
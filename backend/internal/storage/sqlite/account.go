@@ -0,0 +1,210 @@
+// internal/storage/sqlite/account.go
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"multibank/backend/internal/domain"
+	sqliteutils "multibank/backend/internal/storage/sqlite/utils"
+	"strings"
+	"time"
+)
+
+type AccountRepo struct {
+	db *sql.DB
+}
+
+func NewAccountRepo(db *sql.DB) *AccountRepo { return &AccountRepo{db: db} }
+
+// Upsert saves account details (without balances) and returns bank_accounts.id
+func (r *AccountRepo) Upsert(ctx context.Context, a domain.BankAccount) (int64, error) {
+	const op = "storage.sqlite.account.Upsert"
+
+	var id int64
+	err := r.db.QueryRowContext(ctx, `
+INSERT INTO bank_accounts
+(user_id, bank_id, consent_id, account_id, client_id, status, currency,
+ account_type, account_sub_type, nickname, opening_date, identification)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(user_id, bank_id, account_id) DO UPDATE SET
+    consent_id       = excluded.consent_id,
+    client_id        = excluded.client_id,
+    status           = excluded.status,
+    currency         = excluded.currency,
+    account_type     = excluded.account_type,
+    account_sub_type = excluded.account_sub_type,
+    nickname         = excluded.nickname,
+    opening_date     = excluded.opening_date,
+    identification   = excluded.identification,
+    updated_at       = datetime('now')
+RETURNING id`,
+		a.UserID, a.BankID, a.ConsentID, a.AccountID, a.ClientID, a.Status, a.Currency,
+		a.AccountType, a.AccountSubType, a.Nickname, a.OpeningDate, a.Identification,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return id, nil
+}
+
+// ReplaceBalances replaces all balances of the account and marks it as synced at the given time
+func (r *AccountRepo) ReplaceBalances(ctx context.Context, bankAccountID int64, items []domain.Balance, syncedAt time.Time) error {
+	const op = "storage.sqlite.account.ReplaceBalances"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM balances WHERE bank_account_id = ?`, bankAccountID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, b := range items {
+		lines := b.CreditLines
+		if lines == nil {
+			lines = []domain.CreditLine{}
+		}
+		linesJSON, _ := json.Marshal(lines)
+
+		if _, err := tx.ExecContext(ctx, `
+INSERT INTO balances (bank_account_id, type, amount, currency, credit_debit_indicator, date_time, credit_lines_json)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(bank_account_id, type) DO UPDATE SET
+    amount                 = excluded.amount,
+    currency               = excluded.currency,
+    credit_debit_indicator = excluded.credit_debit_indicator,
+    date_time              = excluded.date_time,
+    credit_lines_json      = excluded.credit_lines_json,
+    updated_at             = datetime('now')`,
+			bankAccountID, b.Type, b.Amount, b.Currency, b.CreditDebitIndicator, formatTSPtr(b.DateTime), string(linesJSON),
+		); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+UPDATE bank_accounts SET last_synced_at = ?, updated_at = datetime('now') WHERE id = ?`,
+		syncedAt.UTC().Format(sqliteutils.TsLayout), bankAccountID,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// DeleteMissing removes accounts of the consent that the bank does not return anymore
+func (r *AccountRepo) DeleteMissing(ctx context.Context, consentID int64, keep []string) (int64, error) {
+	const op = "storage.sqlite.account.DeleteMissing"
+
+	q := `DELETE FROM bank_accounts WHERE consent_id = ?`
+	args := []any{consentID}
+	if len(keep) > 0 {
+		q += ` AND account_id NOT IN (?` + strings.Repeat(",?", len(keep)-1) + `)`
+		for _, id := range keep {
+			args = append(args, id)
+		}
+	}
+
+	res, err := r.db.ExecContext(ctx, q, args...)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return res.RowsAffected()
+}
+
+// ListByUser returns stored accounts of the user with all their balances.
+// Only accounts of authorized consents are returned. If bankID != nil filter by 1 bank.
+func (r *AccountRepo) ListByUser(ctx context.Context, userID int64, bankID *int64) ([]domain.AccountShort, error) {
+	const op = "storage.sqlite.account.ListByUser"
+
+	q := `
+SELECT a.id, a.account_id, a.nickname, a.status, a.account_sub_type, a.opening_date, a.identification, a.currency,
+       a.bank_id, b.code, a.client_id, a.last_synced_at
+FROM bank_accounts a
+JOIN banks b ON b.id = a.bank_id
+JOIN account_consents c ON c.id = a.consent_id
+WHERE a.user_id = ? AND c.status = 'Authorized'`
+	args := []any{userID}
+	if bankID != nil {
+		q += ` AND a.bank_id = ?`
+		args = append(args, *bankID)
+	}
+	q += ` ORDER BY b.name, a.account_id`
+
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.AccountShort, 0, 16)
+	idx := make(map[int64]int, 16) // bank_accounts.id -> index in out
+	for rows.Next() {
+		var (
+			id     int64
+			a      domain.AccountShort
+			synced *string
+		)
+		if err := rows.Scan(
+			&id, &a.AccountID, &a.Nickname, &a.Status, &a.AccountSubType, &a.OpeningDate, &a.Identification, &a.Currency,
+			&a.BankID, &a.BankCode, &a.ClientID, &synced,
+		); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		a.LastSyncedAt = parseTSPtr(synced)
+		a.Balances = []domain.Balance{}
+		idx[id] = len(out)
+		out = append(out, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(out) == 0 {
+		return out, nil
+	}
+
+	// balances of these accounts
+	brows, err := r.db.QueryContext(ctx, `
+SELECT bl.bank_account_id, bl.type, bl.amount, bl.currency, bl.credit_debit_indicator, bl.date_time, bl.credit_lines_json
+FROM balances bl
+JOIN bank_accounts a ON a.id = bl.bank_account_id
+WHERE a.user_id = ?
+ORDER BY bl.id`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer brows.Close()
+
+	for brows.Next() {
+		var (
+			accID     int64
+			b         domain.Balance
+			dt        *string
+			linesJSON string
+		)
+		if err := brows.Scan(&accID, &b.Type, &b.Amount, &b.Currency, &b.CreditDebitIndicator, &dt, &linesJSON); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		i, ok := idx[accID]
+		if !ok {
+			continue // filtered out by bank or consent status
+		}
+		b.DateTime = parseTSPtr(dt)
+		if linesJSON != "" {
+			_ = json.Unmarshal([]byte(linesJSON), &b.CreditLines)
+		}
+		out[i].Balances = append(out[i].Balances, b)
+	}
+	if err := brows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return out, nil
+}
@@ -0,0 +1,300 @@
+// internal/storage/sqlite/agreement.go
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/storage"
+	sqliteutils "multibank/backend/internal/storage/sqlite/utils"
+	"time"
+)
+
+type AgreementRepo struct {
+	db *sql.DB
+}
+
+func NewAgreementRepo(db *sql.DB) *AgreementRepo { return &AgreementRepo{db: db} }
+
+const agreementConsentCols = `
+c.id, c.user_id, c.bank_id, b.code, c.request_id, c.consent_id, c.status, c.auto_approved,
+c.client_id, c.requesting_bank,
+c.creation_datetime, c.status_update_datetime, c.expiration_datetime,
+c.created_at, c.updated_at
+`
+
+func scanAgreementConsent(rs rowScanner) (domain.ProductAgreementConsent, error) {
+	var (
+		c                               domain.ProductAgreementConsent
+		autoApproved                    *int64
+		creation, statusUpd, expiration *string
+		createdAt, updatedAt            string
+	)
+	if err := rs.Scan(
+		&c.ID, &c.UserID, &c.BankID, &c.BankCode, &c.RequestID, &c.ConsentID, &c.Status, &autoApproved,
+		&c.ClientID, &c.RequestingBank,
+		&creation, &statusUpd, &expiration,
+		&createdAt, &updatedAt,
+	); err != nil {
+		return domain.ProductAgreementConsent{}, err
+	}
+	if autoApproved != nil {
+		v := *autoApproved != 0
+		c.AutoApproved = &v
+	}
+	c.CreationDateTime = sqliteutils.FromISO(creation)
+	c.StatusUpdateDateTime = sqliteutils.FromISO(statusUpd)
+	c.ExpirationDateTime = sqliteutils.FromISO(expiration)
+	c.CreatedAt, _ = sqliteutils.ParseTS(createdAt)
+	c.UpdatedAt, _ = sqliteutils.ParseTS(updatedAt)
+	return c, nil
+}
+
+// CreateConsent saves a new product agreement consent and returns its id
+func (r *AgreementRepo) CreateConsent(ctx context.Context, c *domain.ProductAgreementConsent) (int64, error) {
+	const op = "storage.sqlite.agreement.CreateConsent"
+
+	res, err := r.db.ExecContext(ctx, `
+INSERT INTO product_agreement_consents
+(user_id, bank_id, request_id, consent_id, status, auto_approved, client_id, requesting_bank,
+ creation_datetime, status_update_datetime, expiration_datetime)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		c.UserID, c.BankID, c.RequestID, c.ConsentID, string(c.Status), boolToIntPtr(c.AutoApproved),
+		c.ClientID, c.RequestingBank,
+		sqliteutils.ToISO(c.CreationDateTime), sqliteutils.ToISO(c.StatusUpdateDateTime), sqliteutils.ToISO(c.ExpirationDateTime),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return res.LastInsertId()
+}
+
+// UpdateConsentAfterCheck saves the status received from the bank (nil fields are kept)
+func (r *AgreementRepo) UpdateConsentAfterCheck(ctx context.Context, id int64, upd *domain.ProductAgreementConsent) error {
+	const op = "storage.sqlite.agreement.UpdateConsentAfterCheck"
+
+	_, err := r.db.ExecContext(ctx, `
+UPDATE product_agreement_consents
+SET consent_id             = COALESCE(?, consent_id),
+    status                 = ?,
+    creation_datetime      = COALESCE(?, creation_datetime),
+    status_update_datetime = COALESCE(?, status_update_datetime),
+    expiration_datetime    = COALESCE(?, expiration_datetime),
+    updated_at             = datetime('now')
+WHERE id = ?`,
+		upd.ConsentID, string(upd.Status),
+		sqliteutils.ToISO(upd.CreationDateTime), sqliteutils.ToISO(upd.StatusUpdateDateTime), sqliteutils.ToISO(upd.ExpirationDateTime),
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// GetConsentByID returns the product agreement consent or storage.ErrAgreementConsentNotFound
+func (r *AgreementRepo) GetConsentByID(ctx context.Context, id int64) (domain.ProductAgreementConsent, error) {
+	const op = "storage.sqlite.agreement.GetConsentByID"
+
+	row := r.db.QueryRowContext(ctx, `SELECT `+agreementConsentCols+`
+FROM product_agreement_consents c
+JOIN banks b ON b.id = c.bank_id
+WHERE c.id = ?`, id)
+	c, err := scanAgreementConsent(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ProductAgreementConsent{}, fmt.Errorf("%s: %w", op, storage.ErrAgreementConsentNotFound)
+		}
+		return domain.ProductAgreementConsent{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return c, nil
+}
+
+// LatestConsent returns the newest product agreement consent of the user in the bank that can still be used
+// (Authorized or AwaitingAuthorization) or storage.ErrAgreementConsentNotFound
+func (r *AgreementRepo) LatestConsent(ctx context.Context, userID, bankID int64) (domain.ProductAgreementConsent, error) {
+	const op = "storage.sqlite.agreement.LatestConsent"
+
+	row := r.db.QueryRowContext(ctx, `SELECT `+agreementConsentCols+`
+FROM product_agreement_consents c
+JOIN banks b ON b.id = c.bank_id
+WHERE c.user_id = ? AND c.bank_id = ? AND c.status IN ('Authorized', 'AwaitingAuthorization')
+ORDER BY c.id DESC
+LIMIT 1`, userID, bankID)
+	c, err := scanAgreementConsent(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ProductAgreementConsent{}, fmt.Errorf("%s: %w", op, storage.ErrAgreementConsentNotFound)
+		}
+		return domain.ProductAgreementConsent{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return c, nil
+}
+
+// ListAuthorizedConsents returns authorized product agreement consents of the user (newest first).
+// If bankID != nil filter by 1 bank.
+func (r *AgreementRepo) ListAuthorizedConsents(ctx context.Context, userID int64, bankID *int64) ([]domain.ProductAgreementConsent, error) {
+	const op = "storage.sqlite.agreement.ListAuthorizedConsents"
+
+	q := `SELECT ` + agreementConsentCols + `
+FROM product_agreement_consents c
+JOIN banks b ON b.id = c.bank_id
+WHERE c.user_id = ? AND c.status = 'Authorized' AND c.consent_id IS NOT NULL AND c.consent_id <> ''`
+	args := []any{userID}
+	if bankID != nil {
+		q += ` AND c.bank_id = ?`
+		args = append(args, *bankID)
+	}
+	q += ` ORDER BY c.id DESC`
+
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.ProductAgreementConsent, 0, 4)
+	for rows.Next() {
+		c, err := scanAgreementConsent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		out = append(out, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return out, nil
+}
+
+const agreementCols = `
+a.id, a.user_id, a.bank_id, b.code, a.consent_id, a.agreement_id, a.product_id, a.product_type, a.product_name,
+a.amount, a.term_months, a.source_account_id, a.account_number,
+a.status, a.start_date, a.end_date, a.closed_at, a.created_at, a.updated_at
+`
+
+func scanAgreement(rs rowScanner) (domain.ProductAgreement, error) {
+	var (
+		a                    domain.ProductAgreement
+		start, end, closed   *string
+		createdAt, updatedAt string
+	)
+	if err := rs.Scan(
+		&a.ID, &a.UserID, &a.BankID, &a.BankCode, &a.ConsentID, &a.AgreementID, &a.ProductID, &a.ProductType, &a.ProductName,
+		&a.Amount, &a.TermMonths, &a.SourceAccountID, &a.AccountNumber,
+		&a.Status, &start, &end, &closed, &createdAt, &updatedAt,
+	); err != nil {
+		return domain.ProductAgreement{}, err
+	}
+	a.StartDate = sqliteutils.FromISO(start)
+	a.EndDate = sqliteutils.FromISO(end)
+	a.ClosedAt = parseTSPtr(closed)
+	a.CreatedAt, _ = sqliteutils.ParseTS(createdAt)
+	a.UpdatedAt, _ = sqliteutils.ParseTS(updatedAt)
+	return a, nil
+}
+
+// Upsert saves the agreement by (bank_id, agreement_id) and returns product_agreements.id.
+// Data we sent to the bank (amount, term, source account) is kept if the bank does not report it.
+func (r *AgreementRepo) Upsert(ctx context.Context, a *domain.ProductAgreement) (int64, error) {
+	const op = "storage.sqlite.agreement.Upsert"
+
+	var id int64
+	err := r.db.QueryRowContext(ctx, `
+INSERT INTO product_agreements
+(user_id, bank_id, consent_id, agreement_id, product_id, product_type, product_name,
+ amount, term_months, source_account_id, account_number, status, start_date, end_date)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(bank_id, agreement_id) DO UPDATE SET
+    consent_id     = excluded.consent_id,
+    product_type   = CASE WHEN excluded.product_type   <> '' THEN excluded.product_type   ELSE product_type   END,
+    product_name   = CASE WHEN excluded.product_name   <> '' THEN excluded.product_name   ELSE product_name   END,
+    amount         = CASE WHEN excluded.amount         <> '' THEN excluded.amount         ELSE amount         END,
+    term_months    = CASE WHEN excluded.term_months    <> 0  THEN excluded.term_months    ELSE term_months    END,
+    account_number = CASE WHEN excluded.account_number <> '' THEN excluded.account_number ELSE account_number END,
+    status         = excluded.status,
+    start_date     = COALESCE(excluded.start_date, start_date),
+    end_date       = COALESCE(excluded.end_date, end_date),
+    updated_at     = datetime('now')
+RETURNING id`,
+		a.UserID, a.BankID, a.ConsentID, a.AgreementID, a.ProductID, a.ProductType, a.ProductName,
+		a.Amount, a.TermMonths, a.SourceAccountID, a.AccountNumber, string(a.Status),
+		sqliteutils.ToISO(a.StartDate), sqliteutils.ToISO(a.EndDate),
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return id, nil
+}
+
+// GetByID returns the agreement or storage.ErrAgreementNotFound
+func (r *AgreementRepo) GetByID(ctx context.Context, id int64) (domain.ProductAgreement, error) {
+	const op = "storage.sqlite.agreement.GetByID"
+
+	row := r.db.QueryRowContext(ctx, `SELECT `+agreementCols+`
+FROM product_agreements a
+JOIN banks b ON b.id = a.bank_id
+WHERE a.id = ?`, id)
+	a, err := scanAgreement(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ProductAgreement{}, fmt.Errorf("%s: %w", op, storage.ErrAgreementNotFound)
+		}
+		return domain.ProductAgreement{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return a, nil
+}
+
+// ListByUser returns agreements of the user (newest first). If bankID != nil filter by 1 bank.
+func (r *AgreementRepo) ListByUser(ctx context.Context, userID int64, bankID *int64) ([]domain.ProductAgreement, error) {
+	const op = "storage.sqlite.agreement.ListByUser"
+
+	q := `SELECT ` + agreementCols + `
+FROM product_agreements a
+JOIN banks b ON b.id = a.bank_id
+WHERE a.user_id = ?`
+	args := []any{userID}
+	if bankID != nil {
+		q += ` AND a.bank_id = ?`
+		args = append(args, *bankID)
+	}
+	q += ` ORDER BY a.id DESC`
+
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.ProductAgreement, 0, 16)
+	for rows.Next() {
+		a, err := scanAgreement(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		out = append(out, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return out, nil
+}
+
+// MarkClosed keeps the agreement with status closed and the time it was closed
+func (r *AgreementRepo) MarkClosed(ctx context.Context, id int64, at time.Time) error {
+	const op = "storage.sqlite.agreement.MarkClosed"
+
+	_, err := r.db.ExecContext(ctx, `
+UPDATE product_agreements
+SET status     = 'closed',
+    closed_at  = COALESCE(closed_at, ?),
+    updated_at = datetime('now')
+WHERE id = ?`, at.UTC().Format(sqliteutils.TsLayout), id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
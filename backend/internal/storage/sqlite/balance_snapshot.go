@@ -0,0 +1,109 @@
+// internal/storage/sqlite/balance_snapshot.go
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"multibank/backend/internal/domain"
+	sqliteutils "multibank/backend/internal/storage/sqlite/utils"
+	"time"
+)
+
+type BalanceSnapshotRepo struct {
+	db *sql.DB
+}
+
+func NewBalanceSnapshotRepo(db *sql.DB) *BalanceSnapshotRepo { return &BalanceSnapshotRepo{db: db} }
+
+// Record saves the snapshot of the day. A later snapshot of the same day replaces the earlier one.
+func (r *BalanceSnapshotRepo) Record(ctx context.Context, s domain.BalanceSnapshot) error {
+	const op = "storage.sqlite.balance_snapshot.Record"
+
+	_, err := r.db.ExecContext(ctx, `
+INSERT INTO balance_snapshots (user_id, bank_id, account_id, date, amount, currency, balance_type, taken_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(user_id, bank_id, account_id, date) DO UPDATE SET
+    amount       = excluded.amount,
+    currency     = excluded.currency,
+    balance_type = excluded.balance_type,
+    taken_at     = excluded.taken_at`,
+		s.UserID, s.BankID, s.AccountID, s.TakenAt.UTC().Format(dateLayout), s.Amount, s.Currency, s.BalanceType,
+		s.TakenAt.UTC().Format(sqliteutils.TsLayout),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// snapshotColumns — the account is active while it is stored under an authorized consent
+const snapshotColumns = `
+SELECT s.user_id, s.bank_id, b.code, s.account_id, s.date, s.amount, s.currency, s.balance_type, s.taken_at,
+       EXISTS (SELECT 1 FROM bank_accounts a
+               JOIN account_consents c ON c.id = a.consent_id
+               WHERE a.user_id = s.user_id AND a.bank_id = s.bank_id AND a.account_id = s.account_id
+                 AND c.status = 'Authorized')
+FROM balance_snapshots s
+JOIN banks b ON b.id = s.bank_id`
+
+// ListByUser returns snapshots of the user between from and to (dates, inclusive) ordered by date
+func (r *BalanceSnapshotRepo) ListByUser(ctx context.Context, userID int64, from, to time.Time) ([]domain.BalanceSnapshot, error) {
+	const op = "storage.sqlite.balance_snapshot.ListByUser"
+
+	out, err := r.list(ctx, snapshotColumns+`
+WHERE s.user_id = ? AND s.date >= ? AND s.date <= ?
+ORDER BY s.date, s.bank_id, s.account_id`,
+		userID, from.Format(dateLayout), to.Format(dateLayout),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return out, nil
+}
+
+// LastBefore returns the last snapshot of every account of the user taken before the date
+func (r *BalanceSnapshotRepo) LastBefore(ctx context.Context, userID int64, before time.Time) ([]domain.BalanceSnapshot, error) {
+	const op = "storage.sqlite.balance_snapshot.LastBefore"
+
+	out, err := r.list(ctx, snapshotColumns+`
+WHERE s.user_id = ? AND s.date = (
+    SELECT MAX(p.date) FROM balance_snapshots p
+    WHERE p.user_id = s.user_id AND p.bank_id = s.bank_id AND p.account_id = s.account_id AND p.date < ?)
+ORDER BY s.bank_id, s.account_id`,
+		userID, before.Format(dateLayout),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return out, nil
+}
+
+func (r *BalanceSnapshotRepo) list(ctx context.Context, q string, args ...any) ([]domain.BalanceSnapshot, error) {
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]domain.BalanceSnapshot, 0, 64)
+	for rows.Next() {
+		var (
+			s             domain.BalanceSnapshot
+			date, takenAt string
+		)
+		if err := rows.Scan(&s.UserID, &s.BankID, &s.BankCode, &s.AccountID, &date, &s.Amount, &s.Currency,
+			&s.BalanceType, &takenAt, &s.Active); err != nil {
+			return nil, err
+		}
+		if s.Date, err = time.Parse(dateLayout, date); err != nil {
+			return nil, err
+		}
+		if t, err := sqliteutils.ParseTS(takenAt); err == nil {
+			s.TakenAt = t
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
@@ -8,24 +8,68 @@ import (
 	"errors"
 	"fmt"
 	"multibank/backend/internal/domain"
+	"multibank/backend/internal/secret"
 	"multibank/backend/internal/storage"
 	sqliteutils "multibank/backend/internal/storage/sqlite/utils"
+	"strconv"
 	"time"
+
+	"modernc.org/sqlite"
+	sqlitelib "modernc.org/sqlite/lib"
 )
 
+// BankRepo keeps banks.password and bank_tokens.access_token encrypted by the box
 type BankRepo struct {
-	db *sql.DB
+	db  *sql.DB
+	box *secret.Box
+}
+
+func NewBankRepo(db *sql.DB, box *secret.Box) *BankRepo {
+	return &BankRepo{db: db, box: box}
+}
+
+// secretAAD binds a sealed value to its column and row: a value copied to another bank does not open
+func secretAAD(table, column string, id int64) string {
+	return table + "." + column + ":" + strconv.FormatInt(id, 10)
 }
 
-func NewBankRepo(db *sql.DB) *BankRepo {
-	return &BankRepo{db: db}
+func passwordAAD(bankID int64) string { return secretAAD("banks", "password", bankID) }
+func tokenAAD(bankID int64) string    { return secretAAD("bank_tokens", "access_token", bankID) }
+
+const bankColumns = `id, name, code, api_base_url, login, password, is_enabled, rate_limit, rate_burst, adapter, created_at, updated_at`
+
+// scanBank parses DB row (bankColumns) and returns domain.Bank with the decrypted password
+func (s *BankRepo) scanBank(rs rowScanner) (domain.Bank, error) {
+	var b domain.Bank
+	var en int
+	var created, updated string
+
+	if err := rs.Scan(&b.ID, &b.Name, &b.Code, &b.APIBaseURL, &b.Login, &b.Password, &en,
+		&b.RateLimit, &b.RateBurst, &b.Adapter, &created, &updated); err != nil {
+		return domain.Bank{}, err
+	}
+	b.IsEnabled = en == 1
+
+	password, err := s.box.Open(b.Password, passwordAAD(b.ID))
+	if err != nil {
+		return domain.Bank{}, fmt.Errorf("decrypt password of bank %d: %w", b.ID, err)
+	}
+	b.Password = password
+
+	if t, err := sqliteutils.ParseTS(created); err == nil {
+		b.CreatedAt = t
+	}
+	if t, err := sqliteutils.ParseTS(updated); err == nil {
+		b.UpdatedAt = t
+	}
+	return b, nil
 }
 
 func (s *BankRepo) ListEnabledBanks(ctx context.Context) ([]domain.Bank, error) {
 	const op = "storage.sqlite.bank.ListEnabledBanks"
 
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, name, code, api_base_url, login, password, is_enabled, created_at, updated_at
+		SELECT `+bankColumns+`
 		FROM banks
 		WHERE is_enabled = 1
 		ORDER BY name`)
@@ -36,10 +80,7 @@ func (s *BankRepo) ListEnabledBanks(ctx context.Context) ([]domain.Bank, error)
 
 	var out []domain.Bank
 	for rows.Next() {
-		var b domain.Bank
-		var created, updated string
-		var en int
-		err := rows.Scan(&b.ID, &b.Name, &b.Code, &b.APIBaseURL, &b.Login, &b.Password, &en, &created, &updated)
+		b, err := s.scanBank(rows)
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				return []domain.Bank{}, fmt.Errorf("%s : %w", op, storage.ErrBanksNotFound)
@@ -47,70 +88,157 @@ func (s *BankRepo) ListEnabledBanks(ctx context.Context) ([]domain.Bank, error)
 
 			return []domain.Bank{}, fmt.Errorf("%s : %w", op, err)
 		}
+		out = append(out, b)
+	}
+	return out, rows.Err()
+}
 
-		b.IsEnabled = en == 1
+// ListBanks returns all banks, disabled ones too
+func (s *BankRepo) ListBanks(ctx context.Context) ([]domain.Bank, error) {
+	const op = "storage.sqlite.bank.ListBanks"
 
-		if t, err := sqliteutils.ParseTS(created); err == nil {
-			b.CreatedAt = t
-		}
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+bankColumns+`
+		FROM banks
+		ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
 
-		if t, err := sqliteutils.ParseTS(updated); err == nil {
-			b.UpdatedAt = t
+	out := []domain.Bank{}
+	for rows.Next() {
+		b, err := s.scanBank(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
 		}
-
 		out = append(out, b)
 	}
 	return out, rows.Err()
 }
 
 func (s *BankRepo) GetBankByID(ctx context.Context, id int64) (domain.Bank, error) {
-	row := s.db.QueryRowContext(ctx, `
-		SELECT id, name, code, api_base_url, login, password, is_enabled, created_at, updated_at
-		FROM banks WHERE id = ?`, id)
+	const op = "storage.sqlite.bank.GetBankByID"
 
-	var b domain.Bank
-	var en int
-	var created, updated string
+	b, err := s.scanBank(s.db.QueryRowContext(ctx, `
+		SELECT `+bankColumns+`
+		FROM banks WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Bank{}, fmt.Errorf("%s: %w", op, storage.ErrBankNotFound)
+	}
+	return b, err
+}
 
-	if err := row.Scan(&b.ID, &b.Name, &b.Code, &b.APIBaseURL, &b.Login, &b.Password, &en, &created, &updated); err != nil {
-		return domain.Bank{}, err
+func (s *BankRepo) GetBankByCode(ctx context.Context, code string) (domain.Bank, error) {
+	const op = "storage.sqlite.bank.GetBankByCode"
+
+	b, err := s.scanBank(s.db.QueryRowContext(ctx, `
+		SELECT `+bankColumns+`
+		FROM banks WHERE code = ?`, code))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Bank{}, fmt.Errorf("%s: %w", op, storage.ErrBankNotFound)
 	}
-	b.IsEnabled = en == 1
+	return b, err
+}
 
-	if t, err := sqliteutils.ParseTS(created); err == nil {
-		b.CreatedAt = t
+// CreateBank saves the bank, the password is sealed once the row has its id
+func (s *BankRepo) CreateBank(ctx context.Context, b *domain.Bank) (int64, error) {
+	const op = "storage.sqlite.bank.CreateBank"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
 	}
-	if t, err := sqliteutils.ParseTS(updated); err == nil {
-		b.UpdatedAt = t
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.ExecContext(ctx, `
+INSERT INTO banks (name, code, api_base_url, login, password, is_enabled, rate_limit, rate_burst, adapter)
+VALUES (?, ?, ?, ?, '', ?, ?, ?, ?)`,
+		b.Name, b.Code, b.APIBaseURL, b.Login, b.IsEnabled, b.RateLimit, b.RateBurst, b.Adapter,
+	)
+	if err != nil {
+		var se *sqlite.Error
+		if errors.As(err, &se) && se.Code() == sqlitelib.SQLITE_CONSTRAINT_UNIQUE {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrBankExists)
+		}
+		return 0, fmt.Errorf("%s: %w", op, err)
 	}
-	return b, nil
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	password, err := s.box.Seal(b.Password, passwordAAD(id))
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE banks SET password = ? WHERE id = ?`, password, id); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return id, nil
 }
 
-func (s *BankRepo) GetBankByCode(ctx context.Context, code string) (domain.Bank, error) {
-	row := s.db.QueryRowContext(ctx, `
-		SELECT id, name, code, api_base_url, login, password, is_enabled, created_at, updated_at
-		FROM banks WHERE code = ?`, code)
+// UpdateBank changes settings of the bank, the code and credentials are kept
+func (s *BankRepo) UpdateBank(ctx context.Context, b *domain.Bank) error {
+	const op = "storage.sqlite.bank.UpdateBank"
 
-	var b domain.Bank
-	var en int
-	var created, updated string
+	res, err := s.db.ExecContext(ctx, `
+UPDATE banks
+SET name         = ?,
+    api_base_url = ?,
+    rate_limit   = ?,
+    rate_burst   = ?,
+    adapter      = ?,
+    updated_at   = datetime('now')
+WHERE id = ?`,
+		b.Name, b.APIBaseURL, b.RateLimit, b.RateBurst, b.Adapter, b.ID,
+	)
+	return checkBankUpdated(op, res, err)
+}
 
-	if err := row.Scan(&b.ID, &b.Name, &b.Code, &b.APIBaseURL, &b.Login, &b.Password, &en, &created, &updated); err != nil {
-		return domain.Bank{}, err
+func (s *BankRepo) SetBankEnabled(ctx context.Context, id int64, enabled bool) error {
+	const op = "storage.sqlite.bank.SetBankEnabled"
+
+	res, err := s.db.ExecContext(ctx, `
+UPDATE banks SET is_enabled = ?, updated_at = datetime('now') WHERE id = ?`, enabled, id)
+	return checkBankUpdated(op, res, err)
+}
+
+// UpdateBankCredentials replaces login and secret of the team in the bank
+func (s *BankRepo) UpdateBankCredentials(ctx context.Context, id int64, login, password string) error {
+	const op = "storage.sqlite.bank.UpdateBankCredentials"
+
+	sealed, err := s.box.Seal(password, passwordAAD(id))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
 	}
-	b.IsEnabled = en == 1
+	res, err := s.db.ExecContext(ctx, `
+UPDATE banks SET login = ?, password = ?, updated_at = datetime('now') WHERE id = ?`, login, sealed, id)
+	return checkBankUpdated(op, res, err)
+}
 
-	if t, err := sqliteutils.ParseTS(created); err == nil {
-		b.CreatedAt = t
+func checkBankUpdated(op string, res sql.Result, err error) error {
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
 	}
-	if t, err := sqliteutils.ParseTS(updated); err == nil {
-		b.UpdatedAt = t
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrBankNotFound)
 	}
-	return b, nil
+	return nil
 }
 
 func (s *BankRepo) UpsertBankToken(ctx context.Context, t domain.BankToken) error {
-	_, err := s.db.ExecContext(ctx, `
+	const op = "storage.sqlite.bank.UpsertBankToken"
+
+	token, err := s.box.Seal(t.AccessToken, tokenAAD(t.BankID))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	_, err = s.db.ExecContext(ctx, `
 		INSERT INTO bank_tokens(bank_id, access_token, expires_at, updated_at)
 		VALUES(?,?,?,?)
 		ON CONFLICT(bank_id) DO UPDATE SET
@@ -118,7 +246,7 @@ func (s *BankRepo) UpsertBankToken(ctx context.Context, t domain.BankToken) erro
 		  expires_at   = excluded.expires_at,
 		  updated_at   = excluded.updated_at`,
 		t.BankID,
-		t.AccessToken,
+		token,
 		t.ExpiresAt.UTC().Format(sqliteutils.TsLayout),
 		time.Now().UTC().Format(sqliteutils.TsLayout),
 	)
@@ -136,6 +264,11 @@ func (s *BankRepo) GetBankToken(ctx context.Context, bankID int64) (domain.BankT
 	if err := row.Scan(&t.BankID, &t.AccessToken, &expires, &created, &updated); err != nil {
 		return domain.BankToken{}, err
 	}
+	token, err := s.box.Open(t.AccessToken, tokenAAD(bankID))
+	if err != nil {
+		return domain.BankToken{}, fmt.Errorf("decrypt token of bank %d: %w", bankID, err)
+	}
+	t.AccessToken = token
 	if v, err := sqliteutils.ParseTS(expires); err == nil {
 		t.ExpiresAt = v
 	}
@@ -147,3 +280,80 @@ func (s *BankRepo) GetBankToken(ctx context.Context, bankID int64) (domain.BankT
 	}
 	return t, nil
 }
+
+// DeleteBankToken drops the cached token, the next call gets a new one
+func (s *BankRepo) DeleteBankToken(ctx context.Context, bankID int64) error {
+	const op = "storage.sqlite.bank.DeleteBankToken"
+
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM bank_tokens WHERE bank_id = ?`, bankID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// EncryptSecrets brings banks.password and bank_tokens.access_token to the current master key and format:
+// plaintext and legacy values are sealed bound to their row, values of old keys are re-encrypted.
+// Returns the number of changed values.
+func (s *BankRepo) EncryptSecrets(ctx context.Context) (int, error) {
+	const op = "storage.sqlite.bank.EncryptSecrets"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	changed := 0
+	for _, c := range []struct{ table, key, column string }{
+		{"banks", "id", "password"},
+		{"bank_tokens", "bank_id", "access_token"},
+	} {
+		n, err := s.rewrapColumn(ctx, tx, c.table, c.key, c.column)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %s.%s: %w", op, c.table, c.column, err)
+		}
+		changed += n
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return changed, nil
+}
+
+func (s *BankRepo) rewrapColumn(ctx context.Context, tx *sql.Tx, table, key, column string) (int, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT `+key+`, `+column+` FROM `+table)
+	if err != nil {
+		return 0, err
+	}
+	type value struct {
+		id     int64
+		stored string
+	}
+	var stale []value
+	for rows.Next() {
+		var v value
+		if err := rows.Scan(&v.id, &v.stored); err != nil {
+			_ = rows.Close()
+			return 0, err
+		}
+		if !s.box.Current(v.stored) {
+			stale = append(stale, v)
+		}
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, v := range stale {
+		sealed, err := s.box.Rewrap(v.stored, secretAAD(table, column, v.id))
+		if err != nil {
+			return 0, fmt.Errorf("%s %d: %w", key, v.id, err)
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE `+table+` SET `+column+` = ? WHERE `+key+` = ?`, sealed, v.id); err != nil {
+			return 0, err
+		}
+	}
+	return len(stale), nil
+}
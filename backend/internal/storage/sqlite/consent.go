@@ -6,9 +6,12 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"multibank/backend/internal/domain"
+	"multibank/backend/internal/storage"
 	sqliteutils "multibank/backend/internal/storage/sqlite/utils"
+	"time"
 )
 
 type ConsentRepo struct {
@@ -22,7 +25,7 @@ const consentCols = `
 id,user_id,bank_id,request_id,consent_id,status,auto_approved,permissions_json,
 reason,requesting_bank,requesting_bank_name,
 creation_datetime,status_update_datetime,expiration_datetime,client_id,
-created_at,updated_at,bank_code
+created_at,updated_at,bank_code,revoked_at
 `
 
 // rowScanner allows you to scan both *sql.Row and *sql.Rows
@@ -39,13 +42,14 @@ func scanConsent(rs rowScanner) (domain.AccountConsent, error) {
 		creation, statusUpd, expiration *string
 		createdAtStr, updatedAtStr      *string
 		bankCode                        *string
+		revokedAt                       *string
 	)
 
 	if err := rs.Scan(
 		&c.ID, &c.UserID, &c.BankID, &c.RequestID, &consentID, &c.Status, &autoApproved, &perms,
 		&c.Reason, &c.RequestingBank, &c.RequestingBankName,
 		&creation, &statusUpd, &expiration, &c.ClientID,
-		&createdAtStr, &updatedAtStr, &bankCode,
+		&createdAtStr, &updatedAtStr, &bankCode, &revokedAt,
 	); err != nil {
 		return domain.AccountConsent{}, err
 	}
@@ -80,6 +84,7 @@ func scanConsent(rs rowScanner) (domain.AccountConsent, error) {
 	if bankCode != nil {
 		c.BankCode = *bankCode
 	}
+	c.RevokedAt = parseTSPtr(revokedAt)
 
 	return c, nil
 }
@@ -148,7 +153,11 @@ WHERE id = ?`
 func (r *ConsentRepo) GetByID(ctx context.Context, id int64) (domain.AccountConsent, error) {
 	q := `SELECT ` + consentCols + ` FROM account_consents_view WHERE id=?`
 	row := r.db.QueryRowContext(ctx, q, id)
-	return scanConsent(row)
+	c, err := scanConsent(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.AccountConsent{}, storage.ErrConsentNotFound
+	}
+	return c, err
 }
 
 func (r *ConsentRepo) ListByUser(ctx context.Context, userID int64, bankID *int64) ([]domain.AccountConsent, error) {
@@ -180,6 +189,20 @@ func (r *ConsentRepo) ListByUser(ctx context.Context, userID int64, bankID *int6
 	return out, nil
 }
 
+// MarkRevoked keeps the consent with status Revoked and the time of revocation
+func (r *ConsentRepo) MarkRevoked(ctx context.Context, id int64, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+UPDATE account_consents
+SET status     = 'Revoked',
+    revoked_at = ?,
+    updated_at = datetime('now')
+WHERE id = ?`, at.UTC().Format(sqliteutils.TsLayout), id)
+	if err != nil {
+		return fmt.Errorf("storage.sqlite.consent.MarkRevoked: %w", err)
+	}
+	return nil
+}
+
 func (r *ConsentRepo) DeleteByID(ctx context.Context, id int64) error {
 	_, err := r.db.ExecContext(ctx, `DELETE FROM account_consents WHERE id=?`, id)
 	return err
@@ -216,3 +239,27 @@ LIMIT ?`
 	}
 	return out, rows.Err()
 }
+
+// ListAuthorized returns all authorized consents that have consent_id (i.e. give access to bank data).
+func (r *ConsentRepo) ListAuthorized(ctx context.Context) ([]domain.AccountConsent, error) {
+	q := `
+SELECT ` + consentCols + `
+FROM account_consents_view
+WHERE status = 'Authorized' AND consent_id IS NOT NULL AND consent_id <> ''
+ORDER BY id`
+	rows, err := r.db.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]domain.AccountConsent, 0, 16)
+	for rows.Next() {
+		c, err := scanConsent(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
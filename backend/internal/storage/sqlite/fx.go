@@ -0,0 +1,92 @@
+// internal/storage/sqlite/fx.go
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/storage"
+	"time"
+)
+
+const dateLayout = "2006-01-02" // dates without time (fx_rates, balance_snapshots)
+
+type FXRepo struct {
+	db *sql.DB
+}
+
+func NewFXRepo(db *sql.DB) *FXRepo { return &FXRepo{db: db} }
+
+// UpsertRates saves rates of one or several dates. Rates already stored for the same date are overwritten.
+func (r *FXRepo) UpsertRates(ctx context.Context, items []domain.FXRate) (int, error) {
+	const op = "storage.sqlite.fx.UpsertRates"
+
+	if len(items) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.PrepareContext(ctx, `
+INSERT INTO fx_rates (date, currency, nominal, value, rate, source)
+VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT(currency, date) DO UPDATE SET
+    nominal     = excluded.nominal,
+    value       = excluded.value,
+    rate        = excluded.rate,
+    source      = excluded.source,
+    imported_at = datetime('now')`)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	for _, it := range items {
+		if _, err := stmt.ExecContext(ctx,
+			it.Date.Format(dateLayout), it.Currency, it.Nominal, it.Value, it.Rate, it.Source,
+		); err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return len(items), nil
+}
+
+// LatestRate returns the latest rate of the currency set on or before the given date.
+// Returns storage.ErrRateNotFound if there is no such rate.
+func (r *FXRepo) LatestRate(ctx context.Context, currency string, onOrBefore time.Time) (domain.FXRate, error) {
+	const op = "storage.sqlite.fx.LatestRate"
+
+	var (
+		out  domain.FXRate
+		date string
+	)
+	err := r.db.QueryRowContext(ctx, `
+SELECT date, currency, nominal, value, rate, source
+FROM fx_rates
+WHERE currency = ? AND date <= ?
+ORDER BY date DESC
+LIMIT 1`, currency, onOrBefore.Format(dateLayout),
+	).Scan(&date, &out.Currency, &out.Nominal, &out.Value, &out.Rate, &out.Source)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.FXRate{}, fmt.Errorf("%s: %w", op, storage.ErrRateNotFound)
+		}
+		return domain.FXRate{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if out.Date, err = time.Parse(dateLayout, date); err != nil {
+		return domain.FXRate{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return out, nil
+}
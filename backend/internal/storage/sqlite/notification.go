@@ -0,0 +1,93 @@
+// internal/storage/sqlite/notification.go
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/storage"
+	sqliteutils "multibank/backend/internal/storage/sqlite/utils"
+	"time"
+)
+
+type NotificationRepo struct {
+	db *sql.DB
+}
+
+func NewNotificationRepo(db *sql.DB) *NotificationRepo { return &NotificationRepo{db: db} }
+
+// Create saves the notification and returns its id
+func (r *NotificationRepo) Create(ctx context.Context, n *domain.Notification) (int64, error) {
+	const op = "storage.sqlite.notification.Create"
+
+	res, err := r.db.ExecContext(ctx, `
+INSERT INTO notifications (user_id, kind, title, message, bank_code, product_id)
+VALUES (?, ?, ?, ?, ?, ?)`,
+		n.UserID, string(n.Kind), n.Title, n.Message, n.BankCode, n.ProductID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return res.LastInsertId()
+}
+
+// ListByUser returns notifications of the user (newest first)
+func (r *NotificationRepo) ListByUser(ctx context.Context, userID int64, unreadOnly bool, limit int) ([]domain.Notification, error) {
+	const op = "storage.sqlite.notification.ListByUser"
+
+	q := `
+SELECT id, user_id, kind, title, message, bank_code, product_id, created_at, read_at
+FROM notifications
+WHERE user_id = ?`
+	if unreadOnly {
+		q += ` AND read_at IS NULL`
+	}
+	q += ` ORDER BY id DESC LIMIT ?`
+
+	rows, err := r.db.QueryContext(ctx, q, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.Notification, 0, 16)
+	for rows.Next() {
+		var (
+			n         domain.Notification
+			createdAt string
+			readAt    *string
+		)
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Kind, &n.Title, &n.Message, &n.BankCode, &n.ProductID, &createdAt, &readAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		n.CreatedAt, _ = sqliteutils.ParseTS(createdAt)
+		n.ReadAt = parseTSPtr(readAt)
+		out = append(out, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return out, nil
+}
+
+// MarkRead marks the notification of the user as read or returns storage.ErrNotificationNotFound
+func (r *NotificationRepo) MarkRead(ctx context.Context, userID, id int64, at time.Time) error {
+	const op = "storage.sqlite.notification.MarkRead"
+
+	res, err := r.db.ExecContext(ctx, `
+UPDATE notifications SET read_at = COALESCE(read_at, ?) WHERE id = ? AND user_id = ?`,
+		at.UTC().Format(sqliteutils.TsLayout), id, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrNotificationNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,392 @@
+// internal/storage/sqlite/payment.go
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/storage"
+	sqliteutils "multibank/backend/internal/storage/sqlite/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"modernc.org/sqlite"
+	sqlitelib "modernc.org/sqlite/lib"
+)
+
+type PaymentRepo struct {
+	db *sql.DB
+}
+
+func NewPaymentRepo(db *sql.DB) *PaymentRepo { return &PaymentRepo{db: db} }
+
+const paymentConsentCols = `
+c.id, c.user_id, c.bank_id, b.code, c.request_id, c.consent_id, c.status, c.auto_approved,
+c.client_id, c.requesting_bank, c.consent_type,
+c.amount, c.currency, c.debtor_account, c.creditor_account, c.creditor_name, c.creditor_bank_code, c.reference,
+c.creation_datetime, c.status_update_datetime, c.expiration_datetime,
+c.created_at, c.updated_at
+`
+
+func scanPaymentConsent(rs rowScanner) (domain.PaymentConsent, error) {
+	var (
+		c                               domain.PaymentConsent
+		autoApproved                    *int64
+		creation, statusUpd, expiration *string
+		createdAt, updatedAt            string
+	)
+	if err := rs.Scan(
+		&c.ID, &c.UserID, &c.BankID, &c.BankCode, &c.RequestID, &c.ConsentID, &c.Status, &autoApproved,
+		&c.ClientID, &c.RequestingBank, &c.ConsentType,
+		&c.Amount, &c.Currency, &c.DebtorAccount, &c.CreditorAccount, &c.CreditorName, &c.CreditorBankCode, &c.Reference,
+		&creation, &statusUpd, &expiration,
+		&createdAt, &updatedAt,
+	); err != nil {
+		return domain.PaymentConsent{}, err
+	}
+	if autoApproved != nil {
+		v := *autoApproved != 0
+		c.AutoApproved = &v
+	}
+	c.CreationDateTime = sqliteutils.FromISO(creation)
+	c.StatusUpdateDateTime = sqliteutils.FromISO(statusUpd)
+	c.ExpirationDateTime = sqliteutils.FromISO(expiration)
+	c.CreatedAt, _ = sqliteutils.ParseTS(createdAt)
+	c.UpdatedAt, _ = sqliteutils.ParseTS(updatedAt)
+	return c, nil
+}
+
+func boolToIntPtr(b *bool) *int64 {
+	if b == nil {
+		return nil
+	}
+	var v int64
+	if *b {
+		v = 1
+	}
+	return &v
+}
+
+// CreateConsent saves a new payment consent and returns its id
+func (r *PaymentRepo) CreateConsent(ctx context.Context, c *domain.PaymentConsent) (int64, error) {
+	const op = "storage.sqlite.payment.CreateConsent"
+
+	res, err := r.db.ExecContext(ctx, `
+INSERT INTO payment_consents
+(user_id, bank_id, request_id, consent_id, status, auto_approved, client_id, requesting_bank, consent_type,
+ amount, currency, debtor_account, creditor_account, creditor_name, creditor_bank_code, reference,
+ creation_datetime, status_update_datetime, expiration_datetime)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		c.UserID, c.BankID, c.RequestID, c.ConsentID, string(c.Status), boolToIntPtr(c.AutoApproved),
+		c.ClientID, c.RequestingBank, c.ConsentType,
+		c.Amount, c.Currency, c.DebtorAccount, c.CreditorAccount, c.CreditorName, c.CreditorBankCode, c.Reference,
+		sqliteutils.ToISO(c.CreationDateTime), sqliteutils.ToISO(c.StatusUpdateDateTime), sqliteutils.ToISO(c.ExpirationDateTime),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return res.LastInsertId()
+}
+
+// UpdateConsentAfterCheck saves the status received from the bank (nil fields are kept)
+func (r *PaymentRepo) UpdateConsentAfterCheck(ctx context.Context, id int64, upd *domain.PaymentConsent) error {
+	const op = "storage.sqlite.payment.UpdateConsentAfterCheck"
+
+	_, err := r.db.ExecContext(ctx, `
+UPDATE payment_consents
+SET consent_id             = COALESCE(?, consent_id),
+    status                 = ?,
+    creation_datetime      = COALESCE(?, creation_datetime),
+    status_update_datetime = COALESCE(?, status_update_datetime),
+    expiration_datetime    = COALESCE(?, expiration_datetime),
+    updated_at             = datetime('now')
+WHERE id = ?`,
+		upd.ConsentID, string(upd.Status),
+		sqliteutils.ToISO(upd.CreationDateTime), sqliteutils.ToISO(upd.StatusUpdateDateTime), sqliteutils.ToISO(upd.ExpirationDateTime),
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// GetConsentByID returns the payment consent or storage.ErrPaymentConsentNotFound
+func (r *PaymentRepo) GetConsentByID(ctx context.Context, id int64) (domain.PaymentConsent, error) {
+	const op = "storage.sqlite.payment.GetConsentByID"
+
+	row := r.db.QueryRowContext(ctx, `SELECT `+paymentConsentCols+`
+FROM payment_consents c
+JOIN banks b ON b.id = c.bank_id
+WHERE c.id = ?`, id)
+	c, err := scanPaymentConsent(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.PaymentConsent{}, fmt.Errorf("%s: %w", op, storage.ErrPaymentConsentNotFound)
+		}
+		return domain.PaymentConsent{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return c, nil
+}
+
+// ListConsentsByUser returns payment consents of the user (newest first)
+func (r *PaymentRepo) ListConsentsByUser(ctx context.Context, userID int64) ([]domain.PaymentConsent, error) {
+	const op = "storage.sqlite.payment.ListConsentsByUser"
+
+	rows, err := r.db.QueryContext(ctx, `SELECT `+paymentConsentCols+`
+FROM payment_consents c
+JOIN banks b ON b.id = c.bank_id
+WHERE c.user_id = ?
+ORDER BY c.id DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.PaymentConsent, 0, 16)
+	for rows.Next() {
+		c, err := scanPaymentConsent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		out = append(out, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return out, nil
+}
+
+const paymentCols = `
+p.id, p.user_id, p.bank_id, b.code, p.payment_consent_id, p.payment_id, p.status,
+p.amount, p.currency, p.debtor_account, p.creditor_account, p.creditor_name, p.creditor_bank_code, p.comment,
+p.creation_datetime, p.status_update_datetime, p.created_at, p.updated_at
+`
+
+func scanPayment(rs rowScanner) (domain.Payment, error) {
+	var (
+		p                    domain.Payment
+		creation, statusUpd  *string
+		createdAt, updatedAt string
+	)
+	if err := rs.Scan(
+		&p.ID, &p.UserID, &p.BankID, &p.BankCode, &p.PaymentConsentID, &p.PaymentID, &p.Status,
+		&p.Amount, &p.Currency, &p.DebtorAccount, &p.CreditorAccount, &p.CreditorName, &p.CreditorBankCode, &p.Comment,
+		&creation, &statusUpd, &createdAt, &updatedAt,
+	); err != nil {
+		return domain.Payment{}, err
+	}
+	if strings.HasPrefix(p.PaymentID, reservedPaymentPrefix) {
+		p.PaymentID = "" // the bank has not confirmed the payment yet
+	}
+	p.CreationDateTime = sqliteutils.FromISO(creation)
+	p.StatusUpdateDateTime = sqliteutils.FromISO(statusUpd)
+	p.CreatedAt, _ = sqliteutils.ParseTS(createdAt)
+	p.UpdatedAt, _ = sqliteutils.ParseTS(updatedAt)
+	return p, nil
+}
+
+// reservedPaymentPrefix marks payment_id of a reserved payment (unique per consent, replaced by the bank id)
+const reservedPaymentPrefix = "reserved:"
+
+// ReservePayment saves the payment with status Initiating before the bank is called.
+// The consent has one payment at most: storage.ErrPaymentExists if it is already reserved or made.
+func (r *PaymentRepo) ReservePayment(ctx context.Context, p *domain.Payment) (int64, error) {
+	const op = "storage.sqlite.payment.ReservePayment"
+
+	res, err := r.db.ExecContext(ctx, `
+INSERT INTO payments
+(user_id, bank_id, payment_consent_id, payment_id, status,
+ amount, currency, debtor_account, creditor_account, creditor_name, creditor_bank_code, comment)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		p.UserID, p.BankID, p.PaymentConsentID, reservedPaymentPrefix+strconv.FormatInt(p.PaymentConsentID, 10),
+		string(domain.PaymentInitiating),
+		p.Amount, p.Currency, p.DebtorAccount, p.CreditorAccount, p.CreditorName, p.CreditorBankCode, p.Comment,
+	)
+	if err != nil {
+		var se *sqlite.Error
+		if errors.As(err, &se) && se.Code() == sqlitelib.SQLITE_CONSTRAINT_UNIQUE {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrPaymentExists)
+		}
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return res.LastInsertId()
+}
+
+// RetakePayment takes over a reservation that has not been touched for staleAfter
+// (the attempt that made it was lost). Only one caller gets true.
+func (r *PaymentRepo) RetakePayment(ctx context.Context, id int64, staleAfter time.Duration) (bool, error) {
+	const op = "storage.sqlite.payment.RetakePayment"
+
+	res, err := r.db.ExecContext(ctx, `
+UPDATE payments
+SET updated_at = datetime('now')
+WHERE id = ? AND status = ? AND updated_at <= datetime('now', ?)`,
+		id, string(domain.PaymentInitiating), fmt.Sprintf("-%d seconds", int64(staleAfter/time.Second)),
+	)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	return n == 1, nil
+}
+
+// ReleasePayment deletes the reservation of a payment that was not sent to the bank,
+// so the consent can be used again
+func (r *PaymentRepo) ReleasePayment(ctx context.Context, id int64) error {
+	const op = "storage.sqlite.payment.ReleasePayment"
+
+	_, err := r.db.ExecContext(ctx, `DELETE FROM payments WHERE id = ? AND status = ?`,
+		id, string(domain.PaymentInitiating))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// ListStalePayments returns reservations not touched for staleAfter, the oldest first
+func (r *PaymentRepo) ListStalePayments(ctx context.Context, staleAfter time.Duration, limit int) ([]domain.Payment, error) {
+	const op = "storage.sqlite.payment.ListStalePayments"
+
+	rows, err := r.db.QueryContext(ctx, `SELECT `+paymentCols+`
+FROM payments p
+JOIN banks b ON b.id = p.bank_id
+WHERE p.status = ? AND p.updated_at <= datetime('now', ?)
+ORDER BY p.updated_at, p.id
+LIMIT ?`,
+		string(domain.PaymentInitiating), fmt.Sprintf("-%d seconds", int64(staleAfter/time.Second)), limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var out []domain.Payment
+	for rows.Next() {
+		p, err := scanPayment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		out = append(out, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return out, nil
+}
+
+// ConfirmPayment saves the answer of the bank to the reserved payment
+func (r *PaymentRepo) ConfirmPayment(ctx context.Context, p *domain.Payment) error {
+	const op = "storage.sqlite.payment.ConfirmPayment"
+
+	res, err := r.db.ExecContext(ctx, `
+UPDATE payments
+SET payment_id             = ?,
+    status                 = ?,
+    creation_datetime      = ?,
+    status_update_datetime = ?,
+    updated_at             = datetime('now')
+WHERE id = ? AND status = ?`,
+		p.PaymentID, string(p.Status),
+		sqliteutils.ToISO(p.CreationDateTime), sqliteutils.ToISO(p.StatusUpdateDateTime),
+		p.ID, string(domain.PaymentInitiating),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrPaymentNotFound)
+	}
+	return nil
+}
+
+// UpdatePaymentStatus saves the status received from the bank
+func (r *PaymentRepo) UpdatePaymentStatus(ctx context.Context, id int64, status domain.PaymentStatus, statusUpdated *time.Time) error {
+	const op = "storage.sqlite.payment.UpdatePaymentStatus"
+
+	_, err := r.db.ExecContext(ctx, `
+UPDATE payments
+SET status                 = ?,
+    status_update_datetime = COALESCE(?, status_update_datetime),
+    updated_at             = datetime('now')
+WHERE id = ?`, string(status), sqliteutils.ToISO(statusUpdated), id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// GetPaymentByID returns the payment or storage.ErrPaymentNotFound
+func (r *PaymentRepo) GetPaymentByID(ctx context.Context, id int64) (domain.Payment, error) {
+	const op = "storage.sqlite.payment.GetPaymentByID"
+
+	row := r.db.QueryRowContext(ctx, `SELECT `+paymentCols+`
+FROM payments p
+JOIN banks b ON b.id = p.bank_id
+WHERE p.id = ?`, id)
+	p, err := scanPayment(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Payment{}, fmt.Errorf("%s: %w", op, storage.ErrPaymentNotFound)
+		}
+		return domain.Payment{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return p, nil
+}
+
+// ListPaymentsByUser returns payments of the user (newest first)
+func (r *PaymentRepo) ListPaymentsByUser(ctx context.Context, userID int64) ([]domain.Payment, error) {
+	const op = "storage.sqlite.payment.ListPaymentsByUser"
+
+	rows, err := r.db.QueryContext(ctx, `SELECT `+paymentCols+`
+FROM payments p
+JOIN banks b ON b.id = p.bank_id
+WHERE p.user_id = ?
+ORDER BY p.id DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.Payment, 0, 16)
+	for rows.Next() {
+		p, err := scanPayment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		out = append(out, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return out, nil
+}
+
+// GetPaymentByConsent returns the payment made (or reserved) with the payment consent
+func (r *PaymentRepo) GetPaymentByConsent(ctx context.Context, paymentConsentID int64) (domain.Payment, error) {
+	const op = "storage.sqlite.payment.GetPaymentByConsent"
+
+	row := r.db.QueryRowContext(ctx, `SELECT `+paymentCols+`
+FROM payments p
+JOIN banks b ON b.id = p.bank_id
+WHERE p.payment_consent_id = ?`, paymentConsentID)
+	p, err := scanPayment(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Payment{}, fmt.Errorf("%s: %w", op, storage.ErrPaymentNotFound)
+		}
+		return domain.Payment{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return p, nil
+}
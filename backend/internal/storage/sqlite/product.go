@@ -0,0 +1,413 @@
+// internal/storage/sqlite/product.go
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/storage"
+	sqliteutils "multibank/backend/internal/storage/sqlite/utils"
+	"strings"
+	"time"
+)
+
+type ProductRepo struct {
+	db *sql.DB
+}
+
+func NewProductRepo(db *sql.DB) *ProductRepo { return &ProductRepo{db: db} }
+
+// amounts are stored in kopecks
+func toKopecks(v float64) int64 { return int64(math.Round(v * 100)) }
+
+func fromKopecks(v int64) float64 { return float64(v) / 100 }
+
+// storedProduct — tracked parameters of the product from the previous fetch (amounts in kopecks)
+type storedProduct struct {
+	rate       float64
+	minAmount  int64
+	maxAmount  int64
+	termMonths int
+}
+
+// ReplaceBank saves the catalogue fetched from the bank: products are upserted,
+// products the bank does not return anymore are removed, the fetch is marked successful.
+// Changes of tracked parameters of already known products are written to product_history and returned.
+func (r *ProductRepo) ReplaceBank(ctx context.Context, bankID int64, items []domain.Product, fetchedAt time.Time) ([]domain.ProductChange, error) {
+	const op = "storage.sqlite.product.ReplaceBank"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	prev, err := loadStoredProducts(ctx, tx, bankID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	at := fetchedAt.UTC().Format(sqliteutils.TsLayout)
+	keep := make([]any, 0, len(items)+1)
+	keep = append(keep, bankID)
+	changes := make([]domain.ProductChange, 0)
+
+	for _, p := range items {
+		var maxAmount *int64
+		if p.MaxAmount > 0 {
+			v := toKopecks(p.MaxAmount)
+			maxAmount = &v
+		}
+
+		if old, ok := prev[p.ProductID]; ok {
+			for _, c := range diffProduct(old, p) {
+				c.BankID = bankID
+				c.BankCode = p.BankCode
+				c.ProductID = p.ProductID
+				c.ProductName = p.ProductName
+				c.ChangedAt = fetchedAt
+				if _, err := tx.ExecContext(ctx, `
+INSERT INTO product_history (bank_id, product_id, field, old_value, new_value, changed_at)
+VALUES (?, ?, ?, ?, ?, ?)`, bankID, p.ProductID, string(c.Field), c.OldValue, c.NewValue, at); err != nil {
+					return nil, fmt.Errorf("%s: %w", op, err)
+				}
+				changes = append(changes, c)
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+INSERT INTO products
+(bank_id, product_id, product_type, name, description, interest_rate, min_amount, max_amount, term_months, fetched_at, search_text)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(bank_id, product_id) DO UPDATE SET
+    product_type  = excluded.product_type,
+    name          = excluded.name,
+    description   = excluded.description,
+    interest_rate = excluded.interest_rate,
+    min_amount    = excluded.min_amount,
+    max_amount    = excluded.max_amount,
+    term_months   = excluded.term_months,
+    fetched_at    = excluded.fetched_at,
+    search_text   = excluded.search_text,
+    updated_at    = datetime('now')`,
+			bankID, p.ProductID, p.ProductType, p.ProductName, p.Description,
+			p.InterestRate, toKopecks(p.MinAmount), maxAmount, p.TermMonths, at,
+			strings.ToLower(p.ProductName+"\n"+p.Description),
+		); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		keep = append(keep, p.ProductID)
+	}
+
+	q := `DELETE FROM products WHERE bank_id = ?`
+	if len(keep) > 1 {
+		q += ` AND product_id NOT IN (?` + strings.Repeat(",?", len(keep)-2) + `)`
+	}
+	if _, err := tx.ExecContext(ctx, q, keep...); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+INSERT INTO product_sources (bank_id, last_attempt_at, last_success_at, last_error, failures, products_count)
+VALUES (?, ?, ?, '', 0, ?)
+ON CONFLICT(bank_id) DO UPDATE SET
+    last_attempt_at = excluded.last_attempt_at,
+    last_success_at = excluded.last_success_at,
+    last_error      = '',
+    failures        = 0,
+    products_count  = excluded.products_count`,
+		bankID, at, at, len(items),
+	); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return changes, nil
+}
+
+func loadStoredProducts(ctx context.Context, tx *sql.Tx, bankID int64) (map[string]storedProduct, error) {
+	rows, err := tx.QueryContext(ctx, `
+SELECT product_id, interest_rate, min_amount, COALESCE(max_amount, 0), term_months
+FROM products WHERE bank_id = ?`, bankID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make(map[string]storedProduct, 32)
+	for rows.Next() {
+		var (
+			id string
+			p  storedProduct
+		)
+		if err := rows.Scan(&id, &p.rate, &p.minAmount, &p.maxAmount, &p.termMonths); err != nil {
+			return nil, err
+		}
+		out[id] = p
+	}
+	return out, rows.Err()
+}
+
+// diffProduct compares tracked parameters (amounts are compared in kopecks)
+func diffProduct(old storedProduct, p domain.Product) []domain.ProductChange {
+	var out []domain.ProductChange
+	if old.rate != p.InterestRate {
+		out = append(out, domain.ProductChange{Field: domain.FieldInterestRate, OldValue: old.rate, NewValue: p.InterestRate})
+	}
+	if v := toKopecks(p.MinAmount); old.minAmount != v {
+		out = append(out, domain.ProductChange{Field: domain.FieldMinAmount, OldValue: fromKopecks(old.minAmount), NewValue: fromKopecks(v)})
+	}
+	if v := toKopecks(p.MaxAmount); old.maxAmount != v {
+		out = append(out, domain.ProductChange{Field: domain.FieldMaxAmount, OldValue: fromKopecks(old.maxAmount), NewValue: fromKopecks(v)})
+	}
+	if old.termMonths != p.TermMonths {
+		out = append(out, domain.ProductChange{Field: domain.FieldTermMonths, OldValue: float64(old.termMonths), NewValue: float64(p.TermMonths)})
+	}
+	return out
+}
+
+// MarkFetchFailed keeps stored products of the bank and records the failed attempt
+func (r *ProductRepo) MarkFetchFailed(ctx context.Context, bankID int64, at time.Time, reason string) error {
+	const op = "storage.sqlite.product.MarkFetchFailed"
+
+	_, err := r.db.ExecContext(ctx, `
+INSERT INTO product_sources (bank_id, last_attempt_at, last_error, failures)
+VALUES (?, ?, ?, 1)
+ON CONFLICT(bank_id) DO UPDATE SET
+    last_attempt_at = excluded.last_attempt_at,
+    last_error      = excluded.last_error,
+    failures        = failures + 1`,
+		bankID, at.UTC().Format(sqliteutils.TsLayout), reason,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// productSortCols — ORDER BY columns of the sort, p.id is appended as the tie-breaker
+var productSortCols = map[domain.ProductSort][]string{
+	domain.ProductSortBank: {"b.name", "p.product_type", "p.name"},
+	domain.ProductSortRate: {"p.interest_rate"},
+	domain.ProductSortTerm: {"p.term_months"},
+}
+
+// productCursor — sort keys of the last product of the page (encoded as base64 JSON)
+type productCursor struct {
+	Sort domain.ProductSort `json:"s"`
+	Desc bool               `json:"d,omitempty"`
+	Keys []any              `json:"k"`
+	ID   int64              `json:"id"`
+}
+
+func encodeProductCursor(c productCursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeProductCursor checks that the cursor was issued for the same sort
+func decodeProductCursor(s string, sort domain.ProductSort, desc bool) (productCursor, error) {
+	var c productCursor
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, storage.ErrInvalidCursor
+	}
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return c, storage.ErrInvalidCursor
+	}
+	if c.Sort != sort || c.Desc != desc || len(c.Keys) != len(productSortCols[sort]) {
+		return c, storage.ErrInvalidCursor
+	}
+	for _, k := range c.Keys {
+		switch k.(type) {
+		case string, float64:
+		default:
+			return c, storage.ErrInvalidCursor
+		}
+	}
+	return c, nil
+}
+
+func productSortKeys(sort domain.ProductSort, p domain.Product) []any {
+	switch sort {
+	case domain.ProductSortRate:
+		return []any{p.InterestRate}
+	case domain.ProductSortTerm:
+		return []any{p.TermMonths}
+	default:
+		return []any{p.BankName, p.ProductType, p.ProductName}
+	}
+}
+
+// escapeLike escapes LIKE wildcards (ESCAPE '\')
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// List returns a page of stored products of enabled banks with the health of their source bank.
+// Pages are keyset-based: the cursor holds sort keys of the last product of the previous page.
+func (r *ProductRepo) List(ctx context.Context, f domain.ProductFilter) (domain.ProductPage, error) {
+	const op = "storage.sqlite.product.List"
+
+	sort := f.Sort
+	if _, ok := productSortCols[sort]; !ok {
+		sort = domain.ProductSortBank
+	}
+
+	q := `
+SELECT p.id, p.product_id, p.product_type, p.name, p.description, p.interest_rate, p.min_amount, p.max_amount, p.term_months,
+       p.bank_id, b.code, b.name, p.fetched_at, s.last_attempt_at, COALESCE(s.failures, 0)
+FROM products p
+JOIN banks b ON b.id = p.bank_id
+LEFT JOIN product_sources s ON s.bank_id = p.bank_id
+WHERE b.is_enabled = 1`
+	args := make([]any, 0, len(f.BankIDs)+len(f.ProductTypes)+8)
+	if f.ProductType != "" {
+		q += ` AND p.product_type = ?`
+		args = append(args, f.ProductType)
+	}
+	if len(f.ProductTypes) > 0 {
+		q += ` AND LOWER(p.product_type) IN (?` + strings.Repeat(",?", len(f.ProductTypes)-1) + `)`
+		for _, t := range f.ProductTypes {
+			args = append(args, t)
+		}
+	}
+	if len(f.BankIDs) > 0 {
+		q += ` AND p.bank_id IN (?` + strings.Repeat(",?", len(f.BankIDs)-1) + `)`
+		for _, id := range f.BankIDs {
+			args = append(args, id)
+		}
+	}
+	if f.MinRate != nil {
+		q += ` AND p.interest_rate >= ?`
+		args = append(args, *f.MinRate)
+	}
+	if f.MinTerm != nil {
+		q += ` AND p.term_months >= ?`
+		args = append(args, *f.MinTerm)
+	}
+	if f.MaxTerm != nil {
+		q += ` AND p.term_months <= ?`
+		args = append(args, *f.MaxTerm)
+	}
+	if f.Amount != nil {
+		v := toKopecks(*f.Amount)
+		q += ` AND p.min_amount <= ? AND (p.max_amount IS NULL OR p.max_amount >= ?)`
+		args = append(args, v, v)
+	}
+	if text := strings.TrimSpace(f.Query); text != "" {
+		q += ` AND p.search_text LIKE ? ESCAPE '\'`
+		args = append(args, "%"+escapeLike(strings.ToLower(text))+"%")
+	}
+
+	cols := append(append([]string{}, productSortCols[sort]...), "p.id")
+	cmp, dir := ">", ""
+	if f.Desc {
+		cmp, dir = "<", " DESC"
+	}
+	if f.Cursor != "" {
+		c, err := decodeProductCursor(f.Cursor, sort, f.Desc)
+		if err != nil {
+			return domain.ProductPage{}, fmt.Errorf("%s: %w", op, err)
+		}
+		q += ` AND (` + strings.Join(cols, ", ") + `) ` + cmp + ` (?` + strings.Repeat(",?", len(cols)-1) + `)`
+		args = append(append(args, c.Keys...), c.ID)
+	}
+	q += ` ORDER BY ` + strings.Join(cols, dir+", ") + dir
+	if f.Limit > 0 {
+		q += ` LIMIT ?`
+		args = append(args, f.Limit+1) // one more to know if there is a next page
+	}
+
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return domain.ProductPage{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.Product, 0, 64)
+	ids := make([]int64, 0, 64)
+	for rows.Next() {
+		var (
+			id          int64
+			p           domain.Product
+			minAmount   int64
+			maxAmount   *int64
+			fetchedAt   string
+			lastAttempt *string
+			failures    int
+		)
+		if err := rows.Scan(
+			&id, &p.ProductID, &p.ProductType, &p.ProductName, &p.Description, &p.InterestRate, &minAmount, &maxAmount, &p.TermMonths,
+			&p.BankID, &p.BankCode, &p.BankName, &fetchedAt, &lastAttempt, &failures,
+		); err != nil {
+			return domain.ProductPage{}, fmt.Errorf("%s: %w", op, err)
+		}
+		p.MinAmount = fromKopecks(minAmount)
+		if maxAmount != nil {
+			p.MaxAmount = fromKopecks(*maxAmount)
+		}
+		p.FetchedAt, _ = sqliteutils.ParseTS(fetchedAt)
+		p.BankHealth = domain.ProductSource{LastAttemptAt: parseTSPtr(lastAttempt), Failures: failures}.Health()
+		out = append(out, p)
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return domain.ProductPage{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	page := domain.ProductPage{Items: out}
+	if f.Limit > 0 && len(out) > f.Limit {
+		last := f.Limit - 1
+		page.Items = out[:f.Limit]
+		page.NextCursor = encodeProductCursor(productCursor{
+			Sort: sort,
+			Desc: f.Desc,
+			Keys: productSortKeys(sort, out[last]),
+			ID:   ids[last],
+		})
+	}
+	return page, nil
+}
+
+// ListSources returns the fetch state of every enabled bank (banks never fetched are included)
+func (r *ProductRepo) ListSources(ctx context.Context) ([]domain.ProductSource, error) {
+	const op = "storage.sqlite.product.ListSources"
+
+	rows, err := r.db.QueryContext(ctx, `
+SELECT b.id, b.code, b.name, s.last_attempt_at, s.last_success_at,
+       COALESCE(s.last_error, ''), COALESCE(s.failures, 0), COALESCE(s.products_count, 0)
+FROM banks b
+LEFT JOIN product_sources s ON s.bank_id = b.id
+WHERE b.is_enabled = 1
+ORDER BY b.name`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.ProductSource, 0, 8)
+	for rows.Next() {
+		var (
+			s                   domain.ProductSource
+			lastAttempt, lastOK *string
+		)
+		if err := rows.Scan(&s.BankID, &s.BankCode, &s.BankName, &lastAttempt, &lastOK, &s.LastError, &s.Failures, &s.ProductsCount); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		s.LastAttemptAt = parseTSPtr(lastAttempt)
+		s.LastSuccessAt = parseTSPtr(lastOK)
+		out = append(out, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return out, nil
+}
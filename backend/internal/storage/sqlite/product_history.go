@@ -0,0 +1,166 @@
+// internal/storage/sqlite/product_history.go
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/storage"
+	sqliteutils "multibank/backend/internal/storage/sqlite/utils"
+)
+
+// GetProduct returns the stored product of the bank or storage.ErrProductNotFound
+func (r *ProductRepo) GetProduct(ctx context.Context, bankCode, productID string) (domain.Product, error) {
+	const op = "storage.sqlite.product.GetProduct"
+
+	var (
+		p         domain.Product
+		minAmount int64
+		maxAmount *int64
+		fetchedAt string
+	)
+	err := r.db.QueryRowContext(ctx, `
+SELECT p.product_id, p.product_type, p.name, p.description, p.interest_rate, p.min_amount, p.max_amount, p.term_months,
+       p.bank_id, b.code, b.name, p.fetched_at
+FROM products p
+JOIN banks b ON b.id = p.bank_id
+WHERE b.code = ? AND p.product_id = ?`, bankCode, productID,
+	).Scan(
+		&p.ProductID, &p.ProductType, &p.ProductName, &p.Description, &p.InterestRate, &minAmount, &maxAmount, &p.TermMonths,
+		&p.BankID, &p.BankCode, &p.BankName, &fetchedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Product{}, fmt.Errorf("%s: %w", op, storage.ErrProductNotFound)
+		}
+		return domain.Product{}, fmt.Errorf("%s: %w", op, err)
+	}
+	p.MinAmount = fromKopecks(minAmount)
+	if maxAmount != nil {
+		p.MaxAmount = fromKopecks(*maxAmount)
+	}
+	p.FetchedAt, _ = sqliteutils.ParseTS(fetchedAt)
+	return p, nil
+}
+
+// History returns changes of the product parameters (oldest first)
+func (r *ProductRepo) History(ctx context.Context, bankID int64, productID string) ([]domain.ProductChange, error) {
+	const op = "storage.sqlite.product.History"
+
+	rows, err := r.db.QueryContext(ctx, `
+SELECT h.id, h.bank_id, b.code, h.product_id, COALESCE(p.name, ''), h.field, h.old_value, h.new_value, h.changed_at
+FROM product_history h
+JOIN banks b ON b.id = h.bank_id
+LEFT JOIN products p ON p.bank_id = h.bank_id AND p.product_id = h.product_id
+WHERE h.bank_id = ? AND h.product_id = ?
+ORDER BY h.changed_at, h.id`, bankID, productID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.ProductChange, 0, 16)
+	for rows.Next() {
+		var (
+			c         domain.ProductChange
+			changedAt string
+		)
+		if err := rows.Scan(&c.ID, &c.BankID, &c.BankCode, &c.ProductID, &c.ProductName, &c.Field, &c.OldValue, &c.NewValue, &changedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		c.ChangedAt, _ = sqliteutils.ParseTS(changedAt)
+		out = append(out, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return out, nil
+}
+
+// Subscribe adds the product subscription of the user (idempotent)
+func (r *ProductRepo) Subscribe(ctx context.Context, userID, bankID int64, productID string) error {
+	const op = "storage.sqlite.product.Subscribe"
+
+	_, err := r.db.ExecContext(ctx, `
+INSERT INTO product_subscriptions (user_id, bank_id, product_id)
+VALUES (?, ?, ?)
+ON CONFLICT(user_id, bank_id, product_id) DO NOTHING`, userID, bankID, productID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// Unsubscribe removes the product subscription of the user
+func (r *ProductRepo) Unsubscribe(ctx context.Context, userID, bankID int64, productID string) error {
+	const op = "storage.sqlite.product.Unsubscribe"
+
+	_, err := r.db.ExecContext(ctx, `
+DELETE FROM product_subscriptions WHERE user_id = ? AND bank_id = ? AND product_id = ?`, userID, bankID, productID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// ListSubscriptions returns product subscriptions of the user (newest first)
+func (r *ProductRepo) ListSubscriptions(ctx context.Context, userID int64) ([]domain.ProductSubscription, error) {
+	const op = "storage.sqlite.product.ListSubscriptions"
+
+	rows, err := r.db.QueryContext(ctx, `
+SELECT s.user_id, s.bank_id, b.code, s.product_id, COALESCE(p.name, ''), s.created_at
+FROM product_subscriptions s
+JOIN banks b ON b.id = s.bank_id
+LEFT JOIN products p ON p.bank_id = s.bank_id AND p.product_id = s.product_id
+WHERE s.user_id = ?
+ORDER BY s.created_at DESC, s.product_id`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.ProductSubscription, 0, 8)
+	for rows.Next() {
+		var (
+			s         domain.ProductSubscription
+			createdAt string
+		)
+		if err := rows.Scan(&s.UserID, &s.BankID, &s.BankCode, &s.ProductID, &s.ProductName, &createdAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		s.CreatedAt, _ = sqliteutils.ParseTS(createdAt)
+		out = append(out, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return out, nil
+}
+
+// ListSubscribers returns ids of users subscribed to the product
+func (r *ProductRepo) ListSubscribers(ctx context.Context, bankID int64, productID string) ([]int64, error) {
+	const op = "storage.sqlite.product.ListSubscribers"
+
+	rows, err := r.db.QueryContext(ctx, `
+SELECT user_id FROM product_subscriptions WHERE bank_id = ? AND product_id = ? ORDER BY user_id`, bankID, productID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	out := make([]int64, 0, 8)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		out = append(out, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return out, nil
+}
@@ -0,0 +1,102 @@
+// internal/storage/sqlite/recommendation_event.go
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"multibank/backend/internal/domain"
+	sqliteutils "multibank/backend/internal/storage/sqlite/utils"
+	"time"
+)
+
+type RecommendationEventRepo struct {
+	db *sql.DB
+}
+
+func NewRecommendationEventRepo(db *sql.DB) *RecommendationEventRepo {
+	return &RecommendationEventRepo{db: db}
+}
+
+// Add saves impressions or clicks in one transaction
+func (r *RecommendationEventRepo) Add(ctx context.Context, events []domain.RecommendationEvent) error {
+	const op = "storage.sqlite.recommendation_event.Add"
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.PrepareContext(ctx, `
+INSERT INTO recommendation_events (kind, user_id, bank_code, product_id, product_type, rule_id, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	for _, e := range events {
+		at := e.CreatedAt
+		if at.IsZero() {
+			at = time.Now()
+		}
+		if _, err := stmt.ExecContext(ctx, string(e.Kind), e.UserID, e.BankCode, e.ProductID, e.ProductType, e.RuleID,
+			at.UTC().Format(sqliteutils.TsLayout)); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// Stats returns impressions and clicks grouped by day, rule and bank (newest day first)
+func (r *RecommendationEventRepo) Stats(ctx context.Context, from, to *time.Time) ([]domain.RecommendationStat, error) {
+	const op = "storage.sqlite.recommendation_event.Stats"
+
+	q := `
+SELECT date(e.created_at) AS day, e.rule_id, COALESCE(r.name, ''), e.bank_code,
+       SUM(e.kind = 'impression'), SUM(e.kind = 'click')
+FROM recommendation_events e
+LEFT JOIN recommendation_rules r ON r.id = e.rule_id
+WHERE 1 = 1`
+	args := make([]any, 0, 2)
+	if from != nil {
+		q += ` AND e.created_at >= ?`
+		args = append(args, from.UTC().Format(sqliteutils.TsLayout))
+	}
+	if to != nil {
+		q += ` AND e.created_at <= ?`
+		args = append(args, to.UTC().Format(sqliteutils.TsLayout))
+	}
+	q += `
+GROUP BY day, e.rule_id, e.bank_code
+ORDER BY day DESC, e.rule_id, e.bank_code`
+
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.RecommendationStat, 0, 32)
+	for rows.Next() {
+		var s domain.RecommendationStat
+		if err := rows.Scan(&s.Day, &s.RuleID, &s.RuleName, &s.BankCode, &s.Impressions, &s.Clicks); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		out = append(out, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return out, nil
+}
@@ -0,0 +1,161 @@
+// internal/storage/sqlite/recommendation_rule.go
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/storage"
+	sqliteutils "multibank/backend/internal/storage/sqlite/utils"
+)
+
+type RecommendationRuleRepo struct {
+	db *sql.DB
+}
+
+func NewRecommendationRuleRepo(db *sql.DB) *RecommendationRuleRepo {
+	return &RecommendationRuleRepo{db: db}
+}
+
+const ruleCols = `id, name, bank_code, product_id, product_type, min_age, max_age, min_balance, max_balance,
+bank_connection, priority, active_from, active_to, reason, created_at, updated_at`
+
+func scanRule(s rowScanner) (domain.RecommendationRule, error) {
+	var (
+		r                      domain.RecommendationRule
+		minBalance, maxBalance *int64
+		activeFrom, activeTo   *string
+		createdAt, updatedAt   string
+	)
+	if err := s.Scan(
+		&r.ID, &r.Name, &r.BankCode, &r.ProductID, &r.ProductType, &r.MinAge, &r.MaxAge, &minBalance, &maxBalance,
+		&r.BankConnection, &r.Priority, &activeFrom, &activeTo, &r.Reason, &createdAt, &updatedAt,
+	); err != nil {
+		return domain.RecommendationRule{}, err
+	}
+	if minBalance != nil {
+		v := fromKopecks(*minBalance)
+		r.MinBalance = &v
+	}
+	if maxBalance != nil {
+		v := fromKopecks(*maxBalance)
+		r.MaxBalance = &v
+	}
+	r.ActiveFrom = parseTSPtr(activeFrom)
+	r.ActiveTo = parseTSPtr(activeTo)
+	r.CreatedAt, _ = sqliteutils.ParseTS(createdAt)
+	r.UpdatedAt, _ = sqliteutils.ParseTS(updatedAt)
+	return r, nil
+}
+
+func kopecksPtr(v *float64) *int64 {
+	if v == nil {
+		return nil
+	}
+	k := toKopecks(*v)
+	return &k
+}
+
+// List returns all rules (the highest priority first)
+func (r *RecommendationRuleRepo) List(ctx context.Context) ([]domain.RecommendationRule, error) {
+	const op = "storage.sqlite.recommendation_rule.List"
+
+	rows, err := r.db.QueryContext(ctx, `SELECT `+ruleCols+` FROM recommendation_rules ORDER BY priority DESC, id`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.RecommendationRule, 0, 16)
+	for rows.Next() {
+		rule, err := scanRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		out = append(out, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return out, nil
+}
+
+func (r *RecommendationRuleRepo) GetByID(ctx context.Context, id int64) (domain.RecommendationRule, error) {
+	const op = "storage.sqlite.recommendation_rule.GetByID"
+
+	rule, err := scanRule(r.db.QueryRowContext(ctx, `SELECT `+ruleCols+` FROM recommendation_rules WHERE id = ?`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.RecommendationRule{}, fmt.Errorf("%s: %w", op, storage.ErrRuleNotFound)
+		}
+		return domain.RecommendationRule{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return rule, nil
+}
+
+func (r *RecommendationRuleRepo) Create(ctx context.Context, rule *domain.RecommendationRule) (int64, error) {
+	const op = "storage.sqlite.recommendation_rule.Create"
+
+	res, err := r.db.ExecContext(ctx, `
+INSERT INTO recommendation_rules
+(name, bank_code, product_id, product_type, min_age, max_age, min_balance, max_balance,
+ bank_connection, priority, active_from, active_to, reason)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		rule.Name, rule.BankCode, rule.ProductID, rule.ProductType, rule.MinAge, rule.MaxAge,
+		kopecksPtr(rule.MinBalance), kopecksPtr(rule.MaxBalance), string(rule.BankConnection), rule.Priority,
+		formatTSPtr(rule.ActiveFrom), formatTSPtr(rule.ActiveTo), rule.Reason,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return res.LastInsertId()
+}
+
+func (r *RecommendationRuleRepo) Update(ctx context.Context, rule *domain.RecommendationRule) error {
+	const op = "storage.sqlite.recommendation_rule.Update"
+
+	res, err := r.db.ExecContext(ctx, `
+UPDATE recommendation_rules
+SET name            = ?,
+    bank_code       = ?,
+    product_id      = ?,
+    product_type    = ?,
+    min_age         = ?,
+    max_age         = ?,
+    min_balance     = ?,
+    max_balance     = ?,
+    bank_connection = ?,
+    priority        = ?,
+    active_from     = ?,
+    active_to       = ?,
+    reason          = ?,
+    updated_at      = datetime('now')
+WHERE id = ?`,
+		rule.Name, rule.BankCode, rule.ProductID, rule.ProductType, rule.MinAge, rule.MaxAge,
+		kopecksPtr(rule.MinBalance), kopecksPtr(rule.MaxBalance), string(rule.BankConnection), rule.Priority,
+		formatTSPtr(rule.ActiveFrom), formatTSPtr(rule.ActiveTo), rule.Reason, rule.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrRuleNotFound)
+	}
+	return nil
+}
+
+func (r *RecommendationRuleRepo) Delete(ctx context.Context, id int64) error {
+	const op = "storage.sqlite.recommendation_rule.Delete"
+
+	res, err := r.db.ExecContext(ctx, `DELETE FROM recommendation_rules WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrRuleNotFound)
+	}
+	return nil
+}
@@ -49,7 +49,9 @@ func New(storagePath string) (*Storage, error) {
 	_ = f.Close()
 
 	// 4) connection string (busy_timeout, foreign_keys и т.п.)
-	dsn := fmt.Sprintf("file:%s?_pragma=busy_timeout(5000)&_pragma=journal_mode(DELETE)&_pragma=foreign_keys(ON)", storagePath)
+	// transactions take the write lock on BEGIN: a deferred transaction that reads and then writes
+	// (e.g. ReplaceBank of concurrent bank refreshes) fails with SQLITE_BUSY without waiting for busy_timeout
+	dsn := fmt.Sprintf("file:%s?_pragma=busy_timeout(5000)&_pragma=journal_mode(DELETE)&_pragma=foreign_keys(ON)&_txlock=immediate", storagePath)
 
 	db, err := sql.Open("sqlite", dsn)
 	if err != nil {
@@ -142,30 +144,17 @@ CREATE TABLE IF NOT EXISTS banks(
 		return err
 	}
 
-	// add rows to banks (idempotent)
-	banks := []struct {
-		Name, Code, APIBaseURL, Login, Password string
-		Enabled                                 int
-	}{
-		{"Awesome Bank", "abank", "https://abank.open.bankingapi.ru", "team014", "<secret from the organizers>", 1},
-		{"Super Bank", "sbank", "https://sbank.open.bankingapi.ru", "team014", "<secret from the organizers>", 1},
-		{"Velocity Bank", "vbank", "https://vbank.open.bankingapi.ru", "team014", "<secret from the organizers>", 1},
+	// outgoing rate limit of the bank API (token bucket in openbanking transport)
+	if err = addColumnIfMissing(ctx, tx, "banks", "rate_limit", `REAL NOT NULL DEFAULT 5`); err != nil {
+		return err
+	}
+	if err = addColumnIfMissing(ctx, tx, "banks", "rate_burst", `INTEGER NOT NULL DEFAULT 10`); err != nil {
+		return err
 	}
 
-	for _, b := range banks {
-		if _, err = tx.ExecContext(ctx, `
-INSERT INTO banks (name, code, api_base_url, login, password, is_enabled)
-VALUES (?, ?, ?, ?, ?, ?)
-ON CONFLICT(code) DO UPDATE SET
-    name         = excluded.name,
-    api_base_url = excluded.api_base_url,
-    login        = excluded.login,
-    password     = excluded.password,
-    is_enabled   = excluded.is_enabled,
-    updated_at   = datetime('now')
-`, b.Name, b.Code, b.APIBaseURL, b.Login, b.Password, b.Enabled); err != nil {
-			return err
-		}
+	// API dialect of the bank (openbanking.Adapters)
+	if err = addColumnIfMissing(ctx, tx, "banks", "adapter", `TEXT NOT NULL DEFAULT 'sandbox'`); err != nil {
+		return err
 	}
 
 	// recommended products
@@ -258,5 +247,514 @@ JOIN banks b ON b.id = c.bank_id;
 		return err
 	}
 
+	// when the consent was revoked at the bank (the row is kept as a proof)
+	if err = addColumnIfMissing(ctx, tx, "account_consents", "revoked_at", `TEXT`); err != nil {
+		return err
+	}
+
+	// transactions (history of account operations, synced from banks)
+	if _, err = tx.ExecContext(ctx, `
+CREATE TABLE IF NOT EXISTS transactions (
+  id                     INTEGER PRIMARY KEY AUTOINCREMENT,
+  bank_id                INTEGER NOT NULL,
+  account_id             TEXT    NOT NULL,
+  transaction_id         TEXT    NOT NULL,
+  amount                 TEXT    NOT NULL,
+  currency               TEXT    NOT NULL,
+  credit_debit_indicator TEXT    NOT NULL, -- Credit | Debit
+  status                 TEXT    NOT NULL, -- Booked | Pending
+  booking_date_time      TEXT,             -- YYYY-MM-DD HH:MM:SS (UTC)
+  value_date_time        TEXT,             -- YYYY-MM-DD HH:MM:SS (UTC)
+  info                   TEXT    NOT NULL DEFAULT '',
+  bank_transaction_code  TEXT    NOT NULL DEFAULT '',
+
+  created_at             TEXT    NOT NULL DEFAULT (datetime('now')),
+  updated_at             TEXT    NOT NULL DEFAULT (datetime('now')),
+
+  UNIQUE (bank_id, account_id, transaction_id),
+  FOREIGN KEY (bank_id) REFERENCES banks(id)
+);
+CREATE INDEX IF NOT EXISTS idx_transactions_account_booking
+  ON transactions(bank_id, account_id, booking_date_time);
+`); err != nil {
+		return err
+	}
+
+	// which user's accounts are synced and when (also used as an ownership check)
+	if _, err = tx.ExecContext(ctx, `
+CREATE TABLE IF NOT EXISTS transaction_sync_state (
+  user_id        INTEGER NOT NULL,
+  bank_id        INTEGER NOT NULL,
+  account_id     TEXT    NOT NULL,
+  last_synced_at TEXT    NOT NULL,
+
+  PRIMARY KEY (bank_id, account_id, user_id),
+  FOREIGN KEY (user_id) REFERENCES users(id),
+  FOREIGN KEY (bank_id) REFERENCES banks(id)
+);
+`); err != nil {
+		return err
+	}
+
+	// bank accounts of users (refreshed from banks in background)
+	if _, err = tx.ExecContext(ctx, `
+CREATE TABLE IF NOT EXISTS bank_accounts (
+  id               INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id          INTEGER NOT NULL,
+  bank_id          INTEGER NOT NULL,
+  consent_id       INTEGER NOT NULL, -- account_consents.id
+  account_id       TEXT    NOT NULL, -- accountId in the bank
+  client_id        TEXT    NOT NULL,
+  status           TEXT    NOT NULL DEFAULT '',
+  currency         TEXT    NOT NULL DEFAULT '',
+  account_type     TEXT    NOT NULL DEFAULT '',
+  account_sub_type TEXT    NOT NULL DEFAULT '',
+  nickname         TEXT    NOT NULL DEFAULT '',
+  opening_date     TEXT    NOT NULL DEFAULT '',
+  last_synced_at   TEXT,
+
+  created_at       TEXT    NOT NULL DEFAULT (datetime('now')),
+  updated_at       TEXT    NOT NULL DEFAULT (datetime('now')),
+
+  UNIQUE (user_id, bank_id, account_id),
+  FOREIGN KEY (user_id) REFERENCES users(id),
+  FOREIGN KEY (bank_id) REFERENCES banks(id),
+  FOREIGN KEY (consent_id) REFERENCES account_consents(id) ON DELETE CASCADE
+);
+`); err != nil {
+		return err
+	}
+
+	// balances of bank accounts (one row per balance type)
+	if _, err = tx.ExecContext(ctx, `
+CREATE TABLE IF NOT EXISTS balances (
+  id                     INTEGER PRIMARY KEY AUTOINCREMENT,
+  bank_account_id        INTEGER NOT NULL,
+  type                   TEXT    NOT NULL, -- InterimAvailable | InterimBooked | ...
+  amount                 TEXT    NOT NULL,
+  currency               TEXT    NOT NULL,
+  credit_debit_indicator TEXT    NOT NULL DEFAULT '',
+  date_time              TEXT,
+  updated_at             TEXT    NOT NULL DEFAULT (datetime('now')),
+
+  UNIQUE (bank_account_id, type),
+  FOREIGN KEY (bank_account_id) REFERENCES bank_accounts(id) ON DELETE CASCADE
+);
+`); err != nil {
+		return err
+	}
+
+	// credit lines of balances (JSON array)
+	if err = addColumnIfMissing(ctx, tx, "balances", "credit_lines_json", `TEXT NOT NULL DEFAULT '[]'`); err != nil {
+		return err
+	}
+
+	// account number of bank accounts (needed for payments)
+	if err = addColumnIfMissing(ctx, tx, "bank_accounts", "identification", `TEXT NOT NULL DEFAULT ''`); err != nil {
+		return err
+	}
+
+	// official FX rates (RUB per 1 unit of currency), imported from the Bank of Russia
+	if _, err = tx.ExecContext(ctx, `
+CREATE TABLE IF NOT EXISTS fx_rates (
+  date        TEXT    NOT NULL, -- YYYY-MM-DD
+  currency    TEXT    NOT NULL, -- ISO code, e.g. USD
+  nominal     INTEGER NOT NULL,
+  value       REAL    NOT NULL, -- RUB for nominal units
+  rate        REAL    NOT NULL, -- RUB for 1 unit
+  source      TEXT    NOT NULL DEFAULT '',
+  imported_at TEXT    NOT NULL DEFAULT (datetime('now')),
+
+  PRIMARY KEY (currency, date)
+);
+`); err != nil {
+		return err
+	}
+
+	// daily snapshots of account balances (for charts), kept after the account is removed
+	if _, err = tx.ExecContext(ctx, `
+CREATE TABLE IF NOT EXISTS balance_snapshots (
+  id           INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id      INTEGER NOT NULL,
+  bank_id      INTEGER NOT NULL,
+  account_id   TEXT    NOT NULL,
+  date         TEXT    NOT NULL, -- YYYY-MM-DD (UTC)
+  amount       INTEGER NOT NULL, -- primary balance in kopecks (minor units), signed
+  currency     TEXT    NOT NULL,
+  balance_type TEXT    NOT NULL DEFAULT '',
+  taken_at     TEXT    NOT NULL, -- YYYY-MM-DD HH:MM:SS (UTC)
+
+  UNIQUE (user_id, bank_id, account_id, date),
+  FOREIGN KEY (user_id) REFERENCES users(id),
+  FOREIGN KEY (bank_id) REFERENCES banks(id)
+);
+CREATE INDEX IF NOT EXISTS idx_balance_snapshots_user_date
+  ON balance_snapshots(user_id, date);
+`); err != nil {
+		return err
+	}
+	// amount was REAL (rubles) in earlier versions
+	if err = balanceSnapshotsToKopecks(ctx, tx); err != nil {
+		return err
+	}
+
+	// payment consents (one consent = one payment, single_use)
+	if _, err = tx.ExecContext(ctx, `
+CREATE TABLE IF NOT EXISTS payment_consents (
+  id                     INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id                INTEGER NOT NULL,
+  bank_id                INTEGER NOT NULL, -- bank of the debtor account
+  request_id             TEXT    NOT NULL UNIQUE,
+  consent_id             TEXT    UNIQUE,
+  status                 TEXT    NOT NULL, -- Authorized | AwaitingAuthorization | Rejected | Revoked
+  auto_approved          INTEGER,          -- NULL | 0 | 1
+  client_id              TEXT    NOT NULL,
+  requesting_bank        TEXT    NOT NULL,
+  consent_type           TEXT    NOT NULL DEFAULT 'single_use',
+
+  amount                 TEXT    NOT NULL,
+  currency               TEXT    NOT NULL,
+  debtor_account         TEXT    NOT NULL,
+  creditor_account       TEXT    NOT NULL,
+  creditor_name          TEXT    NOT NULL DEFAULT '',
+  creditor_bank_code     TEXT    NOT NULL DEFAULT '',
+  reference              TEXT    NOT NULL DEFAULT '',
+
+  creation_datetime      TEXT,
+  status_update_datetime TEXT,
+  expiration_datetime    TEXT,
+
+  created_at             TEXT    NOT NULL DEFAULT (datetime('now')),
+  updated_at             TEXT    NOT NULL DEFAULT (datetime('now')),
+
+  FOREIGN KEY (user_id) REFERENCES users(id),
+  FOREIGN KEY (bank_id) REFERENCES banks(id)
+);
+`); err != nil {
+		return err
+	}
+
+	// payments
+	if _, err = tx.ExecContext(ctx, `
+CREATE TABLE IF NOT EXISTS payments (
+  id                     INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id                INTEGER NOT NULL,
+  bank_id                INTEGER NOT NULL,
+  payment_consent_id     INTEGER NOT NULL, -- payment_consents.id
+  payment_id             TEXT    NOT NULL, -- id in the bank
+  status                 TEXT    NOT NULL, -- Initiating | AcceptedSettlementInProcess | AcceptedSettlementCompleted | Rejected ...
+
+  amount                 TEXT    NOT NULL,
+  currency               TEXT    NOT NULL,
+  debtor_account         TEXT    NOT NULL,
+  creditor_account       TEXT    NOT NULL,
+  creditor_name          TEXT    NOT NULL DEFAULT '',
+  creditor_bank_code     TEXT    NOT NULL DEFAULT '',
+  comment                TEXT    NOT NULL DEFAULT '',
+
+  creation_datetime      TEXT,
+  status_update_datetime TEXT,
+
+  created_at             TEXT    NOT NULL DEFAULT (datetime('now')),
+  updated_at             TEXT    NOT NULL DEFAULT (datetime('now')),
+
+  UNIQUE (bank_id, payment_id),
+  FOREIGN KEY (user_id) REFERENCES users(id),
+  FOREIGN KEY (bank_id) REFERENCES banks(id),
+  FOREIGN KEY (payment_consent_id) REFERENCES payment_consents(id)
+);
+-- a payment consent is single-use: one payment row, reserved before the bank is called
+CREATE UNIQUE INDEX IF NOT EXISTS ux_payments_consent ON payments(payment_consent_id);
+`); err != nil {
+		return err
+	}
+
+	// transfers between own accounts (payment consent + payment at the source bank)
+	if _, err = tx.ExecContext(ctx, `
+CREATE TABLE IF NOT EXISTS transfers (
+  id                 INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id            INTEGER NOT NULL,
+  source_bank_id     INTEGER NOT NULL,
+  source_account_id  TEXT    NOT NULL,
+  target_bank_id     INTEGER NOT NULL,
+  target_account_id  TEXT    NOT NULL,
+  amount             TEXT    NOT NULL,
+  currency           TEXT    NOT NULL,
+  status             TEXT    NOT NULL, -- AwaitingAuthorization | Initiating | Processing | Completed | Failed
+  payment_consent_id INTEGER,          -- payment_consents.id
+  payment_id         INTEGER,          -- payments.id
+  error              TEXT    NOT NULL DEFAULT '',
+
+  created_at         TEXT    NOT NULL DEFAULT (datetime('now')),
+  updated_at         TEXT    NOT NULL DEFAULT (datetime('now')),
+  completed_at       TEXT,
+
+  FOREIGN KEY (user_id) REFERENCES users(id),
+  FOREIGN KEY (source_bank_id) REFERENCES banks(id),
+  FOREIGN KEY (target_bank_id) REFERENCES banks(id),
+  FOREIGN KEY (payment_consent_id) REFERENCES payment_consents(id),
+  FOREIGN KEY (payment_id) REFERENCES payments(id)
+);
+CREATE INDEX IF NOT EXISTS idx_transfers_status ON transfers(status);
+`); err != nil {
+		return err
+	}
+
+	// product agreement consents (one authorized consent per user and bank is reused)
+	if _, err = tx.ExecContext(ctx, `
+CREATE TABLE IF NOT EXISTS product_agreement_consents (
+  id                     INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id                INTEGER NOT NULL,
+  bank_id                INTEGER NOT NULL,
+  request_id             TEXT    NOT NULL UNIQUE,
+  consent_id             TEXT    UNIQUE,
+  status                 TEXT    NOT NULL, -- Authorized | AwaitingAuthorization | Rejected | Revoked
+  auto_approved          INTEGER,          -- NULL | 0 | 1
+  client_id              TEXT    NOT NULL,
+  requesting_bank        TEXT    NOT NULL,
+
+  creation_datetime      TEXT,
+  status_update_datetime TEXT,
+  expiration_datetime    TEXT,
+
+  created_at             TEXT    NOT NULL DEFAULT (datetime('now')),
+  updated_at             TEXT    NOT NULL DEFAULT (datetime('now')),
+
+  FOREIGN KEY (user_id) REFERENCES users(id),
+  FOREIGN KEY (bank_id) REFERENCES banks(id)
+);
+CREATE INDEX IF NOT EXISTS idx_agreement_consents_user_bank ON product_agreement_consents(user_id, bank_id);
+`); err != nil {
+		return err
+	}
+
+	// product agreements (deposits, cards, loans opened by users)
+	if _, err = tx.ExecContext(ctx, `
+CREATE TABLE IF NOT EXISTS product_agreements (
+  id                INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id           INTEGER NOT NULL,
+  bank_id           INTEGER NOT NULL,
+  consent_id        INTEGER NOT NULL, -- product_agreement_consents.id
+  agreement_id      TEXT    NOT NULL, -- id in the bank
+  product_id        TEXT    NOT NULL,
+  product_type      TEXT    NOT NULL DEFAULT '',
+  product_name      TEXT    NOT NULL DEFAULT '',
+
+  amount            TEXT    NOT NULL DEFAULT '',
+  term_months       INTEGER NOT NULL DEFAULT 0,
+  source_account_id TEXT    NOT NULL DEFAULT '',
+  account_number    TEXT    NOT NULL DEFAULT '',
+
+  status            TEXT    NOT NULL, -- active | closed
+  start_date        TEXT,
+  end_date          TEXT,
+  closed_at         TEXT,
+
+  created_at        TEXT    NOT NULL DEFAULT (datetime('now')),
+  updated_at        TEXT    NOT NULL DEFAULT (datetime('now')),
+
+  UNIQUE (bank_id, agreement_id),
+  FOREIGN KEY (user_id) REFERENCES users(id),
+  FOREIGN KEY (bank_id) REFERENCES banks(id),
+  FOREIGN KEY (consent_id) REFERENCES product_agreement_consents(id)
+);
+CREATE INDEX IF NOT EXISTS idx_product_agreements_user ON product_agreements(user_id);
+`); err != nil {
+		return err
+	}
+
+	// product catalogue of the banks (refreshed in background)
+	if _, err = tx.ExecContext(ctx, `
+CREATE TABLE IF NOT EXISTS products (
+  id            INTEGER PRIMARY KEY AUTOINCREMENT,
+  bank_id       INTEGER NOT NULL,
+  product_id    TEXT    NOT NULL, -- id in the bank
+  product_type  TEXT    NOT NULL, -- deposit | loan | card | account ...
+  name          TEXT    NOT NULL,
+  description   TEXT    NOT NULL DEFAULT '',
+  interest_rate REAL    NOT NULL DEFAULT 0, -- % per year
+  min_amount    INTEGER NOT NULL DEFAULT 0, -- in kopecks
+  max_amount    INTEGER,                    -- in kopecks, NULL = no limit
+  term_months   INTEGER NOT NULL DEFAULT 0,
+  fetched_at    TEXT    NOT NULL,
+
+  created_at    TEXT    NOT NULL DEFAULT (datetime('now')),
+  updated_at    TEXT    NOT NULL DEFAULT (datetime('now')),
+
+  UNIQUE (bank_id, product_id),
+  FOREIGN KEY (bank_id) REFERENCES banks(id)
+);
+CREATE INDEX IF NOT EXISTS idx_products_type ON products(product_type);
+`); err != nil {
+		return err
+	}
+
+	// lower-cased name and description for the catalogue search (SQLite LOWER/LIKE fold ASCII only)
+	if err = addColumnIfMissing(ctx, tx, "products", "search_text", `TEXT NOT NULL DEFAULT ''`); err != nil {
+		return err
+	}
+
+	// state of the product fetch per bank
+	if _, err = tx.ExecContext(ctx, `
+CREATE TABLE IF NOT EXISTS product_sources (
+  bank_id         INTEGER PRIMARY KEY,
+  last_attempt_at TEXT,
+  last_success_at TEXT,
+  last_error      TEXT    NOT NULL DEFAULT '',
+  failures        INTEGER NOT NULL DEFAULT 0, -- consecutive failed fetches
+  products_count  INTEGER NOT NULL DEFAULT 0,
+
+  FOREIGN KEY (bank_id) REFERENCES banks(id)
+);
+`); err != nil {
+		return err
+	}
+
+	// changes of product parameters between fetches
+	if _, err = tx.ExecContext(ctx, `
+CREATE TABLE IF NOT EXISTS product_history (
+  id         INTEGER PRIMARY KEY AUTOINCREMENT,
+  bank_id    INTEGER NOT NULL,
+  product_id TEXT    NOT NULL,
+  field      TEXT    NOT NULL, -- interest_rate | min_amount | max_amount | term_months
+  old_value  REAL    NOT NULL, -- amounts in rubles
+  new_value  REAL    NOT NULL,
+  changed_at TEXT    NOT NULL,
+
+  FOREIGN KEY (bank_id) REFERENCES banks(id)
+);
+CREATE INDEX IF NOT EXISTS idx_product_history_product ON product_history(bank_id, product_id, changed_at);
+`); err != nil {
+		return err
+	}
+
+	// users waiting for product rate changes
+	if _, err = tx.ExecContext(ctx, `
+CREATE TABLE IF NOT EXISTS product_subscriptions (
+  user_id    INTEGER NOT NULL,
+  bank_id    INTEGER NOT NULL,
+  product_id TEXT    NOT NULL,
+  created_at TEXT    NOT NULL DEFAULT (datetime('now')),
+
+  PRIMARY KEY (user_id, bank_id, product_id),
+  FOREIGN KEY (user_id) REFERENCES users(id),
+  FOREIGN KEY (bank_id) REFERENCES banks(id)
+);
+CREATE INDEX IF NOT EXISTS idx_product_subscriptions_product ON product_subscriptions(bank_id, product_id);
+`); err != nil {
+		return err
+	}
+
+	// in-app notifications of users
+	if _, err = tx.ExecContext(ctx, `
+CREATE TABLE IF NOT EXISTS notifications (
+  id         INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id    INTEGER NOT NULL,
+  kind       TEXT    NOT NULL, -- product_rate_changed ...
+  title      TEXT    NOT NULL,
+  message    TEXT    NOT NULL DEFAULT '',
+  bank_code  TEXT    NOT NULL DEFAULT '',
+  product_id TEXT    NOT NULL DEFAULT '',
+  created_at TEXT    NOT NULL DEFAULT (datetime('now')),
+  read_at    TEXT,
+
+  FOREIGN KEY (user_id) REFERENCES users(id)
+);
+CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications(user_id, id);
+`); err != nil {
+		return err
+	}
+
+	// targeting rules of recommended products (evaluated per user)
+	if _, err = tx.ExecContext(ctx, `
+CREATE TABLE IF NOT EXISTS recommendation_rules (
+  id              INTEGER PRIMARY KEY AUTOINCREMENT,
+  name            TEXT    NOT NULL,
+
+  bank_code       TEXT    NOT NULL DEFAULT '', -- '' = any
+  product_id      TEXT    NOT NULL DEFAULT '',
+  product_type    TEXT    NOT NULL DEFAULT '',
+
+  min_age         INTEGER,
+  max_age         INTEGER,
+  min_balance     INTEGER,                     -- total balance in kopecks (RUB)
+  max_balance     INTEGER,
+  bank_connection TEXT    NOT NULL DEFAULT '' CHECK (bank_connection IN ('', 'connected', 'not_connected')),
+
+  priority        INTEGER NOT NULL DEFAULT 0,
+  active_from     TEXT,
+  active_to       TEXT,
+  reason          TEXT    NOT NULL DEFAULT '',
+
+  created_at      TEXT    NOT NULL DEFAULT (datetime('now')),
+  updated_at      TEXT    NOT NULL DEFAULT (datetime('now'))
+);
+`); err != nil {
+		return err
+	}
+
+	// impressions and clicks of recommended products
+	if _, err = tx.ExecContext(ctx, `
+CREATE TABLE IF NOT EXISTS recommendation_events (
+  id           INTEGER PRIMARY KEY AUTOINCREMENT,
+  kind         TEXT    NOT NULL CHECK (kind IN ('impression', 'click')),
+  user_id      INTEGER NOT NULL,
+  bank_code    TEXT    NOT NULL,
+  product_id   TEXT    NOT NULL,
+  product_type TEXT    NOT NULL DEFAULT '',
+  rule_id      INTEGER,                     -- NULL = the static recommended list
+  created_at   TEXT    NOT NULL DEFAULT (datetime('now'))
+);
+CREATE INDEX IF NOT EXISTS idx_recommendation_events_created ON recommendation_events(created_at);
+`); err != nil {
+		return err
+	}
+
 	return tx.Commit()
 }
+
+// balanceSnapshotsToKopecks rewrites balance_snapshots.amount from REAL rubles to INTEGER kopecks, once
+func balanceSnapshotsToKopecks(ctx context.Context, tx *sql.Tx) error {
+	var typ string
+	err := tx.QueryRowContext(ctx,
+		`SELECT type FROM pragma_table_info('balance_snapshots') WHERE name = 'amount'`).Scan(&typ)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(typ, "REAL") {
+		return nil
+	}
+
+	_, err = tx.ExecContext(ctx, `
+ALTER TABLE balance_snapshots ADD COLUMN amount_kopecks INTEGER NOT NULL DEFAULT 0;
+UPDATE balance_snapshots SET amount_kopecks = CAST(ROUND(amount * 100) AS INTEGER);
+ALTER TABLE balance_snapshots DROP COLUMN amount;
+ALTER TABLE balance_snapshots RENAME COLUMN amount_kopecks TO amount;
+`)
+	return err
+}
+
+// addColumnIfMissing - idempotent ALTER TABLE ... ADD COLUMN for tables created by earlier versions
+func addColumnIfMissing(ctx context.Context, tx *sql.Tx, table, column, ddl string) error {
+	rows, err := tx.QueryContext(ctx, `SELECT name FROM pragma_table_info(?)`, table)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `ALTER TABLE `+table+` ADD COLUMN `+column+` `+ddl)
+	return err
+}
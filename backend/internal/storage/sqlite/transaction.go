@@ -0,0 +1,227 @@
+// internal/storage/sqlite/transaction.go
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"multibank/backend/internal/domain"
+	sqliteutils "multibank/backend/internal/storage/sqlite/utils"
+	"time"
+)
+
+type TransactionRepo struct {
+	db *sql.DB
+}
+
+func NewTransactionRepo(db *sql.DB) *TransactionRepo { return &TransactionRepo{db: db} }
+
+const transactionCols = `
+t.bank_id, b.code, t.account_id, t.transaction_id, t.amount, t.currency,
+t.credit_debit_indicator, t.status, t.booking_date_time, t.value_date_time,
+t.info, t.bank_transaction_code
+`
+
+func scanTransaction(rs rowScanner) (domain.Transaction, error) {
+	var (
+		t                domain.Transaction
+		booking, valueDT *string
+	)
+	if err := rs.Scan(
+		&t.BankID, &t.BankCode, &t.AccountID, &t.TransactionID, &t.Amount, &t.Currency,
+		&t.CreditDebitIndicator, &t.Status, &booking, &valueDT,
+		&t.Info, &t.BankTransactionCode,
+	); err != nil {
+		return domain.Transaction{}, err
+	}
+	t.BookingDateTime = parseTSPtr(booking)
+	t.ValueDateTime = parseTSPtr(valueDT)
+	return t, nil
+}
+
+// UpsertMany saves a batch of transactions of one bank in a single db transaction.
+// Existing rows (same bank_id, account_id, transaction_id) are updated, so repeated syncs do not create duplicates.
+// Returns the number of rows that were inserted or actually changed.
+func (r *TransactionRepo) UpsertMany(ctx context.Context, bankID int64, items []domain.Transaction) (int, error) {
+	const op = "storage.sqlite.transaction.UpsertMany"
+
+	if len(items) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.PrepareContext(ctx, `
+INSERT INTO transactions
+(bank_id, account_id, transaction_id, amount, currency, credit_debit_indicator, status,
+ booking_date_time, value_date_time, info, bank_transaction_code)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(bank_id, account_id, transaction_id) DO UPDATE SET
+    amount                 = excluded.amount,
+    currency               = excluded.currency,
+    credit_debit_indicator = excluded.credit_debit_indicator,
+    status                 = excluded.status,
+    booking_date_time      = excluded.booking_date_time,
+    value_date_time        = excluded.value_date_time,
+    info                   = excluded.info,
+    bank_transaction_code  = excluded.bank_transaction_code,
+    updated_at             = datetime('now')
+WHERE transactions.status IS NOT excluded.status
+   OR transactions.amount IS NOT excluded.amount
+   OR transactions.booking_date_time IS NOT excluded.booking_date_time
+   OR transactions.value_date_time IS NOT excluded.value_date_time
+   OR transactions.info IS NOT excluded.info`)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	n := 0
+	for _, t := range items {
+		res, err := stmt.ExecContext(ctx,
+			bankID, t.AccountID, t.TransactionID, t.Amount, t.Currency, t.CreditDebitIndicator, t.Status,
+			formatTSPtr(t.BookingDateTime), formatTSPtr(t.ValueDateTime), t.Info, t.BankTransactionCode,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+		if affected, err := res.RowsAffected(); err == nil {
+			n += int(affected)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return n, nil
+}
+
+// LastBookingDate returns the latest stored booking date of the account (nil if nothing is stored yet)
+func (r *TransactionRepo) LastBookingDate(ctx context.Context, bankID int64, accountID string) (*time.Time, error) {
+	const op = "storage.sqlite.transaction.LastBookingDate"
+
+	var last *string
+	err := r.db.QueryRowContext(ctx, `
+SELECT MAX(booking_date_time) FROM transactions
+WHERE bank_id = ? AND account_id = ?`, bankID, accountID).Scan(&last)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return parseTSPtr(last), nil
+}
+
+// MarkSynced remembers that the account of the user was synced at the given time
+func (r *TransactionRepo) MarkSynced(ctx context.Context, userID, bankID int64, accountID string, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+INSERT INTO transaction_sync_state (user_id, bank_id, account_id, last_synced_at)
+VALUES (?, ?, ?, ?)
+ON CONFLICT(bank_id, account_id, user_id) DO UPDATE SET
+    last_synced_at = excluded.last_synced_at`,
+		userID, bankID, accountID, at.UTC().Format(sqliteutils.TsLayout),
+	)
+	if err != nil {
+		return fmt.Errorf("storage.sqlite.transaction.MarkSynced: %w", err)
+	}
+	return nil
+}
+
+// LastSyncedAt returns the last sync time of the user's account (nil if the account was never synced for the user).
+// If bankID == nil, the account is searched in all banks.
+func (r *TransactionRepo) LastSyncedAt(ctx context.Context, userID int64, bankID *int64, accountID string) (*time.Time, error) {
+	const op = "storage.sqlite.transaction.LastSyncedAt"
+
+	q := `SELECT MAX(last_synced_at) FROM transaction_sync_state WHERE user_id = ? AND account_id = ?`
+	args := []any{userID, accountID}
+	if bankID != nil {
+		q += ` AND bank_id = ?`
+		args = append(args, *bankID)
+	}
+
+	var last *string
+	if err := r.db.QueryRowContext(ctx, q, args...).Scan(&last); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return parseTSPtr(last), nil
+}
+
+// ListByUserAccount returns a page of stored transactions of the user's account (newest first) and the total count.
+// Only accounts synced for this user (transaction_sync_state) are visible.
+func (r *TransactionRepo) ListByUserAccount(ctx context.Context, userID int64, bankID *int64, accountID string, f domain.TransactionFilter) ([]domain.Transaction, int, error) {
+	const op = "storage.sqlite.transaction.ListByUserAccount"
+
+	where := `
+FROM transactions t
+JOIN banks b ON b.id = t.bank_id
+JOIN transaction_sync_state s
+  ON s.bank_id = t.bank_id AND s.account_id = t.account_id AND s.user_id = ?
+WHERE t.account_id = ?`
+	args := []any{userID, accountID}
+	if bankID != nil {
+		where += ` AND t.bank_id = ?`
+		args = append(args, *bankID)
+	}
+	if f.From != nil {
+		where += ` AND t.booking_date_time >= ?`
+		args = append(args, f.From.UTC().Format(sqliteutils.TsLayout))
+	}
+	if f.To != nil {
+		where += ` AND t.booking_date_time <= ?`
+		args = append(args, f.To.UTC().Format(sqliteutils.TsLayout))
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	q := `SELECT ` + transactionCols + where + ` ORDER BY t.booking_date_time DESC, t.id DESC LIMIT ? OFFSET ?`
+	args = append(args, f.Limit, (f.Page-1)*f.Limit)
+
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.Transaction, 0, f.Limit)
+	for rows.Next() {
+		t, err := scanTransaction(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%s: %w", op, err)
+		}
+		out = append(out, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return out, total, nil
+}
+
+// helpers for nullable timestamps in TsLayout (sortable and comparable with datetime())
+func formatTSPtr(t *time.Time) *string {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+	s := t.UTC().Format(sqliteutils.TsLayout)
+	return &s
+}
+
+func parseTSPtr(s *string) *time.Time {
+	if s == nil || *s == "" {
+		return nil
+	}
+	t, err := sqliteutils.ParseTS(*s)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,160 @@
+// internal/storage/sqlite/transfer.go
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/storage"
+	sqliteutils "multibank/backend/internal/storage/sqlite/utils"
+)
+
+type TransferRepo struct {
+	db *sql.DB
+}
+
+func NewTransferRepo(db *sql.DB) *TransferRepo { return &TransferRepo{db: db} }
+
+const transferCols = `
+t.id, t.user_id,
+t.source_bank_id, sb.code, t.source_account_id,
+t.target_bank_id, tb.code, t.target_account_id,
+t.amount, t.currency, t.status, t.payment_consent_id, t.payment_id, t.error,
+t.created_at, t.updated_at, t.completed_at
+`
+
+const transferFrom = `
+FROM transfers t
+JOIN banks sb ON sb.id = t.source_bank_id
+JOIN banks tb ON tb.id = t.target_bank_id
+`
+
+func scanTransfer(rs rowScanner) (domain.Transfer, error) {
+	var (
+		t                    domain.Transfer
+		createdAt, updatedAt string
+		completedAt          *string
+	)
+	if err := rs.Scan(
+		&t.ID, &t.UserID,
+		&t.SourceBankID, &t.SourceBankCode, &t.SourceAccountID,
+		&t.TargetBankID, &t.TargetBankCode, &t.TargetAccountID,
+		&t.Amount, &t.Currency, &t.Status, &t.PaymentConsentID, &t.PaymentID, &t.Error,
+		&createdAt, &updatedAt, &completedAt,
+	); err != nil {
+		return domain.Transfer{}, err
+	}
+	t.CreatedAt, _ = sqliteutils.ParseTS(createdAt)
+	t.UpdatedAt, _ = sqliteutils.ParseTS(updatedAt)
+	t.CompletedAt = parseTSPtr(completedAt)
+	return t, nil
+}
+
+// Create saves a new transfer and returns its id
+func (r *TransferRepo) Create(ctx context.Context, t *domain.Transfer) (int64, error) {
+	const op = "storage.sqlite.transfer.Create"
+
+	res, err := r.db.ExecContext(ctx, `
+INSERT INTO transfers
+(user_id, source_bank_id, source_account_id, target_bank_id, target_account_id, amount, currency, status)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		t.UserID, t.SourceBankID, t.SourceAccountID, t.TargetBankID, t.TargetAccountID, t.Amount, t.Currency, string(t.Status),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return res.LastInsertId()
+}
+
+// Update saves the lifecycle fields of the transfer (status, payment ids, error, completed_at)
+func (r *TransferRepo) Update(ctx context.Context, t domain.Transfer) error {
+	const op = "storage.sqlite.transfer.Update"
+
+	_, err := r.db.ExecContext(ctx, `
+UPDATE transfers
+SET status             = ?,
+    payment_consent_id = ?,
+    payment_id         = ?,
+    error              = ?,
+    completed_at       = ?,
+    updated_at         = datetime('now')
+WHERE id = ?`,
+		string(t.Status), t.PaymentConsentID, t.PaymentID, t.Error, formatTSPtr(t.CompletedAt), t.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// ClaimInitiation moves the transfer from AwaitingAuthorization to Initiating.
+// Only one caller gets true: it is the one to initiate the payment.
+func (r *TransferRepo) ClaimInitiation(ctx context.Context, id int64) (bool, error) {
+	const op = "storage.sqlite.transfer.ClaimInitiation"
+
+	res, err := r.db.ExecContext(ctx, `
+UPDATE transfers
+SET status     = ?,
+    updated_at = datetime('now')
+WHERE id = ? AND status = ?`,
+		string(domain.TransferInitiating), id, string(domain.TransferAwaitingAuthorization),
+	)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	return n == 1, nil
+}
+
+// GetByID returns the transfer or storage.ErrTransferNotFound
+func (r *TransferRepo) GetByID(ctx context.Context, id int64) (domain.Transfer, error) {
+	const op = "storage.sqlite.transfer.GetByID"
+
+	t, err := scanTransfer(r.db.QueryRowContext(ctx, `SELECT `+transferCols+transferFrom+`WHERE t.id = ?`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Transfer{}, fmt.Errorf("%s: %w", op, storage.ErrTransferNotFound)
+		}
+		return domain.Transfer{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return t, nil
+}
+
+// ListByUser returns transfers of the user (newest first)
+func (r *TransferRepo) ListByUser(ctx context.Context, userID int64) ([]domain.Transfer, error) {
+	return r.list(ctx, "storage.sqlite.transfer.ListByUser",
+		`WHERE t.user_id = ? ORDER BY t.id DESC`, userID)
+}
+
+// ListInFlight returns up to limit transfers that are not finished yet (oldest first)
+func (r *TransferRepo) ListInFlight(ctx context.Context, limit int) ([]domain.Transfer, error) {
+	return r.list(ctx, "storage.sqlite.transfer.ListInFlight",
+		`WHERE t.status IN ('AwaitingAuthorization', 'Initiating', 'Processing') ORDER BY t.updated_at, t.id LIMIT ?`, limit)
+}
+
+func (r *TransferRepo) list(ctx context.Context, op, where string, args ...any) ([]domain.Transfer, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+transferCols+transferFrom+where, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.Transfer, 0, 16)
+	for rows.Next() {
+		t, err := scanTransfer(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		out = append(out, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return out, nil
+}
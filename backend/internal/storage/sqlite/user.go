@@ -101,3 +101,22 @@ FROM users WHERE email = ?`, email).Scan(
 	}
 	return u, nil
 }
+
+// SetAdmin grants or revokes admin rights of the user with the email
+func (r *UserRepo) SetAdmin(ctx context.Context, email string, admin bool) error {
+	const op = "storage.sqlite.user.SetAdmin"
+
+	res, err := r.db.ExecContext(ctx, `
+UPDATE users SET is_admin = ?, updated_at = datetime('now') WHERE email = ?`, admin, email)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+// tests/accounts_e2e_test.go
+
+package tests
+
+import (
+	"fmt"
+	"multibank/backend/internal/http-server/dto"
+	"net/http"
+	"testing"
+
+	"multibank/backend/tests/suite"
+	testutils "multibank/backend/tests/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestHTTP_AccountSyncOffline keeps accounts and balances in the local store: the sync refreshes balances,
+// removes accounts closed in the bank and keeps the stored data while the bank is down
+func TestHTTP_AccountSyncOffline(t *testing.T) {
+	st := suite.NewOffline(t)
+	defer st.Cancel()
+
+	token := testutils.
+		PostWithBody(t, st, "/auth/register", testutils.NewFakeUser()).
+		ExpectStatus(t, http.StatusCreated).
+		DecodeTokenResponse(t).AccessToken
+
+	testutils.PostWithAuth(t, st, "/consents/request", token, dto.ConsentCreateRequest{
+		BankCode: "vbank",
+		ClientID: "team014-1",
+	}).ExpectStatus(t, http.StatusCreated)
+
+	bank, err := st.BankService.GetBankByCode(st.Ctx, "vbank")
+	require.NoError(t, err)
+	fake := st.FakeBanks["vbank"]
+
+	list := func(t *testing.T) map[string]dto.AccountResponse {
+		t.Helper()
+		resp := testutils.GetWithAuth(t, st, fmt.Sprintf("/accounts?bank_id=%d", bank.ID), token).
+			ExpectStatus(t, http.StatusOK).Resp
+		out := map[string]dto.AccountResponse{}
+		for _, a := range testutils.DecodeJSON[[]dto.AccountResponse](t, resp) {
+			out[a.AccountID] = a
+		}
+		return out
+	}
+
+	var checking, savings string
+	t.Run("sync -> accounts with balances are stored", func(t *testing.T) {
+		n, err := st.AccountService.SyncAccounts(st.Ctx, 2)
+		require.NoError(t, err)
+		require.Equal(t, 2, n)
+
+		accounts := list(t)
+		require.Len(t, accounts, 2)
+		for id, a := range accounts {
+			require.NotNil(t, a.LastSyncedAt)
+			require.False(t, a.Stale)
+			require.NotEmpty(t, a.Balances)
+			if a.AccountSubType == "Checking" {
+				checking = id
+			} else {
+				savings = id
+			}
+		}
+		require.NotEmpty(t, checking)
+		require.NotEmpty(t, savings)
+	})
+
+	t.Run("balance changed in the bank -> refreshed by the next sync", func(t *testing.T) {
+		require.True(t, fake.SetBalance("team014-1", checking, -1500))
+		require.NotEqual(t, "-1500.00", list(t)[checking].Amount)
+
+		_, err := st.AccountService.SyncAccounts(st.Ctx, 2)
+		require.NoError(t, err)
+		require.Equal(t, "-1500.00", list(t)[checking].Amount)
+	})
+
+	t.Run("bank is down -> stored accounts are served", func(t *testing.T) {
+		fake.SetFaults(0, 0, 1)
+		n, err := st.AccountService.SyncAccounts(st.Ctx, 2)
+		fake.SetFaults(0, 0, 0)
+		require.NoError(t, err)
+		require.Zero(t, n)
+
+		accounts := list(t)
+		require.Len(t, accounts, 2)
+		require.Equal(t, "-1500.00", accounts[checking].Amount)
+	})
+
+	t.Run("account closed in the bank -> removed by the next sync", func(t *testing.T) {
+		require.True(t, fake.CloseAccount("team014-1", savings))
+
+		_, err := st.AccountService.SyncAccounts(st.Ctx, 2)
+		require.NoError(t, err)
+
+		accounts := list(t)
+		require.Len(t, accounts, 1)
+		require.Contains(t, accounts, checking)
+	})
+}
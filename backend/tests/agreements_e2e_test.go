@@ -0,0 +1,110 @@
+// tests/agreements_e2e_test.go
+
+package tests
+
+import (
+	"fmt"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/http-server/dto"
+	"net/http"
+	"testing"
+	"time"
+
+	"multibank/backend/tests/suite"
+	testutils "multibank/backend/tests/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestHTTP_ProductAgreementsOffline opens products of a connected bank, lists and closes the agreements
+func TestHTTP_ProductAgreementsOffline(t *testing.T) {
+	st := suite.NewOffline(t)
+	defer st.Cancel()
+
+	register := func(t *testing.T) string {
+		t.Helper()
+		return testutils.
+			PostWithBody(t, st, "/auth/register", testutils.NewFakeUser()).
+			ExpectStatus(t, http.StatusCreated).
+			DecodeTokenResponse(t).AccessToken
+	}
+	token := register(t)
+	fake := st.FakeBanks["vbank"]
+
+	apply := func(t *testing.T, productID string, req dto.AgreementApplyRequest) *http.Response {
+		t.Helper()
+		return testutils.PostWithAuth(t, st, "/products/vbank/"+productID+"/apply", token, req).Resp
+	}
+	list := func(t *testing.T) []dto.AgreementResponse {
+		t.Helper()
+		resp := testutils.GetWithAuth(t, st, "/me/agreements", token).ExpectStatus(t, http.StatusOK).Resp
+		return testutils.DecodeJSON[[]dto.AgreementResponse](t, resp)
+	}
+
+	t.Run("bank not connected -> 409", func(t *testing.T) {
+		require.Equal(t, http.StatusConflict,
+			apply(t, "prod-vbank-deposit-001", dto.AgreementApplyRequest{Amount: "100000"}).StatusCode)
+		require.Zero(t, fake.AgreementCount())
+	})
+
+	testutils.PostWithAuth(t, st, "/consents/request", token, dto.ConsentCreateRequest{
+		BankCode: "vbank",
+		ClientID: "team014-1",
+	}).ExpectStatus(t, http.StatusCreated)
+
+	var deposit dto.AgreementResponse
+	t.Run("deposit -> opened for the term", func(t *testing.T) {
+		resp := apply(t, "prod-vbank-deposit-001", dto.AgreementApplyRequest{Amount: "100000", TermMonths: 6})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		deposit = testutils.DecodeJSON[dto.AgreementResponse](t, resp)
+
+		require.Equal(t, "vbank", deposit.BankCode)
+		require.Equal(t, "deposit", deposit.ProductType)
+		require.Equal(t, domain.AgreementActive, deposit.Status)
+		require.Equal(t, "100000.00", deposit.Amount)
+		require.Equal(t, 6, deposit.TermMonths)
+		require.NotEmpty(t, deposit.AccountNumber)
+		require.NotNil(t, deposit.StartDate)
+		require.NotNil(t, deposit.EndDate)
+		require.Equal(t, deposit.StartDate.AddDate(0, 6, 0), *deposit.EndDate)
+	})
+
+	t.Run("card without amount, invalid amount -> 400", func(t *testing.T) {
+		require.Equal(t, http.StatusCreated, apply(t, "prod-vbank-card-002", dto.AgreementApplyRequest{}).StatusCode)
+		require.Equal(t, http.StatusBadRequest,
+			apply(t, "prod-vbank-deposit-001", dto.AgreementApplyRequest{Amount: "-1"}).StatusCode)
+		require.Equal(t, 2, fake.AgreementCount())
+	})
+
+	t.Run("list -> agreements of the bank", func(t *testing.T) {
+		items := list(t)
+		require.Len(t, items, 2)
+		for _, a := range items {
+			require.Equal(t, domain.AgreementActive, a.Status)
+		}
+	})
+
+	t.Run("another user -> cannot close", func(t *testing.T) {
+		testutils.DeleteWithAuth(t, st, fmt.Sprintf("/me/agreements/%d", deposit.ID), register(t)).
+			ExpectStatus(t, http.StatusNotFound)
+	})
+
+	t.Run("close -> closed in the bank, second close -> 409", func(t *testing.T) {
+		resp := testutils.DeleteWithAuth(t, st, fmt.Sprintf("/me/agreements/%d", deposit.ID), token).
+			ExpectStatus(t, http.StatusOK).Resp
+		closed := testutils.DecodeJSON[dto.AgreementResponse](t, resp)
+		require.Equal(t, domain.AgreementClosed, closed.Status)
+		require.NotNil(t, closed.ClosedAt)
+		require.WithinDuration(t, time.Now(), *closed.ClosedAt, time.Minute)
+
+		testutils.DeleteWithAuth(t, st, fmt.Sprintf("/me/agreements/%d", deposit.ID), token).
+			ExpectStatus(t, http.StatusConflict)
+
+		// the refresh from the bank keeps it closed
+		for _, a := range list(t) {
+			if a.ID == deposit.ID {
+				require.Equal(t, domain.AgreementClosed, a.Status)
+			}
+		}
+	})
+}
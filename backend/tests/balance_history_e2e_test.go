@@ -0,0 +1,165 @@
+// tests/balance_history_e2e_test.go
+
+package tests
+
+import (
+	"fmt"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/http-server/dto"
+	"multibank/backend/internal/storage/sqlite"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"multibank/backend/tests/suite"
+	testutils "multibank/backend/tests/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestHTTP_BalanceHistoryOffline carries balances forward from the last snapshot before the range
+// and only while the account is still synced
+func TestHTTP_BalanceHistoryOffline(t *testing.T) {
+	st := suite.NewOffline(t)
+	defer st.Cancel()
+
+	token := testutils.
+		PostWithBody(t, st, "/auth/register", testutils.NewFakeUser()).
+		ExpectStatus(t, http.StatusCreated).
+		DecodeTokenResponse(t).AccessToken
+	me := testutils.DecodeJSON[dto.UserResponse](t,
+		testutils.GetWithAuth(t, st, "/me", token).ExpectStatus(t, http.StatusOK).Resp)
+
+	resp := testutils.PostWithAuth(t, st, "/consents/request", token, dto.ConsentCreateRequest{
+		BankCode: "vbank",
+		ClientID: "team014-1",
+	}).ExpectStatus(t, http.StatusCreated).Resp
+	consent := testutils.DecodeJSON[dto.ConsentResponse](t, resp)
+
+	bank, err := st.BankService.GetBankByCode(st.Ctx, "vbank")
+	require.NoError(t, err)
+
+	// the first read syncs the accounts and takes today's snapshots
+	resp = testutils.GetWithAuth(t, st, fmt.Sprintf("/accounts?bank_id=%d", bank.ID), token).
+		ExpectStatus(t, http.StatusOK).Resp
+	accounts := testutils.DecodeJSON[[]dto.AccountResponse](t, resp)
+	require.Len(t, accounts, 2)
+	active := accounts[0]
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	day := func(offset int) time.Time { return today.AddDate(0, 0, offset) }
+	date := func(offset int) string { return day(offset).Format("2006-01-02") }
+
+	snaps := sqlite.NewBalanceSnapshotRepo(st.Storage.DB())
+	record := func(accountID string, offset int, amount int64) {
+		require.NoError(t, snaps.Record(st.Ctx, domain.BalanceSnapshot{
+			UserID: me.ID, BankID: bank.ID, AccountID: accountID,
+			Amount: amount, Currency: "RUB", BalanceType: "InterimAvailable",
+			TakenAt: day(offset).Add(12 * time.Hour),
+		}))
+	}
+	record(active.AccountID, -10, 100000) // before the range
+	record("closed-acc", -12, 50000)      // the bank does not return this account anymore
+	record("closed-acc", -5, 70001)
+
+	history := func(t *testing.T, to int) dto.BalanceHistoryResponse {
+		t.Helper()
+		resp := testutils.GetWithAuth(t, st,
+			fmt.Sprintf("/me/balances/history?from=%s&to=%s&interval=day", date(-7), date(to)), token).
+			ExpectStatus(t, http.StatusOK).Resp
+		return testutils.DecodeJSON[dto.BalanceHistoryResponse](t, resp)
+	}
+	series := func(h dto.BalanceHistoryResponse, accountID string) []dto.BalancePointResponse {
+		for _, s := range h.Accounts {
+			if s.AccountID == accountID {
+				return s.Points
+			}
+		}
+		return nil
+	}
+
+	t.Run("seeded from the last snapshot before the range", func(t *testing.T) {
+		points := series(history(t, 0), active.AccountID)
+		require.Len(t, points, 8)
+		require.Equal(t, date(-7), points[0].Date)
+		require.Equal(t, "1000.00", points[0].Amount)
+		require.Equal(t, "1000.00", points[6].Amount)
+	})
+
+	t.Run("deleted account -> carried only up to its last snapshot", func(t *testing.T) {
+		h := history(t, 0)
+		points := series(h, "closed-acc")
+		require.Len(t, points, 3)
+		require.Equal(t, "500.00", points[0].Amount)
+		require.Equal(t, date(-5), points[2].Date)
+		require.Equal(t, "700.01", points[2].Amount)
+
+		require.Equal(t, date(-6), h.Total[1].Date)
+		require.Equal(t, "1500.00", h.Total[1].Amount)
+		require.Equal(t, "1700.01", h.Total[2].Amount)
+		require.Equal(t, date(-4), h.Total[3].Date)
+		require.Equal(t, "1000.00", h.Total[3].Amount)
+	})
+
+	t.Run("revoked consent -> its accounts are not carried forward", func(t *testing.T) {
+		require.Len(t, series(history(t, 2), active.AccountID), 10)
+
+		testutils.DeleteWithAuth(t, st, fmt.Sprintf("/consents/%d", consent.ID), token).
+			ExpectStatus(t, http.StatusNoContent)
+
+		points := series(history(t, 2), active.AccountID)
+		require.Len(t, points, 8)
+		require.Equal(t, date(0), points[7].Date)
+	})
+}
+
+// TestBalanceSnapshotsMigratedToKopecks rewrites snapshot amounts stored as REAL rubles by earlier versions
+func TestBalanceSnapshotsMigratedToKopecks(t *testing.T) {
+	st, err := sqlite.New(filepath.Join(t.TempDir(), "storage.db"))
+	require.NoError(t, err)
+	defer st.Close()
+	ctx := t.Context()
+	require.NoError(t, st.Migrate(ctx))
+
+	// the table as created by earlier versions
+	_, err = st.DB().ExecContext(ctx, `
+DROP TABLE balance_snapshots;
+CREATE TABLE balance_snapshots (
+  id           INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id      INTEGER NOT NULL,
+  bank_id      INTEGER NOT NULL,
+  account_id   TEXT    NOT NULL,
+  date         TEXT    NOT NULL,
+  amount       REAL    NOT NULL,
+  currency     TEXT    NOT NULL,
+  balance_type TEXT    NOT NULL DEFAULT '',
+  taken_at     TEXT    NOT NULL,
+  UNIQUE (user_id, bank_id, account_id, date)
+);
+INSERT INTO balance_snapshots (user_id, bank_id, account_id, date, amount, currency, taken_at) VALUES
+  (1, 1, 'acc-1', '2025-01-01', 1234.56, 'RUB', '2025-01-01 12:00:00'),
+  (1, 1, 'acc-2', '2025-01-01', -0.29, 'RUB', '2025-01-01 12:00:00');`)
+	require.NoError(t, err)
+
+	for range 2 {
+		require.NoError(t, st.Migrate(ctx))
+	}
+
+	rows, err := st.DB().QueryContext(ctx, `SELECT amount, typeof(amount) FROM balance_snapshots ORDER BY account_id`)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var amounts []int64
+	for rows.Next() {
+		var (
+			v   int64
+			typ string
+		)
+		require.NoError(t, rows.Scan(&v, &typ))
+		require.Equal(t, "integer", typ)
+		amounts = append(amounts, v)
+	}
+	require.NoError(t, rows.Err())
+	require.Equal(t, []int64{123456, -29}, amounts)
+}
@@ -0,0 +1,201 @@
+// tests/bank_admin_e2e_test.go
+
+package tests
+
+import (
+	"fmt"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/fakebank"
+	"multibank/backend/internal/http-server/dto"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"multibank/backend/tests/suite"
+	testutils "multibank/backend/tests/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestHTTP_BankAdminOffline manages banks through /admin/banks against fake banks
+func TestHTTP_BankAdminOffline(t *testing.T) {
+	st := suite.NewOffline(t)
+	defer st.Cancel()
+
+	token := testutils.RegisterAdmin(t, st)
+
+	fb := fakebank.New(st.Log, fakebank.Options{Code: "xbank", ClientID: "team014", ClientSecret: "x-secret", AutoApprove: true})
+	ts := httptest.NewServer(fb)
+	defer ts.Close()
+
+	var created dto.BankAdminResponse
+	t.Run("create bank -> 201, credentials are not returned", func(t *testing.T) {
+		resp := testutils.PostWithAuth(t, st, "/admin/banks", token, dto.BankCreateRequest{
+			Code: "xbank",
+			BankUpdateRequest: dto.BankUpdateRequest{
+				Name:       "X Bank",
+				APIBaseURL: ts.URL,
+			},
+			BankCredentialsRequest: dto.BankCredentialsRequest{Login: "team014", Password: "wrong-secret"},
+		}).ExpectStatus(t, http.StatusCreated).Resp
+
+		created = testutils.DecodeJSON[dto.BankAdminResponse](t, resp)
+		require.Equal(t, "xbank", created.Code)
+		require.Equal(t, domain.BankAdapterSandbox, created.Adapter)
+		require.True(t, created.IsEnabled)
+		require.EqualValues(t, domain.DefaultBankRateLimit, created.RateLimit)
+		require.False(t, created.Authorized)
+
+		resp = testutils.GetWithAuth(t, st, "/admin/banks", token).ExpectStatus(t, http.StatusOK).Resp
+		require.Len(t, testutils.DecodeJSON[[]dto.BankAdminResponse](t, resp), len(st.FakeBanks)+1)
+	})
+
+	t.Run("same code -> 409, invalid bank -> 400", func(t *testing.T) {
+		testutils.PostWithAuth(t, st, "/admin/banks", token, dto.BankCreateRequest{
+			Code:                   "xbank",
+			BankUpdateRequest:      dto.BankUpdateRequest{Name: "X Bank", APIBaseURL: ts.URL},
+			BankCredentialsRequest: dto.BankCredentialsRequest{Login: "team014", Password: "x"},
+		}).ExpectStatus(t, http.StatusConflict)
+
+		testutils.PostWithAuth(t, st, "/admin/banks", token, dto.BankCreateRequest{
+			Code:                   "ybank",
+			BankUpdateRequest:      dto.BankUpdateRequest{Name: "Y Bank", APIBaseURL: "not a url"},
+			BankCredentialsRequest: dto.BankCredentialsRequest{Login: "team014", Password: "x"},
+		}).ExpectStatus(t, http.StatusBadRequest)
+
+		testutils.PutWithAuth(t, st, fmt.Sprintf("/admin/banks/%d", created.ID), token, dto.BankUpdateRequest{
+			Name: "X Bank", APIBaseURL: ts.URL, Adapter: "nope",
+		}).ExpectStatus(t, http.StatusBadRequest)
+	})
+
+	t.Run("wrong secret -> token refresh 502, rotated secret -> 200", func(t *testing.T) {
+		path := fmt.Sprintf("/admin/banks/%d/token", created.ID)
+		testutils.PostWithAuth(t, st, path, token, nil).ExpectStatus(t, http.StatusBadGateway)
+
+		testutils.PutWithAuth(t, st, fmt.Sprintf("/admin/banks/%d/credentials", created.ID), token,
+			dto.BankCredentialsRequest{Login: "team014", Password: "x-secret"}).
+			ExpectStatus(t, http.StatusOK)
+
+		resp := testutils.PostWithAuth(t, st, path, token, nil).ExpectStatus(t, http.StatusOK).Resp
+		require.Equal(t, "authorized", testutils.DecodeJSON[dto.BankAuthorizeResponse](t, resp).Status)
+	})
+
+	t.Run("update bank -> settings changed, token dropped", func(t *testing.T) {
+		limit := 2.5
+		resp := testutils.PutWithAuth(t, st, fmt.Sprintf("/admin/banks/%d", created.ID), token, dto.BankUpdateRequest{
+			Name: "X Bank 2", APIBaseURL: ts.URL + "/", Adapter: domain.BankAdapterSandbox, RateLimit: &limit,
+		}).ExpectStatus(t, http.StatusOK).Resp
+
+		got := testutils.DecodeJSON[dto.BankAdminResponse](t, resp)
+		require.Equal(t, "X Bank 2", got.Name)
+		require.Equal(t, ts.URL, got.APIBaseURL)
+		require.Equal(t, limit, got.RateLimit)
+		require.False(t, got.Authorized)
+
+		testutils.PutWithAuth(t, st, "/admin/banks/999999", token, dto.BankUpdateRequest{
+			Name: "None", APIBaseURL: ts.URL,
+		}).ExpectStatus(t, http.StatusNotFound)
+	})
+
+	t.Run("disable -> hidden from /banks, enable -> back", func(t *testing.T) {
+		listed := func() bool {
+			resp := testutils.GetWithAuth(t, st, "/banks", token).ExpectStatus(t, http.StatusOK).Resp
+			for _, b := range testutils.DecodeJSON[[]dto.BankResponse](t, resp) {
+				if b.ID == created.ID {
+					return true
+				}
+			}
+			return false
+		}
+		require.True(t, listed())
+
+		resp := testutils.PostWithAuth(t, st, fmt.Sprintf("/admin/banks/%d/disable", created.ID), token, nil).
+			ExpectStatus(t, http.StatusOK).Resp
+		require.False(t, testutils.DecodeJSON[dto.BankAdminResponse](t, resp).IsEnabled)
+		require.False(t, listed())
+
+		testutils.PostWithAuth(t, st, fmt.Sprintf("/admin/banks/%d/enable", created.ID), token, nil).
+			ExpectStatus(t, http.StatusOK)
+		require.True(t, listed())
+	})
+
+	t.Run("restart -> operator edits of seeded banks are kept", func(t *testing.T) {
+		abank, err := st.BankService.GetBankByCode(st.Ctx, "abank")
+		require.NoError(t, err)
+		testutils.PostWithAuth(t, st, fmt.Sprintf("/admin/banks/%d/disable", abank.ID), token, nil).
+			ExpectStatus(t, http.StatusOK)
+
+		require.NoError(t, st.Storage.Migrate(st.Ctx))
+
+		got, err := st.BankService.GetBankByCode(st.Ctx, "abank")
+		require.NoError(t, err)
+		require.False(t, got.IsEnabled)
+		require.Equal(t, abank.APIBaseURL, got.APIBaseURL) // the fake bank, not the seeded sandbox url
+		require.Equal(t, abank.Password, got.Password)
+	})
+}
+
+// TestHTTP_BankAdminForbiddenOffline keeps bank settings and credentials away from users without admin rights:
+// they cannot point a bank to another host and make the backend send it the bank secret
+func TestHTTP_BankAdminForbiddenOffline(t *testing.T) {
+	st := suite.NewOffline(t)
+	defer st.Cancel()
+
+	user := testutils.NewFakeUser()
+	token := testutils.
+		PostWithBody(t, st, "/auth/register", user).
+		ExpectStatus(t, http.StatusCreated).
+		DecodeTokenResponse(t).AccessToken
+
+	var calls atomic.Int32
+	evil := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer evil.Close()
+
+	vbank, err := st.BankService.GetBankByCode(st.Ctx, "vbank")
+	require.NoError(t, err)
+	path := fmt.Sprintf("/admin/banks/%d", vbank.ID)
+
+	forbidden := func(t *testing.T) {
+		t.Helper()
+		testutils.GetWithAuth(t, st, "/admin/banks", token).ExpectStatus(t, http.StatusForbidden)
+		testutils.PostWithAuth(t, st, "/admin/banks", token, dto.BankCreateRequest{
+			Code:                   "evilbank",
+			BankUpdateRequest:      dto.BankUpdateRequest{Name: "Evil", APIBaseURL: evil.URL},
+			BankCredentialsRequest: dto.BankCredentialsRequest{Login: "team014", Password: "x"},
+		}).ExpectStatus(t, http.StatusForbidden)
+		testutils.PutWithAuth(t, st, path, token, dto.BankUpdateRequest{Name: "V Bank", APIBaseURL: evil.URL}).
+			ExpectStatus(t, http.StatusForbidden)
+		testutils.PutWithAuth(t, st, path+"/credentials", token, dto.BankCredentialsRequest{Login: "x", Password: "x"}).
+			ExpectStatus(t, http.StatusForbidden)
+		testutils.PostWithAuth(t, st, path+"/token", token, nil).ExpectStatus(t, http.StatusForbidden)
+		testutils.PostWithAuth(t, st, path+"/disable", token, nil).ExpectStatus(t, http.StatusForbidden)
+		testutils.PostWithAuth(t, st, path+"/enable", token, nil).ExpectStatus(t, http.StatusForbidden)
+		testutils.GetWithAuth(t, st, "/admin/banks/breakers", token).ExpectStatus(t, http.StatusForbidden)
+	}
+
+	t.Run("no token -> 401", func(t *testing.T) {
+		testutils.GetWithAuth(t, st, "/admin/banks", "").ExpectStatus(t, http.StatusUnauthorized)
+	})
+
+	t.Run("not an admin -> 403, the bank is unchanged", func(t *testing.T) {
+		forbidden(t)
+
+		got, err := st.BankService.GetBankByCode(st.Ctx, "vbank")
+		require.NoError(t, err)
+		require.Equal(t, vbank.APIBaseURL, got.APIBaseURL)
+		require.Equal(t, vbank.Password, got.Password)
+		require.True(t, got.IsEnabled)
+		require.Zero(t, calls.Load())
+	})
+
+	t.Run("admin -> allowed, revoked -> 403 with the same token", func(t *testing.T) {
+		require.NoError(t, st.UserService.SetAdmin(st.Ctx, user.Email, true))
+		testutils.GetWithAuth(t, st, "/admin/banks", token).ExpectStatus(t, http.StatusOK)
+
+		require.NoError(t, st.UserService.SetAdmin(st.Ctx, user.Email, false))
+		forbidden(t)
+	})
+}
@@ -0,0 +1,126 @@
+// tests/bank_config_test.go
+
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"multibank/backend/internal/config"
+	"multibank/backend/internal/domain"
+	banksvc "multibank/backend/internal/service/bank"
+	"multibank/backend/tests/suite"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestBankConfigReconcile checks how banks declared in the config are brought into the DB on start
+func TestBankConfigReconcile(t *testing.T) {
+	st := suite.NewOffline(t)
+	defer st.Cancel()
+
+	reconcile := func(t *testing.T, banks []config.Bank) error {
+		cfg := *st.Cfg
+		cfg.Banks = banks
+		decls, err := cfg.BankDeclarations()
+		require.NoError(t, err)
+		return st.BankService.Reconcile(st.Ctx, decls)
+	}
+	declared := func(code string) config.Bank {
+		i := slices.IndexFunc(st.Cfg.Banks, func(b config.Bank) bool { return b.Code == code })
+		require.GreaterOrEqual(t, i, 0)
+		return st.Cfg.Banks[i]
+	}
+
+	vbank, err := st.BankService.GetBankByCode(st.Ctx, "vbank")
+	require.NoError(t, err)
+	_, _, err = st.BankService.GetOrRefreshToken(st.Ctx, vbank.ID)
+	require.NoError(t, err)
+
+	t.Run("same config on restart -> nothing changes, token kept", func(t *testing.T) {
+		require.NoError(t, reconcile(t, st.Cfg.Banks))
+
+		got, err := st.BankService.GetBankByCode(st.Ctx, "vbank")
+		require.NoError(t, err)
+		require.Equal(t, vbank.UpdatedAt, got.UpdatedAt)
+
+		authorized, _, err := st.BankService.TokenStatus(st.Ctx, vbank.ID)
+		require.NoError(t, err)
+		require.True(t, authorized)
+	})
+
+	t.Run("secret from a file and from env -> credentials updated, token dropped", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "vbank_secret")
+		require.NoError(t, os.WriteFile(path, []byte("file-secret\n"), 0o600))
+		t.Setenv("MB_TEST_ABANK_SECRET", "env-secret")
+
+		v := declared("vbank")
+		v.ClientSecret, v.ClientSecretFile = "", path
+		a := declared("abank")
+		a.ClientSecret, a.ClientSecretEnv = "", "MB_TEST_ABANK_SECRET"
+		require.NoError(t, reconcile(t, []config.Bank{a, v}))
+
+		got, err := st.BankService.GetBankByCode(st.Ctx, "vbank")
+		require.NoError(t, err)
+		require.Equal(t, "file-secret", got.Password)
+		got, err = st.BankService.GetBankByCode(st.Ctx, "abank")
+		require.NoError(t, err)
+		require.Equal(t, "env-secret", got.Password)
+
+		authorized, _, err := st.BankService.TokenStatus(st.Ctx, vbank.ID)
+		require.NoError(t, err)
+		require.False(t, authorized)
+	})
+
+	t.Run("empty env var -> stored secret kept", func(t *testing.T) {
+		t.Setenv("MB_TEST_ABANK_SECRET", "")
+		a := declared("abank")
+		a.ClientSecret, a.ClientSecretEnv = "", "MB_TEST_ABANK_SECRET"
+		require.NoError(t, reconcile(t, []config.Bank{a}))
+
+		got, err := st.BankService.GetBankByCode(st.Ctx, "abank")
+		require.NoError(t, err)
+		require.Equal(t, "env-secret", got.Password)
+	})
+
+	t.Run("new bank -> created, undeclared banks untouched", func(t *testing.T) {
+		sbank, err := st.BankService.GetBankByCode(st.Ctx, "sbank")
+		require.NoError(t, err)
+
+		disabled, limit := false, 1.5
+		require.NoError(t, reconcile(t, []config.Bank{{
+			Code:       "xbank",
+			Name:       "X Bank",
+			APIBaseURL: "https://xbank.example.com/",
+			ClientID:   "team014",
+			RateLimit:  &limit,
+			Enabled:    &disabled,
+		}}))
+
+		x, err := st.BankService.GetBankByCode(st.Ctx, "xbank")
+		require.NoError(t, err)
+		require.Equal(t, "https://xbank.example.com", x.APIBaseURL)
+		require.Equal(t, domain.BankAdapterSandbox, x.Adapter)
+		require.Equal(t, limit, x.RateLimit)
+		require.Equal(t, domain.DefaultBankRateBurst, x.RateBurst)
+		require.False(t, x.IsEnabled)
+		require.Empty(t, x.Password)
+
+		got, err := st.BankService.GetBankByCode(st.Ctx, "sbank")
+		require.NoError(t, err)
+		require.Equal(t, sbank, got)
+	})
+
+	t.Run("invalid config -> error", func(t *testing.T) {
+		v := declared("vbank")
+		require.ErrorIs(t, reconcile(t, []config.Bank{v, v}), banksvc.ErrInvalidBank)
+
+		v.ClientID = ""
+		require.ErrorIs(t, reconcile(t, []config.Bank{v}), banksvc.ErrInvalidBank)
+
+		_, err := (&config.Config{Banks: []config.Bank{{Code: "ybank", ClientSecretFile: "/does/not/exist"}}}).BankDeclarations()
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,192 @@
+// tests/bank_flow_e2e_test.go
+
+package tests
+
+import (
+	"fmt"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/http-server/dto"
+	"net/http"
+	"testing"
+
+	"multibank/backend/tests/suite"
+	testutils "multibank/backend/tests/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestHTTP_BankFlowOffline goes through consent -> accounts -> products against fake banks
+func TestHTTP_BankFlowOffline(t *testing.T) {
+	st := suite.NewOffline(t)
+	defer st.Cancel()
+
+	var token string
+	t.Run("register new user -> 201 + token", func(t *testing.T) {
+		tr := testutils.
+			PostWithBody(t, st, "/auth/register", testutils.NewFakeUser()).
+			ExpectStatus(t, http.StatusCreated).
+			DecodeTokenResponse(t)
+		token = tr.AccessToken
+	})
+
+	banks := map[string]dto.BankResponse{}
+	t.Run("get /banks -> seeded banks", func(t *testing.T) {
+		resp := testutils.GetWithAuth(t, st, "/banks", token).
+			ExpectStatus(t, http.StatusOK).Resp
+
+		for _, b := range testutils.DecodeJSON[[]dto.BankResponse](t, resp) {
+			banks[b.Code] = b
+		}
+		require.Len(t, banks, len(st.FakeBanks))
+		require.Contains(t, banks, "abank")
+		require.Contains(t, banks, "sbank")
+		require.Contains(t, banks, "vbank")
+	})
+
+	t.Run("failing bank -> 502 and the failure is seen by the breaker, admins only", func(t *testing.T) {
+		st.FakeBanks["sbank"].SetFaults(0, 0, 1)
+		defer st.FakeBanks["sbank"].SetFaults(0, 0, 0)
+
+		testutils.PostWithAuth(t, st, fmt.Sprintf("/banks/%d/authorize", banks["sbank"].ID), token, nil).
+			ExpectStatus(t, http.StatusBadGateway)
+
+		testutils.GetWithAuth(t, st, "/admin/banks/breakers", token).ExpectStatus(t, http.StatusForbidden)
+		resp := testutils.GetWithAuth(t, st, "/admin/banks/breakers", testutils.RegisterAdmin(t, st)).
+			ExpectStatus(t, http.StatusOK).Resp
+		breakers := testutils.DecodeJSON[[]dto.BankBreakerResponse](t, resp)
+		require.Len(t, breakers, 1)
+		require.Equal(t, "sbank", breakers[0].BankCode)
+		require.Positive(t, breakers[0].Failures)
+	})
+
+	t.Run("authorize bank -> 200", func(t *testing.T) {
+		resp := testutils.PostWithAuth(t, st, fmt.Sprintf("/banks/%d/authorize", banks["sbank"].ID), token, nil).
+			ExpectStatus(t, http.StatusOK).Resp
+		got := testutils.DecodeJSON[dto.BankAuthorizeResponse](t, resp)
+		require.Equal(t, "authorized", got.Status)
+	})
+
+	t.Run("auto-approved consent -> accounts with balances", func(t *testing.T) {
+		resp := testutils.PostWithAuth(t, st, "/consents/request", token, dto.ConsentCreateRequest{
+			BankCode: "vbank",
+			ClientID: "team014-1",
+		}).ExpectStatus(t, http.StatusCreated).Resp
+
+		c := testutils.DecodeJSON[dto.ConsentResponse](t, resp)
+		require.Equal(t, domain.Authorised, c.Status)
+		require.NotNil(t, c.ConsentID)
+
+		resp = testutils.GetWithAuth(t, st, fmt.Sprintf("/accounts?bank_id=%d", banks["vbank"].ID), token).
+			ExpectStatus(t, http.StatusOK).Resp
+		accounts := testutils.DecodeJSON[[]dto.AccountResponse](t, resp)
+		require.Len(t, accounts, 2)
+		for _, a := range accounts {
+			require.Equal(t, "vbank", a.BankCode)
+			require.Equal(t, "RUB", a.Currency)
+			require.NotEmpty(t, a.Amount)
+			require.NotEmpty(t, a.Identification)
+		}
+	})
+
+	t.Run("consent approved by the client later -> accounts after refresh", func(t *testing.T) {
+		st.FakeBanks["abank"].SetAutoApprove(false)
+
+		resp := testutils.PostWithAuth(t, st, "/consents/request", token, dto.ConsentCreateRequest{
+			BankCode: "abank",
+			ClientID: "team014-1",
+		}).ExpectStatus(t, http.StatusCreated).Resp
+		c := testutils.DecodeJSON[dto.ConsentResponse](t, resp)
+		require.Equal(t, domain.AwaitingAuthorisation, c.Status)
+
+		resp = testutils.GetWithAuth(t, st, fmt.Sprintf("/accounts?bank_id=%d", banks["abank"].ID), token).
+			ExpectStatus(t, http.StatusOK).Resp
+		require.Empty(t, testutils.DecodeJSON[[]dto.AccountResponse](t, resp))
+
+		require.True(t, st.FakeBanks["abank"].Approve(c.RequestID))
+
+		resp = testutils.PostWithAuth(t, st, fmt.Sprintf("/consents/%d/refresh", c.ID), token, nil).
+			ExpectStatus(t, http.StatusOK).Resp
+		c = testutils.DecodeJSON[dto.ConsentResponse](t, resp)
+		require.Equal(t, domain.Authorised, c.Status)
+
+		resp = testutils.GetWithAuth(t, st, fmt.Sprintf("/accounts?bank_id=%d", banks["abank"].ID), token).
+			ExpectStatus(t, http.StatusOK).Resp
+		require.Len(t, testutils.DecodeJSON[[]dto.AccountResponse](t, resp), 2)
+	})
+
+	t.Run("get /products -> catalogue of every bank", func(t *testing.T) {
+		resp := testutils.GetWithAuth(t, st, "/products", token).
+			ExpectStatus(t, http.StatusOK).Resp
+		products := testutils.DecodeJSON[[]dto.ProductResponse](t, resp)
+
+		perBank := map[string]int{}
+		for _, p := range products {
+			perBank[p.BankCode]++
+			require.Equal(t, domain.BankHealthOK, p.BankHealth)
+		}
+		require.Len(t, perBank, len(st.FakeBanks))
+
+		resp = testutils.GetWithAuth(t, st, "/products?product_type=deposit&sort=rate", token).
+			ExpectStatus(t, http.StatusOK).Resp
+		deposits := testutils.DecodeJSON[[]dto.ProductResponse](t, resp)
+		require.NotEmpty(t, deposits)
+		for i, p := range deposits {
+			require.Equal(t, "deposit", p.ProductType)
+			if i > 0 {
+				require.GreaterOrEqual(t, deposits[i-1].InterestRate, p.InterestRate)
+			}
+		}
+	})
+}
+
+// TestHTTP_BankAdapterOffline switches a bank to the oauth2 adapter without touching the services
+func TestHTTP_BankAdapterOffline(t *testing.T) {
+	st := suite.NewOffline(t)
+	defer st.Cancel()
+
+	token := testutils.
+		PostWithBody(t, st, "/auth/register", testutils.NewFakeUser()).
+		ExpectStatus(t, http.StatusCreated).
+		DecodeTokenResponse(t).AccessToken
+
+	bank, err := st.BankService.GetBankByCode(st.Ctx, "vbank")
+	require.NoError(t, err)
+	path := fmt.Sprintf("/banks/%d/authorize", bank.ID)
+
+	st.FakeBanks["vbank"].SetOAuth2(true)
+
+	t.Run("bank issues tokens by oauth2, sandbox adapter -> 502", func(t *testing.T) {
+		testutils.PostWithAuth(t, st, path, token, nil).
+			ExpectStatus(t, http.StatusBadGateway)
+	})
+
+	t.Run("adapter oauth2 -> authorized, consent and accounts work", func(t *testing.T) {
+		_, err := st.Storage.DB().ExecContext(st.Ctx, `UPDATE banks SET adapter = ? WHERE id = ?`,
+			domain.BankAdapterOAuth2, bank.ID)
+		require.NoError(t, err)
+
+		resp := testutils.PostWithAuth(t, st, path, token, nil).
+			ExpectStatus(t, http.StatusOK).Resp
+		require.Equal(t, "authorized", testutils.DecodeJSON[dto.BankAuthorizeResponse](t, resp).Status)
+
+		resp = testutils.PostWithAuth(t, st, "/consents/request", token, dto.ConsentCreateRequest{
+			BankCode: "vbank",
+			ClientID: "team014-1",
+		}).ExpectStatus(t, http.StatusCreated).Resp
+		require.Equal(t, domain.Authorised, testutils.DecodeJSON[dto.ConsentResponse](t, resp).Status)
+
+		resp = testutils.GetWithAuth(t, st, fmt.Sprintf("/accounts?bank_id=%d", bank.ID), token).
+			ExpectStatus(t, http.StatusOK).Resp
+		require.Len(t, testutils.DecodeJSON[[]dto.AccountResponse](t, resp), 2)
+	})
+
+	t.Run("unknown adapter -> 502", func(t *testing.T) {
+		abank, err := st.BankService.GetBankByCode(st.Ctx, "abank")
+		require.NoError(t, err)
+		_, err = st.Storage.DB().ExecContext(st.Ctx, `UPDATE banks SET adapter = 'nope' WHERE id = ?`, abank.ID)
+		require.NoError(t, err)
+
+		testutils.PostWithAuth(t, st, fmt.Sprintf("/banks/%d/authorize", abank.ID), token, nil).
+			ExpectStatus(t, http.StatusBadGateway)
+	})
+}
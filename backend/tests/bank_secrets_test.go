@@ -0,0 +1,196 @@
+// tests/bank_secrets_test.go
+
+package tests
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"multibank/backend/internal/secret"
+	"multibank/backend/internal/storage/sqlite"
+	"multibank/backend/tests/suite"
+	testutils "multibank/backend/tests/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestBankSecretsEncrypted checks that bank secrets are not readable from the DB file and survive key rotation
+func TestBankSecretsEncrypted(t *testing.T) {
+	st := suite.NewOffline(t)
+	defer st.Cancel()
+
+	token := testutils.
+		PostWithBody(t, st, "/auth/register", testutils.NewFakeUser()).
+		ExpectStatus(t, http.StatusCreated).
+		DecodeTokenResponse(t).AccessToken
+
+	vbank, err := st.BankService.GetBankByCode(st.Ctx, "vbank")
+	require.NoError(t, err)
+	testutils.PostWithAuth(t, st, fmt.Sprintf("/banks/%d/authorize", vbank.ID), token, nil).
+		ExpectStatus(t, http.StatusOK)
+
+	oldBox, err := secret.NewBox(st.Cfg.Secrets.MasterKey)
+	require.NoError(t, err)
+
+	stored := func(t *testing.T) []string {
+		var out []string
+		for _, q := range []string{`SELECT password FROM banks`, `SELECT access_token FROM bank_tokens`} {
+			rows, err := st.Storage.DB().QueryContext(st.Ctx, q)
+			require.NoError(t, err)
+			for rows.Next() {
+				var v string
+				require.NoError(t, rows.Scan(&v))
+				out = append(out, v)
+			}
+			require.NoError(t, rows.Close())
+		}
+		return out
+	}
+
+	t.Run("secrets and tokens are encrypted at rest", func(t *testing.T) {
+		values := stored(t)
+		require.Len(t, values, len(st.FakeBanks)+1) // passwords + vbank token
+		for _, v := range values {
+			require.True(t, oldBox.Current(v), v)
+			require.NotContains(t, v, "fake-secret")
+			require.False(t, strings.HasPrefix(v, "tok-"), v)
+		}
+	})
+
+	t.Run("plaintext of an older version -> encrypted on start", func(t *testing.T) {
+		_, err := st.Storage.DB().ExecContext(st.Ctx, `UPDATE banks SET password = 'fake-secret' WHERE id = ?`, vbank.ID)
+		require.NoError(t, err)
+
+		repo := sqlite.NewBankRepo(st.Storage.DB(), oldBox)
+		n, err := repo.EncryptSecrets(st.Ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+
+		b, err := repo.GetBankByCode(st.Ctx, "vbank")
+		require.NoError(t, err)
+		require.Equal(t, "fake-secret", b.Password)
+		for _, v := range stored(t) {
+			require.True(t, oldBox.Current(v), v)
+		}
+	})
+
+	t.Run("legacy value without additional data -> re-sealed bound to its row", func(t *testing.T) {
+		_, err := st.Storage.DB().ExecContext(st.Ctx, `UPDATE banks SET password = ? WHERE id = ?`,
+			legacySeal(t, st.Cfg.Secrets.MasterKey, "fake-secret"), vbank.ID)
+		require.NoError(t, err)
+
+		repo := sqlite.NewBankRepo(st.Storage.DB(), oldBox)
+		b, err := repo.GetBankByCode(st.Ctx, "vbank")
+		require.NoError(t, err)
+		require.Equal(t, "fake-secret", b.Password)
+
+		n, err := repo.EncryptSecrets(st.Ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+		for _, v := range stored(t) {
+			require.True(t, oldBox.Current(v), v)
+		}
+		b, err = repo.GetBankByCode(st.Ctx, "vbank")
+		require.NoError(t, err)
+		require.Equal(t, "fake-secret", b.Password)
+	})
+
+	t.Run("value copied to another bank -> does not open", func(t *testing.T) {
+		abank, err := st.BankService.GetBankByCode(st.Ctx, "abank")
+		require.NoError(t, err)
+		db := st.Storage.DB()
+		repo := sqlite.NewBankRepo(db, oldBox)
+
+		var own, copied string
+		require.NoError(t, db.QueryRowContext(st.Ctx, `SELECT password FROM banks WHERE id = ?`, abank.ID).Scan(&own))
+		require.NoError(t, db.QueryRowContext(st.Ctx, `SELECT password FROM banks WHERE id = ?`, vbank.ID).Scan(&copied))
+
+		_, err = db.ExecContext(st.Ctx, `UPDATE banks SET password = ? WHERE id = ?`, copied, abank.ID)
+		require.NoError(t, err)
+		_, err = repo.GetBankByCode(st.Ctx, "abank")
+		require.ErrorIs(t, err, secret.ErrCorrupted)
+		_, err = db.ExecContext(st.Ctx, `UPDATE banks SET password = ? WHERE id = ?`, own, abank.ID)
+		require.NoError(t, err)
+
+		_, err = db.ExecContext(st.Ctx, `
+INSERT INTO bank_tokens (bank_id, access_token, expires_at, created_at, updated_at)
+SELECT ?, access_token, expires_at, created_at, updated_at FROM bank_tokens WHERE bank_id = ?`, abank.ID, vbank.ID)
+		require.NoError(t, err)
+		_, err = repo.GetBankToken(st.Ctx, abank.ID)
+		require.ErrorIs(t, err, secret.ErrCorrupted)
+		require.NoError(t, repo.DeleteBankToken(st.Ctx, abank.ID))
+
+		b, err := repo.GetBankByCode(st.Ctx, "abank")
+		require.NoError(t, err)
+		require.Equal(t, abank.Password, b.Password)
+	})
+
+	t.Run("rotation re-encrypts every value by the new key", func(t *testing.T) {
+		cachedBefore, err := sqlite.NewBankRepo(st.Storage.DB(), oldBox).GetBankToken(st.Ctx, vbank.ID)
+		require.NoError(t, err)
+
+		newBox, err := secret.NewBox(secret.GenerateKey(), st.Cfg.Secrets.MasterKey)
+		require.NoError(t, err)
+		repo := sqlite.NewBankRepo(st.Storage.DB(), newBox)
+
+		n, err := repo.EncryptSecrets(st.Ctx)
+		require.NoError(t, err)
+		require.Equal(t, len(st.FakeBanks)+1, n)
+
+		n, err = repo.EncryptSecrets(st.Ctx)
+		require.NoError(t, err)
+		require.Zero(t, n)
+
+		for _, v := range stored(t) {
+			require.True(t, newBox.Current(v))
+		}
+
+		b, err := repo.GetBankByCode(st.Ctx, "vbank")
+		require.NoError(t, err)
+		require.Equal(t, vbank.Password, b.Password)
+
+		cached, err := repo.GetBankToken(st.Ctx, vbank.ID)
+		require.NoError(t, err)
+		require.Equal(t, cachedBefore.AccessToken, cached.AccessToken)
+
+		// the old key alone does not open values any more
+		_, err = sqlite.NewBankRepo(st.Storage.DB(), oldBox).GetBankByCode(st.Ctx, "vbank")
+		require.ErrorIs(t, err, secret.ErrUnknownKey)
+	})
+}
+
+// legacySeal seals the value as earlier versions did: enc:v1:, without additional data
+func legacySeal(t *testing.T, masterKey, plaintext string) string {
+	t.Helper()
+
+	gcm := func(key []byte) cipher.AEAD {
+		block, err := aes.NewCipher(key)
+		require.NoError(t, err)
+		aead, err := cipher.NewGCM(block)
+		require.NoError(t, err)
+		return aead
+	}
+	seal := func(aead cipher.AEAD, plaintext []byte) []byte {
+		nonce := make([]byte, aead.NonceSize())
+		_, _ = rand.Read(nonce)
+		return aead.Seal(nonce, nonce, plaintext, nil)
+	}
+
+	master, err := base64.StdEncoding.DecodeString(masterKey)
+	require.NoError(t, err)
+	sum := sha256.Sum256(master)
+	dek := make([]byte, 32)
+	_, _ = rand.Read(dek)
+
+	enc := base64.RawStdEncoding
+	return "enc:v1:" + hex.EncodeToString(sum[:4]) + ":" + enc.EncodeToString(seal(gcm(master), dek)) + ":" +
+		enc.EncodeToString(seal(gcm(dek), []byte(plaintext)))
+}
@@ -0,0 +1,76 @@
+// tests/consent_e2e_test.go
+
+package tests
+
+import (
+	"fmt"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/http-server/dto"
+	"net/http"
+	"testing"
+
+	"multibank/backend/tests/suite"
+	testutils "multibank/backend/tests/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestHTTP_ConsentRevokeOffline revokes a consent at the fake bank; other users cannot see or revoke it
+func TestHTTP_ConsentRevokeOffline(t *testing.T) {
+	st := suite.NewOffline(t)
+	defer st.Cancel()
+
+	owner := testutils.
+		PostWithBody(t, st, "/auth/register", testutils.NewFakeUser()).
+		ExpectStatus(t, http.StatusCreated).
+		DecodeTokenResponse(t).AccessToken
+	other := testutils.
+		PostWithBody(t, st, "/auth/register", testutils.NewFakeUser()).
+		ExpectStatus(t, http.StatusCreated).
+		DecodeTokenResponse(t).AccessToken
+
+	resp := testutils.PostWithAuth(t, st, "/consents/request", owner, dto.ConsentCreateRequest{
+		BankCode: "vbank",
+		ClientID: "team014-1",
+	}).ExpectStatus(t, http.StatusCreated).Resp
+	c := testutils.DecodeJSON[dto.ConsentResponse](t, resp)
+	require.Equal(t, domain.Authorised, c.Status)
+	require.NotNil(t, c.ConsentID)
+	path := fmt.Sprintf("/consents/%d", c.ID)
+
+	t.Run("invalid id -> 400", func(t *testing.T) {
+		testutils.GetWithAuth(t, st, "/consents/abc", owner).ExpectStatus(t, http.StatusBadRequest)
+		testutils.PostWithAuth(t, st, "/consents/abc/refresh", owner, nil).ExpectStatus(t, http.StatusBadRequest)
+		testutils.DeleteWithAuth(t, st, "/consents/abc", owner).ExpectStatus(t, http.StatusBadRequest)
+	})
+
+	t.Run("unknown consent -> 404", func(t *testing.T) {
+		testutils.DeleteWithAuth(t, st, "/consents/999999", owner).ExpectStatus(t, http.StatusNotFound)
+	})
+
+	t.Run("consent of another user -> 404, nothing revoked", func(t *testing.T) {
+		testutils.GetWithAuth(t, st, path, other).ExpectStatus(t, http.StatusNotFound)
+		testutils.PostWithAuth(t, st, path+"/refresh", other, nil).ExpectStatus(t, http.StatusNotFound)
+		testutils.DeleteWithAuth(t, st, path, other).ExpectStatus(t, http.StatusNotFound)
+
+		status, ok := st.FakeBanks["vbank"].ConsentStatus(*c.ConsentID)
+		require.True(t, ok)
+		require.Equal(t, "Authorized", status)
+	})
+
+	t.Run("owner revokes -> revoked at the bank and kept locally", func(t *testing.T) {
+		testutils.DeleteWithAuth(t, st, path, owner).ExpectStatus(t, http.StatusNoContent)
+
+		status, ok := st.FakeBanks["vbank"].ConsentStatus(*c.ConsentID)
+		require.True(t, ok)
+		require.Equal(t, "Revoked", status)
+
+		resp := testutils.GetWithAuth(t, st, path, owner).ExpectStatus(t, http.StatusOK).Resp
+		got := testutils.DecodeJSON[dto.ConsentResponse](t, resp)
+		require.Equal(t, domain.Revoked, got.Status)
+		require.NotNil(t, got.RevokedAt)
+
+		// repeated revoke is a no-op
+		testutils.DeleteWithAuth(t, st, path, owner).ExpectStatus(t, http.StatusNoContent)
+	})
+}
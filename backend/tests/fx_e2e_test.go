@@ -0,0 +1,91 @@
+// tests/fx_e2e_test.go
+
+package tests
+
+import (
+	"multibank/backend/internal/http-server/dto"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"multibank/backend/tests/suite"
+	testutils "multibank/backend/tests/utils"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// TestHTTP_NetWorthOffline imports the Bank of Russia daily file (windows-1251, decimal commas, nominals)
+// and converts the balances into the requested base currency
+func TestHTTP_NetWorthOffline(t *testing.T) {
+	st := suite.NewOffline(t)
+	defer st.Cancel()
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	xml := `<?xml version="1.0" encoding="windows-1251"?>
+<ValCurs Date="` + today.Format("02.01.2006") + `" name="Foreign Currency Market">
+  <Valute ID="R01235"><NumCode>840</NumCode><CharCode>USD</CharCode><Nominal>1</Nominal><Name>Доллар США</Name><Value>80,0000</Value></Valute>
+  <Valute ID="R01820"><NumCode>392</NumCode><CharCode>JPY</CharCode><Nominal>100</Nominal><Name>Японских иен</Name><Value>50,0000</Value></Valute>
+</ValCurs>`
+	encoded, err := charmap.Windows1251.NewEncoder().String(xml)
+	require.NoError(t, err)
+	source := filepath.Join(t.TempDir(), "XML_daily.asp")
+	require.NoError(t, os.WriteFile(source, []byte(encoded), 0o600))
+
+	n, err := st.FXService.ImportFrom(st.Ctx, source)
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+
+	token := testutils.
+		PostWithBody(t, st, "/auth/register", testutils.NewFakeUser()).
+		ExpectStatus(t, http.StatusCreated).
+		DecodeTokenResponse(t).AccessToken
+	testutils.PostWithAuth(t, st, "/consents/request", token, dto.ConsentCreateRequest{
+		BankCode: "vbank",
+		ClientID: "team014-1",
+	}).ExpectStatus(t, http.StatusCreated)
+
+	netWorth := func(t *testing.T, base string) dto.NetWorthResponse {
+		t.Helper()
+		resp := testutils.GetWithAuth(t, st, "/me/net-worth?base="+base, token).
+			ExpectStatus(t, http.StatusOK).Resp
+		return testutils.DecodeJSON[dto.NetWorthResponse](t, resp)
+	}
+	total := func(t *testing.T, nw dto.NetWorthResponse) float64 {
+		t.Helper()
+		v, err := strconv.ParseFloat(nw.Total, 64)
+		require.NoError(t, err)
+		return v
+	}
+
+	rub := netWorth(t, "RUB")
+	require.Len(t, rub.Accounts, 2)
+	require.Empty(t, rub.Rates)
+	require.NotZero(t, total(t, rub))
+
+	t.Run("USD -> converted by the imported rate", func(t *testing.T) {
+		usd := netWorth(t, "USD")
+		require.Equal(t, "USD", usd.BaseCurrency)
+		require.InDelta(t, total(t, rub)/80, total(t, usd), 0.01)
+		require.Len(t, usd.Rates, 1)
+		require.Equal(t, "RUB", usd.Rates[0].Currency)
+		require.InDelta(t, 1.0/80, usd.Rates[0].Rate, 1e-9)
+		require.Equal(t, today.Format("2006-01-02"), usd.Rates[0].Date)
+		for _, a := range usd.Accounts {
+			require.True(t, a.Included)
+			require.Equal(t, "RUB", a.Currency)
+		}
+	})
+
+	t.Run("JPY -> rate per unit, not per nominal", func(t *testing.T) {
+		jpy := netWorth(t, "JPY")
+		require.InDelta(t, total(t, rub)/0.5, total(t, jpy), 0.01)
+	})
+
+	t.Run("unknown base -> 400", func(t *testing.T) {
+		testutils.GetWithAuth(t, st, "/me/net-worth?base=XYZ", token).ExpectStatus(t, http.StatusBadRequest)
+	})
+}
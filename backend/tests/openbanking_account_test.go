@@ -0,0 +1,122 @@
+// tests/openbanking_account_test.go
+
+package tests
+
+import (
+	"io"
+	"log/slog"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/service/openbanking"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// cannedBank answers every request with the body
+func cannedBank(t *testing.T, body string, seen *http.Request) domain.Bank {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*seen = *r.Clone(r.Context())
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, body)
+	}))
+	t.Cleanup(srv.Close)
+
+	return domain.Bank{ID: 1, Code: "cannedbank", Name: "Canned Bank", APIBaseURL: srv.URL, IsEnabled: true}
+}
+
+// TestOpenBanking_TransactionDates reads dates of different layouts; a bad date does not fail the page
+func TestOpenBanking_TransactionDates(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	var seen http.Request
+	bank := cannedBank(t, `{
+  "data": {"transaction": [
+    {"accountId": "acc-1", "transactionId": "tx-1", "amount": {"amount": "100.00", "currency": "RUB"},
+     "creditDebitIndicator": "Credit", "status": "Booked",
+     "bookingDateTime": "2025-10-01T12:30:00+03:00", "valueDateTime": "2025-10-01T12:30:00.123Z"},
+    {"accountId": "acc-1", "transactionId": "tx-2", "amount": {"amount": "50.00", "currency": "RUB"},
+     "creditDebitIndicator": "Debit", "status": "Booked",
+     "bookingDateTime": "2025-10-02T08:00:00", "valueDateTime": "2025-10-03"},
+    {"accountId": "acc-1", "transactionId": "tx-3", "amount": {"amount": "10.00", "currency": "RUB"},
+     "creditDebitIndicator": "Debit", "status": "Pending",
+     "bookingDateTime": "02.10.2025", "valueDateTime": ""}
+  ]},
+  "meta": {"totalPages": 3}
+}`, &seen)
+
+	client := openbanking.NewAccountClient(log, &http.Client{Timeout: 5 * time.Second})
+	from := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	txs, pages, err := client.ListTransactions(t.Context(), bank, "acc-1", "token", "consent-1", "team014",
+		domain.TransactionFilter{From: &from, Page: 2, Limit: 100})
+	require.NoError(t, err)
+	require.Equal(t, 3, pages)
+	require.Len(t, txs, 3)
+
+	require.Equal(t, "2025-10-01T00:00:00Z", seen.URL.Query().Get("from_booking_date_time"))
+	require.Equal(t, "2", seen.URL.Query().Get("page"))
+	require.Equal(t, "consent-1", seen.Header.Get("x-consent-id"))
+
+	require.NotNil(t, txs[0].BookingDateTime)
+	require.True(t, txs[0].BookingDateTime.Equal(time.Date(2025, 10, 1, 9, 30, 0, 0, time.UTC)))
+	require.NotNil(t, txs[0].ValueDateTime)
+	require.Equal(t, 123*time.Millisecond, time.Duration(txs[0].ValueDateTime.Nanosecond()))
+
+	// without a zone the time is UTC
+	require.NotNil(t, txs[1].BookingDateTime)
+	require.Equal(t, time.Date(2025, 10, 2, 8, 0, 0, 0, time.UTC), *txs[1].BookingDateTime)
+	require.Equal(t, time.Date(2025, 10, 3, 0, 0, 0, 0, time.UTC), *txs[1].ValueDateTime)
+	require.Equal(t, "Debit", txs[1].CreditDebitIndicator)
+
+	// unknown layout and empty dates are left unset
+	require.Equal(t, "tx-3", txs[2].TransactionID)
+	require.Nil(t, txs[2].BookingDateTime)
+	require.Nil(t, txs[2].ValueDateTime)
+}
+
+// TestOpenBanking_Balances returns every balance type with the sign taken from creditDebitIndicator
+func TestOpenBanking_Balances(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	var seen http.Request
+	bank := cannedBank(t, `{
+  "data": {"balance": [
+    {"accountId": "acc-1", "type": "ClosingAvailable", "dateTime": "2025-10-01T00:00:00Z",
+     "amount": {"amount": "1500.00", "currency": "RUB"}, "creditDebitIndicator": "Debit",
+     "creditLine": [{"included": true, "type": "Pre-Agreed", "amount": {"amount": "50000.00", "currency": "RUB"}}]},
+    {"accountId": "acc-1", "type": "InterimBooked", "dateTime": "2025-10-02T10:00:00Z",
+     "amount": {"amount": "200.50", "currency": "RUB"}, "creditDebitIndicator": "Credit"},
+    {"accountId": "acc-1", "type": "InterimAvailable", "dateTime": "2025-10-02T10:00:00Z",
+     "amount": {"amount": "700.00", "currency": "RUB"}, "creditDebitIndicator": "Debit"}
+  ]}
+}`, &seen)
+
+	client := openbanking.NewAccountClient(log, &http.Client{Timeout: 5 * time.Second})
+
+	balances, err := client.GetBalances(t.Context(), bank, "acc-1", "token", "consent-1", "team014")
+	require.NoError(t, err)
+	require.Equal(t, "/accounts/acc-1/balances", seen.URL.Path)
+	require.Len(t, balances, 3)
+
+	closing := balances[0]
+	require.Equal(t, "ClosingAvailable", closing.Type)
+	require.Equal(t, "-1500.00", closing.Amount)
+	require.Equal(t, "Debit", closing.CreditDebitIndicator)
+	require.Equal(t, time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC), *closing.DateTime)
+	require.Equal(t, []domain.CreditLine{{Included: true, Type: "Pre-Agreed", Amount: "50000.00", Currency: "RUB"}},
+		closing.CreditLines)
+
+	require.Equal(t, "200.50", balances[1].Amount)
+	require.Empty(t, balances[1].CreditLines)
+
+	// InterimAvailable is shown as the balance of the account, an overdraft is negative
+	primary, ok := domain.PrimaryBalance(balances)
+	require.True(t, ok)
+	require.Equal(t, "InterimAvailable", primary.Type)
+	require.Equal(t, "-700.00", primary.Amount)
+}
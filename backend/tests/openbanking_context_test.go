@@ -0,0 +1,100 @@
+// tests/openbanking_context_test.go
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/service/openbanking"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// hangingBank answers only when the request is cancelled by the client
+func hangingBank(t *testing.T, calls *atomic.Int32) domain.Bank {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		_, _ = io.Copy(io.Discard, r.Body) // the closed connection is noticed only after the body is read
+		select {
+		case <-r.Context().Done():
+		case <-time.After(10 * time.Second):
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return domain.Bank{ID: 1, Code: "hangbank", Name: "Hanging Bank", APIBaseURL: srv.URL, IsEnabled: true}
+}
+
+func TestOpenBanking_ContextPropagation(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	var calls atomic.Int32
+	bank := hangingBank(t, &calls)
+
+	transport := openbanking.NewTransport(log, nil, openbanking.DefaultTransportOptions())
+	httpClient := &http.Client{Timeout: 10 * time.Second, Transport: transport}
+
+	consents := openbanking.NewConsentClient(log, httpClient, "team014", "Team 14 Multibank", "test")
+	accounts := openbanking.NewAccountClient(log, httpClient)
+
+	cases := map[string]func(ctx context.Context) error{
+		"RequestConsent": func(ctx context.Context) error {
+			_, err := consents.RequestConsent(ctx, bank, "team014-1", []domain.Permission{domain.ReadBalances}, "token")
+			return err
+		},
+		"GetConsent": func(ctx context.Context) error {
+			_, err := consents.GetConsent(ctx, bank, "consent-1", "token", "team014")
+			return err
+		},
+		"ListAccounts": func(ctx context.Context) error {
+			_, err := accounts.ListAccounts(ctx, bank, "team014-1", "token", "consent-1", "team014")
+			return err
+		},
+		"GetBalances": func(ctx context.Context) error {
+			_, err := accounts.GetBalances(ctx, bank, "acc-1", "token", "consent-1", "team014")
+			return err
+		},
+	}
+
+	for name, call := range cases {
+		t.Run(name+": deadline stops the bank call", func(t *testing.T) {
+			calls.Store(0)
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+
+			start := time.Now()
+			err := call(ctx)
+			require.Error(t, err)
+			require.True(t, errors.Is(err, context.DeadlineExceeded), "got %v", err)
+			require.Less(t, time.Since(start), 2*time.Second)
+			require.EqualValues(t, 1, calls.Load(), "expired calls must not be retried")
+		})
+
+		t.Run(name+": cancellation stops the bank call", func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(100*time.Millisecond, cancel)
+
+			start := time.Now()
+			err := call(ctx)
+			require.True(t, errors.Is(err, context.Canceled), "got %v", err)
+			require.Less(t, time.Since(start), 2*time.Second)
+		})
+	}
+
+	t.Run("cancelled calls do not open the circuit", func(t *testing.T) {
+		for _, b := range transport.Breakers() {
+			require.NotEqual(t, domain.BreakerOpen, b.State, "bank %s", b.BankCode)
+		}
+	})
+}
@@ -0,0 +1,288 @@
+// tests/openbanking_transport_test.go
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/service/openbanking"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedBank answers the n-th call (from 1) with reply
+func scriptedBank(t *testing.T, code string, calls *atomic.Int32, reply func(n int32, w http.ResponseWriter, r *http.Request)) domain.Bank {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reply(calls.Add(1), w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	return domain.Bank{ID: 1, Code: code, Name: code, APIBaseURL: srv.URL, IsEnabled: true}
+}
+
+// TestOpenBanking_Transport retries failed idempotent calls, waits for Retry-After of 429
+// and opens the circuit of a failing bank until a probe succeeds
+func TestOpenBanking_Transport(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	transport := openbanking.NewTransport(log, http.DefaultTransport, openbanking.TransportOptions{
+		MaxAttempts:      3,
+		BaseDelay:        time.Millisecond,
+		MaxDelay:         5 * time.Millisecond,
+		FailureThreshold: 2,
+		OpenTimeout:      200 * time.Millisecond,
+	})
+	client := &http.Client{Timeout: 5 * time.Second, Transport: transport}
+
+	call := func(t *testing.T, bank domain.Bank, method, body string, idempotent bool) (int, error) {
+		t.Helper()
+		ctx := openbanking.WithBank(t.Context(), bank)
+		if idempotent {
+			ctx = openbanking.WithIdempotent(ctx)
+		}
+		var rd io.Reader
+		if body != "" {
+			rd = strings.NewReader(body)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, bank.APIBaseURL+"/accounts", rd)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode, nil
+	}
+	breaker := func(code string) (domain.BankBreaker, bool) {
+		for _, b := range transport.Breakers() {
+			if b.BankCode == code {
+				return b, true
+			}
+		}
+		return domain.BankBreaker{}, false
+	}
+
+	t.Run("5xx of GET -> retried until success", func(t *testing.T) {
+		var calls atomic.Int32
+		bank := scriptedBank(t, "flaky", &calls, func(n int32, w http.ResponseWriter, _ *http.Request) {
+			if n < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		})
+
+		code, err := call(t, bank, http.MethodGet, "", false)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, code)
+		require.EqualValues(t, 3, calls.Load())
+		_, ok := breaker("flaky")
+		require.False(t, ok)
+	})
+
+	t.Run("5xx of POST -> retried only when marked idempotent, with the same body", func(t *testing.T) {
+		var calls atomic.Int32
+		var bodies []string
+		bank := scriptedBank(t, "posts", &calls, func(_ int32, w http.ResponseWriter, r *http.Request) {
+			b, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(b))
+			w.WriteHeader(http.StatusBadGateway)
+		})
+
+		code, err := call(t, bank, http.MethodPost, `{"amount":"10.00"}`, false)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadGateway, code)
+		require.EqualValues(t, 1, calls.Load())
+
+		_, err = call(t, bank, http.MethodPost, `{"amount":"10.00"}`, true)
+		require.NoError(t, err)
+		require.EqualValues(t, 4, calls.Load())
+		require.Len(t, bodies, 4)
+		for _, b := range bodies {
+			require.Equal(t, `{"amount":"10.00"}`, b)
+		}
+	})
+
+	t.Run("429 -> retried after Retry-After, the circuit stays closed", func(t *testing.T) {
+		var calls atomic.Int32
+		bank := scriptedBank(t, "busy", &calls, func(n int32, w http.ResponseWriter, _ *http.Request) {
+			if n == 1 {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+			}
+		})
+
+		start := time.Now()
+		code, err := call(t, bank, http.MethodGet, "", false)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, code)
+		require.EqualValues(t, 2, calls.Load())
+		require.GreaterOrEqual(t, time.Since(start), 900*time.Millisecond)
+		_, ok := breaker("busy")
+		require.False(t, ok)
+	})
+
+	t.Run("bank keeps failing -> circuit open, fails fast, closed by a successful probe", func(t *testing.T) {
+		var calls atomic.Int32
+		var up atomic.Bool
+		bank := scriptedBank(t, "down", &calls, func(_ int32, w http.ResponseWriter, _ *http.Request) {
+			if !up.Load() {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		})
+
+		for range 2 {
+			code, err := call(t, bank, http.MethodGet, "", false)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusServiceUnavailable, code)
+		}
+		require.EqualValues(t, 6, calls.Load())
+
+		b, ok := breaker("down")
+		require.True(t, ok)
+		require.Equal(t, domain.BreakerOpen, b.State)
+		require.Equal(t, 2, b.Failures)
+		require.NotNil(t, b.RetryAt)
+		require.Contains(t, b.LastError, "503")
+
+		_, err := call(t, bank, http.MethodGet, "", false)
+		require.True(t, errors.Is(err, openbanking.ErrCircuitOpen))
+		require.EqualValues(t, 6, calls.Load())
+
+		// other banks are not affected
+		var other atomic.Int32
+		code, err := call(t, scriptedBank(t, "other", &other, func(int32, http.ResponseWriter, *http.Request) {}),
+			http.MethodGet, "", false)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, code)
+
+		up.Store(true)
+		time.Sleep(250 * time.Millisecond)
+
+		code, err = call(t, bank, http.MethodGet, "", false)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, code)
+		b, _ = breaker("down")
+		require.Equal(t, domain.BreakerClosed, b.State)
+		require.Zero(t, b.Failures)
+	})
+}
+
+// TestOpenBanking_RateLimit spaces requests to a bank by its rate_limit and rate_burst. Every bank has
+// its own bucket, and bank tokens are requested through the same limiter as the other calls.
+func TestOpenBanking_RateLimit(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	opts := openbanking.DefaultTransportOptions()
+	opts.MaxAttempts = 1
+	client := &http.Client{Timeout: 5 * time.Second, Transport: openbanking.NewTransport(log, http.DefaultTransport, opts)}
+
+	// limitedBank records when each call reached it
+	limitedBank := func(t *testing.T, code string, rate float64, burst int) (domain.Bank, func() []time.Time) {
+		t.Helper()
+		var (
+			mu   sync.Mutex
+			seen []time.Time
+		)
+		var calls atomic.Int32
+		bank := scriptedBank(t, code, &calls, func(_ int32, w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			seen = append(seen, time.Now())
+			mu.Unlock()
+			if r.URL.Path == "/auth/bank-token" {
+				_, _ = io.WriteString(w, `{"access_token":"token","expires_in":3600}`)
+			}
+		})
+		bank.RateLimit, bank.RateBurst = rate, burst
+		return bank, func() []time.Time {
+			mu.Lock()
+			defer mu.Unlock()
+			return slices.Clone(seen)
+		}
+	}
+	send := func(ctx context.Context, bank domain.Bank) error {
+		req, err := http.NewRequestWithContext(openbanking.WithBank(ctx, bank), http.MethodGet,
+			bank.APIBaseURL+"/accounts", nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+	get := func(t *testing.T, bank domain.Bank) {
+		t.Helper()
+		require.NoError(t, send(t.Context(), bank))
+	}
+
+	t.Run("burst -> the first rate_burst requests at once, the rest 1/rate_limit apart", func(t *testing.T) {
+		bank, seen := limitedBank(t, "limited", 20, 2) // 50ms apart
+
+		for range 6 {
+			get(t, bank)
+		}
+
+		at := seen()
+		require.Len(t, at, 6)
+		require.Less(t, at[1].Sub(at[0]), 40*time.Millisecond)
+		for i := 2; i < len(at); i++ {
+			require.GreaterOrEqual(t, at[i].Sub(at[i-1]), 35*time.Millisecond, "request %d", i+1)
+		}
+		require.GreaterOrEqual(t, at[5].Sub(at[0]), 190*time.Millisecond)
+	})
+
+	t.Run("throttled bank -> another bank is not delayed", func(t *testing.T) {
+		slow, seen := limitedBank(t, "slow", 5, 1) // 200ms apart
+		fast, _ := limitedBank(t, "fast", 0, 0)
+
+		errs := make(chan error, 4)
+		var wg sync.WaitGroup
+		for range 4 {
+			wg.Go(func() { errs <- send(t.Context(), slow) })
+		}
+		time.Sleep(20 * time.Millisecond)
+
+		start := time.Now()
+		for range 5 {
+			get(t, fast)
+		}
+		require.Less(t, time.Since(start), 150*time.Millisecond)
+
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			require.NoError(t, err)
+		}
+		at := seen()
+		require.Len(t, at, 4)
+		slices.SortFunc(at, time.Time.Compare)
+		require.GreaterOrEqual(t, at[3].Sub(at[0]), 550*time.Millisecond)
+	})
+
+	t.Run("bank token refresh -> waits in the same bucket", func(t *testing.T) {
+		bank, seen := limitedBank(t, "tokens", 5, 1) // 200ms apart
+		bank.Login, bank.Password = "team", "secret"
+
+		get(t, bank)
+		tok, err := openbanking.NewTokenClient(log, client).Token(t.Context(), bank)
+		require.NoError(t, err)
+		require.Equal(t, "token", tok.AccessToken)
+
+		at := seen()
+		require.Len(t, at, 2)
+		require.GreaterOrEqual(t, at[1].Sub(at[0]), 180*time.Millisecond)
+	})
+}
@@ -0,0 +1,231 @@
+// tests/payments_e2e_test.go
+
+package tests
+
+import (
+	"fmt"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/http-server/dto"
+	"net/http"
+	"sync"
+	"testing"
+
+	"multibank/backend/tests/suite"
+	testutils "multibank/backend/tests/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestHTTP_PaymentsOffline makes a payment with a single-use consent: concurrent calls reach the bank once,
+// a used or not yet approved consent cannot pay
+func TestHTTP_PaymentsOffline(t *testing.T) {
+	st := suite.NewOffline(t)
+	defer st.Cancel()
+
+	token := testutils.
+		PostWithBody(t, st, "/auth/register", testutils.NewFakeUser()).
+		ExpectStatus(t, http.StatusCreated).
+		DecodeTokenResponse(t).AccessToken
+
+	testutils.PostWithAuth(t, st, "/consents/request", token, dto.ConsentCreateRequest{
+		BankCode: "vbank",
+		ClientID: "team014-1",
+	}).ExpectStatus(t, http.StatusCreated)
+
+	resp := testutils.GetWithAuth(t, st, "/accounts", token).ExpectStatus(t, http.StatusOK).Resp
+	accounts := testutils.DecodeJSON[[]dto.AccountResponse](t, resp)
+	require.Len(t, accounts, 2)
+	debtor, creditor := accounts[0], accounts[1]
+	fake := st.FakeBanks["vbank"]
+
+	newConsent := func(t *testing.T, amount string) dto.PaymentConsentResponse {
+		t.Helper()
+		resp := testutils.PostWithAuth(t, st, "/payments/consents", token, dto.PaymentConsentCreateRequest{
+			AccountID:       debtor.AccountID,
+			Amount:          amount,
+			CreditorAccount: creditor.Identification,
+			Reference:       "Savings",
+		}).ExpectStatus(t, http.StatusCreated).Resp
+		return testutils.DecodeJSON[dto.PaymentConsentResponse](t, resp)
+	}
+	pay := func(t *testing.T, consentID int64) *http.Response {
+		t.Helper()
+		return testutils.PostWithAuth(t, st, "/payments", token, dto.PaymentCreateRequest{
+			PaymentConsentID: consentID,
+		}).Resp
+	}
+
+	t.Run("amount with more than two fraction digits -> 400", func(t *testing.T) {
+		for _, amount := range []string{"10.505", "1e3", "-5", "0"} {
+			testutils.PostWithAuth(t, st, "/payments/consents", token, dto.PaymentConsentCreateRequest{
+				AccountID:       debtor.AccountID,
+				Amount:          amount,
+				CreditorAccount: creditor.Identification,
+			}).ExpectStatus(t, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("concurrent payments with one consent -> one payment in the bank", func(t *testing.T) {
+		c := newConsent(t, "100.5")
+		require.Equal(t, domain.Authorised, c.Status)
+		require.Equal(t, "100.50", c.Amount)
+
+		const calls = 5
+		codes := make(chan int, calls)
+		var wg sync.WaitGroup
+		for range calls {
+			wg.Go(func() {
+				resp := pay(t, c.ID)
+				_ = resp.Body.Close()
+				codes <- resp.StatusCode
+			})
+		}
+		wg.Wait()
+		close(codes)
+
+		created := 0
+		for code := range codes {
+			if code == http.StatusCreated {
+				created++
+				continue
+			}
+			require.Equal(t, http.StatusConflict, code)
+		}
+		require.Equal(t, 1, created)
+		require.Equal(t, 1, fake.PaymentCount())
+	})
+
+	t.Run("used consent -> 409, the payment settles", func(t *testing.T) {
+		c := newConsent(t, "20.00")
+		resp := pay(t, c.ID)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		p := testutils.DecodeJSON[dto.PaymentResponse](t, resp)
+		require.Equal(t, domain.PaymentAcceptedSettlementProcess, p.Status)
+		require.False(t, p.Final)
+
+		require.Equal(t, http.StatusConflict, pay(t, c.ID).StatusCode)
+		require.Equal(t, 2, fake.PaymentCount())
+
+		resp = testutils.GetWithAuth(t, st, fmt.Sprintf("/payments/%d", p.ID), token).
+			ExpectStatus(t, http.StatusOK).Resp
+		p = testutils.DecodeJSON[dto.PaymentResponse](t, resp)
+		require.Equal(t, domain.PaymentAcceptedSettlementComplete, p.Status)
+		require.True(t, p.Final)
+	})
+
+	t.Run("consent awaiting approval -> 409 until approved", func(t *testing.T) {
+		fake.SetAutoApprove(false)
+		defer fake.SetAutoApprove(true)
+
+		c := newConsent(t, "5.00")
+		require.Equal(t, domain.AwaitingAuthorisation, c.Status)
+		require.Equal(t, http.StatusConflict, pay(t, c.ID).StatusCode)
+
+		require.True(t, fake.ApprovePayment(c.RequestID))
+		require.Equal(t, http.StatusCreated, pay(t, c.ID).StatusCode)
+		require.Equal(t, 3, fake.PaymentCount())
+	})
+}
+
+// TestHTTP_PaymentResumeOffline resends a payment left with an unknown outcome in the background,
+// and releases the consent when the bank is cut off by its circuit breaker
+func TestHTTP_PaymentResumeOffline(t *testing.T) {
+	st := suite.NewOffline(t)
+	defer st.Cancel()
+
+	token := testutils.
+		PostWithBody(t, st, "/auth/register", testutils.NewFakeUser()).
+		ExpectStatus(t, http.StatusCreated).
+		DecodeTokenResponse(t).AccessToken
+
+	testutils.PostWithAuth(t, st, "/consents/request", token, dto.ConsentCreateRequest{
+		BankCode: "vbank",
+		ClientID: "team014-1",
+	}).ExpectStatus(t, http.StatusCreated)
+
+	resp := testutils.GetWithAuth(t, st, "/accounts", token).ExpectStatus(t, http.StatusOK).Resp
+	accounts := testutils.DecodeJSON[[]dto.AccountResponse](t, resp)
+	require.Len(t, accounts, 2)
+	consentReq := dto.PaymentConsentCreateRequest{
+		AccountID:       accounts[0].AccountID,
+		Amount:          "15.00",
+		CreditorAccount: accounts[1].Identification,
+	}
+	fake := st.FakeBanks["vbank"]
+
+	newConsent := func(t *testing.T) dto.PaymentConsentResponse {
+		t.Helper()
+		resp := testutils.PostWithAuth(t, st, "/payments/consents", token, consentReq).
+			ExpectStatus(t, http.StatusCreated).Resp
+		return testutils.DecodeJSON[dto.PaymentConsentResponse](t, resp)
+	}
+	pay := func(t *testing.T, consentID int64) *testutils.ResponseWrapper {
+		t.Helper()
+		return testutils.PostWithAuth(t, st, "/payments", token, dto.PaymentCreateRequest{PaymentConsentID: consentID})
+	}
+	byConsent := func(t *testing.T, consentID int64) (dto.PaymentResponse, bool) {
+		t.Helper()
+		resp := testutils.GetWithAuth(t, st, "/payments", token).ExpectStatus(t, http.StatusOK).Resp
+		for _, p := range testutils.DecodeJSON[[]dto.PaymentResponse](t, resp) {
+			if p.PaymentConsentID == consentID {
+				return p, true
+			}
+		}
+		return dto.PaymentResponse{}, false
+	}
+
+	t.Run("bank does not answer -> resent in the background with the same consent", func(t *testing.T) {
+		c := newConsent(t)
+
+		fake.SetFaults(0, 0, 1)
+		pay(t, c.ID).ExpectStatus(t, http.StatusBadGateway)
+		fake.SetFaults(0, 0, 0)
+
+		p, ok := byConsent(t, c.ID)
+		require.True(t, ok)
+		require.Equal(t, domain.PaymentInitiating, p.Status)
+		pay(t, c.ID).ExpectStatus(t, http.StatusConflict)
+
+		// not stale yet: a call may still be in flight
+		n, err := st.PaymentService.ResumePayments(st.Ctx, 10)
+		require.NoError(t, err)
+		require.Zero(t, n)
+
+		_, err = st.Storage.DB().ExecContext(st.Ctx,
+			`UPDATE payments SET updated_at = datetime('now', '-10 minutes') WHERE id = ?`, p.ID)
+		require.NoError(t, err)
+
+		n, err = st.PaymentService.ResumePayments(st.Ctx, 10)
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+		require.Equal(t, 1, fake.PaymentCount())
+
+		p, _ = byConsent(t, c.ID)
+		require.NotEqual(t, domain.PaymentInitiating, p.Status)
+		require.NotEmpty(t, p.PaymentID)
+		pay(t, c.ID).ExpectStatus(t, http.StatusConflict)
+
+		n, err = st.PaymentService.ResumePayments(st.Ctx, 10)
+		require.NoError(t, err)
+		require.Zero(t, n)
+	})
+
+	t.Run("circuit of the bank is open -> 503, the consent is not used", func(t *testing.T) {
+		c := newConsent(t)
+
+		fake.SetFaults(0, 0, 1)
+		defer fake.SetFaults(0, 0, 0)
+		for range 5 {
+			testutils.PostWithAuth(t, st, "/payments/consents", token, consentReq).
+				ExpectStatus(t, http.StatusBadGateway)
+		}
+
+		pay(t, c.ID).ExpectStatus(t, http.StatusServiceUnavailable)
+		_, ok := byConsent(t, c.ID)
+		require.False(t, ok, "the reservation is released")
+		require.Equal(t, 1, fake.PaymentCount())
+
+		// still cut off: not "in progress"
+		pay(t, c.ID).ExpectStatus(t, http.StatusServiceUnavailable)
+	})
+}
@@ -0,0 +1,192 @@
+// tests/product_catalogue_e2e_test.go
+
+package tests
+
+import (
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/http-server/dto"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"multibank/backend/tests/suite"
+	testutils "multibank/backend/tests/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestHTTP_ProductCatalogueOffline serves /products from the local catalogue: a bank whose API is down
+// keeps its last fetched products, marked with bank_health "down"
+func TestHTTP_ProductCatalogueOffline(t *testing.T) {
+	st := suite.NewOffline(t)
+	defer st.Cancel()
+
+	token := testutils.
+		PostWithBody(t, st, "/auth/register", testutils.NewFakeUser()).
+		ExpectStatus(t, http.StatusCreated).
+		DecodeTokenResponse(t).AccessToken
+
+	byBank := func(t *testing.T) map[string][]dto.ProductResponse {
+		t.Helper()
+		resp := testutils.GetWithAuth(t, st, "/products", token).ExpectStatus(t, http.StatusOK).Resp
+		out := map[string][]dto.ProductResponse{}
+		for _, p := range testutils.DecodeJSON[[]dto.ProductResponse](t, resp) {
+			out[p.BankCode] = append(out[p.BankCode], p)
+		}
+		return out
+	}
+	sources := func(t *testing.T) map[string]dto.ProductSourceResponse {
+		t.Helper()
+		resp := testutils.GetWithAuth(t, st, "/products/sources", token).ExpectStatus(t, http.StatusOK).Resp
+		out := map[string]dto.ProductSourceResponse{}
+		for _, s := range testutils.DecodeJSON[[]dto.ProductSourceResponse](t, resp) {
+			out[s.BankCode] = s
+		}
+		return out
+	}
+
+	var fetchedAt time.Time
+	t.Run("first access -> catalogue filled from every bank", func(t *testing.T) {
+		products := byBank(t)
+		require.Len(t, products, 3)
+		for _, items := range products {
+			require.Len(t, items, 5)
+			for _, p := range items {
+				require.Equal(t, domain.BankHealthOK, p.BankHealth)
+				require.WithinDuration(t, time.Now(), p.FetchedAt, time.Minute)
+			}
+		}
+		fetchedAt = products["vbank"][0].FetchedAt
+
+		for _, s := range sources(t) {
+			require.Equal(t, domain.BankHealthOK, s.Health)
+			require.Equal(t, 5, s.ProductsCount)
+			require.Zero(t, s.Failures)
+		}
+	})
+
+	fake := st.FakeBanks["vbank"]
+
+	t.Run("bank is down -> last fetched products, health down", func(t *testing.T) {
+		fake.SetFaults(0, 0, 1)
+		defer fake.SetFaults(0, 0, 0)
+
+		n, err := st.ProductService.Refresh(st.Ctx, 4)
+		require.NoError(t, err)
+		require.Equal(t, 10, n)
+
+		products := byBank(t)
+		require.Len(t, products["vbank"], 5)
+		for _, p := range products["vbank"] {
+			require.Equal(t, domain.BankHealthDown, p.BankHealth)
+			require.True(t, fetchedAt.Equal(p.FetchedAt), "fetched_at of the last successful fetch")
+		}
+		for _, p := range products["abank"] {
+			require.Equal(t, domain.BankHealthOK, p.BankHealth)
+		}
+
+		src := sources(t)["vbank"]
+		require.Equal(t, domain.BankHealthDown, src.Health)
+		require.Equal(t, 1, src.Failures)
+		require.NotEmpty(t, src.LastError)
+		require.Equal(t, 5, src.ProductsCount)
+	})
+}
+
+// TestHTTP_ProductSearchOffline filters and sorts the catalogue and walks it by cursor pages
+func TestHTTP_ProductSearchOffline(t *testing.T) {
+	st := suite.NewOffline(t)
+	defer st.Cancel()
+
+	token := testutils.
+		PostWithBody(t, st, "/auth/register", testutils.NewFakeUser()).
+		ExpectStatus(t, http.StatusCreated).
+		DecodeTokenResponse(t).AccessToken
+
+	list := func(t *testing.T, query string) ([]dto.ProductResponse, string) {
+		t.Helper()
+		resp := testutils.GetWithAuth(t, st, "/products?"+query, token).ExpectStatus(t, http.StatusOK).Resp
+		return testutils.DecodeJSON[[]dto.ProductResponse](t, resp), resp.Header.Get("X-Next-Cursor")
+	}
+	ids := func(items []dto.ProductResponse) []string {
+		out := make([]string, 0, len(items))
+		for _, p := range items {
+			out = append(out, p.ProductID)
+		}
+		return out
+	}
+
+	all, next := list(t, "")
+	require.Len(t, all, 15)
+	require.Empty(t, next)
+
+	t.Run("filters -> matching products only", func(t *testing.T) {
+		items, _ := list(t, "product_type=deposit&amount=20000")
+		require.Len(t, items, 3)
+		for _, p := range items {
+			require.Contains(t, p.ProductID, "-deposit-001")
+		}
+
+		items, _ = list(t, "term_from=6&term_to=12")
+		require.Len(t, items, 6)
+
+		items, _ = list(t, "q="+url.QueryEscape("КЭШБЭК"))
+		require.Len(t, items, 3)
+		for _, p := range items {
+			require.Equal(t, "card", p.ProductType)
+		}
+
+		minRate := 20.0
+		want := 0
+		for _, p := range all {
+			if p.InterestRate >= minRate {
+				want++
+			}
+		}
+		items, _ = list(t, "min_rate=20")
+		require.Len(t, items, want)
+		for _, p := range items {
+			require.GreaterOrEqual(t, p.InterestRate, minRate)
+		}
+	})
+
+	t.Run("sort -> best rate first, shortest term first", func(t *testing.T) {
+		items, _ := list(t, "sort=rate")
+		for i := 1; i < len(items); i++ {
+			require.GreaterOrEqual(t, items[i-1].InterestRate, items[i].InterestRate)
+		}
+
+		items, _ = list(t, "product_type=deposit&sort=term&order=asc")
+		require.Len(t, items, 6)
+		for i := 1; i < len(items); i++ {
+			require.LessOrEqual(t, items[i-1].TermMonths, items[i].TermMonths)
+		}
+	})
+
+	t.Run("cursor pages -> the whole list once, in order", func(t *testing.T) {
+		full, _ := list(t, "sort=rate")
+
+		var paged []dto.ProductResponse
+		query := "sort=rate&limit=4"
+		for pages := 0; ; pages++ {
+			require.Less(t, pages, 4)
+			items, next := list(t, query)
+			require.LessOrEqual(t, len(items), 4)
+			paged = append(paged, items...)
+			if next == "" {
+				break
+			}
+			query = "sort=rate&limit=4&cursor=" + url.QueryEscape(next)
+		}
+		require.Equal(t, ids(full), ids(paged))
+	})
+
+	t.Run("invalid parameters -> 400", func(t *testing.T) {
+		for _, query := range []string{
+			"sort=name", "order=up", "term_from=12&term_to=6", "min_rate=-1", "limit=0", "cursor=garbage",
+		} {
+			testutils.GetWithAuth(t, st, "/products?"+query, token).ExpectStatus(t, http.StatusBadRequest)
+		}
+	})
+}
@@ -0,0 +1,353 @@
+// tests/product_e2e_test.go
+
+package tests
+
+import (
+	"fmt"
+	"math"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/http-server/dto"
+	"net/http"
+	"testing"
+
+	"multibank/backend/tests/suite"
+	testutils "multibank/backend/tests/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestHTTP_RecommendationImpressionsOffline counts impressions of recommended products only in the catalogue;
+// comparisons and the recommendations list do not inflate them, clicks give the CTR
+func TestHTTP_RecommendationImpressionsOffline(t *testing.T) {
+	st := suite.NewOffline(t)
+	defer st.Cancel()
+
+	token := testutils.
+		PostWithBody(t, st, "/auth/register", testutils.NewFakeUser()).
+		ExpectStatus(t, http.StatusCreated).
+		DecodeTokenResponse(t).AccessToken
+	admin := testutils.RegisterAdmin(t, st)
+
+	testutils.PostWithAuth(t, st, "/admin/recommended-products", admin, dto.RecommendedUpsertRequest{
+		ProductID:   "prod-abank-deposit-001",
+		BankCode:    "abank",
+		ProductType: "deposit",
+	}).ExpectStatus(t, http.StatusNoContent)
+
+	impressions := func(t *testing.T) (int, int, float64) {
+		t.Helper()
+		resp := testutils.GetWithAuth(t, st, "/admin/recommended-products/stats", admin).
+			ExpectStatus(t, http.StatusOK).Resp
+		var shown, clicked int
+		var ctr float64
+		for _, s := range testutils.DecodeJSON[[]dto.RecommendationStatResponse](t, resp) {
+			require.Equal(t, "abank", s.BankCode)
+			shown, clicked, ctr = shown+s.Impressions, clicked+s.Clicks, s.CTR
+		}
+		return shown, clicked, ctr
+	}
+
+	t.Run("catalogue -> one impression", func(t *testing.T) {
+		resp := testutils.GetWithAuth(t, st, "/products?product_type=deposit", token).
+			ExpectStatus(t, http.StatusOK).Resp
+		var recommended int
+		for _, p := range testutils.DecodeJSON[[]dto.ProductResponse](t, resp) {
+			if p.IsRecommended {
+				recommended++
+			}
+		}
+		require.Equal(t, 1, recommended)
+
+		shown, _, _ := impressions(t)
+		require.Equal(t, 1, shown)
+	})
+
+	t.Run("comparisons and recommendations -> no impressions", func(t *testing.T) {
+		testutils.PostWithAuth(t, st, "/products/compare", token, dto.DepositCompareRequest{
+			Amount:     100000,
+			TermMonths: 12,
+		}).ExpectStatus(t, http.StatusOK)
+		testutils.PostWithAuth(t, st, "/products/compare-loans", token, dto.LoanCompareRequest{
+			Amount:     100000,
+			TermMonths: 12,
+		}).ExpectStatus(t, http.StatusOK)
+		testutils.GetWithAuth(t, st, "/me/recommendations", token).ExpectStatus(t, http.StatusOK)
+
+		shown, _, _ := impressions(t)
+		require.Equal(t, 1, shown)
+	})
+
+	t.Run("click -> CTR", func(t *testing.T) {
+		testutils.PostWithAuth(t, st, "/products/abank/prod-abank-deposit-001/click", token, nil).
+			ExpectStatus(t, http.StatusNoContent)
+
+		shown, clicked, ctr := impressions(t)
+		require.Equal(t, 1, shown)
+		require.Equal(t, 1, clicked)
+		require.InDelta(t, 1.0, ctr, 1e-9)
+	})
+
+	t.Run("not an admin -> no stats, the list is not changed", func(t *testing.T) {
+		testutils.GetWithAuth(t, st, "/admin/recommended-products/stats", token).ExpectStatus(t, http.StatusForbidden)
+		testutils.GetWithAuth(t, st, "/admin/recommended-products", token).ExpectStatus(t, http.StatusForbidden)
+		testutils.PostWithAuth(t, st, "/admin/recommended-products", token, dto.RecommendedUpsertRequest{
+			ProductID:   "prod-sbank-deposit-001",
+			BankCode:    "sbank",
+			ProductType: "deposit",
+		}).ExpectStatus(t, http.StatusForbidden)
+	})
+}
+
+// TestHTTP_LoanScheduleOffline builds annuity and differentiated schedules and compares credit products only
+func TestHTTP_LoanScheduleOffline(t *testing.T) {
+	st := suite.NewOffline(t)
+	defer st.Cancel()
+
+	token := testutils.
+		PostWithBody(t, st, "/auth/register", testutils.NewFakeUser()).
+		ExpectStatus(t, http.StatusCreated).
+		DecodeTokenResponse(t).AccessToken
+
+	// the first catalogue read fills the catalogue from the fake banks
+	testutils.GetWithAuth(t, st, "/products", token).ExpectStatus(t, http.StatusOK)
+
+	schedule := func(t *testing.T, typ string) dto.PaymentScheduleResponse {
+		t.Helper()
+		resp := testutils.GetWithAuth(t, st,
+			"/products/abank/prod-abank-loan-001/schedule?amount=120000&term=12&type="+typ, token).
+			ExpectStatus(t, http.StatusOK).Resp
+		s := testutils.DecodeJSON[dto.PaymentScheduleResponse](t, resp)
+		require.Len(t, s.Payments, 12)
+
+		var principal float64
+		for _, p := range s.Payments {
+			principal += p.Principal
+			require.InDelta(t, p.Principal+p.Interest, p.Payment, 0.005)
+		}
+		require.InDelta(t, 120000, principal, 0.005)
+		require.Zero(t, s.Payments[11].Balance)
+		require.InDelta(t, 120000+s.Overpayment, s.TotalPayment, 0.005)
+		return s
+	}
+
+	var annuity, differentiated dto.PaymentScheduleResponse
+	t.Run("annuity -> equal payments", func(t *testing.T) {
+		annuity = schedule(t, "annuity")
+		for _, p := range annuity.Payments[:11] {
+			require.Equal(t, annuity.Payments[0].Payment, p.Payment)
+		}
+	})
+
+	t.Run("differentiated -> equal principal, decreasing payments", func(t *testing.T) {
+		differentiated = schedule(t, "differentiated")
+		require.Equal(t, 10000.0, differentiated.Payments[0].Principal)
+		for i := 1; i < 12; i++ {
+			require.Less(t, differentiated.Payments[i].Payment, differentiated.Payments[i-1].Payment)
+		}
+		require.Less(t, differentiated.Overpayment, annuity.Overpayment)
+	})
+
+	t.Run("not a credit product -> 400", func(t *testing.T) {
+		testutils.GetWithAuth(t, st, "/products/abank/prod-abank-deposit-001/schedule?amount=120000", token).
+			ExpectStatus(t, http.StatusBadRequest)
+	})
+
+	t.Run("compare loans -> credit products only, the cheapest first", func(t *testing.T) {
+		resp := testutils.PostWithAuth(t, st, "/products/compare-loans", token, dto.LoanCompareRequest{
+			Amount:     100000,
+			TermMonths: 12,
+		}).ExpectStatus(t, http.StatusOK).Resp
+		out := testutils.DecodeJSON[dto.LoanCompareResponse](t, resp)
+
+		// a loan and a credit card of every bank in the catalogue
+		credit := 0
+		for _, typ := range []string{"loan", "credit_card"} {
+			resp := testutils.GetWithAuth(t, st, "/products?product_type="+typ, token).
+				ExpectStatus(t, http.StatusOK).Resp
+			credit += len(testutils.DecodeJSON[[]dto.ProductResponse](t, resp))
+		}
+		require.NotZero(t, credit)
+		require.Len(t, out.Offers, credit)
+		for i, o := range out.Offers {
+			require.Contains(t, []string{"loan", "credit_card"}, o.Product.ProductType)
+			require.Equal(t, i+1, o.Rank)
+			if i > 0 {
+				require.GreaterOrEqual(t, o.Annuity.Overpayment, out.Offers[i-1].Annuity.Overpayment)
+			}
+		}
+	})
+}
+
+// TestHTTP_RecommendationRulesOffline targets catalogue products by bank connection and total balance;
+// the profile is read from the store only, the catalogue never syncs accounts
+func TestHTTP_RecommendationRulesOffline(t *testing.T) {
+	st := suite.NewOffline(t)
+	defer st.Cancel()
+
+	token := testutils.
+		PostWithBody(t, st, "/auth/register", testutils.NewFakeUser()).
+		ExpectStatus(t, http.StatusCreated).
+		DecodeTokenResponse(t).AccessToken
+	me := testutils.DecodeJSON[dto.UserResponse](t,
+		testutils.GetWithAuth(t, st, "/me", token).ExpectStatus(t, http.StatusOK).Resp)
+	admin := testutils.RegisterAdmin(t, st)
+
+	minBalance := 1.0
+	for _, rule := range []dto.RecommendationRuleRequest{{
+		Name:           "Open abank",
+		BankCode:       "abank",
+		ProductType:    "deposit",
+		BankConnection: domain.BankConnectionNotConnected,
+		Reason:         "abank is not connected",
+	}, {
+		Name:        "Keep savings in vbank",
+		BankCode:    "vbank",
+		ProductType: "deposit",
+		MinBalance:  &minBalance,
+		Reason:      "money on accounts",
+	}} {
+		testutils.PostWithAuth(t, st, "/admin/recommendation-rules", token, rule).
+			ExpectStatus(t, http.StatusForbidden)
+		testutils.PostWithAuth(t, st, "/admin/recommendation-rules", admin, rule).
+			ExpectStatus(t, http.StatusCreated)
+	}
+
+	// bank code -> reasons of the deposits recommended by rules (the static list gives no reason)
+	recommended := func(t *testing.T) map[string][]string {
+		t.Helper()
+		resp := testutils.GetWithAuth(t, st, "/products?product_type=deposit", token).
+			ExpectStatus(t, http.StatusOK).Resp
+		out := map[string][]string{}
+		for _, p := range testutils.DecodeJSON[[]dto.ProductResponse](t, resp) {
+			if p.IsRecommended && p.RecommendationReason != "" {
+				out[p.BankCode] = append(out[p.BankCode], p.RecommendationReason)
+			}
+		}
+		return out
+	}
+	connect := func(t *testing.T, bankCode string) {
+		t.Helper()
+		testutils.PostWithAuth(t, st, "/consents/request", token, dto.ConsentCreateRequest{
+			BankCode: bankCode,
+			ClientID: "team014-1",
+		}).ExpectStatus(t, http.StatusCreated)
+	}
+
+	t.Run("nothing connected -> only the not_connected rule", func(t *testing.T) {
+		got := recommended(t)
+		require.Equal(t, []string{"abank is not connected", "abank is not connected"}, got["abank"])
+		require.Empty(t, got["vbank"])
+	})
+
+	t.Run("connected, accounts not read yet -> no sync from the catalogue", func(t *testing.T) {
+		connect(t, "vbank")
+
+		require.Empty(t, recommended(t)["vbank"])
+		stored, err := st.AccountService.ListStoredAccounts(st.Ctx, me.ID)
+		require.NoError(t, err)
+		require.Empty(t, stored)
+	})
+
+	t.Run("stored balances -> min_balance rule matches", func(t *testing.T) {
+		testutils.GetWithAuth(t, st, "/accounts", token).ExpectStatus(t, http.StatusOK)
+
+		require.Equal(t, []string{"money on accounts", "money on accounts"}, recommended(t)["vbank"])
+	})
+
+	t.Run("abank connected -> the not_connected rule stops matching", func(t *testing.T) {
+		connect(t, "abank")
+
+		got := recommended(t)
+		require.Empty(t, got["abank"])
+		require.Len(t, got["vbank"], 2)
+	})
+
+	t.Run("not an admin -> cannot read or change the rules", func(t *testing.T) {
+		testutils.GetWithAuth(t, st, "/admin/recommendation-rules", token).ExpectStatus(t, http.StatusForbidden)
+
+		resp := testutils.GetWithAuth(t, st, "/admin/recommendation-rules", admin).ExpectStatus(t, http.StatusOK).Resp
+		rules := testutils.DecodeJSON[[]dto.RecommendationRuleResponse](t, resp)
+		require.Len(t, rules, 2)
+
+		path := fmt.Sprintf("/admin/recommendation-rules/%d", rules[0].ID)
+		testutils.PutWithAuth(t, st, path, token, rules[0].RecommendationRuleRequest).ExpectStatus(t, http.StatusForbidden)
+		testutils.DeleteWithAuth(t, st, path, token).ExpectStatus(t, http.StatusForbidden)
+
+		resp = testutils.GetWithAuth(t, st, "/admin/recommendation-rules", admin).ExpectStatus(t, http.StatusOK).Resp
+		require.Len(t, testutils.DecodeJSON[[]dto.RecommendationRuleResponse](t, resp), 2)
+	})
+}
+
+// TestHTTP_DepositCompareOffline calculates simple and capitalized payouts of deposits accepting the amount
+// for the term and ranks them, recommended deposits first among equal payouts
+func TestHTTP_DepositCompareOffline(t *testing.T) {
+	st := suite.NewOffline(t)
+	defer st.Cancel()
+
+	token := testutils.
+		PostWithBody(t, st, "/auth/register", testutils.NewFakeUser()).
+		ExpectStatus(t, http.StatusCreated).
+		DecodeTokenResponse(t).AccessToken
+
+	compare := func(t *testing.T, req dto.DepositCompareRequest) []dto.DepositOfferResponse {
+		t.Helper()
+		resp := testutils.PostWithAuth(t, st, "/products/compare", token, req).ExpectStatus(t, http.StatusOK).Resp
+		out := testutils.DecodeJSON[dto.DepositCompareResponse](t, resp)
+		require.Equal(t, "RUB", out.Currency)
+		return out.Offers
+	}
+
+	t.Run("invalid input -> 400", func(t *testing.T) {
+		for _, req := range []dto.DepositCompareRequest{
+			{Amount: 0, TermMonths: 12},
+			{Amount: 100000, TermMonths: 0},
+			{Amount: 100000, TermMonths: 12, Currency: "USD"},
+		} {
+			testutils.PostWithAuth(t, st, "/products/compare", token, req).ExpectStatus(t, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("12 months -> yearly deposits ranked by the capitalized payout", func(t *testing.T) {
+		offers := compare(t, dto.DepositCompareRequest{Amount: 100000, TermMonths: 12})
+		require.Len(t, offers, 3)
+		for i, o := range offers {
+			require.Equal(t, i+1, o.Rank)
+			require.Contains(t, o.Product.ProductID, "-deposit-001")
+
+			rate := o.Product.InterestRate / 100
+			require.InDelta(t, 100000*rate, o.SimpleInterest, 0.01)
+			require.InDelta(t, 100000+o.SimpleInterest, o.SimplePayout, 0.01)
+			require.InDelta(t, 100000*(math.Pow(1+rate/12, 12)-1), o.CapitalizedInterest, 0.01)
+			require.Greater(t, o.CapitalizedPayout, o.SimplePayout)
+			if i > 0 {
+				require.GreaterOrEqual(t, offers[i-1].CapitalizedPayout, o.CapitalizedPayout)
+			}
+		}
+	})
+
+	t.Run("amount below the minimum -> no offers", func(t *testing.T) {
+		require.Empty(t, compare(t, dto.DepositCompareRequest{Amount: 20000, TermMonths: 6}))
+		require.Len(t, compare(t, dto.DepositCompareRequest{Amount: 50000, TermMonths: 6}), 3)
+	})
+
+	t.Run("equal payouts -> recommended deposits first", func(t *testing.T) {
+		testutils.PostWithAuth(t, st, "/admin/recommended-products", testutils.RegisterAdmin(t, st), dto.RecommendedUpsertRequest{
+			ProductID:   "prod-vbank-deposit-001",
+			BankCode:    "vbank",
+			ProductType: "deposit",
+		}).ExpectStatus(t, http.StatusNoContent)
+		for code, fake := range st.FakeBanks {
+			require.True(t, fake.SetProductRate("prod-"+code+"-deposit-001", "15.00"))
+		}
+		_, err := st.ProductService.Refresh(st.Ctx, 4)
+		require.NoError(t, err)
+
+		offers := compare(t, dto.DepositCompareRequest{Amount: 100000, TermMonths: 12})
+		require.Len(t, offers, 3)
+		require.True(t, offers[0].Product.IsRecommended)
+		require.True(t, offers[1].Product.IsRecommended)
+		require.Equal(t, "sbank", offers[2].Product.BankCode)
+		require.False(t, offers[2].Product.IsRecommended)
+		require.Equal(t, offers[0].CapitalizedPayout, offers[2].CapitalizedPayout)
+	})
+}
@@ -0,0 +1,115 @@
+// tests/product_history_e2e_test.go
+
+package tests
+
+import (
+	"fmt"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/http-server/dto"
+	"net/http"
+	"testing"
+
+	"multibank/backend/tests/suite"
+	testutils "multibank/backend/tests/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestHTTP_ProductHistoryOffline records rate changes between refreshes and notifies subscribers only
+func TestHTTP_ProductHistoryOffline(t *testing.T) {
+	st := suite.NewOffline(t)
+	defer st.Cancel()
+
+	register := func(t *testing.T) string {
+		t.Helper()
+		return testutils.
+			PostWithBody(t, st, "/auth/register", testutils.NewFakeUser()).
+			ExpectStatus(t, http.StatusCreated).
+			DecodeTokenResponse(t).AccessToken
+	}
+	token, other := register(t), register(t)
+	fake := st.FakeBanks["vbank"]
+
+	const path = "/products/vbank/prod-vbank-deposit-001"
+	history := func(t *testing.T) []dto.ProductChangeResponse {
+		t.Helper()
+		resp := testutils.GetWithAuth(t, st, path+"/history", token).ExpectStatus(t, http.StatusOK).Resp
+		return testutils.DecodeJSON[dto.ProductHistoryResponse](t, resp).Changes
+	}
+	notifications := func(t *testing.T, token string, unread bool) []dto.NotificationResponse {
+		t.Helper()
+		resp := testutils.GetWithAuth(t, st, fmt.Sprintf("/me/notifications?unread=%t", unread), token).
+			ExpectStatus(t, http.StatusOK).Resp
+		return testutils.DecodeJSON[[]dto.NotificationResponse](t, resp)
+	}
+	changeRate := func(t *testing.T, rate float64) {
+		t.Helper()
+		require.True(t, fake.SetProductRate("prod-vbank-deposit-001", fmt.Sprintf("%.2f", rate)))
+		_, err := st.ProductService.Refresh(st.Ctx, 4)
+		require.NoError(t, err)
+	}
+
+	var rate float64
+	resp := testutils.GetWithAuth(t, st, "/products?product_type=deposit", token).ExpectStatus(t, http.StatusOK).Resp
+	for _, p := range testutils.DecodeJSON[[]dto.ProductResponse](t, resp) {
+		if p.ProductID == "prod-vbank-deposit-001" {
+			rate = p.InterestRate
+		}
+	}
+	require.NotZero(t, rate)
+
+	t.Run("unknown product -> 404", func(t *testing.T) {
+		testutils.GetWithAuth(t, st, "/products/vbank/prod-unknown/history", token).
+			ExpectStatus(t, http.StatusNotFound)
+		testutils.PostWithAuth(t, st, "/products/vbank/prod-unknown/subscription", token, nil).
+			ExpectStatus(t, http.StatusNotFound)
+	})
+
+	t.Run("subscribe -> listed", func(t *testing.T) {
+		testutils.PostWithAuth(t, st, path+"/subscription", token, nil).ExpectStatus(t, http.StatusNoContent)
+
+		resp := testutils.GetWithAuth(t, st, "/me/subscriptions", token).ExpectStatus(t, http.StatusOK).Resp
+		subs := testutils.DecodeJSON[[]dto.ProductSubscriptionResponse](t, resp)
+		require.Len(t, subs, 1)
+		require.Equal(t, "prod-vbank-deposit-001", subs[0].ProductID)
+		require.Empty(t, history(t))
+	})
+
+	t.Run("refresh without changes -> no history", func(t *testing.T) {
+		_, err := st.ProductService.Refresh(st.Ctx, 4)
+		require.NoError(t, err)
+		require.Empty(t, history(t))
+		require.Empty(t, notifications(t, token, false))
+	})
+
+	t.Run("rate changed -> history and a notification of the subscriber", func(t *testing.T) {
+		changeRate(t, rate+1)
+
+		changes := history(t)
+		require.Len(t, changes, 1)
+		require.Equal(t, domain.FieldInterestRate, changes[0].Field)
+		require.InDelta(t, rate, changes[0].OldValue, 1e-9)
+		require.InDelta(t, rate+1, changes[0].NewValue, 1e-9)
+
+		items := notifications(t, token, true)
+		require.Len(t, items, 1)
+		require.Equal(t, domain.NotificationProductRateChanged, items[0].Kind)
+		require.Equal(t, "vbank", items[0].BankCode)
+		require.Equal(t, "prod-vbank-deposit-001", items[0].ProductID)
+		require.Empty(t, notifications(t, other, false))
+
+		testutils.PostWithAuth(t, st, fmt.Sprintf("/me/notifications/%d/read", items[0].ID), other, nil).
+			ExpectStatus(t, http.StatusNotFound)
+		testutils.PostWithAuth(t, st, fmt.Sprintf("/me/notifications/%d/read", items[0].ID), token, nil).
+			ExpectStatus(t, http.StatusNoContent)
+		require.Empty(t, notifications(t, token, true))
+	})
+
+	t.Run("unsubscribed -> history only", func(t *testing.T) {
+		testutils.DeleteWithAuth(t, st, path+"/subscription", token).ExpectStatus(t, http.StatusNoContent)
+		changeRate(t, rate)
+
+		require.Len(t, history(t), 2)
+		require.Len(t, notifications(t, token, false), 1)
+	})
+}
@@ -0,0 +1,183 @@
+// tests/suite/offline.go
+
+package suite
+
+import (
+	"context"
+	"log/slog"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/fakebank"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	httpserver "multibank/backend/internal/http-server"
+	"multibank/backend/internal/logger"
+	"multibank/backend/internal/secret"
+	accountsvc "multibank/backend/internal/service/account"
+	agreementsvc "multibank/backend/internal/service/agreement"
+	authsvc "multibank/backend/internal/service/auth"
+	"multibank/backend/internal/service/auth/jwt"
+	balancesvc "multibank/backend/internal/service/balance"
+	banksvc "multibank/backend/internal/service/bank"
+	consentsvc "multibank/backend/internal/service/consent"
+	fxsvc "multibank/backend/internal/service/fx"
+	notificationsvc "multibank/backend/internal/service/notification"
+	"multibank/backend/internal/service/openbanking"
+	paymentsvc "multibank/backend/internal/service/payment"
+	productsvc "multibank/backend/internal/service/product"
+	recommendationsvc "multibank/backend/internal/service/recommendation"
+	transfersvc "multibank/backend/internal/service/transfer"
+	usersvc "multibank/backend/internal/service/user"
+	"multibank/backend/internal/storage/sqlite"
+)
+
+const fakeClientSecret = "fake-secret"
+
+// NewOffline starts the backend on a temporary database with every bank of the config served by a fake bank,
+// so the consent -> accounts -> products -> payments flow runs without the sandbox.
+func NewOffline(t *testing.T) *Suite {
+	t.Helper()
+
+	cfg, _ := mustLoadTestConfig(t)
+	cfg.StoragePath = filepath.Join(t.TempDir(), "multibank.db")
+
+	st, err := sqlite.New(cfg.StoragePath)
+	if err != nil {
+		t.Fatalf("init sqlite: %v", err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	t.Cleanup(cancel)
+
+	if err := st.Migrate(ctx); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	log := logger.Setup(cfg.Level).With(slog.String("scope", "test-offline"))
+
+	// --- services (as in app.New, without background jobs) ---
+	obTransport := openbanking.NewTransport(log, http.DefaultTransport, openbanking.DefaultTransportOptions())
+	obHTTP := &http.Client{Timeout: 10 * time.Second, Transport: obTransport}
+
+	box, err := secret.NewBox(cfg.Secrets.MasterKey, cfg.Secrets.OldMasterKeys...)
+	if err != nil {
+		t.Fatalf("master key: %v", err)
+	}
+	bankRepo := sqlite.NewBankRepo(st.DB(), box)
+	obAdapters := openbanking.NewAdapters(openbanking.NewSandboxAdapter(log, obHTTP,
+		cfg.Requester.Code, cfg.Requester.Name, cfg.Requester.Reason))
+	bankSvc := banksvc.New(log, bankRepo, obAdapters)
+
+	// --- fake banks instead of the sandbox, declared as the config does ---
+	fakes := make(map[string]*fakebank.Server, len(cfg.Banks))
+	for i, b := range cfg.Banks {
+		fb := fakebank.New(log, fakebank.Options{
+			Code:         b.Code,
+			Name:         b.Name,
+			ClientID:     b.ClientID,
+			ClientSecret: fakeClientSecret,
+			AutoApprove:  true,
+		})
+		ts := httptest.NewServer(fb)
+		t.Cleanup(ts.Close)
+		fakes[b.Code] = fb
+
+		b.APIBaseURL = ts.URL
+		b.ClientSecret, b.ClientSecretEnv, b.ClientSecretFile = fakeClientSecret, "", ""
+		cfg.Banks[i] = b
+	}
+	decls, err := cfg.BankDeclarations()
+	if err != nil {
+		t.Fatalf("config banks: %v", err)
+	}
+	if err := bankSvc.Reconcile(ctx, decls); err != nil {
+		t.Fatalf("reconcile banks: %v", err)
+	}
+
+	userRepo := sqlite.NewUserRepo(st.DB())
+	userSvc := usersvc.New(log, userRepo)
+
+	consentRepo := sqlite.NewConsentRepo(st.DB())
+	consentSvc := consentsvc.New(log, consentRepo, bankSvc, obAdapters,
+		[]domain.Permission{domain.ReadAccountsDetail, domain.ReadBalances, domain.ReadTransactionsDetail},
+		cfg.Requester.Code, cfg.Requester.Name, cfg.Requester.Reason)
+
+	snapshotRepo := sqlite.NewBalanceSnapshotRepo(st.DB())
+	accountSvc := accountsvc.New(log, consentRepo, bankSvc, obAdapters,
+		sqlite.NewTransactionRepo(st.DB()), sqlite.NewAccountRepo(st.DB()), snapshotRepo,
+		15*time.Minute)
+
+	fxSvc := fxsvc.New(log, sqlite.NewFXRepo(st.DB()), accountSvc, obHTTP, cfg.FX.Source)
+	balanceSvc := balancesvc.New(log, snapshotRepo, fxSvc)
+	recommendationSvc := recommendationsvc.New(log, sqlite.NewRecommendationRuleRepo(st.DB()), userRepo, consentRepo, fxSvc)
+
+	eventRepo := sqlite.NewRecommendationEventRepo(st.DB())
+	recommendedRepo := sqlite.NewRecommendedProductsRepo(st.DB())
+	notificationSvc := notificationsvc.New(log, sqlite.NewNotificationRepo(st.DB()))
+	prodSvc := productsvc.New(log, bankRepo, bankSvc, recommendedRepo,
+		sqlite.NewProductRepo(st.DB()), notificationSvc, recommendationSvc, eventRepo, obAdapters)
+
+	paymentSvc := paymentsvc.New(log, sqlite.NewPaymentRepo(st.DB()), bankSvc, accountSvc,
+		openbanking.NewPaymentClient(log, obHTTP, cfg.Requester.Code), cfg.Requester.Code)
+	transferSvc := transfersvc.New(log, sqlite.NewTransferRepo(st.DB()), accountSvc, paymentSvc)
+	agreementSvc := agreementsvc.New(log, sqlite.NewAgreementRepo(st.DB()), consentRepo, bankSvc,
+		openbanking.NewProductAgreementClient(log, obHTTP, cfg.Requester.Code), cfg.Requester.Code)
+
+	jwtMng := jwt.New(cfg.HTTPServer.JWTSecret, cfg.HTTPServer.TokenTTL)
+	authSvc := authsvc.New(log, userSvc, jwtMng)
+
+	srv := httpserver.New(httpserver.Deps{
+		Logger:         log,
+		UserService:    userSvc,
+		AuthService:    authSvc,
+		BankService:    bankSvc,
+		BankAdmin:      bankSvc,
+		BankBreakers:   obTransport,
+		ConsentService: consentSvc,
+		AccountService: accountSvc,
+		ProductService: prodSvc,
+		FXService:      fxSvc,
+		BalanceService: balanceSvc,
+		JWT:            jwtMng,
+
+		PaymentService:   paymentSvc,
+		TransferService:  transferSvc,
+		AgreementService: agreementSvc,
+
+		NotificationService: notificationSvc,
+		RecommendedService:  productsvc.NewRecommendedService(recommendedRepo, eventRepo),
+		RecommendationRules: recommendationSvc,
+	}, httpserver.Options{
+		RequestTimeout: cfg.HTTPServer.Timeout,
+	})
+	t.Cleanup(srv.Stop)
+
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+
+	return &Suite{
+		T:           t,
+		Ctx:         ctx,
+		Cancel:      cancel,
+		Log:         log,
+		Cfg:         cfg,
+		JWTManager:  jwtMng,
+		UserService: userSvc,
+		BankService: bankSvc,
+		AuthService: authSvc,
+		Server:      ts,
+		BaseURL:     ts.URL,
+		Client:      ts.Client(),
+		Storage:     st,
+		FakeBanks:   fakes,
+
+		AccountService: accountSvc,
+		FXService:      fxSvc,
+		ProductService: prodSvc,
+		PaymentService: paymentSvc,
+	}
+}
@@ -3,9 +3,15 @@ package suite
 import (
 	"context"
 	"log/slog"
+	"multibank/backend/internal/fakebank"
 	"multibank/backend/internal/logger"
+	"multibank/backend/internal/secret"
+	accountsvc "multibank/backend/internal/service/account"
 	authsvc "multibank/backend/internal/service/auth"
 	"multibank/backend/internal/service/auth/jwt"
+	fxsvc "multibank/backend/internal/service/fx"
+	paymentsvc "multibank/backend/internal/service/payment"
+	productsvc "multibank/backend/internal/service/product"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -18,6 +24,7 @@ import (
 
 	"multibank/backend/internal/config"
 	banksvc "multibank/backend/internal/service/bank"
+	"multibank/backend/internal/service/openbanking"
 	usersvc "multibank/backend/internal/service/user"
 	"multibank/backend/internal/storage/sqlite"
 )
@@ -36,6 +43,12 @@ type Suite struct {
 	BaseURL     string
 	Client      *http.Client
 	Storage     *sqlite.Storage
+	FakeBanks   map[string]*fakebank.Server // by bank code, NewOffline only
+
+	AccountService *accountsvc.Service // NewOffline only
+	FXService      *fxsvc.Service      // NewOffline only
+	ProductService *productsvc.Service // NewOffline only
+	PaymentService *paymentsvc.Service // NewOffline only
 }
 
 func New(t *testing.T) *Suite {
@@ -81,8 +94,20 @@ func New(t *testing.T) *Suite {
 	usrRepo := sqlite.NewUserRepo(st.DB())
 	userSvc := usersvc.New(log, usrRepo)
 
-	bankRepo := sqlite.NewBankRepo(st.DB())
-	bankSvc := banksvc.New(log, bankRepo)
+	box, err := secret.NewBox(cfg.Secrets.MasterKey, cfg.Secrets.OldMasterKeys...)
+	if err != nil {
+		t.Fatalf("master key: %v", err)
+	}
+	bankRepo := sqlite.NewBankRepo(st.DB(), box)
+	bankSvc := banksvc.New(log, bankRepo, openbanking.NewTokenClient(log, nil))
+
+	decls, err := cfg.BankDeclarations()
+	if err != nil {
+		t.Fatalf("config banks: %v", err)
+	}
+	if err := bankSvc.Reconcile(ctx, decls); err != nil {
+		t.Fatalf("reconcile banks: %v", err)
+	}
 
 	jwtMng := jwt.New(cfg.HTTPServer.JWTSecret, cfg.HTTPServer.TokenTTL)
 	authSvc := authsvc.New(log, userSvc, jwtMng)
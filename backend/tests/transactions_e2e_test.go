@@ -0,0 +1,93 @@
+// tests/transactions_e2e_test.go
+
+package tests
+
+import (
+	"fmt"
+	"multibank/backend/internal/fakebank"
+	"multibank/backend/internal/http-server/dto"
+	"net/http"
+	"testing"
+
+	"multibank/backend/tests/suite"
+	testutils "multibank/backend/tests/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestHTTP_TransactionSyncOffline syncs transactions incrementally: the last booking date is requested again
+// (inclusive), so a transaction booked at the same time is picked up and the known ones are not duplicated
+func TestHTTP_TransactionSyncOffline(t *testing.T) {
+	st := suite.NewOffline(t)
+	defer st.Cancel()
+
+	token := testutils.
+		PostWithBody(t, st, "/auth/register", testutils.NewFakeUser()).
+		ExpectStatus(t, http.StatusCreated).
+		DecodeTokenResponse(t).AccessToken
+
+	testutils.PostWithAuth(t, st, "/consents/request", token, dto.ConsentCreateRequest{
+		BankCode: "vbank",
+		ClientID: "team014-1",
+	}).ExpectStatus(t, http.StatusCreated)
+
+	bank, err := st.BankService.GetBankByCode(st.Ctx, "vbank")
+	require.NoError(t, err)
+	resp := testutils.GetWithAuth(t, st, fmt.Sprintf("/accounts?bank_id=%d", bank.ID), token).
+		ExpectStatus(t, http.StatusOK).Resp
+	accounts := testutils.DecodeJSON[[]dto.AccountResponse](t, resp)
+	require.Len(t, accounts, 2)
+	accountID := accounts[0].AccountID
+
+	list := func(t *testing.T) dto.TransactionsPageResponse {
+		t.Helper()
+		resp := testutils.GetWithAuth(t, st,
+			fmt.Sprintf("/accounts/%s/transactions?bank_id=%d&limit=100", accountID, bank.ID), token).
+			ExpectStatus(t, http.StatusOK).Resp
+		return testutils.DecodeJSON[dto.TransactionsPageResponse](t, resp)
+	}
+
+	t.Run("first sync -> every transaction of both accounts", func(t *testing.T) {
+		n, err := st.AccountService.SyncTransactions(st.Ctx, 2)
+		require.NoError(t, err)
+		require.Equal(t, 10, n)
+		require.Equal(t, 5, list(t).Total)
+	})
+
+	t.Run("repeated sync -> nothing changed, no duplicates", func(t *testing.T) {
+		n, err := st.AccountService.SyncTransactions(st.Ctx, 2)
+		require.NoError(t, err)
+		require.Zero(t, n)
+		require.Equal(t, 5, list(t).Total)
+	})
+
+	t.Run("transaction booked at the last booking time -> picked up once", func(t *testing.T) {
+		page := list(t)
+		last := page.Transactions[0].BookingDateTime
+		require.NotNil(t, last)
+		for _, tx := range page.Transactions {
+			require.False(t, tx.BookingDateTime.After(*last), "transactions are ordered by booking date desc")
+		}
+
+		require.True(t, st.FakeBanks["vbank"].AddTransaction("team014-1", accountID, fakebank.Transaction{
+			ID:          "tx-same-time",
+			Amount:      -250.5,
+			Info:        "Оплата покупки",
+			Code:        "PMNT",
+			BookingTime: *last,
+		}))
+
+		n, err := st.AccountService.SyncTransactions(st.Ctx, 2)
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+
+		page = list(t)
+		require.Equal(t, 6, page.Total)
+		ids := map[string]struct{}{}
+		for _, tx := range page.Transactions {
+			ids[tx.TransactionID] = struct{}{}
+		}
+		require.Len(t, ids, 6)
+		require.Contains(t, ids, "tx-same-time")
+	})
+}
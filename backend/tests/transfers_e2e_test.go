@@ -0,0 +1,154 @@
+// tests/transfers_e2e_test.go
+
+package tests
+
+import (
+	"fmt"
+	"multibank/backend/internal/domain"
+	"multibank/backend/internal/http-server/dto"
+	"net/http"
+	"testing"
+
+	"multibank/backend/tests/suite"
+	testutils "multibank/backend/tests/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestHTTP_TransfersOffline moves money between own accounts: AwaitingAuthorization -> Processing -> Completed,
+// one payment per transfer however often it is polled, Failed when the bank refuses
+func TestHTTP_TransfersOffline(t *testing.T) {
+	st := suite.NewOffline(t)
+	defer st.Cancel()
+
+	token := testutils.
+		PostWithBody(t, st, "/auth/register", testutils.NewFakeUser()).
+		ExpectStatus(t, http.StatusCreated).
+		DecodeTokenResponse(t).AccessToken
+
+	testutils.PostWithAuth(t, st, "/consents/request", token, dto.ConsentCreateRequest{
+		BankCode: "vbank",
+		ClientID: "team014-1",
+	}).ExpectStatus(t, http.StatusCreated)
+
+	balances := func(t *testing.T) map[string]int64 {
+		t.Helper()
+		resp := testutils.GetWithAuth(t, st, "/accounts", token).ExpectStatus(t, http.StatusOK).Resp
+		out := map[string]int64{}
+		for _, a := range testutils.DecodeJSON[[]dto.AccountResponse](t, resp) {
+			k, err := domain.ParseKopecks(a.Amount)
+			require.NoError(t, err)
+			out[a.AccountID] = k
+		}
+		return out
+	}
+	before := balances(t)
+	require.Len(t, before, 2)
+	ids := make([]string, 0, 2)
+	for id := range before {
+		ids = append(ids, id)
+	}
+	src, dst := ids[0], ids[1]
+	if before[src] < before[dst] { // pay from the larger balance
+		src, dst = dst, src
+	}
+	fake := st.FakeBanks["vbank"]
+
+	create := func(t *testing.T, amount string) *http.Response {
+		t.Helper()
+		return testutils.PostWithAuth(t, st, "/transfers", token, dto.TransferCreateRequest{
+			Source: dto.TransferAccountRef{AccountID: src},
+			Target: dto.TransferAccountRef{AccountID: dst},
+			Amount: amount,
+		}).Resp
+	}
+	get := func(t *testing.T, id int64) dto.TransferResponse {
+		t.Helper()
+		resp := testutils.GetWithAuth(t, st, fmt.Sprintf("/transfers/%d", id), token).
+			ExpectStatus(t, http.StatusOK).Resp
+		return testutils.DecodeJSON[dto.TransferResponse](t, resp)
+	}
+
+	t.Run("invalid transfers -> rejected before the bank", func(t *testing.T) {
+		require.Equal(t, http.StatusBadRequest, create(t, "1.001").StatusCode)
+		require.Equal(t, http.StatusUnprocessableEntity,
+			create(t, domain.FormatKopecks(before[src]+1)).StatusCode)
+		require.Equal(t, 0, fake.PaymentCount())
+	})
+
+	t.Run("approved consent -> Processing, then Completed with one payment", func(t *testing.T) {
+		resp := create(t, "1000.00")
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		tr := testutils.DecodeJSON[dto.TransferResponse](t, resp)
+		require.Equal(t, domain.TransferProcessing, tr.Status)
+		require.NotNil(t, tr.PaymentID)
+
+		tr = get(t, tr.ID)
+		require.Equal(t, domain.TransferCompleted, tr.Status)
+		require.NotNil(t, tr.CompletedAt)
+		require.Equal(t, domain.TransferCompleted, get(t, tr.ID).Status)
+		require.Equal(t, 1, fake.PaymentCount())
+
+		_, err := st.AccountService.SyncAccounts(st.Ctx, 1)
+		require.NoError(t, err)
+		after := balances(t)
+		require.Equal(t, before[src]-100000, after[src])
+		require.Equal(t, before[dst]+100000, after[dst])
+	})
+
+	t.Run("consent awaiting approval -> paid after the approval", func(t *testing.T) {
+		fake.SetAutoApprove(false)
+		defer fake.SetAutoApprove(true)
+
+		resp := create(t, "10.00")
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		tr := testutils.DecodeJSON[dto.TransferResponse](t, resp)
+		require.Equal(t, domain.TransferAwaitingAuthorization, tr.Status)
+		require.NotNil(t, tr.PaymentConsentID)
+		require.Equal(t, domain.TransferAwaitingAuthorization, get(t, tr.ID).Status)
+
+		resp = testutils.GetWithAuth(t, st, fmt.Sprintf("/payments/consents/%d", *tr.PaymentConsentID), token).
+			ExpectStatus(t, http.StatusOK).Resp
+		require.True(t, fake.ApprovePayment(testutils.DecodeJSON[dto.PaymentConsentResponse](t, resp).RequestID))
+
+		require.Equal(t, domain.TransferProcessing, get(t, tr.ID).Status)
+		require.Equal(t, domain.TransferCompleted, get(t, tr.ID).Status)
+		require.Equal(t, 2, fake.PaymentCount())
+	})
+
+	t.Run("consent rejected in the bank -> Failed", func(t *testing.T) {
+		fake.SetAutoApprove(false)
+		defer fake.SetAutoApprove(true)
+
+		resp := create(t, "10.00")
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		tr := testutils.DecodeJSON[dto.TransferResponse](t, resp)
+
+		resp = testutils.GetWithAuth(t, st, fmt.Sprintf("/payments/consents/%d", *tr.PaymentConsentID), token).
+			ExpectStatus(t, http.StatusOK).Resp
+		require.True(t, fake.RejectPayment(testutils.DecodeJSON[dto.PaymentConsentResponse](t, resp).RequestID))
+
+		tr = get(t, tr.ID)
+		require.Equal(t, domain.TransferFailed, tr.Status)
+		require.NotEmpty(t, tr.Error)
+		require.Equal(t, 2, fake.PaymentCount())
+	})
+
+	t.Run("bank is down -> 502, the transfer is saved as Failed", func(t *testing.T) {
+		fake.SetFaults(0, 0, 1)
+		defer fake.SetFaults(0, 0, 0)
+
+		require.Equal(t, http.StatusBadGateway, create(t, "10.00").StatusCode)
+
+		resp := testutils.GetWithAuth(t, st, "/transfers", token).ExpectStatus(t, http.StatusOK).Resp
+		items := testutils.DecodeJSON[[]dto.TransferResponse](t, resp)
+		require.Len(t, items, 4)
+		failed := 0
+		for _, it := range items {
+			if it.Status == domain.TransferFailed {
+				failed++
+			}
+		}
+		require.Equal(t, 2, failed)
+	})
+}
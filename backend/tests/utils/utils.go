@@ -5,6 +5,7 @@ package testutils
 import (
 	"bytes"
 	"encoding/json"
+	"io"
 	"net/http"
 	"testing"
 	"time"
@@ -35,6 +36,40 @@ func PostWithBody(t *testing.T, s *suite.Suite, path string, body any) *Response
 	return &ResponseWrapper{Resp: resp}
 }
 
+// PostWithAuth does POST with JSON body (nil = no body) and the header Authorization: Bearer <token>
+func PostWithAuth(t *testing.T, s *suite.Suite, path, token string, body any) *ResponseWrapper {
+	return sendWithAuth(t, s, http.MethodPost, path, token, body)
+}
+
+// PutWithAuth does PUT with JSON body and the header Authorization: Bearer <token>
+func PutWithAuth(t *testing.T, s *suite.Suite, path, token string, body any) *ResponseWrapper {
+	return sendWithAuth(t, s, http.MethodPut, path, token, body)
+}
+
+// DeleteWithAuth does DELETE with the header Authorization: Bearer <token>
+func DeleteWithAuth(t *testing.T, s *suite.Suite, path, token string) *ResponseWrapper {
+	return sendWithAuth(t, s, http.MethodDelete, path, token, nil)
+}
+
+func sendWithAuth(t *testing.T, s *suite.Suite, method, path, token string, body any) *ResponseWrapper {
+	var rd io.Reader = http.NoBody
+	if body != nil {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		rd = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, s.BaseURL+path, rd)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := s.Client.Do(req)
+	require.NoError(t, err)
+
+	return &ResponseWrapper{Resp: resp}
+}
+
 // GetWOBody doest HTTP GET-request without and returns ResponseWrapper.
 func GetWOBody(t *testing.T, s *suite.Suite, path string, headers ...map[string]string) *ResponseWrapper {
 	req, err := http.NewRequest(http.MethodGet, s.BaseURL+path, nil)
@@ -115,6 +150,18 @@ func NewFakeUser() FakeUser {
 	}
 }
 
+// RegisterAdmin registers a new user, grants it admin rights and returns its access token
+func RegisterAdmin(t *testing.T, s *suite.Suite) string {
+	t.Helper()
+
+	u := NewFakeUser()
+	token := PostWithBody(t, s, "/auth/register", u).
+		ExpectStatus(t, http.StatusCreated).
+		DecodeTokenResponse(t).AccessToken
+	require.NoError(t, s.UserService.SetAdmin(s.Ctx, u.Email, true))
+	return token
+}
+
 func RandomFakePassword() string {
 	return gofakeit.Password(true, true, true, true, false, PassDefaultLen)
 }